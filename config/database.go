@@ -58,10 +58,48 @@ func ConnectDb() {
 		Gorm.AutoMigrate(
 
 			&schema.Order{},
+			&schema.OrderSequence{},
+			&schema.OrderEvent{},
 			&schema.Product{},
+			&schema.Category{},
+			&schema.ProductCategory{},
+			&schema.ProductOption{},
+			&schema.ProductVariant{},
+			&schema.ProductImage{},
+			&schema.ProductPrice{},
+			&schema.ProductImportJob{},
 			&schema.User{},
+			&schema.Address{},
+			&schema.Shipment{},
+			&schema.ShipmentEvent{},
+			&schema.Coupon{},
+			&schema.CouponProduct{},
+			&schema.CouponCategory{},
+			&schema.CouponRedemption{},
+			&schema.Invoice{},
+			&schema.InvoiceLine{},
+			&schema.InvoiceSequence{},
+			&schema.Warehouse{},
+			&schema.WarehouseStock{},
+			&schema.StockMovement{},
+			&schema.StockAlert{},
+			&schema.Supplier{},
+			&schema.PurchaseOrder{},
+			&schema.PurchaseOrderLine{},
+			&schema.Review{},
 		)
 
+		// products created before variants existed get a default variant,
+		// and their orders point to it
+		Gorm.Exec(`INSERT INTO product_variants (product_id, sku, options, price, qty, weight)
+		SELECT p.id, CONCAT('SKU-', LPAD(p.id, 6, '0')), '{}', p.price, p.qty, p.weight
+		FROM products p
+		WHERE NOT EXISTS (SELECT 1 FROM product_variants v WHERE v.product_id = p.id)`)
+		Gorm.Exec(`UPDATE orders o
+		JOIN product_variants v ON v.product_id = o.product_id AND v.options = '{}'
+		SET o.variant_id = v.id, o.sku = v.sku
+		WHERE o.variant_id = 0`)
+
 		fmt.Println("All tables recreated successfully...")
 	}
 
@@ -17,6 +17,41 @@ type Env struct {
 	DbUser        string
 	DbPassword    string
 	TokenExpired  string
+
+	ShippingOriginProvince string
+	ShippingOriginCity     string
+	ShippingRateFile       string
+	CourierName            string
+	CourierUrl             string
+	TaxRuleFile            string
+	OrderNumberFormat      string
+	SearchMode             string
+	ProductTrashDays       int
+	PriceScheduleSeconds   int
+
+	BlobStore    string
+	BlobLocalDir string
+	BlobBaseUrl  string
+	S3Endpoint   string
+	S3Region     string
+	S3Bucket     string
+	S3AccessKey  string
+	S3SecretKey  string
+	S3PublicUrl  string
+
+	InvoiceIssuerName    string
+	InvoiceIssuerAddress string
+	InvoiceIssuerTaxId   string
+
+	StockAlertNotifiers  string
+	StockAlertSeconds    int
+	StockAlertWebhookUrl string
+	StockAlertEmailTo    string
+	SmtpHost             string
+	SmtpPort             string
+	SmtpUsername         string
+	SmtpPassword         string
+	SmtpFrom             string
 }
 
 var (
@@ -35,5 +70,40 @@ func LoadEnv() {
 	env.DbUser = os.Getenv("DB_USER")
 	env.DbPassword = os.Getenv("DB_PASSWORD")
 
+	env.ShippingOriginProvince = os.Getenv("SHIPPING_ORIGIN_PROVINCE")
+	env.ShippingOriginCity = os.Getenv("SHIPPING_ORIGIN_CITY")
+	env.ShippingRateFile = os.Getenv("SHIPPING_RATE_FILE")
+	env.CourierName = os.Getenv("COURIER_NAME")
+	env.CourierUrl = os.Getenv("COURIER_URL")
+	env.TaxRuleFile = os.Getenv("TAX_RULE_FILE")
+	env.OrderNumberFormat = os.Getenv("ORDER_NUMBER_FORMAT")
+	env.SearchMode = os.Getenv("SEARCH_MODE")
+	env.ProductTrashDays, _ = strconv.Atoi(os.Getenv("PRODUCT_TRASH_DAYS"))
+	env.PriceScheduleSeconds, _ = strconv.Atoi(os.Getenv("PRICE_SCHEDULE_SECONDS"))
+
+	env.BlobStore = os.Getenv("BLOB_STORE")
+	env.BlobLocalDir = os.Getenv("BLOB_LOCAL_DIR")
+	env.BlobBaseUrl = os.Getenv("BLOB_BASE_URL")
+	env.S3Endpoint = os.Getenv("S3_ENDPOINT")
+	env.S3Region = os.Getenv("S3_REGION")
+	env.S3Bucket = os.Getenv("S3_BUCKET")
+	env.S3AccessKey = os.Getenv("S3_ACCESS_KEY")
+	env.S3SecretKey = os.Getenv("S3_SECRET_KEY")
+	env.S3PublicUrl = os.Getenv("S3_PUBLIC_URL")
+
+	env.InvoiceIssuerName = os.Getenv("INVOICE_ISSUER_NAME")
+	env.InvoiceIssuerAddress = os.Getenv("INVOICE_ISSUER_ADDRESS")
+	env.InvoiceIssuerTaxId = os.Getenv("INVOICE_ISSUER_TAX_ID")
+
+	env.StockAlertNotifiers = os.Getenv("STOCK_ALERT_NOTIFIERS")
+	env.StockAlertSeconds, _ = strconv.Atoi(os.Getenv("STOCK_ALERT_SECONDS"))
+	env.StockAlertWebhookUrl = os.Getenv("STOCK_ALERT_WEBHOOK_URL")
+	env.StockAlertEmailTo = os.Getenv("STOCK_ALERT_EMAIL_TO")
+	env.SmtpHost = os.Getenv("SMTP_HOST")
+	env.SmtpPort = os.Getenv("SMTP_PORT")
+	env.SmtpUsername = os.Getenv("SMTP_USERNAME")
+	env.SmtpPassword = os.Getenv("SMTP_PASSWORD")
+	env.SmtpFrom = os.Getenv("SMTP_FROM")
+
 	EnvFile = env
 }
@@ -1,11 +1,24 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"kanggo/config"
+	"kanggo/pkg/alert"
+	"kanggo/pkg/blob"
+	"kanggo/pkg/entity/model"
 	userHandler "kanggo/pkg/handler/user"
+	"kanggo/pkg/mail"
+	"kanggo/pkg/ordernumber"
+	"kanggo/pkg/scheduler"
+	"kanggo/pkg/search"
+	"kanggo/pkg/shipping"
 	userStorage "kanggo/pkg/storage/user"
+	"kanggo/pkg/tax"
 	userUsecase "kanggo/pkg/usecase/user"
+	"log"
+	"strings"
+	"time"
 
 	productHandler "kanggo/pkg/handler/product"
 	productStorage "kanggo/pkg/storage/product"
@@ -15,6 +28,45 @@ import (
 	orderStorage "kanggo/pkg/storage/order"
 	orderUsecase "kanggo/pkg/usecase/order"
 
+	addressHandler "kanggo/pkg/handler/address"
+	addressStorage "kanggo/pkg/storage/address"
+	addressUsecase "kanggo/pkg/usecase/address"
+
+	shipmentHandler "kanggo/pkg/handler/shipment"
+	shipmentStorage "kanggo/pkg/storage/shipment"
+	shipmentUsecase "kanggo/pkg/usecase/shipment"
+
+	categoryHandler "kanggo/pkg/handler/category"
+	categoryStorage "kanggo/pkg/storage/category"
+	categoryUsecase "kanggo/pkg/usecase/category"
+
+	couponHandler "kanggo/pkg/handler/coupon"
+	couponStorage "kanggo/pkg/storage/coupon"
+	couponUsecase "kanggo/pkg/usecase/coupon"
+
+	invoiceHandler "kanggo/pkg/handler/invoice"
+	invoiceStorage "kanggo/pkg/storage/invoice"
+	invoiceUsecase "kanggo/pkg/usecase/invoice"
+
+	warehouseHandler "kanggo/pkg/handler/warehouse"
+	warehouseStorage "kanggo/pkg/storage/warehouse"
+	warehouseUsecase "kanggo/pkg/usecase/warehouse"
+
+	inventoryHandler "kanggo/pkg/handler/inventory"
+	inventoryStorage "kanggo/pkg/storage/inventory"
+	inventoryUsecase "kanggo/pkg/usecase/inventory"
+
+	purchaseHandler "kanggo/pkg/handler/purchase"
+	purchaseStorage "kanggo/pkg/storage/purchase"
+	purchaseUsecase "kanggo/pkg/usecase/purchase"
+
+	reviewHandler "kanggo/pkg/handler/review"
+	reviewStorage "kanggo/pkg/storage/review"
+	reviewUsecase "kanggo/pkg/usecase/review"
+
+	shippingHandler "kanggo/pkg/handler/shipping"
+	shippingUsecase "kanggo/pkg/usecase/shipping"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -36,21 +88,200 @@ func main() {
 	userStorage := userStorage.NewUserStorage(config.Native, config.Gorm)
 	productStorage := productStorage.NewProductStorage(config.Native, config.Gorm)
 	orderStorage := orderStorage.NewOrderStorage(config.Native, config.Gorm)
+	addressStorage := addressStorage.NewAddressStorage(config.Native, config.Gorm)
+	shipmentStorage := shipmentStorage.NewShipmentStorage(config.Native, config.Gorm)
+	couponStorage := couponStorage.NewCouponStorage(config.Native, config.Gorm)
+	categoryStorage := categoryStorage.NewCategoryStorage(config.Native, config.Gorm)
+	invoiceStorage := invoiceStorage.NewInvoiceStorage(config.Native, config.Gorm)
+	warehouseStorage := warehouseStorage.NewWarehouseStorage(config.Native, config.Gorm)
+	inventoryStorage := inventoryStorage.NewInventoryStorage(config.Native, config.Gorm)
+	purchaseStorage := purchaseStorage.NewPurchaseStorage(config.Native, config.Gorm)
+	reviewStorage := reviewStorage.NewReviewStorage(config.Native, config.Gorm)
+
+	//shipping
+	rateProviders := shipping.Providers{}
+	if config.EnvFile.ShippingRateFile != "" {
+		table, err := shipping.LoadTableProvider(config.EnvFile.ShippingRateFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		rateProviders = append(rateProviders, table)
+	}
+	if config.EnvFile.CourierUrl != "" {
+		rateProviders = append(rateProviders, shipping.NewCourierProvider(config.EnvFile.CourierName, config.EnvFile.CourierUrl))
+	}
+	origin := shipping.Region{
+		Province: config.EnvFile.ShippingOriginProvince,
+		City:     config.EnvFile.ShippingOriginCity,
+	}
+
+	//tax
+	taxEngine := tax.NewEngine(nil)
+	if config.EnvFile.TaxRuleFile != "" {
+		engine, err := tax.LoadEngine(config.EnvFile.TaxRuleFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		taxEngine = engine
+	}
+
+	//order number
+	numberPattern := ordernumber.DefaultFormat
+	if config.EnvFile.OrderNumberFormat != "" {
+		numberPattern = config.EnvFile.OrderNumberFormat
+	}
+	numberFormat, err := ordernumber.Parse(numberPattern)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	//search
+	searchMode, err := search.ParseMode(config.EnvFile.SearchMode)
+	if err != nil {
+		log.Fatal(err)
+	}
+	searcher := search.NewSQLSearcher(config.Native, searchMode)
+
+	//trash
+	trashDays := config.EnvFile.ProductTrashDays
+	if trashDays <= 0 {
+		trashDays = 30
+	}
+	trashRetention := time.Duration(trashDays) * 24 * time.Hour
+
+	//blob
+	var blobStore blob.BlobStore
+	switch config.EnvFile.BlobStore {
+	case "", "local":
+		dir := config.EnvFile.BlobLocalDir
+		if dir == "" {
+			dir = "uploads"
+		}
+		baseUrl := config.EnvFile.BlobBaseUrl
+		if baseUrl == "" {
+			baseUrl = "/uploads"
+		}
+		blobStore = blob.NewLocalStore(dir, baseUrl)
+		engine.Static("/uploads", dir)
+	case "s3":
+		blobStore = blob.NewS3Store(blob.S3Config{
+			Endpoint:  config.EnvFile.S3Endpoint,
+			Region:    config.EnvFile.S3Region,
+			Bucket:    config.EnvFile.S3Bucket,
+			AccessKey: config.EnvFile.S3AccessKey,
+			SecretKey: config.EnvFile.S3SecretKey,
+			PublicUrl: config.EnvFile.S3PublicUrl,
+		})
+	default:
+		log.Fatalf("unknown blob store %q", config.EnvFile.BlobStore)
+	}
+
+	//stock alerts
+	notifiers := alert.Notifiers{}
+	for _, name := range strings.Split(config.EnvFile.StockAlertNotifiers, ",") {
+		switch strings.TrimSpace(name) {
+		case "":
+		case "log":
+			notifiers = append(notifiers, alert.NewLogNotifier(nil))
+		case "webhook":
+			notifiers = append(notifiers, alert.NewWebhookNotifier(config.EnvFile.StockAlertWebhookUrl))
+		case "email":
+			notifiers = append(notifiers, alert.NewEmailNotifier(alert.EmailConfig{
+				Host:     config.EnvFile.SmtpHost,
+				Port:     config.EnvFile.SmtpPort,
+				Username: config.EnvFile.SmtpUsername,
+				Password: config.EnvFile.SmtpPassword,
+				From:     config.EnvFile.SmtpFrom,
+				To:       strings.Split(config.EnvFile.StockAlertEmailTo, ","),
+			}))
+		default:
+			log.Fatalf("unknown stock alert notifier %q", name)
+		}
+	}
+	if len(notifiers) == 0 {
+		notifiers = append(notifiers, alert.NewLogNotifier(nil))
+	}
+
+	//mail
+	var mailer mail.Mailer = mail.NewLogMailer(nil)
+	if config.EnvFile.SmtpHost != "" {
+		mailer = mail.NewSMTPMailer(mail.SMTPConfig{
+			Host:     config.EnvFile.SmtpHost,
+			Port:     config.EnvFile.SmtpPort,
+			Username: config.EnvFile.SmtpUsername,
+			Password: config.EnvFile.SmtpPassword,
+			From:     config.EnvFile.SmtpFrom,
+		})
+	}
 
 	//usecase
-	userUsecase := userUsecase.NewUserUsecase(userStorage)
-	productUsecase := productUsecase.NewProductUsecase(productStorage)
-	orderUsecase := orderUsecase.NewOrderUsecase(orderStorage, productStorage)
+	userUsecase := userUsecase.NewUserUsecase(userStorage, mailer)
+	productUsecase := productUsecase.NewProductUsecase(productStorage, searcher, categoryStorage, blobStore, trashRetention)
+	orderUsecase := orderUsecase.NewOrderUsecase(orderStorage, productStorage, addressStorage, rateProviders, origin, taxEngine, couponStorage, categoryStorage, numberFormat)
+	addressUsecase := addressUsecase.NewAddressUsecase(addressStorage)
+	shippingUsecase := shippingUsecase.NewShippingUsecase(addressStorage, productStorage, rateProviders, origin)
+	shipmentUsecase := shipmentUsecase.NewShipmentUsecase(shipmentStorage, orderStorage)
+	couponUsecase := couponUsecase.NewCouponUsecase(couponStorage, categoryStorage)
+	categoryUsecase := categoryUsecase.NewCategoryUsecase(categoryStorage)
+	warehouseUsecase := warehouseUsecase.NewWarehouseUsecase(warehouseStorage)
+	inventoryUsecase := inventoryUsecase.NewInventoryUsecase(inventoryStorage, notifiers)
+	purchaseUsecase := purchaseUsecase.NewPurchaseUsecase(purchaseStorage)
+	reviewUsecase := reviewUsecase.NewReviewUsecase(reviewStorage)
+	invoiceUsecase := invoiceUsecase.NewInvoiceUsecase(invoiceStorage, model.InvoiceIssuer{
+		Name:    config.EnvFile.InvoiceIssuerName,
+		Address: config.EnvFile.InvoiceIssuerAddress,
+		TaxId:   config.EnvFile.InvoiceIssuerTaxId,
+	})
+
+	//price scheduler
+	priceInterval := time.Duration(config.EnvFile.PriceScheduleSeconds) * time.Second
+	if priceInterval <= 0 {
+		priceInterval = time.Minute
+	}
+	go scheduler.Every(context.Background(), "apply prices", priceInterval, func(ctx context.Context) error {
+		_, err := productUsecase.ApplyPrices(ctx)
+		return err
+	})
+
+	//stock alert notifier
+	alertInterval := time.Duration(config.EnvFile.StockAlertSeconds) * time.Second
+	if alertInterval <= 0 {
+		alertInterval = time.Minute
+	}
+	go scheduler.Every(context.Background(), "notify low stock", alertInterval, func(ctx context.Context) error {
+		_, err := inventoryUsecase.NotifyLowStock(ctx)
+		return err
+	})
 
 	//handler
 	userHandler := userHandler.NewUserhandler(userUsecase)
 	productHandler := productHandler.NewProductHandler(productUsecase)
 	orderHandler := orderHandler.NewOrderHandler(orderUsecase)
+	addressHandler := addressHandler.NewAddressHandler(addressUsecase)
+	shippingHandler := shippingHandler.NewShippingHandler(shippingUsecase)
+	shipmentHandler := shipmentHandler.NewShipmentHandler(shipmentUsecase, orderUsecase)
+	couponHandler := couponHandler.NewCouponHandler(couponUsecase)
+	invoiceHandler := invoiceHandler.NewInvoiceHandler(invoiceUsecase, orderUsecase)
+	categoryHandler := categoryHandler.NewCategoryHandler(categoryUsecase)
+	warehouseHandler := warehouseHandler.NewWarehouseHandler(warehouseUsecase)
+	inventoryHandler := inventoryHandler.NewInventoryHandler(inventoryUsecase)
+	purchaseHandler := purchaseHandler.NewPurchaseHandler(purchaseUsecase)
+	reviewHandler := reviewHandler.NewReviewHandler(reviewUsecase)
 
 	//router
-	userHandler.Route(engine)
-	productHandler.Route(engine)
-	orderHandler.Route(engine)
+	userHandler.Route(engine, userStorage)
+	productHandler.Route(engine, userStorage)
+	orderHandler.Route(engine, userStorage)
+	addressHandler.Route(engine, userStorage)
+	shippingHandler.Route(engine, userStorage)
+	shipmentHandler.Route(engine, userStorage)
+	couponHandler.Route(engine, userStorage)
+	invoiceHandler.Route(engine, userStorage)
+	categoryHandler.Route(engine, userStorage)
+	warehouseHandler.Route(engine, userStorage)
+	inventoryHandler.Route(engine, userStorage)
+	purchaseHandler.Route(engine, userStorage)
+	reviewHandler.Route(engine, userStorage)
 
 	fmt.Println("Running on port : 8080")
 	engine.Run(config.EnvFile.AppsPort)
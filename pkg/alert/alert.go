@@ -0,0 +1,77 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+//go:generate mockery --name Notifier --case snake --output ../mocks --disable-version-string
+
+type (
+	// Alert tells that a product is down to its reorder level.
+	Alert struct {
+		Id           int64     `json:"id"`
+		ProductId    int64     `json:"product_id"`
+		ProductName  string    `json:"product_name"`
+		Qty          int64     `json:"qty"`
+		ReorderLevel int64     `json:"reorder_level"`
+		RaisedAt     time.Time `json:"raised_at"`
+	}
+
+	// Notifier delivers an alert to the people restocking, by email, a
+	// webhook or just the log.
+	Notifier interface {
+		Notify(ctx context.Context, alert Alert) error
+	}
+
+	// Notifiers delivers an alert through every notifier. A notifier failing
+	// does not keep the alert from the others.
+	Notifiers []Notifier
+
+	logNotifier struct {
+		logger *log.Logger
+	}
+)
+
+func (n Notifiers) Notify(ctx context.Context, alert Alert) error {
+	failed := []string{}
+	for _, notifier := range n {
+		if err := notifier.Notify(ctx, alert); err != nil {
+			failed = append(failed, err.Error())
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("alert %d: %s", alert.Id, strings.Join(failed, "; "))
+	}
+
+	return nil
+}
+
+// NewLogNotifier writes alerts to the logger, or to the standard logger when
+// it is nil.
+func NewLogNotifier(logger *log.Logger) Notifier {
+	if logger == nil {
+		logger = log.Default()
+	}
+
+	return &logNotifier{logger: logger}
+}
+
+func (n *logNotifier) Notify(ctx context.Context, alert Alert) error {
+	n.logger.Print(Message(alert))
+	return nil
+}
+
+// Message is the one-line text of an alert.
+func Message(alert Alert) string {
+	if alert.Qty <= 0 {
+		return fmt.Sprintf("low stock: %s (product %d) is out of stock", alert.ProductName, alert.ProductId)
+	}
+
+	return fmt.Sprintf("low stock: %s (product %d) has %d left, reorder level %d",
+		alert.ProductName, alert.ProductId, alert.Qty, alert.ReorderLevel)
+}
@@ -0,0 +1,107 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var lowStock = Alert{
+	Id:           3,
+	ProductId:    12,
+	ProductName:  "Semen Putih 40kg",
+	Qty:          4,
+	ReorderLevel: 10,
+	RaisedAt:     time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC),
+}
+
+type failingNotifier struct{}
+
+func (failingNotifier) Notify(ctx context.Context, alert Alert) error {
+	return errors.New("mail server down")
+}
+
+func TestNotifiers(t *testing.T) {
+	var buf bytes.Buffer
+	notifiers := Notifiers{failingNotifier{}, NewLogNotifier(log.New(&buf, "", 0))}
+
+	err := notifiers.Notify(context.Background(), lowStock)
+
+	assert.EqualError(t, err, "alert 3: mail server down")
+	assert.Equal(t, "low stock: Semen Putih 40kg (product 12) has 4 left, reorder level 10\n", buf.String())
+}
+
+func TestMessage(t *testing.T) {
+	out := lowStock
+	out.Qty = 0
+
+	assert.Equal(t, "low stock: Semen Putih 40kg (product 12) is out of stock", Message(out))
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		var received struct {
+			Type  string `json:"type"`
+			Alert Alert  `json:"alert"`
+		}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Nil(t, json.NewDecoder(r.Body).Decode(&received))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		err := NewWebhookNotifier(server.URL).Notify(context.Background(), lowStock)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "low_stock", received.Type)
+		assert.Equal(t, lowStock, received.Alert)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		err := NewWebhookNotifier(server.URL).Notify(context.Background(), lowStock)
+
+		assert.EqualError(t, err, "webhook returned status 502")
+	})
+}
+
+func TestEmailNotifier(t *testing.T) {
+	var addr, from string
+	var to []string
+	var msg []byte
+	n := &emailNotifier{
+		config: EmailConfig{Host: "smtp.example.com", Port: "587", From: "stok@kanggo.id", To: []string{"gudang@kanggo.id", "beli@kanggo.id"}},
+		send: func(a string, auth smtp.Auth, f string, t []string, m []byte) error {
+			addr, from, to, msg = a, f, t, m
+			return nil
+		},
+	}
+
+	named := lowStock
+	named.ProductName = "Semen\r\nBcc: someone@example.com"
+	err := n.Notify(context.Background(), named)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "smtp.example.com:587", addr)
+	assert.Equal(t, "stok@kanggo.id", from)
+	assert.Len(t, to, 2)
+	assert.Contains(t, string(msg), "To: gudang@kanggo.id, beli@kanggo.id\r\n")
+	assert.Contains(t, string(msg), "Subject: Low stock: Semen  Bcc: someone@example.com\r\n")
+	headers := strings.SplitN(string(msg), "\r\n\r\n", 2)[0]
+	assert.False(t, strings.Contains(headers, "\r\nBcc:"))
+}
@@ -0,0 +1,53 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+type (
+	EmailConfig struct {
+		Host     string
+		Port     string
+		Username string
+		Password string
+		From     string
+		To       []string
+	}
+
+	emailNotifier struct {
+		config EmailConfig
+		send   func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+	}
+)
+
+// NewEmailNotifier returns a notifier that mails each alert through the SMTP
+// server. The server is logged in to only when a username is set.
+func NewEmailNotifier(config EmailConfig) Notifier {
+	return &emailNotifier{
+		config: config,
+		send:   smtp.SendMail,
+	}
+}
+
+func (n *emailNotifier) Notify(ctx context.Context, alert Alert) error {
+	var auth smtp.Auth
+	if n.config.Username != "" {
+		auth = smtp.PlainAuth("", n.config.Username, n.config.Password, n.config.Host)
+	}
+
+	// the product name ends up in a header, where a line break would start
+	// another one
+	subject := fmt.Sprintf("Low stock: %s", strings.NewReplacer("\r", " ", "\n", " ").Replace(alert.ProductName))
+	body := Message(alert) + "\r\n"
+	msg := "From: " + n.config.From + "\r\n" +
+		"To: " + strings.Join(n.config.To, ", ") + "\r\n" +
+		"Subject: " + subject + "\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"\r\n" + body
+
+	return n.send(net.JoinHostPort(n.config.Host, n.config.Port), auth, n.config.From, n.config.To, []byte(msg))
+}
@@ -0,0 +1,53 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type webhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier returns a notifier that POSTs each alert as JSON to the
+// url. Any status other than 2xx counts as a failed delivery.
+func NewWebhookNotifier(url string) Notifier {
+	return &webhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"type":    "low_stock",
+		"message": Message(alert),
+		"alert":   alert,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("webhook returned status %d", res.StatusCode)
+	}
+
+	return nil
+}
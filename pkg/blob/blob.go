@@ -0,0 +1,30 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strings"
+)
+
+//go:generate mockery --name BlobStore --case snake --output ../mocks --disable-version-string
+
+// BlobStore keeps uploaded files, such as product images, under a key like
+// "products/12/3f2a.jpg" and serves them from a public URL.
+type BlobStore interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// ErrInvalidKey is returned for keys that are empty, absolute or climb out
+// of the store with "..".
+var ErrInvalidKey = errors.New("blob: invalid key")
+
+func checkKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key || strings.HasPrefix(key, "../") || key == ".." {
+		return ErrInvalidKey
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+// Package blobtest provides a fake S3 compatible server for tests and local
+// development.
+package blobtest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// Object is a blob kept by the stub.
+type Object struct {
+	Data        []byte
+	ContentType string
+}
+
+// S3Stub answers PUT, GET and DELETE of objects at /{bucket}/{key} like an
+// S3 service, keeping them in memory. Requests must carry a version 4
+// signature header and a payload hash matching the body; the signature
+// itself is not checked.
+type S3Stub struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	objects map[string]Object
+}
+
+// NewS3Stub starts the stub. Callers must Close it.
+func NewS3Stub() *S3Stub {
+	stub := &S3Stub{objects: map[string]Object{}}
+	stub.Server = httptest.NewServer(http.HandlerFunc(stub.serve))
+
+	return stub
+}
+
+// Object returns the object stored at path, e.g. "/images/products/1/a.jpg".
+func (s *S3Stub) Object(path string) (Object, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	object, ok := s.objects[path]
+	return object, ok
+}
+
+func (s *S3Stub) serve(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	hash := sha256.Sum256(body)
+	if r.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(hash[:]) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		s.objects[r.URL.Path] = Object{Data: body, ContentType: r.Header.Get("Content-Type")}
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet:
+		object, ok := s.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", object.ContentType)
+		w.Write(object.Data)
+	case http.MethodDelete:
+		delete(s.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,71 @@
+package blob
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type localStore struct {
+	dir     string
+	baseUrl string
+}
+
+// NewLocalStore keeps blobs as files under dir. The files are expected to
+// be served at baseUrl, e.g. by gin's Static.
+func NewLocalStore(dir, baseUrl string) BlobStore {
+	return &localStore{
+		dir:     dir,
+		baseUrl: strings.TrimRight(baseUrl, "/"),
+	}
+}
+
+func (s *localStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+
+	file := filepath.Join(s.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+
+	// written aside and renamed so a half written file is never served
+	tmp, err := os.CreateTemp(filepath.Dir(file), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), file)
+}
+
+func (s *localStore) Delete(ctx context.Context, key string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+
+	if err := os.Remove(filepath.Join(s.dir, filepath.FromSlash(key))); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *localStore) URL(key string) string {
+	return s.baseUrl + "/" + key
+}
@@ -0,0 +1,186 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3Config points to a bucket of an S3 compatible service such as AWS S3 or
+// MinIO. Objects are addressed path style, {Endpoint}/{Bucket}/{key}, which
+// every compatible service accepts. PublicUrl, when set, is where the bucket
+// is served to clients, e.g. a CDN.
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PublicUrl string
+}
+
+type s3Store struct {
+	config S3Config
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewS3Store(config S3Config) BlobStore {
+	config.Endpoint = strings.TrimRight(config.Endpoint, "/")
+	config.PublicUrl = strings.TrimRight(config.PublicUrl, "/")
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+
+	return &s3Store{
+		config: config,
+		client: &http.Client{Timeout: 30 * time.Second},
+		now:    time.Now,
+	}
+}
+
+func (s *s3Store) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectUrl(key), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	return s.do(req, data)
+}
+
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectUrl(key), nil)
+	if err != nil {
+		return err
+	}
+
+	return s.do(req, nil)
+}
+
+func (s *s3Store) URL(key string) string {
+	if s.config.PublicUrl != "" {
+		return s.config.PublicUrl + "/" + escapePath(key)
+	}
+
+	return s.objectUrl(key)
+}
+
+func (s *s3Store) objectUrl(key string) string {
+	return s.config.Endpoint + "/" + escapePath(s.config.Bucket) + "/" + escapePath(key)
+}
+
+func (s *s3Store) do(req *http.Request, payload []byte) error {
+	sign(req, s.config.AccessKey, s.config.SecretKey, s.config.Region, payload, s.now())
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	// deleting a missing object is not an error
+	if res.StatusCode/100 != 2 && !(req.Method == http.MethodDelete && res.StatusCode == http.StatusNotFound) {
+		return fmt.Errorf("blob: %s %s returned status %d", req.Method, req.URL.Path, res.StatusCode)
+	}
+
+	return nil
+}
+
+// sign adds an AWS Signature Version 4 to the request, covering the host and
+// every header already set on it.
+func sign(req *http.Request, accessKey, secretKey, region string, payload []byte, now time.Time) {
+	payloadHash := sha256.Sum256(payload)
+	stamp := now.UTC().Format("20060102T150405Z")
+	day := stamp[:8]
+
+	req.Header.Set("X-Amz-Date", stamp)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadHash[:]))
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := day + "/" + region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + stamp + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSha256([]byte("AWS4"+secretKey), day)
+	key = hmacSha256(key, region)
+	key = hmacSha256(key, "s3")
+	key = hmacSha256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSha256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+accessKey+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func hmacSha256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// escapePath encodes every byte of a key except the unreserved characters
+// and "/", as S3 expects in the canonical request.
+func escapePath(key string) string {
+	var b strings.Builder
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' || c == '/' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+
+	return b.String()
+}
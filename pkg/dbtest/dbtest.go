@@ -0,0 +1,240 @@
+// Package dbtest runs storage code against a scripted database. Each
+// statement must match the next expectation, which answers it with rows or
+// a result, so a test can tell what the storage asked of the database.
+package dbtest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"sync"
+	"testing"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type (
+	// Mock is the script of a test database.
+	Mock struct {
+		mu       sync.Mutex
+		expected []*Expectation
+		next     int
+		failure  error
+	}
+
+	// Expectation is one statement the storage is expected to run, Args
+	// being the arguments it ran it with.
+	Expectation struct {
+		pattern *regexp.Regexp
+		columns []string
+		rows    [][]driver.Value
+		result  driver.Result
+		err     error
+
+		Query string
+		Args  []driver.Value
+	}
+)
+
+var (
+	registry = map[string]*Mock{}
+	sequence int
+	lock     sync.Mutex
+)
+
+func init() {
+	sql.Register("dbtest", fakeDriver{})
+}
+
+// New opens a database scripted by the returned mock, both natively and
+// through gorm the way the storages get it.
+func New(t testing.TB) (*sql.DB, *gorm.DB, *Mock) {
+	m := &Mock{}
+
+	lock.Lock()
+	sequence++
+	name := "dbtest-" + strconv.Itoa(sequence)
+	registry[name] = m
+	lock.Unlock()
+
+	native, err := sql.Open("dbtest", name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	native.SetMaxOpenConns(1)
+	t.Cleanup(func() { native.Close() })
+
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: native, SkipInitializeWithVersion: true}),
+		&gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return native, db, m
+}
+
+// Expect adds the statement matching pattern to the script. Begin, Commit
+// and Rollback are expected by those names.
+func (m *Mock) Expect(pattern string) *Expectation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e := &Expectation{pattern: regexp.MustCompile(pattern), result: driver.RowsAffected(1)}
+	m.expected = append(m.expected, e)
+
+	return e
+}
+
+// Rows answers a query with the rows of the columns.
+func (e *Expectation) Rows(columns []string, rows ...[]driver.Value) *Expectation {
+	e.columns = columns
+	e.rows = rows
+	return e
+}
+
+// Result answers a statement with its last insert id and rows affected.
+func (e *Expectation) Result(lastId, affected int64) *Expectation {
+	e.result = result{lastId: lastId, affected: affected}
+	return e
+}
+
+// Error fails the statement.
+func (e *Expectation) Error(err error) *Expectation {
+	e.err = err
+	return e
+}
+
+// Done reports a statement that did not match the script, or an
+// expectation that was never met.
+func (m *Mock) Done() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.failure != nil {
+		return m.failure
+	}
+	if m.next < len(m.expected) {
+		return fmt.Errorf("expected %q, it was never run", m.expected[m.next].pattern)
+	}
+
+	return nil
+}
+
+func (m *Mock) match(query string, args []driver.NamedValue) (*Expectation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.failure != nil {
+		return nil, m.failure
+	}
+	if m.next >= len(m.expected) {
+		m.failure = fmt.Errorf("unexpected %q", query)
+		return nil, m.failure
+	}
+
+	e := m.expected[m.next]
+	if !e.pattern.MatchString(query) {
+		m.failure = fmt.Errorf("expected %q, got %q", e.pattern, query)
+		return nil, m.failure
+	}
+	m.next++
+
+	e.Query = query
+	for _, arg := range args {
+		e.Args = append(e.Args, arg.Value)
+	}
+
+	return e, e.err
+}
+
+type (
+	fakeDriver struct{}
+	conn       struct{ mock *Mock }
+	tx         struct{ mock *Mock }
+	result     struct{ lastId, affected int64 }
+
+	rows struct {
+		columns []string
+		values  [][]driver.Value
+	}
+)
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	m, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown database %q", name)
+	}
+
+	return &conn{mock: m}, nil
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("prepared statements are not supported")
+}
+
+func (c *conn) Close() error { return nil }
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if _, err := c.mock.match("Begin", nil); err != nil {
+		return nil, err
+	}
+
+	return &tx{mock: c.mock}, nil
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	e, err := c.mock.match(query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rows{columns: e.columns, values: e.rows}, nil
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	e, err := c.mock.match(query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.result, nil
+}
+
+func (t *tx) Commit() error {
+	_, err := t.mock.match("Commit", nil)
+	return err
+}
+
+func (t *tx) Rollback() error {
+	_, err := t.mock.match("Rollback", nil)
+	return err
+}
+
+func (r result) LastInsertId() (int64, error) { return r.lastId, nil }
+func (r result) RowsAffected() (int64, error) { return r.affected, nil }
+
+func (r *rows) Columns() []string { return r.columns }
+func (r *rows) Close() error      { return nil }
+
+func (r *rows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package model
+
+type (
+	AddressRequest struct {
+		Recipient  string `json:"recipient" validate:"required"`
+		Phone      string `json:"phone" validate:"required,numeric,min=8,max=20"`
+		Street     string `json:"street" validate:"required"`
+		Province   string `json:"province" validate:"required"`
+		City       string `json:"city" validate:"required"`
+		District   string `json:"district" validate:"required"`
+		PostalCode string `json:"postal_code" validate:"required,numeric,len=5"`
+		IsDefault  bool   `json:"is_default"`
+	}
+
+	AddressResponse struct {
+		Id         int    `json:"id"`
+		Recipient  string `json:"recipient"`
+		Phone      string `json:"phone"`
+		Street     string `json:"street"`
+		Province   string `json:"province"`
+		City       string `json:"city"`
+		District   string `json:"district"`
+		PostalCode string `json:"postal_code"`
+		IsDefault  bool   `json:"is_default"`
+	}
+
+	ShippingAddress struct {
+		Recipient  string `json:"recipient"`
+		Phone      string `json:"phone"`
+		Street     string `json:"street"`
+		Province   string `json:"province"`
+		City       string `json:"city"`
+		District   string `json:"district"`
+		PostalCode string `json:"postal_code"`
+	}
+)
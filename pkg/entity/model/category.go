@@ -0,0 +1,25 @@
+package model
+
+type (
+	// CategoryRequest creates or moves a category. An empty slug is derived
+	// from the name.
+	CategoryRequest struct {
+		ParentId  *int64 `json:"parent_id"`
+		Name      string `json:"name" validate:"required,max=100"`
+		Slug      string `json:"slug" validate:"max=120"`
+		SortOrder int64  `json:"sort_order"`
+	}
+
+	CategoryResponse struct {
+		Id        int                `json:"id"`
+		ParentId  *int64             `json:"parent_id"`
+		Name      string             `json:"name"`
+		Slug      string             `json:"slug"`
+		SortOrder int64              `json:"sort_order"`
+		Children  []CategoryResponse `json:"children,omitempty"`
+	}
+
+	ProductCategoryRequest struct {
+		CategoryIds []int64 `json:"category_ids"`
+	}
+)
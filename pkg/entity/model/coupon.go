@@ -0,0 +1,51 @@
+package model
+
+import "time"
+
+type (
+	CouponRequest struct {
+		Code         string     `json:"code" validate:"required,max=50"`
+		Description  string     `json:"description"`
+		DiscountType string     `json:"discount_type" validate:"required,oneof=percentage fixed"`
+		Value        float64    `json:"value" validate:"required,gt=0"`
+		MaxDiscount  float64    `json:"max_discount" validate:"min=0"`
+		MinSpend     float64    `json:"min_spend" validate:"min=0"`
+		UsageLimit   int64      `json:"usage_limit" validate:"min=0"`
+		PerUserLimit int64      `json:"per_user_limit" validate:"min=0"`
+		StartsAt     time.Time  `json:"starts_at" validate:"required"`
+		EndsAt       *time.Time `json:"ends_at"`
+		Active       bool       `json:"active"`
+		ProductIds   []int64    `json:"product_ids"`
+		CategoryIds  []int64    `json:"category_ids"`
+	}
+
+	CouponResponse struct {
+		Id           int        `json:"id"`
+		Code         string     `json:"code"`
+		Description  string     `json:"description"`
+		DiscountType string     `json:"discount_type"`
+		Value        float64    `json:"value"`
+		MaxDiscount  float64    `json:"max_discount"`
+		MinSpend     float64    `json:"min_spend"`
+		UsageLimit   int64      `json:"usage_limit"`
+		PerUserLimit int64      `json:"per_user_limit"`
+		UsedCount    int64      `json:"used_count"`
+		StartsAt     time.Time  `json:"starts_at"`
+		EndsAt       *time.Time `json:"ends_at"`
+		Active       bool       `json:"active"`
+		ProductIds   []int64    `json:"product_ids"`
+		CategoryIds  []int64    `json:"category_ids"`
+	}
+
+	ApplyCouponRequest struct {
+		Code      string  `json:"code" validate:"required"`
+		ProductId int64   `json:"product_id" validate:"required"`
+		Amount    float64 `json:"amount" validate:"required,gt=0"`
+	}
+
+	ApplyCouponResponse struct {
+		Code     string  `json:"code"`
+		Discount float64 `json:"discount"`
+		Amount   float64 `json:"amount"`
+	}
+)
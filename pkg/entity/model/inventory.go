@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// LowStockResponse is a product at or below its reorder level. AlertedAt is
+// when the open alert of the product was raised, if there is one.
+type LowStockResponse struct {
+	ProductId    int64      `json:"product_id"`
+	Name         string     `json:"name"`
+	Status       string     `json:"status"`
+	Qty          int64      `json:"qty"`
+	ReorderLevel int64      `json:"reorder_level"`
+	AlertedAt    *time.Time `json:"alerted_at"`
+}
@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+type (
+	InvoiceLine struct {
+		ProductId   int64   `json:"product_id"`
+		Description string  `json:"description"`
+		Quantity    int64   `json:"quantity"`
+		UnitPrice   float64 `json:"unit_price"`
+		Amount      float64 `json:"amount"`
+	}
+
+	InvoiceResponse struct {
+		Id             int64           `json:"id"`
+		Number         string          `json:"number"`
+		OrderId        int64           `json:"order_id"`
+		OrderNumber    string          `json:"order_number"`
+		UserId         int64           `json:"user_id"`
+		BuyerName      string          `json:"buyer_name"`
+		BuyerEmail     string          `json:"buyer_email"`
+		Shipping       ShippingAddress `json:"shipping_address"`
+		Courier        string          `json:"courier"`
+		Service        string          `json:"service"`
+		Lines          []InvoiceLine   `json:"lines"`
+		Subtotal       float64         `json:"subtotal"`
+		DiscountAmount float64         `json:"discount_amount"`
+		NetAmount      float64         `json:"net_amount"`
+		TaxRate        float64         `json:"tax_rate"`
+		TaxAmount      float64         `json:"tax_amount"`
+		TaxInclusive   bool            `json:"tax_inclusive"`
+		ShippingCost   float64         `json:"shipping_cost"`
+		Total          float64         `json:"total"`
+		IssuedAt       time.Time       `json:"issued_at"`
+	}
+
+	// InvoiceIssuer is the seller printed on the invoice header.
+	InvoiceIssuer struct {
+		Name    string
+		Address string
+		TaxId   string
+	}
+)
@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+type (
+	// ListQuery is the paging, sorting and filtering of a list endpoint. Sort
+	// is one of the keys the endpoint allows and is mapped to a column by the
+	// storage. With a Cursor the list continues after that id instead of
+	// using Page. To is exclusive. Category is a category slug, which the
+	// usecase resolves to the ids of the category and its subcategories.
+	ListQuery struct {
+		Page     int
+		Size     int
+		Cursor   int64
+		Sort     string
+		Desc     bool
+		Status   string
+		From     *time.Time
+		To       *time.Time
+		MinPrice *float64
+		MaxPrice *float64
+
+		Category    string
+		CategoryIds []int64
+	}
+)
+
+func (q ListQuery) Offset() int {
+	if q.Cursor > 0 {
+		return 0
+	}
+
+	return (q.Page - 1) * q.Size
+}
@@ -1,26 +1,81 @@
 package model
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type (
+	// OrderRequest places an order. The amount is worked out from the price
+	// in effect at order time; a client may send the amount it showed the
+	// customer, which then has to match.
 	OrderRequest struct {
-		UserId    int64   `json:"user_id" validate:"required"`
-		ProductId int64   `json:"product_id" validate:"required"`
-		Amount    float64 `json:"amount" validate:"required"`
-		Quantity  int64   `json:"quantity" validate:"required"`
+		UserId     int64   `json:"user_id" validate:"required"`
+		ProductId  int64   `json:"product_id" validate:"required"`
+		VariantId  int64   `json:"variant_id"`
+		Amount     float64 `json:"amount"`
+		Quantity   int64   `json:"quantity" validate:"required"`
+		AddressId  int64   `json:"address_id" validate:"required"`
+		Courier    string  `json:"courier" validate:"required"`
+		Service    string  `json:"service" validate:"required"`
+		CouponCode string  `json:"coupon_code"`
 	}
 
 	OrderResponse struct {
-		OrderId     int64   `json:"order_id"`
-		UserId      int64   `json:"user_id"`
-		UserName    string  `json:"user_name"`
-		ProductId   int64   `json:"product_id"`
-		ProductName string  `json:"product_name"`
-		Amount      float64 `json:"amount" `
-		Status      string  `json:"status"`
+		OrderId        int64           `json:"order_id"`
+		OrderNumber    string          `json:"order_number"`
+		UserId         int64           `json:"user_id"`
+		UserName       string          `json:"user_name"`
+		ProductId      int64           `json:"product_id"`
+		ProductName    string          `json:"product_name"`
+		VariantId      int64           `json:"variant_id"`
+		Sku            string          `json:"sku"`
+		Amount         float64         `json:"amount" `
+		Status         string          `json:"status"`
+		Shipping       ShippingAddress `json:"shipping_address"`
+		Courier        string          `json:"courier"`
+		Service        string          `json:"service"`
+		ShippingCost   float64         `json:"shipping_cost"`
+		NetAmount      float64         `json:"net_amount"`
+		TaxRate        float64         `json:"tax_rate"`
+		TaxAmount      float64         `json:"tax_amount"`
+		TaxInclusive   bool            `json:"tax_inclusive"`
+		DiscountAmount float64         `json:"discount_amount"`
+		WarehouseId    int64           `json:"warehouse_id"`
+		Version        uint            `json:"version"`
+	}
+
+	TaxReport struct {
+		TaxRate     float64 `json:"tax_rate"`
+		TotalOrders int64   `json:"total_orders"`
+		NetAmount   float64 `json:"net_amount"`
+		TaxAmount   float64 `json:"tax_amount"`
+		GrossAmount float64 `json:"gross_amount"`
+	}
+
+	CancelOrderRequest struct {
+		Reason string `json:"reason" validate:"max=255"`
+
+		// Version is the version of the order the client read, from the
+		// If-Match header
+		Version uint `json:"-"`
+	}
+
+	OrderEventResponse struct {
+		Id         int64           `json:"id"`
+		Type       string          `json:"type"`
+		FromStatus string          `json:"from_status"`
+		ToStatus   string          `json:"to_status"`
+		ActorId    int64           `json:"actor_id"`
+		ActorRole  string          `json:"actor_role"`
+		Payload    json.RawMessage `json:"payload"`
+		CreatedAt  time.Time       `json:"created_at"`
 	}
 
 	PaymentRequest struct {
 		UserId    int64   `json:"user_id" validate:"required"`
 		ProductId int64   `json:"product_id" validate:"required"`
+		VariantId int64   `json:"variant_id"`
 		Amount    float64 `json:"amount" validate:"required"`
 	}
 )
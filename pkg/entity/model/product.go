@@ -2,16 +2,124 @@ package model
 
 type (
 	ProductRequest struct {
-		Name  string  `json:"name" validate:"required"`
-		Price float64 `json:"price" validate:"required"`
-		Qty   int     `json:"qty" validate:"required"`
+		Name  string  `json:"name" validate:"required,max=255"`
+		Price float64 `json:"price" validate:"required,gt=0"`
+		Qty   int     `json:"qty" validate:"min=0"`
+
+		Description string `json:"description"`
+		Sku         string `json:"sku" validate:"max=64"`
+
+		Weight      int64  `json:"weight" validate:"min=0"`
+		Length      int64  `json:"length" validate:"min=0"`
+		Width       int64  `json:"width" validate:"min=0"`
+		Height      int64  `json:"height" validate:"min=0"`
+		TaxCategory string `json:"tax_category"`
+
+		Unit        string `json:"unit" validate:"max=20"`
+		Brand       string `json:"brand" validate:"max=100"`
+		Barcode     string `json:"barcode" validate:"omitempty,numeric,min=8,max=14"`
+		MinOrderQty int64  `json:"min_order_qty" validate:"min=0"`
+		Status      string `json:"status" validate:"omitempty,oneof=active draft archived"`
+
+		ReorderLevel int64 `json:"reorder_level" validate:"min=0"`
+
+		// Version is the version of the product the client read, from the
+		// If-Match header
+		Version uint `json:"-"`
+	}
+
+	// ProductPatchRequest is a JSON merge patch of a product. Only the members
+	// present in the patch change; Null lists the members sent as null, which
+	// reset the field to its default.
+	ProductPatchRequest struct {
+		Name        *string  `json:"name" validate:"omitempty,min=1,max=255"`
+		Description *string  `json:"description"`
+		Price       *float64 `json:"price" validate:"omitempty,gt=0"`
+		Qty         *int     `json:"qty" validate:"omitempty,min=0"`
+
+		Weight      *int64  `json:"weight" validate:"omitempty,min=0"`
+		Length      *int64  `json:"length" validate:"omitempty,min=0"`
+		Width       *int64  `json:"width" validate:"omitempty,min=0"`
+		Height      *int64  `json:"height" validate:"omitempty,min=0"`
+		TaxCategory *string `json:"tax_category" validate:"omitempty,min=1,max=50"`
+
+		Unit        *string `json:"unit" validate:"omitempty,min=1,max=20"`
+		Brand       *string `json:"brand" validate:"omitempty,max=100"`
+		Barcode     *string `json:"barcode" validate:"omitempty,numeric,min=8,max=14"`
+		MinOrderQty *int64  `json:"min_order_qty" validate:"omitempty,min=1"`
+		Status      *string `json:"status" validate:"omitempty,oneof=active draft archived"`
+
+		ReorderLevel *int64 `json:"reorder_level" validate:"omitempty,min=0"`
+
+		Null    []string `json:"-"`
+		Version uint     `json:"-"`
 	}
 
 	ProductResponse struct {
-		Id        int     `json:"id"`
-		Name      string  `json:"name"`
-		Price     float64 `json:"price"`
-		Qty       int     `json:"qty"`
-		CreatedAt string  `json:"created_at,omitempty"`
+		Id           int     `json:"id"`
+		Name         string  `json:"name"`
+		Description  string  `json:"description"`
+		Price        float64 `json:"price"`
+		Qty          int     `json:"qty"`
+		Weight       int64   `json:"weight"`
+		Length       int64   `json:"length"`
+		Width        int64   `json:"width"`
+		Height       int64   `json:"height"`
+		TaxCategory  string  `json:"tax_category"`
+		Unit         string  `json:"unit"`
+		Brand        string  `json:"brand"`
+		Barcode      string  `json:"barcode"`
+		MinOrderQty  int64   `json:"min_order_qty"`
+		ReorderLevel int64   `json:"reorder_level"`
+		Rating       float64 `json:"rating"`
+		RatingCount  int64   `json:"rating_count"`
+		Status       string  `json:"status"`
+		Version      uint    `json:"version"`
+		CreatedAt    string  `json:"created_at,omitempty"`
+		DeletedAt    string  `json:"deleted_at,omitempty"`
+
+		Images   []ProductImageResponse   `json:"images"`
+		Options  []ProductOptionResponse  `json:"options,omitempty"`
+		Variants []ProductVariantResponse `json:"variants,omitempty"`
+
+		// Stock is the part of Qty each warehouse holds
+		Stock []WarehouseStockResponse `json:"stock,omitempty"`
+	}
+
+	ProductPurgeResponse struct {
+		Purged int64 `json:"purged"`
+	}
+
+	ProductSearchHit struct {
+		ProductResponse
+		Score      float64           `json:"score"`
+		Highlights map[string]string `json:"highlights,omitempty"`
+	}
+
+	// PriceBandFacet counts the hits priced from Min up to, not including,
+	// Max. The last band has no Max.
+	PriceBandFacet struct {
+		Min   float64  `json:"min"`
+		Max   *float64 `json:"max"`
+		Count int64    `json:"count"`
+	}
+
+	CategoryFacet struct {
+		Id    int64  `json:"id"`
+		Name  string `json:"name"`
+		Slug  string `json:"slug"`
+		Count int64  `json:"count"`
+	}
+
+	ProductFacets struct {
+		Categories []CategoryFacet  `json:"categories"`
+		PriceBands []PriceBandFacet `json:"price_bands"`
+		InStock    int64            `json:"in_stock"`
+		OutOfStock int64            `json:"out_of_stock"`
+	}
+
+	ProductSearchResponse struct {
+		Hits   []ProductSearchHit `json:"hits"`
+		Facets ProductFacets      `json:"facets"`
 	}
 )
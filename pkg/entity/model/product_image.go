@@ -0,0 +1,20 @@
+package model
+
+type (
+	ProductImageResponse struct {
+		Id           int64  `json:"id"`
+		Url          string `json:"url"`
+		ThumbnailUrl string `json:"thumbnail_url"`
+		ContentType  string `json:"content_type"`
+		Size         int64  `json:"size"`
+		Width        int64  `json:"width"`
+		Height       int64  `json:"height"`
+		Position     int64  `json:"position"`
+	}
+
+	// ProductImageOrderRequest lists every image of a product in the order
+	// they should be shown.
+	ProductImageOrderRequest struct {
+		ImageIds []int64 `json:"image_ids" validate:"required"`
+	}
+)
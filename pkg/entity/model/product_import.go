@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+type (
+	// ProductImportRequest holds the form fields sent along with an import
+	// file. Mapping is a JSON object from product column to the header used
+	// in the file, e.g. {"name": "Nama Barang"}; columns left out are looked
+	// up by their own name.
+	ProductImportRequest struct {
+		DryRun  bool   `form:"dry_run"`
+		Async   bool   `form:"async"`
+		Mapping string `form:"mapping"`
+	}
+
+	// ProductImportError lists what is wrong with a row; Line is the line of
+	// the row in the file, the header being line 1.
+	ProductImportError struct {
+		Line   int      `json:"line"`
+		Sku    string   `json:"sku,omitempty"`
+		Errors []string `json:"errors"`
+	}
+
+	ProductImportJobResponse struct {
+		Id         int64                `json:"id"`
+		Filename   string               `json:"filename"`
+		DryRun     bool                 `json:"dry_run"`
+		Status     string               `json:"status"`
+		Total      int64                `json:"total"`
+		Created    int64                `json:"created"`
+		Updated    int64                `json:"updated"`
+		Failed     int64                `json:"failed"`
+		Errors     []ProductImportError `json:"errors"`
+		Message    string               `json:"message,omitempty"`
+		CreatedAt  time.Time            `json:"created_at"`
+		FinishedAt *time.Time           `json:"finished_at"`
+	}
+)
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+type (
+	// ProductPriceRequest schedules a price of a variant. Without
+	// effective_from the price takes effect right away. A sale price needs
+	// effective_to, after which the regular price is back. The variant may be
+	// left out for a product sold in a single variant.
+	ProductPriceRequest struct {
+		VariantId     int64      `json:"variant_id"`
+		Price         float64    `json:"price" validate:"required,gt=0"`
+		Sale          bool       `json:"sale"`
+		EffectiveFrom time.Time  `json:"effective_from"`
+		EffectiveTo   *time.Time `json:"effective_to"`
+	}
+
+	ProductPriceResponse struct {
+		Id            int64      `json:"id"`
+		VariantId     int64      `json:"variant_id"`
+		Price         float64    `json:"price"`
+		Sale          bool       `json:"sale"`
+		EffectiveFrom time.Time  `json:"effective_from"`
+		EffectiveTo   *time.Time `json:"effective_to"`
+		Scheduled     bool       `json:"scheduled"`
+		CreatedAt     time.Time  `json:"created_at"`
+	}
+)
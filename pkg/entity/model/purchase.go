@@ -0,0 +1,78 @@
+package model
+
+import "time"
+
+type (
+	// SupplierRequest adds or changes a supplier. Active defaults to true.
+	SupplierRequest struct {
+		Name    string `json:"name" validate:"required,max=255"`
+		Email   string `json:"email" validate:"omitempty,email,max=255"`
+		Phone   string `json:"phone" validate:"omitempty,numeric,max=20"`
+		Address string `json:"address" validate:"max=255"`
+		Active  *bool  `json:"active"`
+	}
+
+	SupplierResponse struct {
+		Id      int64  `json:"id"`
+		Name    string `json:"name"`
+		Email   string `json:"email"`
+		Phone   string `json:"phone"`
+		Address string `json:"address"`
+		Active  bool   `json:"active"`
+	}
+
+	// PurchaseOrderRequest drafts a purchase order. Without a warehouse the
+	// goods are received into the default one.
+	PurchaseOrderRequest struct {
+		SupplierId  int64                      `json:"supplier_id" validate:"required"`
+		WarehouseId int64                      `json:"warehouse_id"`
+		Note        string                     `json:"note" validate:"max=255"`
+		Lines       []PurchaseOrderLineRequest `json:"lines" validate:"required,min=1,dive"`
+	}
+
+	PurchaseOrderLineRequest struct {
+		VariantId int64   `json:"variant_id" validate:"required"`
+		Qty       int64   `json:"qty" validate:"required,gt=0"`
+		UnitCost  float64 `json:"unit_cost" validate:"min=0"`
+	}
+
+	PurchaseOrderResponse struct {
+		Id           int64      `json:"id"`
+		Number       string     `json:"number"`
+		SupplierId   int64      `json:"supplier_id"`
+		SupplierName string     `json:"supplier_name"`
+		WarehouseId  int64      `json:"warehouse_id"`
+		Status       string     `json:"status"`
+		Total        float64    `json:"total"`
+		Note         string     `json:"note"`
+		Version      uint       `json:"version"`
+		CreatedAt    time.Time  `json:"created_at"`
+		SentAt       *time.Time `json:"sent_at"`
+		ReceivedAt   *time.Time `json:"received_at"`
+
+		Lines []PurchaseOrderLineResponse `json:"lines,omitempty"`
+	}
+
+	PurchaseOrderLineResponse struct {
+		Id          int64   `json:"id"`
+		VariantId   int64   `json:"variant_id"`
+		ProductId   int64   `json:"product_id"`
+		ProductName string  `json:"product_name"`
+		Sku         string  `json:"sku"`
+		Qty         int64   `json:"qty"`
+		ReceivedQty int64   `json:"received_qty"`
+		UnitCost    float64 `json:"unit_cost"`
+	}
+
+	// ReceiveRequest records the goods of a delivery against the lines of a
+	// purchase order.
+	ReceiveRequest struct {
+		Lines []ReceiveLineRequest `json:"lines" validate:"required,min=1,dive"`
+		Note  string               `json:"note" validate:"max=255"`
+	}
+
+	ReceiveLineRequest struct {
+		LineId int64 `json:"line_id" validate:"required"`
+		Qty    int64 `json:"qty" validate:"required,gt=0"`
+	}
+)
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+type (
+	ReviewRequest struct {
+		Rating int64  `json:"rating" validate:"required,min=1,max=5"`
+		Text   string `json:"text" validate:"max=2000"`
+	}
+
+	ReviewResponse struct {
+		Id          int64      `json:"id"`
+		ProductId   int64      `json:"product_id"`
+		UserId      int64      `json:"user_id"`
+		UserName    string     `json:"user_name"`
+		Rating      int64      `json:"rating"`
+		Text        string     `json:"text"`
+		Status      string     `json:"status"`
+		CreatedAt   time.Time  `json:"created_at"`
+		ModeratedAt *time.Time `json:"moderated_at,omitempty"`
+	}
+)
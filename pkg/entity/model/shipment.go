@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+type (
+	ShipmentRequest struct {
+		Courier        string `json:"courier" validate:"required"`
+		Service        string `json:"service"`
+		TrackingNumber string `json:"tracking_number" validate:"required"`
+	}
+
+	ShipmentEventRequest struct {
+		Status      string     `json:"status" validate:"required,oneof=picked_up in_transit out_for_delivery delivered failed returned"`
+		Location    string     `json:"location"`
+		Description string     `json:"description"`
+		OccurredAt  *time.Time `json:"occurred_at"`
+	}
+
+	TrackingEvent struct {
+		Status      string    `json:"status"`
+		Location    string    `json:"location"`
+		Description string    `json:"description"`
+		OccurredAt  time.Time `json:"occurred_at"`
+	}
+
+	TrackingResponse struct {
+		OrderId        int64           `json:"order_id"`
+		OrderNumber    string          `json:"order_number"`
+		OrderStatus    string          `json:"order_status"`
+		ShipmentId     int64           `json:"shipment_id"`
+		Courier        string          `json:"courier"`
+		Service        string          `json:"service"`
+		TrackingNumber string          `json:"tracking_number"`
+		ShippedAt      *time.Time      `json:"shipped_at"`
+		DeliveredAt    *time.Time      `json:"delivered_at"`
+		Events         []TrackingEvent `json:"events"`
+	}
+)
@@ -0,0 +1,21 @@
+package model
+
+type (
+	ShippingItem struct {
+		ProductId int64 `json:"product_id" validate:"required"`
+		VariantId int64 `json:"variant_id"`
+		Quantity  int64 `json:"quantity" validate:"required,min=1"`
+	}
+
+	ShippingQuoteRequest struct {
+		AddressId int64          `json:"address_id" validate:"required"`
+		Items     []ShippingItem `json:"items" validate:"required,min=1,dive"`
+	}
+
+	ShippingOption struct {
+		Courier string  `json:"courier"`
+		Service string  `json:"service"`
+		Cost    float64 `json:"cost"`
+		Etd     string  `json:"etd"`
+	}
+)
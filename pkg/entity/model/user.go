@@ -15,6 +15,8 @@ type (
 	LoginResponse struct {
 		Token   string `json:"token"`
 		Expired int64  `json:"expired"`
+
+		PasswordResetRequired bool `json:"password_reset_required,omitempty"`
 	}
 
 	UserResponse struct {
@@ -22,6 +24,54 @@ type (
 		Name     string `json:"name"`
 		Email    string `json:"email,omitempty"`
 		Password string `json:"password,omitempty"`
+		Role     string `json:"role,omitempty"`
+		Status   string `json:"status,omitempty"`
+
+		PasswordResetRequired bool   `json:"password_reset_required,omitempty"`
+		PendingEmail          string `json:"pending_email,omitempty"`
+	}
+
+	UserListResponse struct {
+		Users []UserResponse `json:"users"`
+		Page  int            `json:"page"`
+		Size  int            `json:"size"`
+		Total int64          `json:"total"`
+	}
+
+	OrderSummary struct {
+		TotalOrders   int64   `json:"total_orders"`
+		PaidOrders    int64   `json:"paid_orders"`
+		PendingOrders int64   `json:"pending_orders"`
+		TotalAmount   float64 `json:"total_amount"`
+		PaidAmount    float64 `json:"paid_amount"`
+	}
+
+	UserDetailResponse struct {
+		UserResponse
+		OrderSummary OrderSummary `json:"order_summary"`
+	}
+
+	UpdateRoleRequest struct {
+		Role string `json:"role" validate:"required,oneof=admin user"`
+	}
+
+	UpdateProfileRequest struct {
+		Name     string `json:"name" validate:"omitempty"`
+		Email    string `json:"email" validate:"omitempty,email"`
+		Password string `json:"password" validate:"omitempty,min=8"`
+	}
+
+	ChangePasswordRequest struct {
+		CurrentPassword string `json:"current_password" validate:"required,min=8"`
+		NewPassword     string `json:"new_password" validate:"required,min=8,nefield=CurrentPassword"`
+	}
+
+	VerifyEmailRequest struct {
+		Token string `json:"token" validate:"required"`
+	}
+
+	DeleteAccountRequest struct {
+		Password string `json:"password" validate:"required,min=8"`
 	}
 
 	ValidateResponse struct {
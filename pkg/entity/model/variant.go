@@ -0,0 +1,39 @@
+package model
+
+type (
+	ProductOptionRequest struct {
+		Name   string   `json:"name" validate:"required,max=50"`
+		Values []string `json:"values" validate:"required,min=1,dive,required,max=50"`
+	}
+
+	ProductOptionsRequest struct {
+		Options []ProductOptionRequest `json:"options" validate:"dive"`
+	}
+
+	ProductOptionResponse struct {
+		Name   string   `json:"name"`
+		Values []string `json:"values"`
+	}
+
+	// ProductVariantRequest picks one value for some or all of the product's
+	// options, e.g. {"Berat": "50kg"}.
+	ProductVariantRequest struct {
+		Sku     string            `json:"sku" validate:"required,max=64"`
+		Options map[string]string `json:"options"`
+		Price   float64           `json:"price" validate:"required,gt=0"`
+		Qty     int64             `json:"qty" validate:"min=0"`
+		Weight  int64             `json:"weight" validate:"min=0"`
+	}
+
+	ProductVariantResponse struct {
+		Id        int64             `json:"id"`
+		ProductId int64             `json:"product_id"`
+		Sku       string            `json:"sku"`
+		Options   map[string]string `json:"options"`
+		Price     float64           `json:"price"`
+		Qty       int64             `json:"qty"`
+		Weight    int64             `json:"weight"`
+
+		Stock []WarehouseStockResponse `json:"stock,omitempty"`
+	}
+)
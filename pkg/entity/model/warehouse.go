@@ -0,0 +1,87 @@
+package model
+
+import "time"
+
+type (
+	// WarehouseRequest adds or changes a warehouse. Active defaults to true.
+	WarehouseRequest struct {
+		Code       string `json:"code" validate:"required,max=20"`
+		Name       string `json:"name" validate:"required,max=255"`
+		Street     string `json:"street" validate:"max=255"`
+		Province   string `json:"province" validate:"required,max=100"`
+		City       string `json:"city" validate:"required,max=100"`
+		PostalCode string `json:"postal_code" validate:"omitempty,numeric,len=5"`
+		IsDefault  bool   `json:"is_default"`
+		Active     *bool  `json:"active"`
+	}
+
+	WarehouseResponse struct {
+		Id         int64  `json:"id"`
+		Code       string `json:"code"`
+		Name       string `json:"name"`
+		Street     string `json:"street"`
+		Province   string `json:"province"`
+		City       string `json:"city"`
+		PostalCode string `json:"postal_code"`
+		IsDefault  bool   `json:"is_default"`
+		Active     bool   `json:"active"`
+	}
+
+	// StockRequest sets the stock of a variant in a warehouse, after a count
+	// or a delivery from a supplier.
+	StockRequest struct {
+		VariantId int64  `json:"variant_id" validate:"required"`
+		Qty       int64  `json:"qty" validate:"min=0"`
+		Note      string `json:"note" validate:"max=255"`
+	}
+
+	StockTransferRequest struct {
+		FromWarehouseId int64  `json:"from_warehouse_id" validate:"required"`
+		ToWarehouseId   int64  `json:"to_warehouse_id" validate:"required,nefield=FromWarehouseId"`
+		VariantId       int64  `json:"variant_id" validate:"required"`
+		Qty             int64  `json:"qty" validate:"required,gt=0"`
+		Note            string `json:"note" validate:"max=255"`
+	}
+
+	// StockTransferResponse carries the reference shared by the two
+	// movements of the transfer.
+	StockTransferResponse struct {
+		Reference       string `json:"reference"`
+		FromWarehouseId int64  `json:"from_warehouse_id"`
+		ToWarehouseId   int64  `json:"to_warehouse_id"`
+		VariantId       int64  `json:"variant_id"`
+		Qty             int64  `json:"qty"`
+	}
+
+	// WarehouseStockResponse is the stock held in one warehouse, of a
+	// product or a variant.
+	WarehouseStockResponse struct {
+		WarehouseId   int64  `json:"warehouse_id"`
+		WarehouseCode string `json:"warehouse_code"`
+		WarehouseName string `json:"warehouse_name"`
+		Qty           int64  `json:"qty"`
+	}
+
+	// StockLevelResponse is the stock of a variant in the warehouse listed.
+	StockLevelResponse struct {
+		VariantId   int64  `json:"variant_id"`
+		ProductId   int64  `json:"product_id"`
+		ProductName string `json:"product_name"`
+		Sku         string `json:"sku"`
+		Qty         int64  `json:"qty"`
+	}
+
+	StockMovementResponse struct {
+		Id          int64     `json:"id"`
+		WarehouseId int64     `json:"warehouse_id"`
+		VariantId   int64     `json:"variant_id"`
+		ProductId   int64     `json:"product_id"`
+		Qty         int64     `json:"qty"`
+		Type        string    `json:"type"`
+		Reference   string    `json:"reference,omitempty"`
+		OrderId     int64     `json:"order_id,omitempty"`
+		ActorId     int64     `json:"actor_id"`
+		Note        string    `json:"note,omitempty"`
+		CreatedAt   time.Time `json:"created_at"`
+	}
+)
@@ -0,0 +1,30 @@
+package schema
+
+type Address struct {
+	Base
+	UserId     int64  `gorm:"not null;index"`
+	Recipient  string `gorm:"type:varchar(255);not null"`
+	Phone      string `gorm:"type:varchar(20);not null"`
+	Street     string `gorm:"type:varchar(255);not null"`
+	Province   string `gorm:"type:varchar(100);not null"`
+	City       string `gorm:"type:varchar(100);not null"`
+	District   string `gorm:"type:varchar(100);not null"`
+	PostalCode string `gorm:"type:varchar(10);not null"`
+	IsDefault  bool   `gorm:"not null;default:false"`
+}
+
+func (Address) TableName() string {
+	return "addresses"
+}
+
+// ShippingAddress is the copy of an Address stored on an order, so editing or
+// deleting the address later does not change the order history.
+type ShippingAddress struct {
+	Recipient  string `gorm:"type:varchar(255)"`
+	Phone      string `gorm:"type:varchar(20)"`
+	Street     string `gorm:"type:varchar(255)"`
+	Province   string `gorm:"type:varchar(100)"`
+	City       string `gorm:"type:varchar(100)"`
+	District   string `gorm:"type:varchar(100)"`
+	PostalCode string `gorm:"type:varchar(10)"`
+}
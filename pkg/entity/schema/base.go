@@ -8,4 +8,8 @@ type Base struct {
 	Id        uint      `gorm:"primarykey"`
 	CreatedAt time.Time `gorm:"type:datetime;not null;default:CURRENT_TIMESTAMP"`
 	UpdatedAt time.Time `gorm:"type:datetime;not null;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" `
+
+	// Version counts the changes of the row, so a client can make its
+	// change conditional on the version it last read.
+	Version uint `gorm:"not null;default:1"`
 }
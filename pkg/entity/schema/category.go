@@ -0,0 +1,24 @@
+package schema
+
+// Category is a node of the product category tree. Root categories have no
+// parent.
+type Category struct {
+	Base
+	ParentId  *int64 `gorm:"index"`
+	Name      string `gorm:"type:varchar(100);not null"`
+	Slug      string `gorm:"type:varchar(120);not null;uniqueIndex"`
+	SortOrder int64  `gorm:"not null;default:0"`
+}
+
+func (Category) TableName() string {
+	return "categories"
+}
+
+type ProductCategory struct {
+	ProductId  int64 `gorm:"primaryKey;autoIncrement:false"`
+	CategoryId int64 `gorm:"primaryKey;autoIncrement:false;index"`
+}
+
+func (ProductCategory) TableName() string {
+	return "product_categories"
+}
@@ -0,0 +1,57 @@
+package schema
+
+import "time"
+
+type Coupon struct {
+	Base
+	Code         string    `gorm:"type:varchar(50);not null;uniqueIndex"`
+	Description  string    `gorm:"type:varchar(255)"`
+	DiscountType string    `gorm:"type:varchar(10);not null"`
+	Value        float64   `gorm:"not null"`
+	MaxDiscount  float64   `gorm:"not null;default:0"`
+	MinSpend     float64   `gorm:"not null;default:0"`
+	UsageLimit   int64     `gorm:"not null;default:0"`
+	PerUserLimit int64     `gorm:"not null;default:0"`
+	UsedCount    int64     `gorm:"not null;default:0"`
+	StartsAt     time.Time `gorm:"type:datetime;not null"`
+	EndsAt       *time.Time
+	Active       bool `gorm:"not null"`
+}
+
+func (Coupon) TableName() string {
+	return "coupons"
+}
+
+// CouponProduct restricts a coupon to the listed products. A coupon without
+// rows here applies to every product.
+type CouponProduct struct {
+	CouponId  int64 `gorm:"primaryKey;autoIncrement:false"`
+	ProductId int64 `gorm:"primaryKey;autoIncrement:false"`
+}
+
+func (CouponProduct) TableName() string {
+	return "coupon_products"
+}
+
+// CouponCategory restricts a coupon to the products of the listed categories
+// and their subcategories, in addition to the products in CouponProduct.
+type CouponCategory struct {
+	CouponId   int64 `gorm:"primaryKey;autoIncrement:false"`
+	CategoryId int64 `gorm:"primaryKey;autoIncrement:false"`
+}
+
+func (CouponCategory) TableName() string {
+	return "coupon_categories"
+}
+
+type CouponRedemption struct {
+	Base
+	CouponId int64   `gorm:"not null;index"`
+	UserId   int64   `gorm:"not null;index"`
+	OrderId  int64   `gorm:"not null;index"`
+	Discount float64 `gorm:"not null"`
+}
+
+func (CouponRedemption) TableName() string {
+	return "coupon_redemptions"
+}
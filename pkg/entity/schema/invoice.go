@@ -0,0 +1,60 @@
+package schema
+
+import "time"
+
+// Invoice is issued when an order is paid and snapshots the buyer and the
+// amounts, so later changes to the user or order do not alter it.
+type Invoice struct {
+	Base
+	Number      string          `gorm:"type:varchar(30);not null;uniqueIndex"`
+	Year        int             `gorm:"not null;uniqueIndex:idx_invoice_sequence"`
+	Sequence    int64           `gorm:"not null;uniqueIndex:idx_invoice_sequence"`
+	OrderId     int64           `gorm:"not null;uniqueIndex"`
+	OrderNumber string          `gorm:"type:varchar(30)"`
+	UserId      int64           `gorm:"not null;index"`
+	BuyerName   string          `gorm:"type:varchar(255)"`
+	BuyerEmail  string          `gorm:"type:varchar(255)"`
+	Shipping    ShippingAddress `gorm:"embedded;embeddedPrefix:shipping_"`
+
+	Courier        string    `gorm:"type:varchar(50)"`
+	Service        string    `gorm:"type:varchar(50)"`
+	Subtotal       float64   `gorm:"not null"`
+	DiscountAmount float64   `gorm:"not null;default:0"`
+	NetAmount      float64   `gorm:"not null"`
+	TaxRate        float64   `gorm:"not null;default:0"`
+	TaxAmount      float64   `gorm:"not null;default:0"`
+	TaxInclusive   bool      `gorm:"not null;default:false"`
+	ShippingCost   float64   `gorm:"not null;default:0"`
+	Total          float64   `gorm:"not null"`
+	IssuedAt       time.Time `gorm:"type:datetime;not null"`
+}
+
+func (Invoice) TableName() string {
+	return "invoices"
+}
+
+type InvoiceLine struct {
+	Base
+	InvoiceId   int64   `gorm:"not null;index"`
+	ProductId   int64   `gorm:"not null"`
+	Description string  `gorm:"type:varchar(255)"`
+	Quantity    int64   `gorm:"not null"`
+	UnitPrice   float64 `gorm:"not null"`
+	Amount      float64 `gorm:"not null"`
+}
+
+func (InvoiceLine) TableName() string {
+	return "invoice_lines"
+}
+
+// InvoiceSequence holds the last invoice number issued per year. The row is
+// incremented inside the payment transaction, so a rolled back payment does
+// not consume a number.
+type InvoiceSequence struct {
+	Year       int   `gorm:"primaryKey;autoIncrement:false"`
+	LastNumber int64 `gorm:"not null"`
+}
+
+func (InvoiceSequence) TableName() string {
+	return "invoice_sequences"
+}
@@ -1,13 +1,48 @@
 package schema
 
+import "time"
+
 type Order struct {
 	Base
-	UserId    int64   `gorm:"not null"`
-	ProductId int64   `gorm:"not null"`
-	Amount    float64 `gorm:"not null"`
-	Status    string  `gorm:"not null;type:varchar(10);default:'pending'"`
+	Number    string          `gorm:"type:varchar(30);uniqueIndex"`
+	UserId    int64           `gorm:"not null"`
+	ProductId int64           `gorm:"not null"`
+	VariantId int64           `gorm:"not null;default:0"`
+	Sku       string          `gorm:"type:varchar(64)"`
+	Quantity  int64           `gorm:"not null;default:0"`
+	Amount    float64         `gorm:"not null"`
+	Status    string          `gorm:"not null;type:varchar(10);default:'pending'"`
+	Shipping  ShippingAddress `gorm:"embedded;embeddedPrefix:shipping_"`
+
+	// WarehouseId is the warehouse the order ships from, 0 before warehouses
+	// were set up
+	WarehouseId int64 `gorm:"not null;default:0"`
+
+	Courier      string  `gorm:"type:varchar(50)"`
+	Service      string  `gorm:"type:varchar(50)"`
+	ShippingCost float64 `gorm:"not null;default:0"`
+
+	// Amount is the gross price of the goods; NetAmount and TaxAmount split
+	// it into the taxable base and the VAT charged
+	NetAmount    float64 `gorm:"not null;default:0"`
+	TaxRate      float64 `gorm:"not null;default:0"`
+	TaxAmount    float64 `gorm:"not null;default:0"`
+	TaxInclusive bool    `gorm:"not null;default:false"`
+
+	DiscountAmount float64 `gorm:"not null;default:0"`
+	PaidAt         *time.Time
 }
 
 func (Order) TableName() string {
 	return "orders"
 }
+
+// OrderSequence counts the orders numbered per day, Day being YYYY-MM-DD.
+type OrderSequence struct {
+	Day        string `gorm:"type:varchar(10);primaryKey"`
+	LastNumber int64  `gorm:"not null"`
+}
+
+func (OrderSequence) TableName() string {
+	return "order_sequences"
+}
@@ -0,0 +1,26 @@
+package schema
+
+const (
+	OrderEventCreated       = "created"
+	OrderEventAmountChanged = "amount_changed"
+	OrderEventPaid          = "paid"
+	OrderEventStatusChanged = "status_changed"
+	OrderEventCancelled     = "cancelled"
+)
+
+// OrderEvent is one entry of an order's audit trail. ActorRole is user, admin
+// or system; Payload holds the event details as JSON.
+type OrderEvent struct {
+	Base
+	OrderId    int64  `gorm:"not null;index"`
+	Type       string `gorm:"type:varchar(20);not null"`
+	FromStatus string `gorm:"type:varchar(10)"`
+	ToStatus   string `gorm:"type:varchar(10)"`
+	ActorId    int64  `gorm:"not null;default:0"`
+	ActorRole  string `gorm:"type:varchar(10);not null"`
+	Payload    string `gorm:"type:text"`
+}
+
+func (OrderEvent) TableName() string {
+	return "order_events"
+}
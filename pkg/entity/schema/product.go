@@ -1,12 +1,53 @@
 package schema
 
+import "gorm.io/gorm"
+
 type Product struct {
 	Base
-	Name  string  `gorm:"type:varchar(255);not null"`
-	Price float64 `gorm:"not null"`
-	Qty   int64   `gorm:"not null"`
+	// a deleted product stays in the trash, where orders can still refer to
+	// it, until it is restored or purged
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+
+	Name        string  `gorm:"type:varchar(255);not null;index:idx_product_search,class:FULLTEXT"`
+	Description string  `gorm:"type:text;index:idx_product_search,class:FULLTEXT"`
+	Price       float64 `gorm:"not null"`
+	Qty         int64   `gorm:"not null"`
+
+	// weight in grams, dimensions in centimetres
+	Weight int64 `gorm:"not null;default:0"`
+	Length int64 `gorm:"not null;default:0"`
+	Width  int64 `gorm:"not null;default:0"`
+	Height int64 `gorm:"not null;default:0"`
+
+	TaxCategory string `gorm:"type:varchar(50);not null;default:'standard'"`
+
+	// Unit is the unit of measure the price and stock are counted in, such
+	// as sak, m² or batang. Orders below MinOrderQty units are refused.
+	Unit        string `gorm:"type:varchar(20);not null;default:'pcs'"`
+	Brand       string `gorm:"type:varchar(100)"`
+	Barcode     string `gorm:"type:varchar(64);index"`
+	MinOrderQty int64  `gorm:"not null;default:1"`
+
+	// ReorderLevel is the stock at or below which the product is reported
+	// as running low, so a product alerts when it runs out by default.
+	ReorderLevel int64 `gorm:"not null;default:0"`
+
+	// RatingCount and RatingTotal sum up the approved reviews. They are
+	// kept up to date as reviews are moderated rather than recounted.
+	RatingCount int64 `gorm:"not null;default:0"`
+	RatingTotal int64 `gorm:"not null;default:0"`
+
+	// Status is active, draft or archived. Only active products are listed
+	// to customers and can be ordered.
+	Status string `gorm:"type:varchar(20);not null;default:'active';index"`
 }
 
+const (
+	ProductActive   = "active"
+	ProductDraft    = "draft"
+	ProductArchived = "archived"
+)
+
 func (Product) TableName() string {
 	return "products"
 }
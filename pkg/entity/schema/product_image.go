@@ -0,0 +1,20 @@
+package schema
+
+// ProductImage is an uploaded picture of a product. ObjectKey and ThumbnailKey
+// locate the original and its thumbnail in the blob store. Images are shown
+// by ascending Position.
+type ProductImage struct {
+	Base
+	ProductId    int64  `gorm:"not null;index"`
+	ObjectKey    string `gorm:"type:varchar(255);not null"`
+	ThumbnailKey string `gorm:"type:varchar(255);not null"`
+	ContentType  string `gorm:"type:varchar(50);not null"`
+	Size         int64  `gorm:"not null"`
+	Width        int64  `gorm:"not null"`
+	Height       int64  `gorm:"not null"`
+	Position     int64  `gorm:"not null;default:0"`
+}
+
+func (ProductImage) TableName() string {
+	return "product_images"
+}
@@ -0,0 +1,32 @@
+package schema
+
+import "time"
+
+// ProductImportJob is a bulk product import. Small files are imported while
+// the client waits; larger ones run in the background and the client polls
+// the job. Errors holds the rejected rows as a JSON array.
+type ProductImportJob struct {
+	Base
+	Filename   string `gorm:"type:varchar(255);not null"`
+	DryRun     bool   `gorm:"not null;default:false"`
+	Status     string `gorm:"type:varchar(20);not null;default:'pending';index"`
+	Total      int64  `gorm:"not null;default:0"`
+	Created    int64  `gorm:"not null;default:0"`
+	Updated    int64  `gorm:"not null;default:0"`
+	Failed     int64  `gorm:"not null;default:0"`
+	Errors     string `gorm:"type:longtext"`
+	Message    string `gorm:"type:varchar(255)"`
+	CreatedBy  int64  `gorm:"not null"`
+	FinishedAt *time.Time
+}
+
+const (
+	ImportPending = "pending"
+	ImportRunning = "running"
+	ImportDone    = "done"
+	ImportFailed  = "failed"
+)
+
+func (ProductImportJob) TableName() string {
+	return "product_import_jobs"
+}
@@ -0,0 +1,21 @@
+package schema
+
+import "time"
+
+// ProductPrice is an entry in the price history of a variant. A regular
+// price holds from EffectiveFrom until the next regular price takes over,
+// which is when its EffectiveTo is set. A sale price is time-boxed: it wins
+// over the regular price between EffectiveFrom and EffectiveTo.
+type ProductPrice struct {
+	Base
+	ProductId     int64      `gorm:"not null;index"`
+	VariantId     int64      `gorm:"not null;index"`
+	Price         float64    `gorm:"not null"`
+	Sale          bool       `gorm:"not null;default:false"`
+	EffectiveFrom time.Time  `gorm:"not null;index"`
+	EffectiveTo   *time.Time `gorm:"index"`
+}
+
+func (ProductPrice) TableName() string {
+	return "product_prices"
+}
@@ -0,0 +1,57 @@
+package schema
+
+import "time"
+
+// Supplier is a company stock is bought from.
+type Supplier struct {
+	Base
+	Name    string `gorm:"type:varchar(255);not null"`
+	Email   string `gorm:"type:varchar(255)"`
+	Phone   string `gorm:"type:varchar(20)"`
+	Address string `gorm:"type:varchar(255)"`
+	Active  bool   `gorm:"not null;default:true"`
+}
+
+func (Supplier) TableName() string {
+	return "suppliers"
+}
+
+const (
+	PurchaseDraft             = "draft"
+	PurchaseSent              = "sent"
+	PurchasePartiallyReceived = "partially_received"
+	PurchaseReceived          = "received"
+)
+
+// PurchaseOrder orders stock from a supplier, to be received into the
+// warehouse. It can be changed while it is a draft; once sent, only the
+// goods received are recorded against it.
+type PurchaseOrder struct {
+	Base
+	Number      string  `gorm:"type:varchar(50);index"`
+	SupplierId  int64   `gorm:"not null;index"`
+	WarehouseId int64   `gorm:"not null;index"`
+	Status      string  `gorm:"type:varchar(20);not null;default:'draft';index"`
+	Total       float64 `gorm:"not null;default:0"`
+	Note        string  `gorm:"type:varchar(255)"`
+	SentAt      *time.Time
+	ReceivedAt  *time.Time
+}
+
+func (PurchaseOrder) TableName() string {
+	return "purchase_orders"
+}
+
+type PurchaseOrderLine struct {
+	Base
+	PurchaseOrderId int64   `gorm:"not null;index"`
+	VariantId       int64   `gorm:"not null;index"`
+	ProductId       int64   `gorm:"not null"`
+	Qty             int64   `gorm:"not null"`
+	ReceivedQty     int64   `gorm:"not null;default:0"`
+	UnitCost        float64 `gorm:"not null;default:0"`
+}
+
+func (PurchaseOrderLine) TableName() string {
+	return "purchase_order_lines"
+}
@@ -0,0 +1,29 @@
+package schema
+
+import "time"
+
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewHidden   = "hidden"
+)
+
+// Review rates a product from 1 to 5. Only a user who bought the product can
+// review it, once; OrderId is the order that proves the purchase. A review
+// is shown, and counted in the rating of the product, once approved.
+type Review struct {
+	Base
+	ProductId int64  `gorm:"not null;uniqueIndex:idx_review_product_user"`
+	UserId    int64  `gorm:"not null;uniqueIndex:idx_review_product_user"`
+	OrderId   int64  `gorm:"not null"`
+	Rating    int64  `gorm:"not null"`
+	Text      string `gorm:"type:text"`
+	Status    string `gorm:"type:varchar(10);not null;default:'pending';index"`
+
+	ModeratedBy int64 `gorm:"not null;default:0"`
+	ModeratedAt *time.Time
+}
+
+func (Review) TableName() string {
+	return "reviews"
+}
@@ -0,0 +1,30 @@
+package schema
+
+import "time"
+
+type Shipment struct {
+	Base
+	OrderId        int64  `gorm:"not null;uniqueIndex"`
+	Courier        string `gorm:"type:varchar(50);not null"`
+	Service        string `gorm:"type:varchar(50)"`
+	TrackingNumber string `gorm:"type:varchar(100);not null"`
+	ShippedAt      *time.Time
+	DeliveredAt    *time.Time
+}
+
+func (Shipment) TableName() string {
+	return "shipments"
+}
+
+type ShipmentEvent struct {
+	Base
+	ShipmentId  int64     `gorm:"not null;index"`
+	Status      string    `gorm:"type:varchar(20);not null"`
+	Location    string    `gorm:"type:varchar(255)"`
+	Description string    `gorm:"type:varchar(255)"`
+	OccurredAt  time.Time `gorm:"type:datetime;not null"`
+}
+
+func (ShipmentEvent) TableName() string {
+	return "shipment_events"
+}
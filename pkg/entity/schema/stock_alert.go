@@ -0,0 +1,21 @@
+package schema
+
+import "time"
+
+// StockAlert is raised when an order takes a product down to its reorder
+// level. A product has at most one open alert, which is resolved once the
+// product is found restocked, so it alerts again only after a restock.
+type StockAlert struct {
+	Base
+	ProductId    int64 `gorm:"not null;index"`
+	Qty          int64 `gorm:"not null"`
+	ReorderLevel int64 `gorm:"not null"`
+
+	// NotifiedAt is set once the notifiers have been told about the alert
+	NotifiedAt *time.Time `gorm:"index"`
+	ResolvedAt *time.Time
+}
+
+func (StockAlert) TableName() string {
+	return "stock_alerts"
+}
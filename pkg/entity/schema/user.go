@@ -1,10 +1,23 @@
 package schema
 
+import "time"
+
 type User struct {
 	Base
 	Name     string `gorm:"type:varchar(255);null"`
 	Email    string `gorm:"type:varchar(255);unique;not null"`
 	Password string `gorm:"type:varchar(255);not null"`
+	Role     string `gorm:"type:varchar(10);not null;default:'user'"`
+	Status   string `gorm:"type:varchar(10);not null;default:'active'"`
+
+	PasswordResetRequired bool `gorm:"not null;default:false"`
+
+	// PendingEmail is the address the user is changing to. It replaces Email
+	// once the token mailed to it is confirmed, and only the hash of the
+	// token is kept.
+	PendingEmail        string `gorm:"type:varchar(255)"`
+	EmailTokenHash      string `gorm:"type:varchar(64)"`
+	EmailTokenExpiresAt *time.Time
 }
 
 func (User) TableName() string {
@@ -0,0 +1,34 @@
+package schema
+
+// ProductOption is a choice a product is sold in, e.g. "Berat" with the
+// values ["40kg", "50kg"]. Values is a JSON array.
+type ProductOption struct {
+	Base
+	ProductId int64  `gorm:"not null;index"`
+	Name      string `gorm:"type:varchar(50);not null"`
+	Position  int64  `gorm:"not null;default:0"`
+	Values    string `gorm:"type:text;not null"`
+}
+
+func (ProductOption) TableName() string {
+	return "product_options"
+}
+
+// ProductVariant is a sellable version of a product with its own SKU, price,
+// stock and weight. Options is a JSON object of option name to value, with
+// sorted keys so equal choices compare equal. Every product has at least one
+// variant; the product's price and qty are the lowest variant price and the
+// total variant stock.
+type ProductVariant struct {
+	Base
+	ProductId int64   `gorm:"not null;uniqueIndex:idx_variant_options"`
+	Sku       string  `gorm:"type:varchar(64);not null;uniqueIndex"`
+	Options   string  `gorm:"type:varchar(500);not null;uniqueIndex:idx_variant_options"`
+	Price     float64 `gorm:"not null"`
+	Qty       int64   `gorm:"not null"`
+	Weight    int64   `gorm:"not null;default:0"`
+}
+
+func (ProductVariant) TableName() string {
+	return "product_variants"
+}
@@ -0,0 +1,65 @@
+package schema
+
+// Warehouse is a depot stock is held and shipped from. Its province and city
+// are the shipping origin of the orders it fulfils. Stock that is not placed
+// in a warehouse by name, such as the qty of a product form, goes to the
+// default warehouse.
+type Warehouse struct {
+	Base
+	Code       string `gorm:"type:varchar(20);not null;uniqueIndex"`
+	Name       string `gorm:"type:varchar(255);not null"`
+	Street     string `gorm:"type:varchar(255)"`
+	Province   string `gorm:"type:varchar(100);not null"`
+	City       string `gorm:"type:varchar(100);not null"`
+	PostalCode string `gorm:"type:varchar(10)"`
+	IsDefault  bool   `gorm:"not null;default:false"`
+	Active     bool   `gorm:"not null;default:true"`
+}
+
+func (Warehouse) TableName() string {
+	return "warehouses"
+}
+
+// WarehouseStock is the stock of a variant in a warehouse. Once warehouses
+// exist, the qty of a variant is the sum of its warehouse stock.
+type WarehouseStock struct {
+	Base
+	WarehouseId int64 `gorm:"not null;uniqueIndex:idx_warehouse_variant"`
+	VariantId   int64 `gorm:"not null;uniqueIndex:idx_warehouse_variant;index"`
+	ProductId   int64 `gorm:"not null;index"`
+	Qty         int64 `gorm:"not null;default:0"`
+}
+
+func (WarehouseStock) TableName() string {
+	return "warehouse_stocks"
+}
+
+const (
+	MovementAdjustment  = "adjustment"
+	MovementTransferOut = "transfer_out"
+	MovementTransferIn  = "transfer_in"
+	MovementOrder       = "order"
+	MovementCancel      = "cancel"
+	MovementReceipt     = "receipt"
+)
+
+// StockMovement is one change of the stock of a variant in a warehouse;
+// Qty is negative when stock leaves. The two movements of a transfer share
+// the Reference, an order's movements carry its id and a receipt carries the
+// number of its purchase order.
+type StockMovement struct {
+	Base
+	WarehouseId int64  `gorm:"not null;index"`
+	VariantId   int64  `gorm:"not null;index"`
+	ProductId   int64  `gorm:"not null"`
+	Qty         int64  `gorm:"not null"`
+	Type        string `gorm:"type:varchar(20);not null"`
+	Reference   string `gorm:"type:varchar(50);index"`
+	OrderId     int64  `gorm:"not null;default:0"`
+	ActorId     int64  `gorm:"not null;default:0"`
+	Note        string `gorm:"type:varchar(255)"`
+}
+
+func (StockMovement) TableName() string {
+	return "stock_movements"
+}
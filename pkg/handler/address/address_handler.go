@@ -0,0 +1,157 @@
+package address
+
+import (
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/middleware"
+	"kanggo/pkg/usecase/address"
+	"kanggo/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+var validate *validator.Validate
+
+type AddressHandler struct {
+	addressUsecase address.AddressUsecase
+}
+
+func NewAddressHandler(addressUsecase address.AddressUsecase) *AddressHandler {
+	return &AddressHandler{
+		addressUsecase: addressUsecase,
+	}
+}
+
+func (h *AddressHandler) Route(app *gin.Engine, accounts middleware.AccountLookup) {
+	v1 := app.Group("api/v1")
+	{
+		{
+			v1.POST("/me/addresses", middleware.RoleUser(accounts), h.Insert)
+			v1.GET("/me/addresses", middleware.RoleUser(accounts), h.GetAll)
+			v1.GET("/me/addresses/:id", middleware.RoleUser(accounts), h.GetById)
+			v1.PUT("/me/addresses/:id", middleware.RoleUser(accounts), h.Update)
+			v1.PUT("/me/addresses/:id/default", middleware.RoleUser(accounts), h.SetDefault)
+			v1.DELETE("/me/addresses/:id", middleware.RoleUser(accounts), h.Delete)
+		}
+	}
+
+}
+
+func (h *AddressHandler) Insert(c *gin.Context) {
+	validate = validator.New()
+	address := model.AddressRequest{}
+	userId := c.MustGet("user_id").(uint64)
+	ctx := c.Request.Context()
+
+	if err := c.ShouldBindJSON(&address); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := validate.Struct(address); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := h.addressUsecase.Insert(ctx, userId, address); err != nil {
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 201, "success insert address", nil)
+}
+
+func (h *AddressHandler) GetAll(c *gin.Context) {
+	userId := c.MustGet("user_id").(uint64)
+	ctx := c.Request.Context()
+
+	res, err := h.addressUsecase.GetAll(ctx, userId)
+	if err != nil {
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success", res)
+}
+
+func (h *AddressHandler) GetById(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	userId := c.MustGet("user_id").(uint64)
+	ctx := c.Request.Context()
+
+	res, err := h.addressUsecase.GetById(ctx, int64(id), userId)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			utils.Response(c, 404, "data not found", nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success", res)
+}
+
+func (h *AddressHandler) Update(c *gin.Context) {
+	validate = validator.New()
+	id, _ := strconv.Atoi(c.Param("id"))
+	address := model.AddressRequest{}
+	userId := c.MustGet("user_id").(uint64)
+	ctx := c.Request.Context()
+
+	if err := c.ShouldBindJSON(&address); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := validate.Struct(address); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := h.addressUsecase.Update(ctx, int64(id), userId, address); err != nil {
+		if err.Error() == "sql: no rows in result set" || err.Error() == "data not found" {
+			utils.Response(c, 404, "data not found", nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success update address", nil)
+}
+
+func (h *AddressHandler) SetDefault(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	userId := c.MustGet("user_id").(uint64)
+	ctx := c.Request.Context()
+
+	if err := h.addressUsecase.SetDefault(ctx, int64(id), userId); err != nil {
+		if err.Error() == "data not found" {
+			utils.Response(c, 404, err.Error(), nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success set default address", nil)
+}
+
+func (h *AddressHandler) Delete(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	userId := c.MustGet("user_id").(uint64)
+	ctx := c.Request.Context()
+
+	if err := h.addressUsecase.Delete(ctx, int64(id), userId); err != nil {
+		if err.Error() == "data not found" {
+			utils.Response(c, 404, err.Error(), nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success delete address", nil)
+}
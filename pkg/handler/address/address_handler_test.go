@@ -0,0 +1,117 @@
+package address
+
+import (
+	"bytes"
+	"encoding/json"
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/mocks"
+	"kanggo/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestInsert(t *testing.T) {
+	mockAddressUsecase := new(mocks.AddressUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockRequest := model.AddressRequest{
+			Recipient:  "Agung",
+			Phone:      "081234567890",
+			Street:     "Jl. Sudirman No. 1",
+			Province:   "DKI Jakarta",
+			City:       "Jakarta Selatan",
+			District:   "Setiabudi",
+			PostalCode: "12910",
+		}
+
+		mockAddressUsecase.On("Insert", mock.Anything, uint64(1), mockRequest).Return(nil)
+
+		body, err := json.Marshal(mockRequest)
+		assert.Nil(t, err)
+
+		httpReq, err := http.NewRequest(http.MethodPost, "/api/v1/me/addresses", bytes.NewReader(body))
+		httpReq.Header.Set("Content-Type", "application/json")
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewAddressHandler(mockAddressUsecase)
+
+		r.POST("/api/v1/me/addresses", func(c *gin.Context) { c.Set("user_id", uint64(1)) }, h.Insert)
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusCreated, rr.Code)
+		assert.EqualValues(t, 201, resp.Status)
+		assert.EqualValues(t, "success insert address", resp.Message)
+		mockAddressUsecase.AssertExpectations(t)
+	})
+
+	t.Run("invalid postal code", func(t *testing.T) {
+		mockRequest := model.AddressRequest{
+			Recipient:  "Agung",
+			Phone:      "081234567890",
+			Street:     "Jl. Sudirman No. 1",
+			Province:   "DKI Jakarta",
+			City:       "Jakarta Selatan",
+			District:   "Setiabudi",
+			PostalCode: "ABC",
+		}
+
+		body, err := json.Marshal(mockRequest)
+		assert.Nil(t, err)
+
+		httpReq, err := http.NewRequest(http.MethodPost, "/api/v1/me/addresses", bytes.NewReader(body))
+		httpReq.Header.Set("Content-Type", "application/json")
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewAddressHandler(mockAddressUsecase)
+
+		r.POST("/api/v1/me/addresses", func(c *gin.Context) { c.Set("user_id", uint64(1)) }, h.Insert)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestGetAll(t *testing.T) {
+	mockAddressUsecase := new(mocks.AddressUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockAddressList := []model.AddressResponse{
+			{Id: 1, Recipient: "Agung", City: "Jakarta Selatan", IsDefault: true},
+			{Id: 2, Recipient: "Agung", City: "Bandung"},
+		}
+
+		mockAddressUsecase.On("GetAll", mock.Anything, uint64(1)).Return(mockAddressList, nil)
+
+		httpReq, err := http.NewRequest(http.MethodGet, "/api/v1/me/addresses", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewAddressHandler(mockAddressUsecase)
+
+		r.GET("/api/v1/me/addresses", func(c *gin.Context) { c.Set("user_id", uint64(1)) }, h.GetAll)
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusOK, rr.Code)
+		assert.EqualValues(t, "success", resp.Message)
+		mockAddressUsecase.AssertExpectations(t)
+	})
+}
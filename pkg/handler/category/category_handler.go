@@ -0,0 +1,184 @@
+package category
+
+import (
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/middleware"
+	"kanggo/pkg/usecase/category"
+	"kanggo/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+var validate *validator.Validate
+
+type CategoryHandler struct {
+	categoryUsecase category.CategoryUsecase
+}
+
+func NewCategoryHandler(categoryUsecase category.CategoryUsecase) *CategoryHandler {
+	return &CategoryHandler{
+		categoryUsecase: categoryUsecase,
+	}
+}
+
+func (h *CategoryHandler) Route(app *gin.Engine, accounts middleware.AccountLookup) {
+	v1 := app.Group("api/v1")
+	{
+		{
+			v1.POST("/category", middleware.RoleAdmin(accounts), h.Insert)
+			v1.GET("/category", middleware.RoleUser(accounts), h.GetTree)
+			v1.GET("/category/:slug", middleware.RoleUser(accounts), h.GetBySlug)
+			v1.PUT("/category/:id", middleware.RoleAdmin(accounts), h.Update)
+			v1.DELETE("/category/:id", middleware.RoleAdmin(accounts), h.Delete)
+			v1.GET("/product/:id/categories", middleware.RoleUser(accounts), h.GetProductCategories)
+			v1.PUT("/product/:id/categories", middleware.RoleAdmin(accounts), h.SetProductCategories)
+		}
+	}
+
+}
+
+func (h *CategoryHandler) Insert(c *gin.Context) {
+	validate = validator.New()
+	category := model.CategoryRequest{}
+	ctx := c.Request.Context()
+
+	if err := c.ShouldBindJSON(&category); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := validate.Struct(category); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := h.categoryUsecase.Insert(ctx, category); err != nil {
+		switch err.Error() {
+		case "invalid slug", "slug already exists", "parent category not found":
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 201, "success insert category", nil)
+}
+
+func (h *CategoryHandler) GetTree(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	res, err := h.categoryUsecase.GetTree(ctx)
+	if err != nil {
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success", res)
+}
+
+func (h *CategoryHandler) GetBySlug(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	res, err := h.categoryUsecase.GetBySlug(ctx, c.Param("slug"))
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			utils.Response(c, 404, "data not found", nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success", res)
+}
+
+func (h *CategoryHandler) Update(c *gin.Context) {
+	validate = validator.New()
+	id, _ := strconv.Atoi(c.Param("id"))
+	category := model.CategoryRequest{}
+	ctx := c.Request.Context()
+
+	if err := c.ShouldBindJSON(&category); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := validate.Struct(category); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := h.categoryUsecase.Update(ctx, int64(id), category); err != nil {
+		switch err.Error() {
+		case "invalid slug", "slug already exists", "parent category not found", "category cannot be moved under itself":
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		case "data not found":
+			utils.Response(c, 404, err.Error(), nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success update category", nil)
+}
+
+func (h *CategoryHandler) Delete(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	ctx := c.Request.Context()
+
+	if err := h.categoryUsecase.Delete(ctx, int64(id)); err != nil {
+		switch err.Error() {
+		case "category is not empty", "category is used by a coupon":
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		case "data not found":
+			utils.Response(c, 404, err.Error(), nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success delete category", nil)
+}
+
+func (h *CategoryHandler) GetProductCategories(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	ctx := c.Request.Context()
+
+	res, err := h.categoryUsecase.GetProductCategories(ctx, int64(id))
+	if err != nil {
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success", res)
+}
+
+func (h *CategoryHandler) SetProductCategories(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	request := model.ProductCategoryRequest{}
+	ctx := c.Request.Context()
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := h.categoryUsecase.SetProductCategories(ctx, int64(id), request); err != nil {
+		switch err.Error() {
+		case "product not found", "category not found":
+			utils.Response(c, 404, err.Error(), nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success update product categories", nil)
+}
@@ -0,0 +1,139 @@
+package category
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/mocks"
+	"kanggo/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestInsert(t *testing.T) {
+	mockCategoryUsecase := new(mocks.CategoryUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockRequest := model.CategoryRequest{Name: "Semen"}
+
+		mockCategoryUsecase.On("Insert", mock.Anything, mockRequest).Return(nil)
+
+		body, err := json.Marshal(mockRequest)
+		assert.Nil(t, err)
+
+		httpReq, err := http.NewRequest(http.MethodPost, "/api/v1/category", bytes.NewReader(body))
+		httpReq.Header.Set("Content-Type", "application/json")
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewCategoryHandler(mockCategoryUsecase)
+
+		r.POST("/api/v1/category", h.Insert)
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusCreated, rr.Code)
+		assert.EqualValues(t, "success insert category", resp.Message)
+		mockCategoryUsecase.AssertExpectations(t)
+	})
+}
+
+func TestGetTree(t *testing.T) {
+	mockCategoryUsecase := new(mocks.CategoryUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockTree := []model.CategoryResponse{
+			{
+				Id:   1,
+				Name: "Bahan Bangunan",
+				Slug: "bahan-bangunan",
+				Children: []model.CategoryResponse{
+					{Id: 2, Name: "Semen", Slug: "semen"},
+				},
+			},
+		}
+
+		mockCategoryUsecase.On("GetTree", mock.Anything).Return(mockTree, nil)
+
+		httpReq, err := http.NewRequest(http.MethodGet, "/api/v1/category", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewCategoryHandler(mockCategoryUsecase)
+
+		r.GET("/api/v1/category", h.GetTree)
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusOK, rr.Code)
+		assert.EqualValues(t, "success", resp.Message)
+		mockCategoryUsecase.AssertExpectations(t)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	mockCategoryUsecase := new(mocks.CategoryUsecase)
+
+	t.Run("not empty", func(t *testing.T) {
+		mockCategoryUsecase.On("Delete", mock.Anything, int64(1)).Return(errors.New("category is not empty"))
+
+		httpReq, err := http.NewRequest(http.MethodDelete, "/api/v1/category/1", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewCategoryHandler(mockCategoryUsecase)
+
+		r.DELETE("/api/v1/category/:id", h.Delete)
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusBadRequest, rr.Code)
+		assert.EqualValues(t, "category is not empty", resp.Message)
+		mockCategoryUsecase.AssertExpectations(t)
+	})
+}
+
+func TestSetProductCategories(t *testing.T) {
+	mockCategoryUsecase := new(mocks.CategoryUsecase)
+
+	t.Run("unknown category", func(t *testing.T) {
+		mockRequest := model.ProductCategoryRequest{CategoryIds: []int64{99}}
+		mockCategoryUsecase.On("SetProductCategories", mock.Anything, int64(5), mockRequest).Return(errors.New("category not found"))
+
+		body, err := json.Marshal(mockRequest)
+		assert.Nil(t, err)
+
+		httpReq, err := http.NewRequest(http.MethodPut, "/api/v1/product/5/categories", bytes.NewReader(body))
+		httpReq.Header.Set("Content-Type", "application/json")
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewCategoryHandler(mockCategoryUsecase)
+
+		r.PUT("/api/v1/product/:id/categories", h.SetProductCategories)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusNotFound, rr.Code)
+		mockCategoryUsecase.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,182 @@
+package coupon
+
+import (
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/middleware"
+	"kanggo/pkg/promotion"
+	"kanggo/pkg/usecase/coupon"
+	"kanggo/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+var validate *validator.Validate
+
+type CouponHandler struct {
+	couponUsecase coupon.CouponUsecase
+}
+
+func NewCouponHandler(couponUsecase coupon.CouponUsecase) *CouponHandler {
+	return &CouponHandler{
+		couponUsecase: couponUsecase,
+	}
+}
+
+func (h *CouponHandler) Route(app *gin.Engine, accounts middleware.AccountLookup) {
+	v1 := app.Group("api/v1")
+	{
+		{
+			v1.POST("/coupon", middleware.RoleAdmin(accounts), h.Insert)
+			v1.GET("/coupon", middleware.RoleAdmin(accounts), h.GetAll)
+			v1.GET("/coupon/:id", middleware.RoleAdmin(accounts), h.GetById)
+			v1.PUT("/coupon/:id", middleware.RoleAdmin(accounts), h.Update)
+			v1.DELETE("/coupon/:id", middleware.RoleAdmin(accounts), h.Delete)
+			v1.POST("/coupon/apply", middleware.RoleUser(accounts), h.Apply)
+		}
+	}
+
+}
+
+func (h *CouponHandler) Insert(c *gin.Context) {
+	validate = validator.New()
+	coupon := model.CouponRequest{}
+	ctx := c.Request.Context()
+
+	if err := c.ShouldBindJSON(&coupon); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := validate.Struct(coupon); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := h.couponUsecase.Insert(ctx, coupon); err != nil {
+		switch err.Error() {
+		case "percentage discount cannot exceed 100", "ends_at must be after starts_at":
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 201, "success insert coupon", nil)
+}
+
+func (h *CouponHandler) GetAll(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	res, err := h.couponUsecase.GetAll(ctx)
+	if err != nil {
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success", res)
+}
+
+func (h *CouponHandler) GetById(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	ctx := c.Request.Context()
+
+	res, err := h.couponUsecase.GetById(ctx, int64(id))
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			utils.Response(c, 404, "data not found", nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success", res)
+}
+
+func (h *CouponHandler) Update(c *gin.Context) {
+	validate = validator.New()
+	id, _ := strconv.Atoi(c.Param("id"))
+	coupon := model.CouponRequest{}
+	ctx := c.Request.Context()
+
+	if err := c.ShouldBindJSON(&coupon); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := validate.Struct(coupon); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := h.couponUsecase.Update(ctx, int64(id), coupon); err != nil {
+		switch err.Error() {
+		case "percentage discount cannot exceed 100", "ends_at must be after starts_at":
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		case "sql: no rows in result set":
+			utils.Response(c, 404, "data not found", nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success update coupon", nil)
+}
+
+func (h *CouponHandler) Delete(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	ctx := c.Request.Context()
+
+	if err := h.couponUsecase.Delete(ctx, int64(id)); err != nil {
+		switch err.Error() {
+		case "coupon has been redeemed":
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		case "data not found":
+			utils.Response(c, 404, err.Error(), nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success delete coupon", nil)
+}
+
+func (h *CouponHandler) Apply(c *gin.Context) {
+	validate = validator.New()
+	apply := model.ApplyCouponRequest{}
+	userId := c.MustGet("user_id").(uint64)
+	ctx := c.Request.Context()
+
+	if err := c.ShouldBindJSON(&apply); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := validate.Struct(apply); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	res, err := h.couponUsecase.Apply(ctx, userId, apply)
+	if err != nil {
+		if err.Error() == "coupon not found" {
+			utils.Response(c, 404, err.Error(), nil)
+			return
+		}
+		if promotion.IsRejection(err) {
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success", res)
+}
@@ -0,0 +1,134 @@
+package coupon
+
+import (
+	"bytes"
+	"encoding/json"
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/mocks"
+	"kanggo/pkg/promotion"
+	"kanggo/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestInsert(t *testing.T) {
+	mockCouponUsecase := new(mocks.CouponUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockRequest := model.CouponRequest{
+			Code:         "HEMAT10",
+			DiscountType: "percentage",
+			Value:        10,
+			StartsAt:     time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+			Active:       true,
+		}
+
+		mockCouponUsecase.On("Insert", mock.Anything, mockRequest).Return(nil)
+
+		body, err := json.Marshal(mockRequest)
+		assert.Nil(t, err)
+
+		httpReq, err := http.NewRequest(http.MethodPost, "/api/v1/coupon", bytes.NewReader(body))
+		httpReq.Header.Set("Content-Type", "application/json")
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewCouponHandler(mockCouponUsecase)
+
+		r.POST("/api/v1/coupon", h.Insert)
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusCreated, rr.Code)
+		assert.EqualValues(t, "success insert coupon", resp.Message)
+		mockCouponUsecase.AssertExpectations(t)
+	})
+
+	t.Run("invalid discount type", func(t *testing.T) {
+		body := []byte(`{"code":"HEMAT10","discount_type":"bogus","value":10,"starts_at":"2022-01-01T00:00:00Z"}`)
+
+		httpReq, err := http.NewRequest(http.MethodPost, "/api/v1/coupon", bytes.NewReader(body))
+		httpReq.Header.Set("Content-Type", "application/json")
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewCouponHandler(mockCouponUsecase)
+
+		r.POST("/api/v1/coupon", h.Insert)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestApply(t *testing.T) {
+	mockCouponUsecase := new(mocks.CouponUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockRequest := model.ApplyCouponRequest{Code: "HEMAT10", ProductId: 1, Amount: 100000}
+		mockResponse := model.ApplyCouponResponse{Code: "HEMAT10", Discount: 10000, Amount: 90000}
+
+		mockCouponUsecase.On("Apply", mock.Anything, uint64(1), mockRequest).Return(&mockResponse, nil).Once()
+
+		body, err := json.Marshal(mockRequest)
+		assert.Nil(t, err)
+
+		httpReq, err := http.NewRequest(http.MethodPost, "/api/v1/coupon/apply", bytes.NewReader(body))
+		httpReq.Header.Set("Content-Type", "application/json")
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewCouponHandler(mockCouponUsecase)
+
+		r.POST("/api/v1/coupon/apply", func(c *gin.Context) { c.Set("user_id", uint64(1)) }, h.Apply)
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusOK, rr.Code)
+		assert.EqualValues(t, "success", resp.Message)
+		mockCouponUsecase.AssertExpectations(t)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		mockRequest := model.ApplyCouponRequest{Code: "LAMA", ProductId: 1, Amount: 100000}
+
+		mockCouponUsecase.On("Apply", mock.Anything, uint64(1), mockRequest).Return(nil, promotion.ErrExpired).Once()
+
+		body, err := json.Marshal(mockRequest)
+		assert.Nil(t, err)
+
+		httpReq, err := http.NewRequest(http.MethodPost, "/api/v1/coupon/apply", bytes.NewReader(body))
+		httpReq.Header.Set("Content-Type", "application/json")
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewCouponHandler(mockCouponUsecase)
+
+		r.POST("/api/v1/coupon/apply", func(c *gin.Context) { c.Set("user_id", uint64(1)) }, h.Apply)
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusBadRequest, rr.Code)
+		assert.EqualValues(t, "coupon has expired", resp.Message)
+	})
+}
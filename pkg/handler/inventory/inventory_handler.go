@@ -0,0 +1,56 @@
+package inventory
+
+import (
+	"kanggo/pkg/middleware"
+	"kanggo/pkg/usecase/inventory"
+	"kanggo/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+var lowStockSorts = []string{"qty", "id", "name"}
+
+type InventoryHandler struct {
+	inventoryUsecase inventory.InventoryUsecase
+}
+
+func NewInventoryHandler(inventoryUsecase inventory.InventoryUsecase) *InventoryHandler {
+	return &InventoryHandler{
+		inventoryUsecase: inventoryUsecase,
+	}
+}
+
+func (h *InventoryHandler) Route(app *gin.Engine, accounts middleware.AccountLookup) {
+	v1 := app.Group("api/v1")
+	{
+		{
+			v1.GET("/inventory/low-stock", middleware.RoleAdmin(accounts), h.GetLowStock)
+		}
+	}
+
+}
+
+// GetLowStock reports the products at or below their reorder level, the
+// lowest stock first.
+func (h *InventoryHandler) GetLowStock(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	query, err := utils.ParseListQuery(c, lowStockSorts...)
+	if err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	res, total, err := h.inventoryUsecase.GetLowStock(ctx, query)
+	if err != nil {
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	var lastId int64
+	if len(res) > 0 {
+		lastId = res[len(res)-1].ProductId
+	}
+
+	utils.ResponseList(c, 200, "success", res, utils.NewMeta(query, total, len(res), lastId))
+}
@@ -0,0 +1,59 @@
+package inventory
+
+import (
+	"encoding/json"
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/mocks"
+	"kanggo/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetLowStock(t *testing.T) {
+	mockInventoryUsecase := new(mocks.InventoryUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		query := model.ListQuery{Page: 1, Size: 20, Sort: "qty"}
+		mockInventoryUsecase.On("GetLowStock", mock.Anything, query).Return([]model.LowStockResponse{
+			{ProductId: 12, Name: "Semen Putih 40kg", Status: "active", Qty: 0, ReorderLevel: 10},
+		}, int64(1), nil)
+
+		httpReq, err := http.NewRequest(http.MethodGet, "/api/v1/inventory/low-stock", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewInventoryHandler(mockInventoryUsecase)
+
+		r.GET("/api/v1/inventory/low-stock", h.GetLowStock)
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusOK, rr.Code)
+		assert.EqualValues(t, 1, resp.Meta.Total)
+		mockInventoryUsecase.AssertExpectations(t)
+	})
+
+	t.Run("invalid sort", func(t *testing.T) {
+		httpReq, err := http.NewRequest(http.MethodGet, "/api/v1/inventory/low-stock?sort=price", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewInventoryHandler(mockInventoryUsecase)
+
+		r.GET("/api/v1/inventory/low-stock", h.GetLowStock)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusBadRequest, rr.Code)
+	})
+}
@@ -0,0 +1,81 @@
+package invoice
+
+import (
+	"fmt"
+	"kanggo/pkg/middleware"
+	"kanggo/pkg/usecase/invoice"
+	"kanggo/pkg/usecase/order"
+	"kanggo/utils"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InvoiceHandler struct {
+	invoiceUsecase invoice.InvoiceUsecase
+	orderUsecase   order.OrderUsecase
+}
+
+func NewInvoiceHandler(invoiceUsecase invoice.InvoiceUsecase, orderUsecase order.OrderUsecase) *InvoiceHandler {
+	return &InvoiceHandler{
+		invoiceUsecase: invoiceUsecase,
+		orderUsecase:   orderUsecase,
+	}
+}
+
+func (h *InvoiceHandler) Route(app *gin.Engine, accounts middleware.AccountLookup) {
+	v1 := app.Group("api/v1")
+	{
+		{
+			v1.GET("/order/:id/invoice", middleware.RoleUser(accounts), h.GetByOrderId)
+			v1.GET("/order/:id/invoice.pdf", middleware.RoleUser(accounts), h.GetPdf)
+		}
+	}
+
+}
+
+func (h *InvoiceHandler) GetByOrderId(c *gin.Context) {
+	userId := c.MustGet("user_id").(uint64)
+	ctx := c.Request.Context()
+
+	id, ok := utils.OrderId(c, h.orderUsecase)
+	if !ok {
+		return
+	}
+
+	res, err := h.invoiceUsecase.GetByOrderId(ctx, id, userId, c.GetBool("admin"))
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			utils.Response(c, 404, "data not found", nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success", res)
+}
+
+func (h *InvoiceHandler) GetPdf(c *gin.Context) {
+	userId := c.MustGet("user_id").(uint64)
+	ctx := c.Request.Context()
+
+	id, ok := utils.OrderId(c, h.orderUsecase)
+	if !ok {
+		return
+	}
+
+	res, pdf, err := h.invoiceUsecase.GetPdf(ctx, id, userId, c.GetBool("admin"))
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			utils.Response(c, 404, "data not found", nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	filename := strings.ReplaceAll(res.Number, "/", "-") + ".pdf"
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Data(200, "application/pdf", pdf)
+}
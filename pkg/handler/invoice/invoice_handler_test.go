@@ -0,0 +1,118 @@
+package invoice
+
+import (
+	"database/sql"
+	"encoding/json"
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/mocks"
+	"kanggo/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetByOrderId(t *testing.T) {
+	mockInvoiceUsecase := new(mocks.InvoiceUsecase)
+	mockOrderUsecase := new(mocks.OrderUsecase)
+
+	t.Run("admin", func(t *testing.T) {
+		mockResponse := model.InvoiceResponse{Id: 7, Number: "INV/2022/000007", OrderId: 3, UserId: 1}
+
+		mockInvoiceUsecase.On("GetByOrderId", mock.Anything, int64(3), uint64(5), true).Return(&mockResponse, nil)
+
+		httpReq, err := http.NewRequest(http.MethodGet, "/api/v1/order/3/invoice", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewInvoiceHandler(mockInvoiceUsecase, mockOrderUsecase)
+
+		r.GET("/api/v1/order/:id/invoice", func(c *gin.Context) {
+			c.Set("user_id", uint64(5))
+			c.Set("admin", true)
+		}, h.GetByOrderId)
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusOK, rr.Code)
+		assert.EqualValues(t, "success", resp.Message)
+		mockInvoiceUsecase.AssertExpectations(t)
+	})
+}
+
+func TestGetPdf(t *testing.T) {
+	mockInvoiceUsecase := new(mocks.InvoiceUsecase)
+	mockOrderUsecase := new(mocks.OrderUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockResponse := model.InvoiceResponse{Id: 7, Number: "INV/2022/000007", OrderId: 3, UserId: 1}
+
+		mockInvoiceUsecase.On("GetPdf", mock.Anything, int64(3), uint64(1), false).
+			Return(&mockResponse, []byte("%PDF-1.4\n"), nil).Once()
+
+		httpReq, err := http.NewRequest(http.MethodGet, "/api/v1/order/3/invoice.pdf", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewInvoiceHandler(mockInvoiceUsecase, mockOrderUsecase)
+
+		r.GET("/api/v1/order/:id/invoice.pdf", func(c *gin.Context) { c.Set("user_id", uint64(1)) }, h.GetPdf)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "application/pdf", rr.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="INV-2022-000007.pdf"`, rr.Header().Get("Content-Disposition"))
+		assert.Equal(t, "%PDF-1.4\n", rr.Body.String())
+		mockInvoiceUsecase.AssertExpectations(t)
+	})
+
+	t.Run("not owner", func(t *testing.T) {
+		mockInvoiceUsecase.On("GetPdf", mock.Anything, int64(4), uint64(1), false).
+			Return(nil, nil, sql.ErrNoRows).Once()
+
+		httpReq, err := http.NewRequest(http.MethodGet, "/api/v1/order/4/invoice.pdf", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewInvoiceHandler(mockInvoiceUsecase, mockOrderUsecase)
+
+		r.GET("/api/v1/order/:id/invoice.pdf", func(c *gin.Context) { c.Set("user_id", uint64(1)) }, h.GetPdf)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("by number", func(t *testing.T) {
+		mockResponse := model.InvoiceResponse{Id: 7, Number: "INV/2022/000007", OrderId: 3, UserId: 1}
+
+		mockOrderUsecase.On("GetOrderIdByNumber", mock.Anything, "KG-20221018-000003").Return(int64(3), nil).Once()
+		mockInvoiceUsecase.On("GetPdf", mock.Anything, int64(3), uint64(1), false).
+			Return(&mockResponse, []byte("%PDF-1.4\n"), nil).Once()
+
+		httpReq, err := http.NewRequest(http.MethodGet, "/api/v1/order/KG-20221018-000003/invoice.pdf", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewInvoiceHandler(mockInvoiceUsecase, mockOrderUsecase)
+
+		r.GET("/api/v1/order/:id/invoice.pdf", func(c *gin.Context) { c.Set("user_id", uint64(1)) }, h.GetPdf)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusOK, rr.Code)
+		mockOrderUsecase.AssertExpectations(t)
+		mockInvoiceUsecase.AssertExpectations(t)
+	})
+}
@@ -3,9 +3,10 @@ package order
 import (
 	"kanggo/pkg/entity/model"
 	"kanggo/pkg/middleware"
+	"kanggo/pkg/promotion"
 	"kanggo/pkg/usecase/order"
 	"kanggo/utils"
-	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -13,6 +14,9 @@ import (
 
 var validate *validator.Validate
 
+// orderSorts are the sort keys of the order lists, the first is the default.
+var orderSorts = []string{"id", "created_at", "amount", "status"}
+
 type OrderHandler struct {
 	orderUsecase order.OrderUsecase
 }
@@ -23,15 +27,18 @@ func NewOrderHandler(orderUsecase order.OrderUsecase) *OrderHandler {
 	}
 }
 
-func (o *OrderHandler) Route(app *gin.Engine) {
+func (o *OrderHandler) Route(app *gin.Engine, accounts middleware.AccountLookup) {
 	v1 := app.Group("api/v1")
 	{
 		{
-			v1.POST("/order", middleware.RoleUser(), o.InsertOrder)
-			v1.GET("/order", middleware.RoleAdmin(), o.GetAllOrder)
-			v1.GET("/order/user", middleware.RoleUser(), o.GetAllOrderPerUser)
-			v1.GET("/order/:id", middleware.RoleUser(), o.GetOrderById)
-			v1.PUT("/payment", middleware.RoleUser(), o.UpdatePayment)
+			v1.POST("/order", middleware.RoleUser(accounts), o.InsertOrder)
+			v1.GET("/order", middleware.RoleAdmin(accounts), o.GetAllOrder)
+			v1.GET("/order/user", middleware.RoleUser(accounts), o.GetAllOrderPerUser)
+			v1.GET("/order/tax-report", middleware.RoleAdmin(accounts), o.GetTaxReport)
+			v1.GET("/order/:id", middleware.RoleUser(accounts), o.GetOrderById)
+			v1.PUT("/order/:id/cancel", middleware.RoleUser(accounts), o.CancelOrder)
+			v1.GET("/order/:id/events", middleware.RoleUser(accounts), o.GetEvents)
+			v1.PUT("/payment", middleware.RoleUser(accounts), o.UpdatePayment)
 		}
 	}
 
@@ -62,6 +69,27 @@ func (o *OrderHandler) InsertOrder(c *gin.Context) {
 			utils.Response(c, 400, "not enough product quantity", nil)
 			return
 		}
+		if err.Error() == "shipping option not available" || err.Error() == "amount does not match the current price" {
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		}
+		if err.Error() == "coupon not found" || err.Error() == "variant not found" {
+			utils.Response(c, 404, err.Error(), nil)
+			return
+		}
+		if err.Error() == "variant_id is required" || err.Error() == "product is not available" ||
+			err.Error() == "quantity is below the minimum order quantity" {
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		}
+		if promotion.IsRejection(err) {
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		}
+		if err.Error() == "sql: no rows in result set" {
+			utils.Response(c, 400, "address or product not found", nil)
+			return
+		}
 		utils.Response(c, 500, err.Error(), nil)
 		return
 	}
@@ -72,17 +100,19 @@ func (o *OrderHandler) InsertOrder(c *gin.Context) {
 func (o *OrderHandler) GetAllOrder(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	res, err := o.orderUsecase.GetAllOrder(ctx)
+	query, err := utils.ParseListQuery(c, orderSorts...)
+	if err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	res, total, err := o.orderUsecase.GetAllOrder(ctx, query)
 	if err != nil {
-		if err.Error() == "data not found" {
-			utils.Response(c, 404, err.Error(), nil)
-			return
-		}
 		utils.Response(c, 500, err.Error(), nil)
 		return
 	}
 
-	utils.Response(c, 200, "success", res)
+	utils.ResponseList(c, 200, "success", res, utils.NewMeta(query, total, len(res), lastOrderId(res)))
 }
 
 func (o *OrderHandler) GetAllOrderPerUser(c *gin.Context) {
@@ -90,24 +120,119 @@ func (o *OrderHandler) GetAllOrderPerUser(c *gin.Context) {
 
 	userId := c.MustGet("user_id").(uint64)
 
-	res, err := o.orderUsecase.GetAllOrderPerUser(ctx, userId)
+	query, err := utils.ParseListQuery(c, orderSorts...)
 	if err != nil {
-		if err.Error() == "data not found" {
-			utils.Response(c, 404, err.Error(), nil)
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	res, total, err := o.orderUsecase.GetAllOrderPerUser(ctx, userId, query)
+	if err != nil {
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.ResponseList(c, 200, "success", res, utils.NewMeta(query, total, len(res), lastOrderId(res)))
+}
+
+func lastOrderId(orders []model.OrderResponse) int64 {
+	if len(orders) == 0 {
+		return 0
+	}
+
+	return orders[len(orders)-1].OrderId
+}
+
+// GetOrderById accepts either the order id or the order number, as do the
+// other routes of an order.
+func (o *OrderHandler) GetOrderById(c *gin.Context) {
+	userId := c.MustGet("user_id").(uint64)
+	ctx := c.Request.Context()
+
+	id, ok := utils.OrderId(c, o.orderUsecase)
+	if !ok {
+		return
+	}
+
+	res, err := o.orderUsecase.GetOrderById(ctx, id, userId)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			utils.Response(c, 404, "data not found", nil)
 			return
 		}
 		utils.Response(c, 500, err.Error(), nil)
 		return
 	}
 
+	if utils.NotModified(c, res.Version) {
+		return
+	}
+
 	utils.Response(c, 200, "success", res)
 }
 
-func (o *OrderHandler) GetOrderById(c *gin.Context) {
-	id, _ := strconv.Atoi(c.Param("id"))
+// CancelOrder needs the If-Match header, so a client cannot cancel an order
+// that changed since it last read it.
+func (o *OrderHandler) CancelOrder(c *gin.Context) {
+	validate = validator.New()
+	userId := c.MustGet("user_id").(uint64)
+	cancel := model.CancelOrderRequest{}
+	ctx := c.Request.Context()
+
+	// the reason is optional, so an empty body is accepted
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&cancel); err != nil {
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		}
+	}
+
+	if err := validate.Struct(cancel); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	version, err := utils.IfMatch(c)
+	if err != nil {
+		utils.PreconditionResponse(c, err)
+		return
+	}
+	cancel.Version = version
+
+	id, ok := utils.OrderId(c, o.orderUsecase)
+	if !ok {
+		return
+	}
+
+	if err := o.orderUsecase.CancelOrder(ctx, id, userId, c.GetBool("admin"), cancel); err != nil {
+		switch err.Error() {
+		case "only pending orders can be cancelled":
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		case "data not found", "sql: no rows in result set":
+			utils.Response(c, 404, "data not found", nil)
+			return
+		case "version mismatch":
+			utils.PreconditionResponse(c, utils.ErrPreconditionFailed)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success cancel order", nil)
+}
+
+func (o *OrderHandler) GetEvents(c *gin.Context) {
 	userId := c.MustGet("user_id").(uint64)
 	ctx := c.Request.Context()
-	res, err := o.orderUsecase.GetOrderById(ctx, int64(id), userId)
+
+	id, ok := utils.OrderId(c, o.orderUsecase)
+	if !ok {
+		return
+	}
+
+	res, err := o.orderUsecase.GetEvents(ctx, id, userId, c.GetBool("admin"))
 	if err != nil {
 		if err.Error() == "sql: no rows in result set" {
 			utils.Response(c, 404, "data not found", nil)
@@ -155,3 +280,28 @@ func (o *OrderHandler) UpdatePayment(c *gin.Context) {
 
 	utils.Response(c, 200, "success update payment", nil)
 }
+
+func (o *OrderHandler) GetTaxReport(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	from, err := time.ParseInLocation("2006-01-02", c.Query("from"), time.Local)
+	if err != nil {
+		utils.Response(c, 400, "invalid from date, expected YYYY-MM-DD", nil)
+		return
+	}
+
+	to, err := time.ParseInLocation("2006-01-02", c.Query("to"), time.Local)
+	if err != nil {
+		utils.Response(c, 400, "invalid to date, expected YYYY-MM-DD", nil)
+		return
+	}
+
+	// the to date is inclusive for the caller
+	res, err := o.orderUsecase.GetTaxReport(ctx, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success", res)
+}
@@ -1,13 +1,17 @@
 package order
 
 import (
+	"bytes"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"kanggo/pkg/entity/model"
 	"kanggo/pkg/mocks"
 	"kanggo/utils"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -39,7 +43,7 @@ func TestGetAllOrder(t *testing.T) {
 			},
 		}
 
-		mockOrderUsecase.On("GetAllOrder", mock.Anything).Return(mockOrderList, nil)
+		mockOrderUsecase.On("GetAllOrder", mock.Anything, mock.Anything).Return(mockOrderList, int64(2), nil)
 
 		httpReq, err := http.NewRequest(http.MethodGet, "/api/v1/order", nil)
 		assert.Nil(t, err)
@@ -60,6 +64,279 @@ func TestGetAllOrder(t *testing.T) {
 		assert.EqualValues(t, 200, resp.Status)
 		assert.EqualValues(t, "success", resp.Message)
 		assert.Equal(t, mockOrderList[1].UserName, "Agung")
+		assert.EqualValues(t, 2, resp.Meta.Total)
+		mockOrderUsecase.AssertExpectations(t)
+	})
+
+	t.Run("invalid sort", func(t *testing.T) {
+		httpReq, err := http.NewRequest(http.MethodGet, "/api/v1/order?sort=user_name", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewOrderHandler(mockOrderUsecase)
+
+		r.GET("/api/v1/order", h.GetAllOrder)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestGetTaxReport(t *testing.T) {
+	mockOrderUsecase := new(mocks.OrderUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockReport := []model.TaxReport{
+			{TaxRate: 0, TotalOrders: 2, NetAmount: 80000, TaxAmount: 0, GrossAmount: 80000},
+			{TaxRate: 0.11, TotalOrders: 5, NetAmount: 1000000, TaxAmount: 110000, GrossAmount: 1110000},
+		}
+
+		mockOrderUsecase.On("GetTaxReport", mock.Anything,
+			time.Date(2022, 4, 1, 0, 0, 0, 0, time.Local),
+			time.Date(2022, 5, 1, 0, 0, 0, 0, time.Local)).Return(mockReport, nil)
+
+		httpReq, err := http.NewRequest(http.MethodGet, "/api/v1/order/tax-report?from=2022-04-01&to=2022-04-30", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewOrderHandler(mockOrderUsecase)
+
+		r.GET("/api/v1/order/tax-report", h.GetTaxReport)
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusOK, rr.Code)
+		assert.EqualValues(t, "success", resp.Message)
+		mockOrderUsecase.AssertExpectations(t)
+	})
+
+	t.Run("invalid date", func(t *testing.T) {
+		httpReq, err := http.NewRequest(http.MethodGet, "/api/v1/order/tax-report?from=april&to=2022-04-30", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewOrderHandler(mockOrderUsecase)
+
+		r.GET("/api/v1/order/tax-report", h.GetTaxReport)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestGetOrderById(t *testing.T) {
+	mockOrderUsecase := new(mocks.OrderUsecase)
+	mockOrder := model.OrderResponse{
+		OrderId:     12,
+		OrderNumber: "KG-20261018-000123",
+		UserId:      1,
+		Status:      "pending",
+	}
+
+	mockOrderUsecase.On("GetOrderIdByNumber", mock.Anything, "KG-20261018-000123").Return(int64(12), nil).Once()
+	mockOrderUsecase.On("GetOrderIdByNumber", mock.Anything, "KG-20261018-999999").Return(int64(0), sql.ErrNoRows).Once()
+
+	cases := []struct {
+		name  string
+		param string
+		code  int
+	}{
+		{"by id", "12", http.StatusOK},
+		{"by number", "KG-20261018-000123", http.StatusOK},
+		{"unknown number", "KG-20261018-999999", http.StatusNotFound},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.code == http.StatusOK {
+				mockOrderUsecase.On("GetOrderById", mock.Anything, int64(12), uint64(1)).Return(&mockOrder, nil).Once()
+			}
+
+			httpReq, err := http.NewRequest(http.MethodGet, "/api/v1/order/"+tc.param, nil)
+			assert.Nil(t, err)
+
+			r := gin.Default()
+			rr := httptest.NewRecorder()
+
+			h := NewOrderHandler(mockOrderUsecase)
+
+			r.GET("/api/v1/order/:id", func(c *gin.Context) { c.Set("user_id", uint64(1)) }, h.GetOrderById)
+			r.ServeHTTP(rr, httpReq)
+
+			assert.EqualValues(t, tc.code, rr.Code)
+			if tc.code == http.StatusOK {
+				var resp utils.Respond
+				err = json.Unmarshal(rr.Body.Bytes(), &resp)
+				assert.Nil(t, err)
+				assert.EqualValues(t, "KG-20261018-000123", resp.Data.(map[string]interface{})["order_number"])
+			}
+		})
+	}
+
+	mockOrderUsecase.AssertExpectations(t)
+}
+
+func TestCancelOrder(t *testing.T) {
+	mockOrderUsecase := new(mocks.OrderUsecase)
+
+	t.Run("without reason", func(t *testing.T) {
+		mockOrderUsecase.On("CancelOrder", mock.Anything, int64(5), uint64(1), false, model.CancelOrderRequest{Version: 4}).Return(nil).Once()
+
+		httpReq, err := http.NewRequest(http.MethodPut, "/api/v1/order/5/cancel", nil)
+		httpReq.Header.Set("If-Match", `"4"`)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewOrderHandler(mockOrderUsecase)
+
+		r.PUT("/api/v1/order/:id/cancel", func(c *gin.Context) { c.Set("user_id", uint64(1)) }, h.CancelOrder)
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusOK, rr.Code)
+		assert.EqualValues(t, "success cancel order", resp.Message)
+	})
+
+	t.Run("by number", func(t *testing.T) {
+		mockOrderUsecase.On("GetOrderIdByNumber", mock.Anything, "KG-20261018-000124").Return(int64(5), nil).Once()
+		mockOrderUsecase.On("CancelOrder", mock.Anything, int64(5), uint64(1), false, model.CancelOrderRequest{Version: 4}).Return(nil).Once()
+
+		httpReq, err := http.NewRequest(http.MethodPut, "/api/v1/order/KG-20261018-000124/cancel", nil)
+		httpReq.Header.Set("If-Match", `"4"`)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewOrderHandler(mockOrderUsecase)
+
+		r.PUT("/api/v1/order/:id/cancel", func(c *gin.Context) { c.Set("user_id", uint64(1)) }, h.CancelOrder)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusOK, rr.Code)
+		mockOrderUsecase.AssertExpectations(t)
+	})
+
+	t.Run("not pending", func(t *testing.T) {
+		mockRequest := model.CancelOrderRequest{Reason: "too late"}
+		mockOrderUsecase.On("CancelOrder", mock.Anything, int64(6), uint64(1), false, mockRequest).
+			Return(errors.New("only pending orders can be cancelled")).Once()
+
+		body, err := json.Marshal(mockRequest)
+		assert.Nil(t, err)
+
+		httpReq, err := http.NewRequest(http.MethodPut, "/api/v1/order/6/cancel", bytes.NewReader(body))
+		httpReq.Header.Set("Content-Type", "application/json")
+		httpReq.Header.Set("If-Match", "*")
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewOrderHandler(mockOrderUsecase)
+
+		r.PUT("/api/v1/order/:id/cancel", func(c *gin.Context) { c.Set("user_id", uint64(1)) }, h.CancelOrder)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("stale version", func(t *testing.T) {
+		mockOrderUsecase.On("CancelOrder", mock.Anything, int64(7), uint64(1), false, model.CancelOrderRequest{Version: 2}).
+			Return(errors.New("version mismatch")).Once()
+
+		httpReq, err := http.NewRequest(http.MethodPut, "/api/v1/order/7/cancel", nil)
+		httpReq.Header.Set("If-Match", `"2"`)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewOrderHandler(mockOrderUsecase)
+
+		r.PUT("/api/v1/order/:id/cancel", func(c *gin.Context) { c.Set("user_id", uint64(1)) }, h.CancelOrder)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusPreconditionFailed, rr.Code)
+	})
+
+	t.Run("without If-Match", func(t *testing.T) {
+		httpReq, err := http.NewRequest(http.MethodPut, "/api/v1/order/8/cancel", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewOrderHandler(mockOrderUsecase)
+
+		r.PUT("/api/v1/order/:id/cancel", func(c *gin.Context) { c.Set("user_id", uint64(1)) }, h.CancelOrder)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusPreconditionRequired, rr.Code)
+	})
+
+	mockOrderUsecase.AssertExpectations(t)
+}
+
+func TestGetEvents(t *testing.T) {
+	mockOrderUsecase := new(mocks.OrderUsecase)
+
+	t.Run("admin", func(t *testing.T) {
+		mockEvents := []model.OrderEventResponse{
+			{Id: 1, Type: "created", ToStatus: "pending", ActorId: 1, ActorRole: "user", Payload: json.RawMessage(`{"amount":111000}`)},
+		}
+
+		mockOrderUsecase.On("GetEvents", mock.Anything, int64(5), uint64(9), true).Return(mockEvents, nil)
+
+		httpReq, err := http.NewRequest(http.MethodGet, "/api/v1/order/5/events", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewOrderHandler(mockOrderUsecase)
+
+		r.GET("/api/v1/order/:id/events", func(c *gin.Context) {
+			c.Set("user_id", uint64(9))
+			c.Set("admin", true)
+		}, h.GetEvents)
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusOK, rr.Code)
+		assert.Len(t, resp.Data, 1)
+		mockOrderUsecase.AssertExpectations(t)
+	})
+
+	t.Run("unknown number", func(t *testing.T) {
+		mockOrderUsecase.On("GetOrderIdByNumber", mock.Anything, "KG-20261018-999999").Return(int64(0), sql.ErrNoRows).Once()
+
+		httpReq, err := http.NewRequest(http.MethodGet, "/api/v1/order/KG-20261018-999999/events", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewOrderHandler(mockOrderUsecase)
+
+		r.GET("/api/v1/order/:id/events", func(c *gin.Context) { c.Set("user_id", uint64(1)) }, h.GetEvents)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusNotFound, rr.Code)
 		mockOrderUsecase.AssertExpectations(t)
 	})
 }
@@ -0,0 +1,126 @@
+package product
+
+import (
+	"io"
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/imaging"
+	"kanggo/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// UploadImage takes the image as the "image" field of a multipart form.
+func (h *ProductHandler) UploadImage(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	ctx := c.Request.Context()
+
+	header, err := c.FormFile("image")
+	if err != nil {
+		utils.Response(c, 400, "image is required", nil)
+		return
+	}
+
+	if header.Size > imaging.MaxUploadSize {
+		utils.Response(c, 400, imaging.ErrTooLarge.Error(), nil)
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, imaging.MaxUploadSize+1))
+	if err != nil {
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	res, err := h.productUsecase.UploadImage(ctx, int64(id), data)
+	if err != nil {
+		switch err {
+		case imaging.ErrUnsupported, imaging.ErrTooLarge:
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		}
+		if err.Error() == "sql: no rows in result set" || err.Error() == "product not found" {
+			utils.Response(c, 404, "data not found", nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 201, "success upload product image", res)
+}
+
+func (h *ProductHandler) GetImages(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	ctx := c.Request.Context()
+
+	res, err := h.productUsecase.GetImages(ctx, int64(id))
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			utils.Response(c, 404, "data not found", nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success", res)
+}
+
+func (h *ProductHandler) ReorderImages(c *gin.Context) {
+	validate = validator.New()
+	id, _ := strconv.Atoi(c.Param("id"))
+	request := model.ProductImageOrderRequest{}
+	ctx := c.Request.Context()
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := validate.Struct(request); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := h.productUsecase.ReorderImages(ctx, int64(id), request); err != nil {
+		switch err.Error() {
+		case "image_ids must list every image of the product once":
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		case "product not found":
+			utils.Response(c, 404, err.Error(), nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success reorder product images", nil)
+}
+
+func (h *ProductHandler) DeleteImage(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	imageId, _ := strconv.Atoi(c.Param("imageId"))
+	ctx := c.Request.Context()
+
+	if err := h.productUsecase.DeleteImage(ctx, int64(id), int64(imageId)); err != nil {
+		switch err.Error() {
+		case "sql: no rows in result set", "data not found":
+			utils.Response(c, 404, "data not found", nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success delete product image", nil)
+}
@@ -0,0 +1,101 @@
+package product
+
+import (
+	"bytes"
+	"encoding/json"
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/imaging"
+	"kanggo/pkg/mocks"
+	"kanggo/utils"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func multipartImage(t *testing.T, field string, data []byte) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	part, err := writer.CreateFormFile(field, "semen.jpg")
+	assert.Nil(t, err)
+	_, err = part.Write(data)
+	assert.Nil(t, err)
+	assert.Nil(t, writer.Close())
+
+	return body, writer.FormDataContentType()
+}
+
+func TestUploadImage(t *testing.T) {
+	mockProductUsecase := new(mocks.ProductUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockProductUsecase.On("UploadImage", mock.Anything, int64(1), []byte("jpeg")).
+			Return(&model.ProductImageResponse{Id: 9, Url: "/uploads/products/1/a.jpg"}, nil).Once()
+
+		body, contentType := multipartImage(t, "image", []byte("jpeg"))
+		httpReq, err := http.NewRequest(http.MethodPost, "/api/v1/product/1/image", body)
+		httpReq.Header.Set("Content-Type", contentType)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewProductHandler(mockProductUsecase)
+
+		r.POST("/api/v1/product/:id/image", h.UploadImage)
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusCreated, rr.Code)
+		assert.EqualValues(t, "success upload product image", resp.Message)
+		mockProductUsecase.AssertExpectations(t)
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		mockProductUsecase.On("UploadImage", mock.Anything, int64(1), []byte("%PDF")).
+			Return(nil, imaging.ErrUnsupported).Once()
+
+		body, contentType := multipartImage(t, "image", []byte("%PDF"))
+		httpReq, err := http.NewRequest(http.MethodPost, "/api/v1/product/1/image", body)
+		httpReq.Header.Set("Content-Type", contentType)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewProductHandler(mockProductUsecase)
+
+		r.POST("/api/v1/product/:id/image", h.UploadImage)
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusBadRequest, rr.Code)
+		assert.EqualValues(t, "unsupported image type", resp.Message)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		body, contentType := multipartImage(t, "file", []byte("jpeg"))
+		httpReq, err := http.NewRequest(http.MethodPost, "/api/v1/product/1/image", body)
+		httpReq.Header.Set("Content-Type", contentType)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewProductHandler(mockProductUsecase)
+
+		r.POST("/api/v1/product/:id/image", h.UploadImage)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusBadRequest, rr.Code)
+	})
+}
@@ -0,0 +1,120 @@
+package product
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/entity/schema"
+	"kanggo/pkg/sheet"
+	"kanggo/pkg/usecase/product"
+	"kanggo/utils"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImportProducts takes a CSV or XLSX file as the "file" field of a multipart
+// form, along with the dry_run, async and mapping fields. A finished import
+// answers 200 with its report, one left running in the background 202 with
+// the job to poll.
+func (h *ProductHandler) ImportProducts(c *gin.Context) {
+	request := model.ProductImportRequest{}
+	userId := c.MustGet("user_id").(uint64)
+	ctx := c.Request.Context()
+
+	if err := c.ShouldBind(&request); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		utils.Response(c, 400, "file is required", nil)
+		return
+	}
+
+	if header.Size > sheet.MaxFileSize {
+		utils.Response(c, 400, "file is too large", nil)
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, sheet.MaxFileSize+1))
+	if err != nil {
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	res, err := h.productUsecase.ImportProducts(ctx, header.Filename, data, request, int64(userId))
+	if err != nil {
+		if errors.Is(err, product.ErrImportFile) {
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	if res.Status == schema.ImportPending {
+		utils.Response(c, 202, "import started", res)
+		return
+	}
+
+	utils.Response(c, 200, "success import products", res)
+}
+
+func (h *ProductHandler) GetImportJob(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("jobId"))
+	ctx := c.Request.Context()
+
+	res, err := h.productUsecase.GetImportJob(ctx, int64(id))
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			utils.Response(c, 404, "data not found", nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success", res)
+}
+
+// ExportProducts streams the catalogue as CSV, or XLSX with format=xlsx.
+func (h *ProductHandler) ExportProducts(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	format := sheet.CSV
+	if f := c.Query("format"); f != "" {
+		var err error
+		if format, err = sheet.ParseFormat(f); err != nil {
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		}
+	}
+
+	filename := fmt.Sprintf("products-%s.%s", time.Now().Format("20060102"), format)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Header("Content-Type", format.ContentType())
+	c.Status(200)
+
+	if err := h.productUsecase.ExportProducts(ctx, sheet.NewWriter(c.Writer, format)); err != nil {
+		// once rows went out the status cannot change, and the cut short
+		// file is all the client gets
+		if !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Disposition")
+			c.Writer.Header().Del("Content-Type")
+			utils.Response(c, 500, err.Error(), nil)
+			return
+		}
+		c.Abort()
+	}
+}
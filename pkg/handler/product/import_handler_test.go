@@ -0,0 +1,211 @@
+package product
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/entity/schema"
+	"kanggo/pkg/mocks"
+	"kanggo/pkg/usecase/product"
+	"kanggo/utils"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func importRequest(t *testing.T, filename string, content []byte, fields map[string]string) *http.Request {
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	for name, value := range fields {
+		assert.Nil(t, w.WriteField(name, value))
+	}
+	if filename != "" {
+		part, err := w.CreateFormFile("file", filename)
+		assert.Nil(t, err)
+		_, err = part.Write(content)
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, w.Close())
+
+	httpReq, err := http.NewRequest(http.MethodPost, "/api/v1/product/import", body)
+	assert.Nil(t, err)
+	httpReq.Header.Set("Content-Type", w.FormDataContentType())
+
+	return httpReq
+}
+
+func TestImportProducts(t *testing.T) {
+	mockProductUsecase := new(mocks.ProductUsecase)
+	content := []byte("sku,qty\nSEMEN-50KG,5\n")
+
+	serve := func(httpReq *http.Request) *httptest.ResponseRecorder {
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewProductHandler(mockProductUsecase)
+
+		r.POST("/api/v1/product/import", func(c *gin.Context) {
+			c.Set("user_id", uint64(1))
+			h.ImportProducts(c)
+		})
+		r.ServeHTTP(rr, httpReq)
+
+		return rr
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		mockProductUsecase.On("ImportProducts", mock.Anything, "stok.csv", content,
+			model.ProductImportRequest{DryRun: true}, int64(1)).
+			Return(&model.ProductImportJobResponse{Id: 3, DryRun: true, Status: schema.ImportDone, Total: 1, Updated: 1}, nil).Once()
+
+		rr := serve(importRequest(t, "stok.csv", content, map[string]string{"dry_run": "true"}))
+
+		var resp utils.Respond
+		err := json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusOK, rr.Code)
+		assert.EqualValues(t, "success import products", resp.Message)
+		mockProductUsecase.AssertExpectations(t)
+	})
+
+	t.Run("started", func(t *testing.T) {
+		mockProductUsecase.On("ImportProducts", mock.Anything, "stok.csv", content,
+			model.ProductImportRequest{Async: true}, int64(1)).
+			Return(&model.ProductImportJobResponse{Id: 4, Status: schema.ImportPending, Total: 1}, nil).Once()
+
+		rr := serve(importRequest(t, "stok.csv", content, map[string]string{"async": "true"}))
+
+		var resp utils.Respond
+		err := json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusAccepted, rr.Code)
+		assert.EqualValues(t, "import started", resp.Message)
+		mockProductUsecase.AssertExpectations(t)
+	})
+
+	t.Run("no file", func(t *testing.T) {
+		rr := serve(importRequest(t, "", nil, map[string]string{"dry_run": "true"}))
+
+		assert.EqualValues(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		mockProductUsecase.On("ImportProducts", mock.Anything, "stok.xls", content, model.ProductImportRequest{}, int64(1)).
+			Return(nil, fmt.Errorf("%w: file must be .csv or .xlsx", product.ErrImportFile)).Once()
+
+		rr := serve(importRequest(t, "stok.xls", content, nil))
+
+		assert.EqualValues(t, http.StatusBadRequest, rr.Code)
+		mockProductUsecase.AssertExpectations(t)
+	})
+}
+
+func TestGetImportJob(t *testing.T) {
+	mockProductUsecase := new(mocks.ProductUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockProductUsecase.On("GetImportJob", mock.Anything, int64(4)).
+			Return(&model.ProductImportJobResponse{Id: 4, Status: schema.ImportRunning}, nil).Once()
+
+		httpReq, err := http.NewRequest(http.MethodGet, "/api/v1/product/import/4", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewProductHandler(mockProductUsecase)
+
+		r.GET("/api/v1/product/import/:jobId", h.GetImportJob)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusOK, rr.Code)
+		mockProductUsecase.AssertExpectations(t)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockProductUsecase.On("GetImportJob", mock.Anything, int64(9)).
+			Return(nil, errors.New("sql: no rows in result set")).Once()
+
+		httpReq, err := http.NewRequest(http.MethodGet, "/api/v1/product/import/9", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewProductHandler(mockProductUsecase)
+
+		r.GET("/api/v1/product/import/:jobId", h.GetImportJob)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusNotFound, rr.Code)
+		mockProductUsecase.AssertExpectations(t)
+	})
+}
+
+func TestExportProducts(t *testing.T) {
+	mockProductUsecase := new(mocks.ProductUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockProductUsecase.On("ExportProducts", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+			w := args.Get(1).(interface{ Write([]string) error })
+			w.Write([]string{"sku", "qty"})
+		}).Once()
+
+		httpReq, err := http.NewRequest(http.MethodGet, "/api/v1/product/export?format=xlsx", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewProductHandler(mockProductUsecase)
+
+		r.GET("/api/v1/product/export", h.ExportProducts)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Header().Get("Content-Disposition"), ".xlsx")
+		assert.EqualValues(t, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", rr.Header().Get("Content-Type"))
+		mockProductUsecase.AssertExpectations(t)
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		httpReq, err := http.NewRequest(http.MethodGet, "/api/v1/product/export?format=pdf", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewProductHandler(mockProductUsecase)
+
+		r.GET("/api/v1/product/export", h.ExportProducts)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("failed before any row", func(t *testing.T) {
+		mockProductUsecase.On("ExportProducts", mock.Anything, mock.Anything).Return(errors.New("connection reset")).Once()
+
+		httpReq, err := http.NewRequest(http.MethodGet, "/api/v1/product/export", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewProductHandler(mockProductUsecase)
+
+		r.GET("/api/v1/product/export", h.ExportProducts)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusInternalServerError, rr.Code)
+		assert.Empty(t, rr.Header().Get("Content-Disposition"))
+		mockProductUsecase.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,85 @@
+package product
+
+import (
+	"kanggo/pkg/entity/model"
+	"kanggo/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// GetPrices returns the price history of a product, scheduled prices and
+// sales included, the latest first.
+func (h *ProductHandler) GetPrices(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	ctx := c.Request.Context()
+
+	res, err := h.productUsecase.GetPrices(ctx, int64(id))
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			utils.Response(c, 404, "data not found", nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success", res)
+}
+
+func (h *ProductHandler) SchedulePrice(c *gin.Context) {
+	validate = validator.New()
+	id, _ := strconv.Atoi(c.Param("id"))
+	price := model.ProductPriceRequest{}
+	ctx := c.Request.Context()
+
+	if err := c.ShouldBindJSON(&price); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := validate.Struct(price); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	res, err := h.productUsecase.SchedulePrice(ctx, int64(id), price)
+	if err != nil {
+		switch err.Error() {
+		case "product not found", "variant not found":
+			utils.Response(c, 404, err.Error(), nil)
+			return
+		case "effective_from must not be in the past", "effective_to is required for a sale price",
+			"effective_to is only allowed for a sale price", "effective_to must be after effective_from",
+			"variant_id is required", "sale price overlaps another sale", "a price is already scheduled at that time":
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 201, "success schedule price", res)
+}
+
+func (h *ProductHandler) CancelPrice(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	priceId, _ := strconv.Atoi(c.Param("priceId"))
+	ctx := c.Request.Context()
+
+	if err := h.productUsecase.CancelPrice(ctx, int64(id), int64(priceId)); err != nil {
+		switch err.Error() {
+		case "product not found", "data not found":
+			utils.Response(c, 404, err.Error(), nil)
+			return
+		case "only scheduled prices can be cancelled":
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success cancel price", nil)
+}
@@ -0,0 +1,131 @@
+package product
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/mocks"
+	"kanggo/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetPrices(t *testing.T) {
+	mockProductUsecase := new(mocks.ProductUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockProductUsecase.On("GetPrices", mock.Anything, int64(1)).Return([]model.ProductPriceResponse{
+			{Id: 2, VariantId: 7, Price: 50000, EffectiveFrom: time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)},
+		}, nil)
+
+		httpReq, err := http.NewRequest(http.MethodGet, "/api/v1/product/1/prices", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewProductHandler(mockProductUsecase)
+
+		r.GET("/api/v1/product/:id/prices", h.GetPrices)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusOK, rr.Code)
+		mockProductUsecase.AssertExpectations(t)
+	})
+}
+
+func TestSchedulePrice(t *testing.T) {
+	mockProductUsecase := new(mocks.ProductUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		from := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2030, 1, 8, 0, 0, 0, 0, time.UTC)
+		request := model.ProductPriceRequest{Price: 45000, Sale: true, EffectiveFrom: from, EffectiveTo: &to}
+
+		mockProductUsecase.On("SchedulePrice", mock.Anything, int64(1), request).
+			Return(&model.ProductPriceResponse{Id: 12, VariantId: 7, Price: 45000, Sale: true, EffectiveFrom: from, EffectiveTo: &to, Scheduled: true}, nil)
+
+		body := []byte(`{"price": 45000, "sale": true, "effective_from": "2030-01-01T00:00:00Z", "effective_to": "2030-01-08T00:00:00Z"}`)
+		httpReq, err := http.NewRequest(http.MethodPost, "/api/v1/product/1/prices", bytes.NewBuffer(body))
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewProductHandler(mockProductUsecase)
+
+		r.POST("/api/v1/product/:id/prices", h.SchedulePrice)
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusCreated, rr.Code)
+		assert.EqualValues(t, "success schedule price", resp.Message)
+		mockProductUsecase.AssertExpectations(t)
+	})
+
+	t.Run("overlapping sale", func(t *testing.T) {
+		mockProductUsecase.On("SchedulePrice", mock.Anything, int64(2), mock.Anything).
+			Return(nil, errors.New("sale price overlaps another sale"))
+
+		body := []byte(`{"variant_id": 3, "price": 45000, "sale": true, "effective_to": "2030-01-08T00:00:00Z"}`)
+		httpReq, err := http.NewRequest(http.MethodPost, "/api/v1/product/2/prices", bytes.NewBuffer(body))
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewProductHandler(mockProductUsecase)
+
+		r.POST("/api/v1/product/:id/prices", h.SchedulePrice)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("price required", func(t *testing.T) {
+		httpReq, err := http.NewRequest(http.MethodPost, "/api/v1/product/1/prices", bytes.NewBuffer([]byte(`{"sale": false}`)))
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewProductHandler(mockProductUsecase)
+
+		r.POST("/api/v1/product/:id/prices", h.SchedulePrice)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestCancelPrice(t *testing.T) {
+	mockProductUsecase := new(mocks.ProductUsecase)
+
+	t.Run("already in effect", func(t *testing.T) {
+		mockProductUsecase.On("CancelPrice", mock.Anything, int64(1), int64(2)).
+			Return(errors.New("only scheduled prices can be cancelled"))
+
+		httpReq, err := http.NewRequest(http.MethodDelete, "/api/v1/product/1/prices/2", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewProductHandler(mockProductUsecase)
+
+		r.DELETE("/api/v1/product/:id/prices/:priceId", h.CancelPrice)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusBadRequest, rr.Code)
+		mockProductUsecase.AssertExpectations(t)
+	})
+}
@@ -2,10 +2,13 @@ package product
 
 import (
 	"kanggo/pkg/entity/model"
+	"kanggo/pkg/entity/schema"
 	"kanggo/pkg/middleware"
+	"kanggo/pkg/search"
 	"kanggo/pkg/usecase/product"
 	"kanggo/utils"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -23,15 +26,41 @@ func NewProductHandler(productUsecase product.ProductUsecase) *ProductHandler {
 	}
 }
 
-func (h *ProductHandler) Route(app *gin.Engine) {
+func (h *ProductHandler) Route(app *gin.Engine, accounts middleware.AccountLookup) {
 	v1 := app.Group("api/v1")
 	{
 		{
-			v1.POST("/product", middleware.RoleAdmin(), h.Insert)
-			v1.GET("/product", middleware.RoleUser(), h.GetAll)
-			v1.GET("/product/:id", middleware.RoleUser(), h.GetById)
-			v1.PUT("/product/:id", middleware.RoleAdmin(), h.Update)
-			v1.DELETE("/product/:id", middleware.RoleAdmin(), h.Delete)
+			v1.POST("/product", middleware.RoleAdmin(accounts), h.Insert)
+			v1.GET("/product", middleware.RoleUser(accounts), h.GetAll)
+			v1.GET("/product/search", middleware.RoleUser(accounts), h.Search)
+			v1.GET("/category/:slug/products", middleware.RoleUser(accounts), h.GetByCategory)
+			v1.GET("/product/:id", middleware.RoleUser(accounts), h.GetById)
+			v1.PUT("/product/:id", middleware.RoleAdmin(accounts), h.Update)
+			v1.PATCH("/product/:id", middleware.RoleAdmin(accounts), h.Patch)
+			v1.DELETE("/product/:id", middleware.RoleAdmin(accounts), h.Delete)
+
+			v1.POST("/product/import", middleware.RoleAdmin(accounts), h.ImportProducts)
+			v1.GET("/product/import/:jobId", middleware.RoleAdmin(accounts), h.GetImportJob)
+			v1.GET("/product/export", middleware.RoleAdmin(accounts), h.ExportProducts)
+
+			v1.GET("/product/trash", middleware.RoleAdmin(accounts), h.GetTrash)
+			v1.DELETE("/product/trash", middleware.RoleAdmin(accounts), h.Purge)
+			v1.POST("/product/:id/restore", middleware.RoleAdmin(accounts), h.Restore)
+
+			v1.PUT("/product/:id/options", middleware.RoleAdmin(accounts), h.SetOptions)
+			v1.GET("/product/:id/variant", middleware.RoleUser(accounts), h.GetVariants)
+			v1.POST("/product/:id/variant", middleware.RoleAdmin(accounts), h.InsertVariant)
+			v1.PUT("/product/:id/variant/:variantId", middleware.RoleAdmin(accounts), h.UpdateVariant)
+			v1.DELETE("/product/:id/variant/:variantId", middleware.RoleAdmin(accounts), h.DeleteVariant)
+
+			v1.POST("/product/:id/image", middleware.RoleAdmin(accounts), h.UploadImage)
+			v1.GET("/product/:id/image", middleware.RoleUser(accounts), h.GetImages)
+			v1.PUT("/product/:id/image", middleware.RoleAdmin(accounts), h.ReorderImages)
+			v1.DELETE("/product/:id/image/:imageId", middleware.RoleAdmin(accounts), h.DeleteImage)
+
+			v1.GET("/product/:id/prices", middleware.RoleAdmin(accounts), h.GetPrices)
+			v1.POST("/product/:id/prices", middleware.RoleAdmin(accounts), h.SchedulePrice)
+			v1.DELETE("/product/:id/prices/:priceId", middleware.RoleAdmin(accounts), h.CancelPrice)
 
 		}
 	}
@@ -54,6 +83,10 @@ func (h *ProductHandler) Insert(c *gin.Context) {
 	}
 
 	if err := h.productUsecase.Insert(ctx, product); err != nil {
+		if err.Error() == "sku already exists" || err.Error() == "barcode already exists" {
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		}
 		utils.Response(c, 500, err.Error(), nil)
 		return
 	}
@@ -61,12 +94,42 @@ func (h *ProductHandler) Insert(c *gin.Context) {
 	utils.Response(c, 201, "success insert product", nil)
 }
 
+var productSorts = []string{"id", "name", "price", "qty", "created_at"}
+
 func (h *ProductHandler) GetAll(c *gin.Context) {
+	query, err := utils.ParseListQuery(c, productSorts...)
+	if err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	h.list(c, query)
+}
+
+// GetByCategory lists the products of a category and its subcategories.
+func (h *ProductHandler) GetByCategory(c *gin.Context) {
+	query, err := utils.ParseListQuery(c, productSorts...)
+	if err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+	query.Category = c.Param("slug")
+
+	h.list(c, query)
+}
+
+// list hides draft and archived products from customers. Admins see every
+// product and may filter on status.
+func (h *ProductHandler) list(c *gin.Context, query model.ListQuery) {
 	ctx := c.Request.Context()
 
-	res, err := h.productUsecase.GetAll(ctx)
+	if !c.GetBool("admin") {
+		query.Status = schema.ProductActive
+	}
+
+	res, total, err := h.productUsecase.GetAll(ctx, query)
 	if err != nil {
-		if err.Error() == "data not found" {
+		if err.Error() == "category not found" {
 			utils.Response(c, 404, err.Error(), nil)
 			return
 		}
@@ -74,7 +137,12 @@ func (h *ProductHandler) GetAll(c *gin.Context) {
 		return
 	}
 
-	utils.Response(c, 200, "success", res)
+	var lastId int64
+	if len(res) > 0 {
+		lastId = int64(res[len(res)-1].Id)
+	}
+
+	utils.ResponseList(c, 200, "success", res, utils.NewMeta(query, total, len(res), lastId))
 }
 
 func (h *ProductHandler) GetById(c *gin.Context) {
@@ -90,6 +158,15 @@ func (h *ProductHandler) GetById(c *gin.Context) {
 		return
 	}
 
+	if res.Status != schema.ProductActive && !c.GetBool("admin") {
+		utils.Response(c, 404, "data not found", nil)
+		return
+	}
+
+	if utils.NotModified(c, res.Version) {
+		return
+	}
+
 	utils.Response(c, 200, "success", res)
 }
 
@@ -109,11 +186,75 @@ func (h *ProductHandler) Update(c *gin.Context) {
 		return
 	}
 
+	version, err := utils.IfMatch(c)
+	if err != nil {
+		utils.PreconditionResponse(c, err)
+		return
+	}
+	product.Version = version
+
 	if err := h.productUsecase.Update(ctx, uint(id), product); err != nil {
 		if err.Error() == "data not found" {
 			utils.Response(c, 404, err.Error(), nil)
 			return
 		}
+		if err.Error() == "version mismatch" {
+			utils.PreconditionResponse(c, utils.ErrPreconditionFailed)
+			return
+		}
+		if err.Error() == "barcode already exists" {
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success update product", nil)
+}
+
+// Patch applies a JSON merge patch (RFC 7386) to the product, so a field can
+// be set to zero or cleared without sending the whole product.
+func (h *ProductHandler) Patch(c *gin.Context) {
+	validate = validator.New()
+	id, _ := strconv.Atoi(c.Param("id"))
+	patch := model.ProductPatchRequest{}
+	ctx := c.Request.Context()
+
+	null, err := utils.BindMergePatch(c, &patch)
+	if err != nil {
+		if err == utils.ErrPatchType {
+			utils.Response(c, 415, err.Error(), nil)
+			return
+		}
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+	patch.Null = null
+
+	if err := validate.Struct(patch); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if patch.Version, err = utils.IfMatch(c); err != nil {
+		utils.PreconditionResponse(c, err)
+		return
+	}
+
+	if err := h.productUsecase.Patch(ctx, uint(id), patch); err != nil {
+		if err.Error() == "data not found" {
+			utils.Response(c, 404, err.Error(), nil)
+			return
+		}
+		if err.Error() == "version mismatch" {
+			utils.PreconditionResponse(c, utils.ErrPreconditionFailed)
+			return
+		}
+		if err.Error() == "barcode already exists" || strings.HasSuffix(err.Error(), "cannot be null") {
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		}
 		utils.Response(c, 500, err.Error(), nil)
 		return
 	}
@@ -125,14 +266,68 @@ func (h *ProductHandler) Delete(c *gin.Context) {
 	id, _ := strconv.Atoi(c.Param("id"))
 	ctx := c.Request.Context()
 
-	if err := h.productUsecase.Delete(ctx, int64(id)); err != nil {
+	version, err := utils.IfMatch(c)
+	if err != nil {
+		utils.PreconditionResponse(c, err)
+		return
+	}
+
+	if err := h.productUsecase.Delete(ctx, int64(id), version); err != nil {
 		if err.Error() == "data not found" {
 			utils.Response(c, 404, err.Error(), nil)
 			return
 		}
+		if err.Error() == "version mismatch" {
+			utils.PreconditionResponse(c, utils.ErrPreconditionFailed)
+			return
+		}
 		utils.Response(c, 500, err.Error(), nil)
 		return
 	}
 
 	utils.Response(c, 200, "success delete product", nil)
 }
+
+func (h *ProductHandler) Search(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	text := strings.TrimSpace(c.Query("q"))
+	if len(search.Terms(text)) == 0 {
+		utils.Response(c, 400, "q must contain a word of at least two characters", nil)
+		return
+	}
+
+	list, err := utils.ParseListQuery(c, "relevance")
+	if err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	query := search.Query{
+		Text:     text,
+		Page:     list.Page,
+		Size:     list.Size,
+		MinPrice: list.MinPrice,
+		MaxPrice: list.MaxPrice,
+	}
+	if v := c.Query("in_stock"); v != "" {
+		inStock, err := strconv.ParseBool(v)
+		if err != nil {
+			utils.Response(c, 400, "invalid in_stock", nil)
+			return
+		}
+		query.InStock = &inStock
+	}
+
+	res, total, err := h.productUsecase.Search(ctx, query, list.Category)
+	if err != nil {
+		if err.Error() == "category not found" {
+			utils.Response(c, 404, err.Error(), nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.ResponseList(c, 200, "success", res, utils.NewMeta(list, total, len(res.Hits), 0))
+}
@@ -4,11 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"kanggo/pkg/entity/model"
 	"kanggo/pkg/mocks"
+	"kanggo/pkg/search"
 	"kanggo/utils"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -76,7 +79,10 @@ func TestGetAll(t *testing.T) {
 			},
 		}
 
-		mockProductUsecase.On("GetAll", mock.Anything).Return(mockProductList, nil)
+		// customers only get to see active products
+		mockProductUsecase.On("GetAll", mock.Anything, mock.MatchedBy(func(query model.ListQuery) bool {
+			return query.Status == "active"
+		})).Return(mockProductList, int64(2), nil)
 
 		httpReq, err := http.NewRequest(http.MethodGet, "/api/v1/product", nil)
 		assert.Nil(t, err)
@@ -97,6 +103,8 @@ func TestGetAll(t *testing.T) {
 		assert.EqualValues(t, 200, resp.Status)
 		assert.EqualValues(t, "success", resp.Message)
 		assert.Equal(t, mockProductList[1].Name, "product 2")
+		assert.EqualValues(t, 2, resp.Meta.Total)
+		assert.EqualValues(t, 1, resp.Meta.Page)
 		mockProductUsecase.AssertExpectations(t)
 	})
 }
@@ -110,6 +118,8 @@ func TestGetById(t *testing.T) {
 			Name:      "product 1",
 			Price:     10000,
 			Qty:       10,
+			Status:    "active",
+			Version:   1,
 			CreatedAt: "2021-11-05 15:52:59 +0700 WIB",
 		}
 
@@ -135,6 +145,51 @@ func TestGetById(t *testing.T) {
 		assert.EqualValues(t, 200, resp.Status)
 		assert.EqualValues(t, "success", resp.Message)
 		assert.Equal(t, mockResponse.Price, float64(10000))
+		assert.Equal(t, `"1"`, rr.Header().Get("ETag"))
+		mockProductUsecase.AssertExpectations(t)
+	})
+
+	t.Run("not modified", func(t *testing.T) {
+		mockProductUsecase := new(mocks.ProductUsecase)
+		mockResponse := model.ProductResponse{Id: 1, Name: "product 1", Status: "active", Version: 6}
+
+		mockProductUsecase.On("GetById", mock.Anything, int64(1)).Return(&mockResponse, nil)
+
+		httpReq, err := http.NewRequest(http.MethodGet, "/api/v1/products/1", nil)
+		httpReq.Header.Set("If-None-Match", `"5", W/"6"`)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewProductHandler(mockProductUsecase)
+
+		r.GET("/api/v1/products/:id", h.GetById)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusNotModified, rr.Code)
+		assert.Empty(t, rr.Body.String())
+		assert.Equal(t, `"6"`, rr.Header().Get("ETag"))
+	})
+
+	t.Run("draft hidden from customers", func(t *testing.T) {
+		mockProductUsecase := new(mocks.ProductUsecase)
+		mockResponse := model.ProductResponse{Id: 2, Name: "product 2", Status: "draft"}
+
+		mockProductUsecase.On("GetById", mock.Anything, int64(2)).Return(&mockResponse, nil)
+
+		httpReq, err := http.NewRequest(http.MethodGet, "/api/v1/products/2", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewProductHandler(mockProductUsecase)
+
+		r.GET("/api/v1/products/:id", h.GetById)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusNotFound, rr.Code)
 		mockProductUsecase.AssertExpectations(t)
 	})
 }
@@ -144,9 +199,10 @@ func TestUpdate(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		mockRequest := model.ProductRequest{
-			Name:  "Produk 1",
-			Price: 10000,
-			Qty:   2,
+			Name:    "Produk 1",
+			Price:   10000,
+			Qty:     2,
+			Version: 3,
 		}
 
 		ctx := context.Background()
@@ -160,6 +216,7 @@ func TestUpdate(t *testing.T) {
 		httpReq, err := http.NewRequest(http.MethodPut, "/api/v1/product/"+id, bytes.NewReader(body))
 		httpReq.Header.Set("X-Custom-Header", "myvalue")
 		httpReq.Header.Set("Content-Type", "application/json")
+		httpReq.Header.Set("If-Match", `"3"`)
 		assert.Nil(t, err)
 
 		r := gin.Default()
@@ -179,16 +236,133 @@ func TestUpdate(t *testing.T) {
 		assert.EqualValues(t, "success update product", resp.Message)
 		mockProductUsecase.AssertExpectations(t)
 	})
+
+	t.Run("without If-Match", func(t *testing.T) {
+		body, err := json.Marshal(model.ProductRequest{Name: "Produk 1", Price: 10000})
+		assert.Nil(t, err)
+
+		httpReq, err := http.NewRequest(http.MethodPut, "/api/v1/product/1", bytes.NewReader(body))
+		httpReq.Header.Set("Content-Type", "application/json")
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewProductHandler(mockProductUsecase)
+
+		r.PUT("/api/v1/product/:id", h.Update)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusPreconditionRequired, rr.Code)
+	})
+}
+
+func TestPatch(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockProductUsecase := new(mocks.ProductUsecase)
+		qty := 0
+
+		mockProductUsecase.On("Patch", mock.Anything, uint(1), model.ProductPatchRequest{
+			Qty:     &qty,
+			Null:    []string{"barcode"},
+			Version: 5,
+		}).Return(nil)
+
+		httpReq, err := http.NewRequest(http.MethodPatch, "/api/v1/product/1", strings.NewReader(`{"qty": 0, "barcode": null}`))
+		httpReq.Header.Set("Content-Type", "application/merge-patch+json")
+		httpReq.Header.Set("If-Match", `"5"`)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewProductHandler(mockProductUsecase)
+
+		r.PATCH("/api/v1/product/:id", h.Patch)
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusOK, rr.Code)
+		assert.EqualValues(t, "success update product", resp.Message)
+		mockProductUsecase.AssertExpectations(t)
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		mockProductUsecase := new(mocks.ProductUsecase)
+
+		httpReq, err := http.NewRequest(http.MethodPatch, "/api/v1/product/1", strings.NewReader(`{"qty": -1}`))
+		httpReq.Header.Set("Content-Type", "application/merge-patch+json")
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewProductHandler(mockProductUsecase)
+
+		r.PATCH("/api/v1/product/:id", h.Patch)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusBadRequest, rr.Code)
+		mockProductUsecase.AssertNotCalled(t, "Patch")
+	})
+
+	t.Run("stale version", func(t *testing.T) {
+		mockProductUsecase := new(mocks.ProductUsecase)
+		price := 12000.0
+
+		mockProductUsecase.On("Patch", mock.Anything, uint(1), model.ProductPatchRequest{
+			Price:   &price,
+			Null:    []string{},
+			Version: 4,
+		}).Return(errors.New("version mismatch"))
+
+		httpReq, err := http.NewRequest(http.MethodPatch, "/api/v1/product/1", strings.NewReader(`{"price": 12000}`))
+		httpReq.Header.Set("Content-Type", "application/merge-patch+json")
+		httpReq.Header.Set("If-Match", `"4"`)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewProductHandler(mockProductUsecase)
+
+		r.PATCH("/api/v1/product/:id", h.Patch)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusPreconditionFailed, rr.Code)
+		mockProductUsecase.AssertExpectations(t)
+	})
+
+	t.Run("unsupported content type", func(t *testing.T) {
+		mockProductUsecase := new(mocks.ProductUsecase)
+
+		httpReq, err := http.NewRequest(http.MethodPatch, "/api/v1/product/1", strings.NewReader(`qty=0`))
+		httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewProductHandler(mockProductUsecase)
+
+		r.PATCH("/api/v1/product/:id", h.Patch)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusUnsupportedMediaType, rr.Code)
+	})
 }
 
 func TestDelete(t *testing.T) {
 	mockProductUsecase := new(mocks.ProductUsecase)
 
 	t.Run("success", func(t *testing.T) {
-		mockProductUsecase.On("Delete", mock.Anything, mock.AnythingOfType("int64")).Return(nil)
+		mockProductUsecase.On("Delete", mock.Anything, mock.AnythingOfType("int64"), uint(0)).Return(nil)
 
 		id := "1"
 		httpReq, err := http.NewRequest(http.MethodDelete, "/api/v1/product/"+id, nil)
+		httpReq.Header.Set("If-Match", "*")
 		assert.Nil(t, err)
 
 		r := gin.Default()
@@ -209,3 +383,56 @@ func TestDelete(t *testing.T) {
 		mockProductUsecase.AssertExpectations(t)
 	})
 }
+
+func TestSearch(t *testing.T) {
+	mockProductUsecase := new(mocks.ProductUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		inStock := true
+		query := search.Query{Text: "semen putih", Page: 1, Size: 20, InStock: &inStock}
+		mockResult := model.ProductSearchResponse{
+			Hits: []model.ProductSearchHit{
+				{
+					ProductResponse: model.ProductResponse{Id: 3, Name: "Semen Putih 40kg", Price: 45000, Qty: 20},
+					Score:           5,
+				},
+			},
+		}
+
+		mockProductUsecase.On("Search", mock.Anything, query, "").Return(&mockResult, int64(1), nil)
+
+		httpReq, err := http.NewRequest(http.MethodGet, "/api/v1/product/search?q=semen+putih&in_stock=true", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewProductHandler(mockProductUsecase)
+
+		r.GET("/api/v1/product/search", h.Search)
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusOK, rr.Code)
+		assert.EqualValues(t, "success", resp.Message)
+		assert.EqualValues(t, 1, resp.Meta.Total)
+		mockProductUsecase.AssertExpectations(t)
+	})
+
+	t.Run("missing query", func(t *testing.T) {
+		httpReq, err := http.NewRequest(http.MethodGet, "/api/v1/product/search?q=+", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewProductHandler(mockProductUsecase)
+
+		r.GET("/api/v1/product/search", h.Search)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusBadRequest, rr.Code)
+	})
+}
@@ -0,0 +1,73 @@
+package product
+
+import (
+	"kanggo/pkg/entity/model"
+	"kanggo/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+var trashSorts = []string{"deleted_at", "id", "name", "price", "qty", "created_at"}
+
+// GetTrash lists the deleted products, most recently deleted first unless
+// sorted otherwise.
+func (h *ProductHandler) GetTrash(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	query, err := utils.ParseListQuery(c, trashSorts...)
+	if err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+	if c.Query("sort") == "" {
+		query.Desc = true
+	}
+
+	res, total, err := h.productUsecase.GetTrash(ctx, query)
+	if err != nil {
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	var lastId int64
+	if len(res) > 0 {
+		lastId = int64(res[len(res)-1].Id)
+	}
+
+	utils.ResponseList(c, 200, "success", res, utils.NewMeta(query, total, len(res), lastId))
+}
+
+func (h *ProductHandler) Restore(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	ctx := c.Request.Context()
+
+	if err := h.productUsecase.Restore(ctx, int64(id)); err != nil {
+		switch err.Error() {
+		case "data not found":
+			utils.Response(c, 404, err.Error(), nil)
+			return
+		case "barcode already exists":
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success restore product", nil)
+}
+
+// Purge permanently deletes the products whose retention period in the trash
+// is over.
+func (h *ProductHandler) Purge(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	purged, err := h.productUsecase.Purge(ctx)
+	if err != nil {
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success purge products", model.ProductPurgeResponse{Purged: purged})
+}
@@ -0,0 +1,113 @@
+package product
+
+import (
+	"encoding/json"
+	"errors"
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/mocks"
+	"kanggo/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetTrash(t *testing.T) {
+	mockProductUsecase := new(mocks.ProductUsecase)
+
+	t.Run("newest first", func(t *testing.T) {
+		mockProductUsecase.On("GetTrash", mock.Anything, mock.MatchedBy(func(query model.ListQuery) bool {
+			return query.Sort == "deleted_at" && query.Desc
+		})).Return([]model.ProductResponse{
+			{Id: 3, Name: "Semen Putih 40kg", DeletedAt: "2022-03-01 10:00:00 +0000 UTC"},
+		}, int64(1), nil)
+
+		httpReq, err := http.NewRequest(http.MethodGet, "/api/v1/product/trash", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewProductHandler(mockProductUsecase)
+
+		r.GET("/api/v1/product/trash", h.GetTrash)
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusOK, rr.Code)
+		assert.EqualValues(t, 1, resp.Meta.Total)
+		mockProductUsecase.AssertExpectations(t)
+	})
+}
+
+func TestRestore(t *testing.T) {
+	mockProductUsecase := new(mocks.ProductUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockProductUsecase.On("Restore", mock.Anything, int64(3)).Return(nil)
+
+		httpReq, err := http.NewRequest(http.MethodPost, "/api/v1/product/3/restore", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewProductHandler(mockProductUsecase)
+
+		r.POST("/api/v1/product/:id/restore", h.Restore)
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusOK, rr.Code)
+		assert.EqualValues(t, "success restore product", resp.Message)
+	})
+
+	t.Run("barcode taken", func(t *testing.T) {
+		mockProductUsecase.On("Restore", mock.Anything, int64(4)).Return(errors.New("barcode already exists"))
+
+		httpReq, err := http.NewRequest(http.MethodPost, "/api/v1/product/4/restore", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewProductHandler(mockProductUsecase)
+
+		r.POST("/api/v1/product/:id/restore", h.Restore)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusBadRequest, rr.Code)
+	})
+
+	mockProductUsecase.AssertExpectations(t)
+}
+
+func TestPurge(t *testing.T) {
+	mockProductUsecase := new(mocks.ProductUsecase)
+	mockProductUsecase.On("Purge", mock.Anything).Return(int64(2), nil)
+
+	httpReq, err := http.NewRequest(http.MethodDelete, "/api/v1/product/trash", nil)
+	assert.Nil(t, err)
+
+	r := gin.Default()
+	rr := httptest.NewRecorder()
+
+	h := NewProductHandler(mockProductUsecase)
+
+	r.DELETE("/api/v1/product/trash", h.Purge)
+	r.ServeHTTP(rr, httpReq)
+
+	var resp utils.Respond
+	err = json.Unmarshal(rr.Body.Bytes(), &resp)
+	assert.Nil(t, err)
+	assert.EqualValues(t, http.StatusOK, rr.Code)
+	assert.Equal(t, map[string]interface{}{"purged": float64(2)}, resp.Data)
+	mockProductUsecase.AssertExpectations(t)
+}
@@ -0,0 +1,126 @@
+package product
+
+import (
+	"kanggo/pkg/entity/model"
+	"kanggo/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// variantError maps the errors of the option and variant endpoints, which
+// share most of their failure cases.
+func variantError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "sql: no rows in result set":
+		utils.Response(c, 404, "data not found", nil)
+	case "data not found", "product not found":
+		utils.Response(c, 404, err.Error(), nil)
+	case "duplicate option name", "duplicate option value", "option is used by a variant",
+		"invalid variant option", "sku already exists", "variant already exists",
+		"variant has orders", "product must have at least one variant":
+		utils.Response(c, 400, err.Error(), nil)
+	default:
+		utils.Response(c, 500, err.Error(), nil)
+	}
+}
+
+func (h *ProductHandler) SetOptions(c *gin.Context) {
+	validate = validator.New()
+	id, _ := strconv.Atoi(c.Param("id"))
+	request := model.ProductOptionsRequest{}
+	ctx := c.Request.Context()
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := validate.Struct(request); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := h.productUsecase.SetOptions(ctx, int64(id), request); err != nil {
+		variantError(c, err)
+		return
+	}
+
+	utils.Response(c, 200, "success update product options", nil)
+}
+
+func (h *ProductHandler) GetVariants(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	ctx := c.Request.Context()
+
+	res, err := h.productUsecase.GetVariants(ctx, int64(id))
+	if err != nil {
+		variantError(c, err)
+		return
+	}
+
+	utils.Response(c, 200, "success", res)
+}
+
+func (h *ProductHandler) InsertVariant(c *gin.Context) {
+	validate = validator.New()
+	id, _ := strconv.Atoi(c.Param("id"))
+	request := model.ProductVariantRequest{}
+	ctx := c.Request.Context()
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := validate.Struct(request); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := h.productUsecase.InsertVariant(ctx, int64(id), request); err != nil {
+		variantError(c, err)
+		return
+	}
+
+	utils.Response(c, 201, "success insert product variant", nil)
+}
+
+func (h *ProductHandler) UpdateVariant(c *gin.Context) {
+	validate = validator.New()
+	id, _ := strconv.Atoi(c.Param("id"))
+	variantId, _ := strconv.Atoi(c.Param("variantId"))
+	request := model.ProductVariantRequest{}
+	ctx := c.Request.Context()
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := validate.Struct(request); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := h.productUsecase.UpdateVariant(ctx, int64(id), int64(variantId), request); err != nil {
+		variantError(c, err)
+		return
+	}
+
+	utils.Response(c, 200, "success update product variant", nil)
+}
+
+func (h *ProductHandler) DeleteVariant(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	variantId, _ := strconv.Atoi(c.Param("variantId"))
+	ctx := c.Request.Context()
+
+	if err := h.productUsecase.DeleteVariant(ctx, int64(id), int64(variantId)); err != nil {
+		variantError(c, err)
+		return
+	}
+
+	utils.Response(c, 200, "success delete product variant", nil)
+}
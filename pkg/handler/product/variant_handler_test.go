@@ -0,0 +1,101 @@
+package product
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/mocks"
+	"kanggo/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestInsertVariant(t *testing.T) {
+	mockProductUsecase := new(mocks.ProductUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockRequest := model.ProductVariantRequest{
+			Sku:     "SEMEN-40KG",
+			Options: map[string]string{"Berat": "40kg"},
+			Price:   55000,
+			Qty:     20,
+			Weight:  40000,
+		}
+
+		mockProductUsecase.On("InsertVariant", mock.Anything, int64(1), mockRequest).Return(nil)
+
+		body, err := json.Marshal(mockRequest)
+		assert.Nil(t, err)
+
+		httpReq, err := http.NewRequest(http.MethodPost, "/api/v1/product/1/variant", bytes.NewReader(body))
+		httpReq.Header.Set("Content-Type", "application/json")
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewProductHandler(mockProductUsecase)
+
+		r.POST("/api/v1/product/:id/variant", h.InsertVariant)
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusCreated, rr.Code)
+		assert.EqualValues(t, "success insert product variant", resp.Message)
+		mockProductUsecase.AssertExpectations(t)
+	})
+
+	t.Run("missing sku", func(t *testing.T) {
+		body, err := json.Marshal(model.ProductVariantRequest{Price: 55000})
+		assert.Nil(t, err)
+
+		httpReq, err := http.NewRequest(http.MethodPost, "/api/v1/product/1/variant", bytes.NewReader(body))
+		httpReq.Header.Set("Content-Type", "application/json")
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewProductHandler(mockProductUsecase)
+
+		r.POST("/api/v1/product/:id/variant", h.InsertVariant)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestDeleteVariant(t *testing.T) {
+	mockProductUsecase := new(mocks.ProductUsecase)
+
+	t.Run("last variant", func(t *testing.T) {
+		mockProductUsecase.On("DeleteVariant", mock.Anything, int64(1), int64(3)).
+			Return(errors.New("product must have at least one variant"))
+
+		httpReq, err := http.NewRequest(http.MethodDelete, "/api/v1/product/1/variant/3", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewProductHandler(mockProductUsecase)
+
+		r.DELETE("/api/v1/product/:id/variant/:variantId", h.DeleteVariant)
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusBadRequest, rr.Code)
+		assert.EqualValues(t, "product must have at least one variant", resp.Message)
+		mockProductUsecase.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,339 @@
+package purchase
+
+import (
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/middleware"
+	"kanggo/pkg/usecase/purchase"
+	"kanggo/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+var validate *validator.Validate
+
+var purchaseSorts = []string{"id", "created_at", "total", "status"}
+
+type PurchaseHandler struct {
+	purchaseUsecase purchase.PurchaseUsecase
+}
+
+func NewPurchaseHandler(purchaseUsecase purchase.PurchaseUsecase) *PurchaseHandler {
+	return &PurchaseHandler{
+		purchaseUsecase: purchaseUsecase,
+	}
+}
+
+func (h *PurchaseHandler) Route(app *gin.Engine, accounts middleware.AccountLookup) {
+	v1 := app.Group("api/v1")
+	{
+		{
+			v1.POST("/supplier", middleware.RoleAdmin(accounts), h.InsertSupplier)
+			v1.GET("/supplier", middleware.RoleAdmin(accounts), h.GetSuppliers)
+			v1.GET("/supplier/:id", middleware.RoleAdmin(accounts), h.GetSupplierById)
+			v1.PUT("/supplier/:id", middleware.RoleAdmin(accounts), h.UpdateSupplier)
+			v1.DELETE("/supplier/:id", middleware.RoleAdmin(accounts), h.DeleteSupplier)
+		}
+		{
+			v1.POST("/purchase-order", middleware.RoleAdmin(accounts), h.Insert)
+			v1.GET("/purchase-order", middleware.RoleAdmin(accounts), h.GetAll)
+			v1.GET("/purchase-order/:id", middleware.RoleAdmin(accounts), h.GetById)
+			v1.PUT("/purchase-order/:id", middleware.RoleAdmin(accounts), h.Update)
+			v1.DELETE("/purchase-order/:id", middleware.RoleAdmin(accounts), h.Delete)
+			v1.POST("/purchase-order/:id/send", middleware.RoleAdmin(accounts), h.Send)
+			v1.POST("/purchase-order/:id/receive", middleware.RoleAdmin(accounts), h.Receive)
+		}
+	}
+
+}
+
+func (h *PurchaseHandler) InsertSupplier(c *gin.Context) {
+	validate = validator.New()
+	supplier := model.SupplierRequest{}
+	ctx := c.Request.Context()
+
+	if err := c.ShouldBindJSON(&supplier); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := validate.Struct(supplier); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := h.purchaseUsecase.InsertSupplier(ctx, supplier); err != nil {
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 201, "success insert supplier", nil)
+}
+
+func (h *PurchaseHandler) GetSuppliers(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	res, err := h.purchaseUsecase.GetSuppliers(ctx)
+	if err != nil {
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success", res)
+}
+
+func (h *PurchaseHandler) GetSupplierById(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	ctx := c.Request.Context()
+
+	res, err := h.purchaseUsecase.GetSupplierById(ctx, int64(id))
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			utils.Response(c, 404, "data not found", nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success", res)
+}
+
+func (h *PurchaseHandler) UpdateSupplier(c *gin.Context) {
+	validate = validator.New()
+	id, _ := strconv.Atoi(c.Param("id"))
+	supplier := model.SupplierRequest{}
+	ctx := c.Request.Context()
+
+	if err := c.ShouldBindJSON(&supplier); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := validate.Struct(supplier); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := h.purchaseUsecase.UpdateSupplier(ctx, int64(id), supplier); err != nil {
+		if err.Error() == "data not found" {
+			utils.Response(c, 404, err.Error(), nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success update supplier", nil)
+}
+
+func (h *PurchaseHandler) DeleteSupplier(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	ctx := c.Request.Context()
+
+	if err := h.purchaseUsecase.DeleteSupplier(ctx, int64(id)); err != nil {
+		switch err.Error() {
+		case "supplier has purchase orders":
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		case "data not found":
+			utils.Response(c, 404, err.Error(), nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success delete supplier", nil)
+}
+
+func (h *PurchaseHandler) Insert(c *gin.Context) {
+	validate = validator.New()
+	order := model.PurchaseOrderRequest{}
+	ctx := c.Request.Context()
+
+	if err := c.ShouldBindJSON(&order); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := validate.Struct(order); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	res, err := h.purchaseUsecase.Insert(ctx, order)
+	if err != nil {
+		if isRejection(err) {
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 201, "success insert purchase order", res)
+}
+
+func (h *PurchaseHandler) GetAll(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	query, err := utils.ParseListQuery(c, purchaseSorts...)
+	if err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	res, total, err := h.purchaseUsecase.GetAll(ctx, query)
+	if err != nil {
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	var lastId int64
+	if len(res) > 0 {
+		lastId = res[len(res)-1].Id
+	}
+
+	utils.ResponseList(c, 200, "success", res, utils.NewMeta(query, total, len(res), lastId))
+}
+
+func (h *PurchaseHandler) GetById(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	ctx := c.Request.Context()
+
+	res, err := h.purchaseUsecase.GetById(ctx, int64(id))
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			utils.Response(c, 404, "data not found", nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success", res)
+}
+
+func (h *PurchaseHandler) Update(c *gin.Context) {
+	validate = validator.New()
+	id, _ := strconv.Atoi(c.Param("id"))
+	order := model.PurchaseOrderRequest{}
+	ctx := c.Request.Context()
+
+	if err := c.ShouldBindJSON(&order); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := validate.Struct(order); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := h.purchaseUsecase.Update(ctx, int64(id), order); err != nil {
+		if err.Error() == "data not found" {
+			utils.Response(c, 404, err.Error(), nil)
+			return
+		}
+		if isRejection(err) {
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success update purchase order", nil)
+}
+
+func (h *PurchaseHandler) Delete(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	ctx := c.Request.Context()
+
+	if err := h.purchaseUsecase.Delete(ctx, int64(id)); err != nil {
+		switch err.Error() {
+		case "only draft purchase orders can be deleted":
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		case "data not found":
+			utils.Response(c, 404, err.Error(), nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success delete purchase order", nil)
+}
+
+func (h *PurchaseHandler) Send(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	ctx := c.Request.Context()
+
+	if err := h.purchaseUsecase.Send(ctx, int64(id)); err != nil {
+		switch err.Error() {
+		case "only draft purchase orders can be sent":
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		case "data not found":
+			utils.Response(c, 404, err.Error(), nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success send purchase order", nil)
+}
+
+// Receive records goods delivered against a sent purchase order and adds
+// them to the stock of its warehouse.
+func (h *PurchaseHandler) Receive(c *gin.Context) {
+	validate = validator.New()
+	id, _ := strconv.Atoi(c.Param("id"))
+	userId := c.MustGet("user_id").(uint64)
+	receive := model.ReceiveRequest{}
+	ctx := c.Request.Context()
+
+	if err := c.ShouldBindJSON(&receive); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := validate.Struct(receive); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	res, err := h.purchaseUsecase.Receive(ctx, int64(id), userId, receive)
+	if err != nil {
+		switch err.Error() {
+		case "purchase order is not awaiting goods", "line not found", "received qty exceeds the ordered qty",
+			"a line can only be received once per delivery":
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		case "data not found":
+			utils.Response(c, 404, err.Error(), nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success receive goods", res)
+}
+
+// isRejection tells the errors of a purchase order the admin can fix from
+// those of the server.
+func isRejection(err error) bool {
+	switch err.Error() {
+	case "supplier not found", "supplier is inactive", "warehouse not found", "warehouse is inactive",
+		"no warehouse to receive the goods into", "variant not found", "a variant can only be listed once",
+		"only draft purchase orders can be changed":
+		return true
+	}
+
+	return false
+}
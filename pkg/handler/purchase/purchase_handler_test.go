@@ -0,0 +1,194 @@
+package purchase
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/mocks"
+	"kanggo/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestInsert(t *testing.T) {
+	mockPurchaseUsecase := new(mocks.PurchaseUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockRequest := model.PurchaseOrderRequest{SupplierId: 1, Lines: []model.PurchaseOrderLineRequest{{VariantId: 3, Qty: 100, UnitCost: 52000}}}
+		mockPurchaseUsecase.On("Insert", mock.Anything, mockRequest).
+			Return(&model.PurchaseOrderResponse{Id: 7, Number: "PO-000007", Status: "draft", Total: 5200000}, nil)
+
+		body, err := json.Marshal(mockRequest)
+		assert.Nil(t, err)
+
+		httpReq, err := http.NewRequest(http.MethodPost, "/api/v1/purchase-order", bytes.NewReader(body))
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewPurchaseHandler(mockPurchaseUsecase)
+
+		r.POST("/api/v1/purchase-order", h.Insert)
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusCreated, rr.Code)
+		assert.EqualValues(t, "success insert purchase order", resp.Message)
+		mockPurchaseUsecase.AssertExpectations(t)
+	})
+
+	t.Run("without lines", func(t *testing.T) {
+		body := []byte(`{"supplier_id":1,"lines":[]}`)
+
+		httpReq, err := http.NewRequest(http.MethodPost, "/api/v1/purchase-order", bytes.NewReader(body))
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewPurchaseHandler(mockPurchaseUsecase)
+
+		r.POST("/api/v1/purchase-order", h.Insert)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("inactive supplier", func(t *testing.T) {
+		mockPurchaseUsecase.On("Insert", mock.Anything, mock.MatchedBy(func(data model.PurchaseOrderRequest) bool {
+			return data.SupplierId == 5
+		})).Return(nil, errors.New("supplier is inactive"))
+
+		body := []byte(`{"supplier_id":5,"lines":[{"variant_id":3,"qty":1}]}`)
+
+		httpReq, err := http.NewRequest(http.MethodPost, "/api/v1/purchase-order", bytes.NewReader(body))
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewPurchaseHandler(mockPurchaseUsecase)
+
+		r.POST("/api/v1/purchase-order", h.Insert)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestGetAll(t *testing.T) {
+	mockPurchaseUsecase := new(mocks.PurchaseUsecase)
+
+	t.Run("by status", func(t *testing.T) {
+		query := model.ListQuery{Page: 1, Size: 1, Sort: "id", Status: "sent"}
+		mockPurchaseUsecase.On("GetAll", mock.Anything, query).
+			Return([]model.PurchaseOrderResponse{{Id: 7, Number: "PO-000007", Status: "sent"}}, int64(3), nil)
+
+		httpReq, err := http.NewRequest(http.MethodGet, "/api/v1/purchase-order?status=sent&size=1", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewPurchaseHandler(mockPurchaseUsecase)
+
+		r.GET("/api/v1/purchase-order", h.GetAll)
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "7", resp.Meta.NextCursor)
+		mockPurchaseUsecase.AssertExpectations(t)
+	})
+}
+
+func TestReceive(t *testing.T) {
+	mockPurchaseUsecase := new(mocks.PurchaseUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockRequest := model.ReceiveRequest{Lines: []model.ReceiveLineRequest{{LineId: 1, Qty: 60}}, Note: "DO-88123"}
+		mockPurchaseUsecase.On("Receive", mock.Anything, int64(7), uint64(1), mockRequest).
+			Return(&model.PurchaseOrderResponse{Id: 7, Status: "partially_received"}, nil)
+
+		body, err := json.Marshal(mockRequest)
+		assert.Nil(t, err)
+
+		httpReq, err := http.NewRequest(http.MethodPost, "/api/v1/purchase-order/7/receive", bytes.NewReader(body))
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewPurchaseHandler(mockPurchaseUsecase)
+
+		r.POST("/api/v1/purchase-order/:id/receive", func(c *gin.Context) {
+			c.Set("user_id", uint64(1))
+			h.Receive(c)
+		})
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusOK, rr.Code)
+		assert.EqualValues(t, "success receive goods", resp.Message)
+		mockPurchaseUsecase.AssertExpectations(t)
+	})
+
+	t.Run("more than ordered", func(t *testing.T) {
+		mockPurchaseUsecase.On("Receive", mock.Anything, int64(8), uint64(1), mock.Anything).
+			Return(nil, errors.New("received qty exceeds the ordered qty"))
+
+		body := []byte(`{"lines":[{"line_id":4,"qty":500}]}`)
+
+		httpReq, err := http.NewRequest(http.MethodPost, "/api/v1/purchase-order/8/receive", bytes.NewReader(body))
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewPurchaseHandler(mockPurchaseUsecase)
+
+		r.POST("/api/v1/purchase-order/:id/receive", func(c *gin.Context) {
+			c.Set("user_id", uint64(1))
+			h.Receive(c)
+		})
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("unknown purchase order", func(t *testing.T) {
+		mockPurchaseUsecase.On("Receive", mock.Anything, int64(99), uint64(1), mock.Anything).
+			Return(nil, errors.New("data not found"))
+
+		body := []byte(`{"lines":[{"line_id":1,"qty":1}]}`)
+
+		httpReq, err := http.NewRequest(http.MethodPost, "/api/v1/purchase-order/99/receive", bytes.NewReader(body))
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewPurchaseHandler(mockPurchaseUsecase)
+
+		r.POST("/api/v1/purchase-order/:id/receive", func(c *gin.Context) {
+			c.Set("user_id", uint64(1))
+			h.Receive(c)
+		})
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusNotFound, rr.Code)
+	})
+}
@@ -0,0 +1,158 @@
+package review
+
+import (
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/middleware"
+	"kanggo/pkg/usecase/review"
+	"kanggo/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+var validate *validator.Validate
+
+var reviewSorts = []string{"id", "rating"}
+
+type ReviewHandler struct {
+	reviewUsecase review.ReviewUsecase
+}
+
+func NewReviewHandler(reviewUsecase review.ReviewUsecase) *ReviewHandler {
+	return &ReviewHandler{
+		reviewUsecase: reviewUsecase,
+	}
+}
+
+func (h *ReviewHandler) Route(app *gin.Engine, accounts middleware.AccountLookup) {
+	v1 := app.Group("api/v1")
+	{
+		{
+			v1.POST("/product/:id/review", middleware.RoleUser(accounts), h.Insert)
+			v1.GET("/product/:id/review", middleware.RoleUser(accounts), h.GetByProduct)
+			v1.GET("/review", middleware.RoleAdmin(accounts), h.GetAll)
+			v1.PUT("/review/:id/approve", middleware.RoleAdmin(accounts), h.Approve)
+			v1.PUT("/review/:id/hide", middleware.RoleAdmin(accounts), h.Hide)
+		}
+	}
+
+}
+
+func (h *ReviewHandler) Insert(c *gin.Context) {
+	validate = validator.New()
+	id, _ := strconv.Atoi(c.Param("id"))
+	userId := c.MustGet("user_id").(uint64)
+	review := model.ReviewRequest{}
+	ctx := c.Request.Context()
+
+	if err := c.ShouldBindJSON(&review); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := validate.Struct(review); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	res, err := h.reviewUsecase.Insert(ctx, int64(id), userId, review)
+	if err != nil {
+		switch err.Error() {
+		case "only buyers of the product can review it", "product already reviewed":
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		case "product not found":
+			utils.Response(c, 404, err.Error(), nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 201, "success insert review", res)
+}
+
+// GetByProduct lists the approved reviews of a product.
+func (h *ReviewHandler) GetByProduct(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	ctx := c.Request.Context()
+
+	query, err := utils.ParseListQuery(c, reviewSorts...)
+	if err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	res, total, err := h.reviewUsecase.GetByProduct(ctx, int64(id), query)
+	if err != nil {
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	var lastId int64
+	if len(res) > 0 {
+		lastId = res[len(res)-1].Id
+	}
+
+	utils.ResponseList(c, 200, "success", res, utils.NewMeta(query, total, len(res), lastId))
+}
+
+// GetAll lists the reviews to moderate. The status query narrows them to
+// pending, approved or hidden ones.
+func (h *ReviewHandler) GetAll(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	query, err := utils.ParseListQuery(c, reviewSorts...)
+	if err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	res, total, err := h.reviewUsecase.GetAll(ctx, query)
+	if err != nil {
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	var lastId int64
+	if len(res) > 0 {
+		lastId = res[len(res)-1].Id
+	}
+
+	utils.ResponseList(c, 200, "success", res, utils.NewMeta(query, total, len(res), lastId))
+}
+
+func (h *ReviewHandler) Approve(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	userId := c.MustGet("user_id").(uint64)
+	ctx := c.Request.Context()
+
+	if err := h.reviewUsecase.Approve(ctx, int64(id), userId); err != nil {
+		if err.Error() == "data not found" {
+			utils.Response(c, 404, err.Error(), nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success approve review", nil)
+}
+
+func (h *ReviewHandler) Hide(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	userId := c.MustGet("user_id").(uint64)
+	ctx := c.Request.Context()
+
+	if err := h.reviewUsecase.Hide(ctx, int64(id), userId); err != nil {
+		if err.Error() == "data not found" {
+			utils.Response(c, 404, err.Error(), nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success hide review", nil)
+}
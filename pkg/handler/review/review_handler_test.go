@@ -0,0 +1,147 @@
+package review
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/mocks"
+	"kanggo/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestInsert(t *testing.T) {
+	mockReviewUsecase := new(mocks.ReviewUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockRequest := model.ReviewRequest{Rating: 5, Text: "Semennya cepat kering"}
+		mockReviewUsecase.On("Insert", mock.Anything, int64(1), uint64(2), mockRequest).
+			Return(&model.ReviewResponse{Id: 9, ProductId: 1, UserId: 2, Rating: 5, Status: "pending"}, nil)
+
+		body, err := json.Marshal(mockRequest)
+		assert.Nil(t, err)
+
+		httpReq, err := http.NewRequest(http.MethodPost, "/api/v1/product/1/review", bytes.NewReader(body))
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewReviewHandler(mockReviewUsecase)
+
+		r.POST("/api/v1/product/:id/review", func(c *gin.Context) {
+			c.Set("user_id", uint64(2))
+			h.Insert(c)
+		})
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusCreated, rr.Code)
+		assert.EqualValues(t, "success insert review", resp.Message)
+		mockReviewUsecase.AssertExpectations(t)
+	})
+
+	t.Run("rating out of range", func(t *testing.T) {
+		body := []byte(`{"rating":6,"text":"Mantap"}`)
+
+		httpReq, err := http.NewRequest(http.MethodPost, "/api/v1/product/1/review", bytes.NewReader(body))
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewReviewHandler(mockReviewUsecase)
+
+		r.POST("/api/v1/product/:id/review", func(c *gin.Context) {
+			c.Set("user_id", uint64(2))
+			h.Insert(c)
+		})
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("already reviewed", func(t *testing.T) {
+		mockReviewUsecase.On("Insert", mock.Anything, int64(1), uint64(3), mock.Anything).
+			Return(nil, errors.New("product already reviewed"))
+
+		body := []byte(`{"rating":4}`)
+
+		httpReq, err := http.NewRequest(http.MethodPost, "/api/v1/product/1/review", bytes.NewReader(body))
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewReviewHandler(mockReviewUsecase)
+
+		r.POST("/api/v1/product/:id/review", func(c *gin.Context) {
+			c.Set("user_id", uint64(3))
+			h.Insert(c)
+		})
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestGetByProduct(t *testing.T) {
+	mockReviewUsecase := new(mocks.ReviewUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		query := model.ListQuery{Page: 1, Size: 1, Sort: "id"}
+		mockReviewUsecase.On("GetByProduct", mock.Anything, int64(1), query).
+			Return([]model.ReviewResponse{{Id: 9, ProductId: 1, Rating: 5, Status: "approved"}}, int64(3), nil)
+
+		httpReq, err := http.NewRequest(http.MethodGet, "/api/v1/product/1/review?size=1", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewReviewHandler(mockReviewUsecase)
+
+		r.GET("/api/v1/product/:id/review", h.GetByProduct)
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "9", resp.Meta.NextCursor)
+		mockReviewUsecase.AssertExpectations(t)
+	})
+}
+
+func TestHide(t *testing.T) {
+	mockReviewUsecase := new(mocks.ReviewUsecase)
+
+	t.Run("unknown review", func(t *testing.T) {
+		mockReviewUsecase.On("Hide", mock.Anything, int64(99), uint64(1)).Return(errors.New("data not found"))
+
+		httpReq, err := http.NewRequest(http.MethodPut, "/api/v1/review/99/hide", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewReviewHandler(mockReviewUsecase)
+
+		r.PUT("/api/v1/review/:id/hide", func(c *gin.Context) {
+			c.Set("user_id", uint64(1))
+			h.Hide(c)
+		})
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusNotFound, rr.Code)
+		mockReviewUsecase.AssertExpectations(t)
+	})
+}
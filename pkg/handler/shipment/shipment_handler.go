@@ -0,0 +1,131 @@
+package shipment
+
+import (
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/middleware"
+	"kanggo/pkg/usecase/order"
+	"kanggo/pkg/usecase/shipment"
+	"kanggo/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+var validate *validator.Validate
+
+type ShipmentHandler struct {
+	shipmentUsecase shipment.ShipmentUsecase
+	orderUsecase    order.OrderUsecase
+}
+
+func NewShipmentHandler(shipmentUsecase shipment.ShipmentUsecase, orderUsecase order.OrderUsecase) *ShipmentHandler {
+	return &ShipmentHandler{
+		shipmentUsecase: shipmentUsecase,
+		orderUsecase:    orderUsecase,
+	}
+}
+
+func (h *ShipmentHandler) Route(app *gin.Engine, accounts middleware.AccountLookup) {
+	v1 := app.Group("api/v1")
+	{
+		{
+			v1.POST("/order/:id/shipment", middleware.RoleAdmin(accounts), h.Insert)
+			v1.POST("/shipment/:id/events", middleware.RoleAdmin(accounts), h.InsertEvent)
+			v1.GET("/order/:id/tracking", middleware.RoleUser(accounts), h.GetTracking)
+		}
+	}
+
+}
+
+func (h *ShipmentHandler) Insert(c *gin.Context) {
+	validate = validator.New()
+	shipment := model.ShipmentRequest{}
+	ctx := c.Request.Context()
+
+	if err := c.ShouldBindJSON(&shipment); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := validate.Struct(shipment); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	id, ok := utils.OrderId(c, h.orderUsecase)
+	if !ok {
+		return
+	}
+
+	if err := h.shipmentUsecase.Insert(ctx, id, shipment); err != nil {
+		switch err.Error() {
+		case "order is not paid", "shipment already exists":
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		case "data not found":
+			utils.Response(c, 404, err.Error(), nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 201, "success insert shipment", nil)
+}
+
+// InsertEvent takes the shipment id, as a shipment has no number of its own.
+func (h *ShipmentHandler) InsertEvent(c *gin.Context) {
+	validate = validator.New()
+	id, _ := strconv.Atoi(c.Param("id"))
+	event := model.ShipmentEventRequest{}
+	userId := c.MustGet("user_id").(uint64)
+	ctx := c.Request.Context()
+
+	if err := c.ShouldBindJSON(&event); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := validate.Struct(event); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := h.shipmentUsecase.InsertEvent(ctx, int64(id), userId, event); err != nil {
+		switch err.Error() {
+		case "shipment already delivered":
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		case "data not found":
+			utils.Response(c, 404, err.Error(), nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 201, "success insert shipment event", nil)
+}
+
+func (h *ShipmentHandler) GetTracking(c *gin.Context) {
+	userId := c.MustGet("user_id").(uint64)
+	ctx := c.Request.Context()
+
+	id, ok := utils.OrderId(c, h.orderUsecase)
+	if !ok {
+		return
+	}
+
+	res, err := h.shipmentUsecase.GetTracking(ctx, id, userId)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			utils.Response(c, 404, "data not found", nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success", res)
+}
@@ -0,0 +1,111 @@
+package shipment
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/mocks"
+	"kanggo/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestInsert(t *testing.T) {
+	mockShipmentUsecase := new(mocks.ShipmentUsecase)
+	mockOrderUsecase := new(mocks.OrderUsecase)
+
+	t.Run("order not paid", func(t *testing.T) {
+		mockRequest := model.ShipmentRequest{
+			Courier:        "jne",
+			Service:        "REG",
+			TrackingNumber: "JNE0012345678",
+		}
+
+		mockShipmentUsecase.On("Insert", mock.Anything, int64(1), mockRequest).Return(errors.New("order is not paid"))
+
+		body, err := json.Marshal(mockRequest)
+		assert.Nil(t, err)
+
+		httpReq, err := http.NewRequest(http.MethodPost, "/api/v1/order/1/shipment", bytes.NewReader(body))
+		httpReq.Header.Set("Content-Type", "application/json")
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewShipmentHandler(mockShipmentUsecase, mockOrderUsecase)
+
+		r.POST("/api/v1/order/:id/shipment", h.Insert)
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusBadRequest, rr.Code)
+		assert.EqualValues(t, "order is not paid", resp.Message)
+		mockShipmentUsecase.AssertExpectations(t)
+	})
+}
+
+func TestGetTracking(t *testing.T) {
+	mockShipmentUsecase := new(mocks.ShipmentUsecase)
+	mockOrderUsecase := new(mocks.OrderUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockResponse := model.TrackingResponse{
+			OrderId:        1,
+			OrderStatus:    "shipped",
+			ShipmentId:     3,
+			Courier:        "jne",
+			TrackingNumber: "JNE0012345678",
+			Events:         []model.TrackingEvent{},
+		}
+
+		mockShipmentUsecase.On("GetTracking", mock.Anything, int64(1), uint64(1)).Return(&mockResponse, nil)
+
+		httpReq, err := http.NewRequest(http.MethodGet, "/api/v1/order/1/tracking", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewShipmentHandler(mockShipmentUsecase, mockOrderUsecase)
+
+		r.GET("/api/v1/order/:id/tracking", func(c *gin.Context) { c.Set("user_id", uint64(1)) }, h.GetTracking)
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusOK, rr.Code)
+		assert.EqualValues(t, "success", resp.Message)
+		mockShipmentUsecase.AssertExpectations(t)
+	})
+
+	t.Run("by number", func(t *testing.T) {
+		mockResponse := model.TrackingResponse{OrderId: 1, OrderStatus: "shipped", Events: []model.TrackingEvent{}}
+
+		mockOrderUsecase.On("GetOrderIdByNumber", mock.Anything, "KG-20261018-000001").Return(int64(1), nil).Once()
+		mockShipmentUsecase.On("GetTracking", mock.Anything, int64(1), uint64(1)).Return(&mockResponse, nil).Once()
+
+		httpReq, err := http.NewRequest(http.MethodGet, "/api/v1/order/KG-20261018-000001/tracking", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewShipmentHandler(mockShipmentUsecase, mockOrderUsecase)
+
+		r.GET("/api/v1/order/:id/tracking", func(c *gin.Context) { c.Set("user_id", uint64(1)) }, h.GetTracking)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusOK, rr.Code)
+		mockOrderUsecase.AssertExpectations(t)
+	})
+}
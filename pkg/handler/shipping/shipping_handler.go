@@ -0,0 +1,70 @@
+package shipping
+
+import (
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/middleware"
+	"kanggo/pkg/usecase/shipping"
+	"kanggo/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+var validate *validator.Validate
+
+type ShippingHandler struct {
+	shippingUsecase shipping.ShippingUsecase
+}
+
+func NewShippingHandler(shippingUsecase shipping.ShippingUsecase) *ShippingHandler {
+	return &ShippingHandler{
+		shippingUsecase: shippingUsecase,
+	}
+}
+
+func (h *ShippingHandler) Route(app *gin.Engine, accounts middleware.AccountLookup) {
+	v1 := app.Group("api/v1")
+	{
+		{
+			v1.POST("/shipping/quote", middleware.RoleUser(accounts), h.Quote)
+		}
+	}
+
+}
+
+func (h *ShippingHandler) Quote(c *gin.Context) {
+	validate = validator.New()
+	quote := model.ShippingQuoteRequest{}
+	userId := c.MustGet("user_id").(uint64)
+	ctx := c.Request.Context()
+
+	if err := c.ShouldBindJSON(&quote); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := validate.Struct(quote); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	res, err := h.shippingUsecase.Quote(ctx, userId, quote)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			utils.Response(c, 404, "address or product not found", nil)
+			return
+		}
+		if err.Error() == "variant not found" {
+			utils.Response(c, 404, err.Error(), nil)
+			return
+		}
+		if err.Error() == "not enough product quantity" {
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success", res)
+}
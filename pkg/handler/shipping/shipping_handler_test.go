@@ -0,0 +1,75 @@
+package shipping
+
+import (
+	"bytes"
+	"encoding/json"
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/mocks"
+	"kanggo/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestQuote(t *testing.T) {
+	mockShippingUsecase := new(mocks.ShippingUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockRequest := model.ShippingQuoteRequest{
+			AddressId: 1,
+			Items:     []model.ShippingItem{{ProductId: 1, Quantity: 2}},
+		}
+		mockResponse := []model.ShippingOption{
+			{Courier: "jne", Service: "CARGO", Cost: 600000, Etd: "5-8 days"},
+			{Courier: "jne", Service: "REG", Cost: 1100000, Etd: "2-3 days"},
+		}
+
+		mockShippingUsecase.On("Quote", mock.Anything, uint64(1), mockRequest).Return(mockResponse, nil)
+
+		body, err := json.Marshal(mockRequest)
+		assert.Nil(t, err)
+
+		httpReq, err := http.NewRequest(http.MethodPost, "/api/v1/shipping/quote", bytes.NewReader(body))
+		httpReq.Header.Set("Content-Type", "application/json")
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewShippingHandler(mockShippingUsecase)
+
+		r.POST("/api/v1/shipping/quote", func(c *gin.Context) { c.Set("user_id", uint64(1)) }, h.Quote)
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusOK, rr.Code)
+		assert.EqualValues(t, "success", resp.Message)
+		assert.Len(t, resp.Data, 2)
+		mockShippingUsecase.AssertExpectations(t)
+	})
+
+	t.Run("empty items", func(t *testing.T) {
+		body, err := json.Marshal(model.ShippingQuoteRequest{AddressId: 1})
+		assert.Nil(t, err)
+
+		httpReq, err := http.NewRequest(http.MethodPost, "/api/v1/shipping/quote", bytes.NewReader(body))
+		httpReq.Header.Set("Content-Type", "application/json")
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewShippingHandler(mockShippingUsecase)
+
+		r.POST("/api/v1/shipping/quote", func(c *gin.Context) { c.Set("user_id", uint64(1)) }, h.Quote)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusBadRequest, rr.Code)
+	})
+}
@@ -3,8 +3,10 @@ package user
 import (
 	"context"
 	"kanggo/pkg/entity/model"
+	"kanggo/pkg/middleware"
 	"kanggo/pkg/usecase/user"
 	"kanggo/utils"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -23,11 +25,23 @@ func NewUserhandler(userUsecase user.UserUsecase) *UserHandler {
 	}
 }
 
-func (h *UserHandler) Route(app *gin.Engine) {
+func (h *UserHandler) Route(app *gin.Engine, accounts middleware.AccountLookup) {
 	v1 := app.Group("api/v1")
 	{
 		v1.POST("/register", h.Insert)
 		v1.POST("/login", h.Login)
+		v1.GET("/me", middleware.RoleUser(accounts), h.GetProfile)
+		v1.PATCH("/me", middleware.RoleUser(accounts), h.UpdateProfile)
+		v1.POST("/me/email/verify", middleware.RoleUser(accounts), h.ConfirmEmail)
+		v1.PUT("/me/password", middleware.RoleUser(accounts), h.UpdatePassword)
+		v1.DELETE("/me", middleware.RoleUser(accounts), h.Delete)
+
+		v1.GET("/user", middleware.RoleAdmin(accounts), h.GetAll)
+		v1.GET("/user/:id", middleware.RoleAdmin(accounts), h.GetDetail)
+		v1.PUT("/user/:id/suspend", middleware.RoleAdmin(accounts), h.Suspend)
+		v1.PUT("/user/:id/reactivate", middleware.RoleAdmin(accounts), h.Reactivate)
+		v1.PUT("/user/:id/role", middleware.RoleAdmin(accounts), h.UpdateRole)
+		v1.PUT("/user/:id/password-reset", middleware.RoleAdmin(accounts), h.ForcePasswordReset)
 	}
 }
 
@@ -88,7 +102,12 @@ func (h *UserHandler) Login(c *gin.Context) {
 			utils.Response(c, 400, "invalid password or username", nil)
 			return
 		}
+		if res.UserResponse.Status == "suspended" {
+			utils.Response(c, 403, "account suspended", nil)
+			return
+		}
 		val = *res
+		admin = val.Role == "admin"
 	}
 
 	expired := time.Now().Local().Add(time.Minute * time.Duration(300)).Unix()
@@ -100,11 +119,281 @@ func (h *UserHandler) Login(c *gin.Context) {
 	result := &model.LoginResponse{
 		Token:   token,
 		Expired: expired,
+
+		PasswordResetRequired: val.PasswordResetRequired,
 	}
 
 	utils.Response(c, 200, "success", result)
 }
 
+func (h *UserHandler) GetProfile(c *gin.Context) {
+	userId := c.MustGet("user_id").(uint64)
+	ctx := c.Request.Context()
+
+	res, err := h.userUsecase.GetById(ctx, userId)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			utils.Response(c, 404, "data not found", nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success", res)
+}
+
+func (h *UserHandler) UpdateProfile(c *gin.Context) {
+	validate = validator.New()
+	profile := model.UpdateProfileRequest{}
+	userId := c.MustGet("user_id").(uint64)
+	ctx := c.Request.Context()
+
+	if err := c.ShouldBindJSON(&profile); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := validate.Struct(profile); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := h.userUsecase.UpdateProfile(ctx, userId, profile); err != nil {
+		switch err.Error() {
+		case "password is required to change email", "invalid password":
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		case "email already registered":
+			utils.Response(c, 409, err.Error(), nil)
+			return
+		case "sql: no rows in result set", "data not found":
+			utils.Response(c, 404, "data not found", nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success update profile", nil)
+}
+
+// ConfirmEmail applies the change of email started by UpdateProfile with the
+// token mailed to the new address.
+func (h *UserHandler) ConfirmEmail(c *gin.Context) {
+	validate = validator.New()
+	verify := model.VerifyEmailRequest{}
+	userId := c.MustGet("user_id").(uint64)
+	ctx := c.Request.Context()
+
+	if err := c.ShouldBindJSON(&verify); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := validate.Struct(verify); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := h.userUsecase.ConfirmEmail(ctx, userId, verify); err != nil {
+		switch err.Error() {
+		case "invalid or expired token":
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		case "email already registered":
+			utils.Response(c, 409, err.Error(), nil)
+			return
+		case "data not found":
+			utils.Response(c, 404, err.Error(), nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success confirm email", nil)
+}
+
+func (h *UserHandler) UpdatePassword(c *gin.Context) {
+	validate = validator.New()
+	password := model.ChangePasswordRequest{}
+	userId := c.MustGet("user_id").(uint64)
+	ctx := c.Request.Context()
+
+	if err := c.ShouldBindJSON(&password); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := validate.Struct(password); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := h.userUsecase.UpdatePassword(ctx, userId, password); err != nil {
+		switch err.Error() {
+		case "invalid password":
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		case "sql: no rows in result set", "data not found":
+			utils.Response(c, 404, "data not found", nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success update password", nil)
+}
+
+func (h *UserHandler) Delete(c *gin.Context) {
+	validate = validator.New()
+	account := model.DeleteAccountRequest{}
+	userId := c.MustGet("user_id").(uint64)
+	ctx := c.Request.Context()
+
+	if err := c.ShouldBindJSON(&account); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := validate.Struct(account); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := h.userUsecase.Delete(ctx, userId, account); err != nil {
+		switch err.Error() {
+		case "invalid password":
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		case "sql: no rows in result set", "data not found":
+			utils.Response(c, 404, "data not found", nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success delete account", nil)
+}
+
+func (h *UserHandler) GetAll(c *gin.Context) {
+	page, _ := strconv.Atoi(c.Query("page"))
+	size, _ := strconv.Atoi(c.Query("size"))
+	ctx := c.Request.Context()
+
+	res, err := h.userUsecase.GetAll(ctx, c.Query("search"), page, size)
+	if err != nil {
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success", res)
+}
+
+func (h *UserHandler) GetDetail(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	ctx := c.Request.Context()
+
+	res, err := h.userUsecase.GetDetail(ctx, uint64(id))
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			utils.Response(c, 404, "data not found", nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success", res)
+}
+
+func (h *UserHandler) Suspend(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	ctx := c.Request.Context()
+
+	if err := h.userUsecase.Suspend(ctx, uint64(id)); err != nil {
+		switch err.Error() {
+		case "user already suspended":
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		case "sql: no rows in result set", "data not found":
+			utils.Response(c, 404, "data not found", nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success suspend user", nil)
+}
+
+func (h *UserHandler) Reactivate(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	ctx := c.Request.Context()
+
+	if err := h.userUsecase.Reactivate(ctx, uint64(id)); err != nil {
+		switch err.Error() {
+		case "user already active":
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		case "sql: no rows in result set", "data not found":
+			utils.Response(c, 404, "data not found", nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success reactivate user", nil)
+}
+
+func (h *UserHandler) UpdateRole(c *gin.Context) {
+	validate = validator.New()
+	id, _ := strconv.Atoi(c.Param("id"))
+	role := model.UpdateRoleRequest{}
+	ctx := c.Request.Context()
+
+	if err := c.ShouldBindJSON(&role); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := validate.Struct(role); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := h.userUsecase.UpdateRole(ctx, uint64(id), role); err != nil {
+		if err.Error() == "sql: no rows in result set" || err.Error() == "data not found" {
+			utils.Response(c, 404, "data not found", nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success update role", nil)
+}
+
+func (h *UserHandler) ForcePasswordReset(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	ctx := c.Request.Context()
+
+	if err := h.userUsecase.ForcePasswordReset(ctx, uint64(id)); err != nil {
+		if err.Error() == "sql: no rows in result set" || err.Error() == "data not found" {
+			utils.Response(c, 404, "data not found", nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success force password reset", nil)
+}
+
 func (h *UserHandler) ValidateUser(ctx context.Context, email, pass string) (*model.ValidateResponse, error) {
 
 	res, err := h.userUsecase.GetByEmail(ctx, email)
@@ -3,6 +3,7 @@ package user
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"kanggo/pkg/entity/model"
 	"kanggo/pkg/mocks"
 	"kanggo/utils"
@@ -100,3 +101,130 @@ func TestLogin(t *testing.T) {
 		mockUserUsecase.AssertExpectations(t)
 	})
 }
+
+func TestGetProfile(t *testing.T) {
+	mockUserUsecase := new(mocks.UserUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockResponse := model.UserResponse{
+			Id:    1,
+			Name:  "agung",
+			Email: "agung@gmail.com",
+		}
+
+		mockUserUsecase.On("GetById", mock.Anything, uint64(1)).Return(&mockResponse, nil)
+
+		httpReq, err := http.NewRequest(http.MethodGet, "/api/v1/me", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewUserhandler(mockUserUsecase)
+
+		r.GET("/api/v1/me", func(c *gin.Context) { c.Set("user_id", uint64(1)) }, h.GetProfile)
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.NotNil(t, resp)
+		assert.EqualValues(t, http.StatusOK, rr.Code)
+		assert.EqualValues(t, 200, resp.Status)
+		assert.EqualValues(t, "success", resp.Message)
+		mockUserUsecase.AssertExpectations(t)
+	})
+}
+
+func TestUpdatePassword(t *testing.T) {
+	mockUserUsecase := new(mocks.UserUsecase)
+
+	t.Run("invalid password", func(t *testing.T) {
+		mockRequest := model.ChangePasswordRequest{
+			CurrentPassword: "wrong1234",
+			NewPassword:     "agung12345",
+		}
+
+		mockUserUsecase.On("UpdatePassword", mock.Anything, uint64(1), mockRequest).Return(errors.New("invalid password"))
+
+		body, err := json.Marshal(mockRequest)
+		assert.Nil(t, err)
+
+		httpReq, err := http.NewRequest(http.MethodPut, "/api/v1/me/password", bytes.NewReader(body))
+		httpReq.Header.Set("Content-Type", "application/json")
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewUserhandler(mockUserUsecase)
+
+		r.PUT("/api/v1/me/password", func(c *gin.Context) { c.Set("user_id", uint64(1)) }, h.UpdatePassword)
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusBadRequest, rr.Code)
+		assert.EqualValues(t, "invalid password", resp.Message)
+		mockUserUsecase.AssertExpectations(t)
+	})
+}
+
+func TestConfirmEmail(t *testing.T) {
+	mockUserUsecase := new(mocks.UserUsecase)
+
+	t.Run("expired token", func(t *testing.T) {
+		mockRequest := model.VerifyEmailRequest{Token: "abc123"}
+
+		mockUserUsecase.On("ConfirmEmail", mock.Anything, uint64(1), mockRequest).Return(errors.New("invalid or expired token"))
+
+		body, err := json.Marshal(mockRequest)
+		assert.Nil(t, err)
+
+		httpReq, err := http.NewRequest(http.MethodPost, "/api/v1/me/email/verify", bytes.NewReader(body))
+		httpReq.Header.Set("Content-Type", "application/json")
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewUserhandler(mockUserUsecase)
+
+		r.POST("/api/v1/me/email/verify", func(c *gin.Context) { c.Set("user_id", uint64(1)) }, h.ConfirmEmail)
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusBadRequest, rr.Code)
+		assert.EqualValues(t, "invalid or expired token", resp.Message)
+		mockUserUsecase.AssertExpectations(t)
+	})
+}
+
+func TestSuspend(t *testing.T) {
+	mockUserUsecase := new(mocks.UserUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockUserUsecase.On("Suspend", mock.Anything, uint64(2)).Return(nil)
+
+		httpReq, err := http.NewRequest(http.MethodPut, "/api/v1/user/2/suspend", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewUserhandler(mockUserUsecase)
+
+		r.PUT("/api/v1/user/:id/suspend", h.Suspend)
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusOK, rr.Code)
+		assert.EqualValues(t, "success suspend user", resp.Message)
+		mockUserUsecase.AssertExpectations(t)
+	})
+}
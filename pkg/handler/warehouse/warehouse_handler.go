@@ -0,0 +1,270 @@
+package warehouse
+
+import (
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/middleware"
+	"kanggo/pkg/usecase/warehouse"
+	"kanggo/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+var validate *validator.Validate
+
+type WarehouseHandler struct {
+	warehouseUsecase warehouse.WarehouseUsecase
+}
+
+func NewWarehouseHandler(warehouseUsecase warehouse.WarehouseUsecase) *WarehouseHandler {
+	return &WarehouseHandler{
+		warehouseUsecase: warehouseUsecase,
+	}
+}
+
+func (h *WarehouseHandler) Route(app *gin.Engine, accounts middleware.AccountLookup) {
+	v1 := app.Group("api/v1")
+	{
+		{
+			v1.POST("/warehouse", middleware.RoleAdmin(accounts), h.Insert)
+			v1.GET("/warehouse", middleware.RoleAdmin(accounts), h.GetAll)
+			v1.GET("/warehouse/:id", middleware.RoleAdmin(accounts), h.GetById)
+			v1.PUT("/warehouse/:id", middleware.RoleAdmin(accounts), h.Update)
+			v1.DELETE("/warehouse/:id", middleware.RoleAdmin(accounts), h.Delete)
+			v1.GET("/warehouse/:id/stock", middleware.RoleAdmin(accounts), h.GetStock)
+			v1.PUT("/warehouse/:id/stock", middleware.RoleAdmin(accounts), h.SetStock)
+			v1.GET("/warehouse/:id/movements", middleware.RoleAdmin(accounts), h.GetMovements)
+			v1.POST("/warehouse/transfer", middleware.RoleAdmin(accounts), h.Transfer)
+		}
+	}
+
+}
+
+func (h *WarehouseHandler) Insert(c *gin.Context) {
+	validate = validator.New()
+	warehouse := model.WarehouseRequest{}
+	ctx := c.Request.Context()
+
+	if err := c.ShouldBindJSON(&warehouse); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := validate.Struct(warehouse); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := h.warehouseUsecase.Insert(ctx, warehouse); err != nil {
+		switch err.Error() {
+		case "code already exists", "the default warehouse must be active":
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 201, "success insert warehouse", nil)
+}
+
+func (h *WarehouseHandler) GetAll(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	res, err := h.warehouseUsecase.GetAll(ctx)
+	if err != nil {
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success", res)
+}
+
+func (h *WarehouseHandler) GetById(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	ctx := c.Request.Context()
+
+	res, err := h.warehouseUsecase.GetById(ctx, int64(id))
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			utils.Response(c, 404, "data not found", nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success", res)
+}
+
+func (h *WarehouseHandler) Update(c *gin.Context) {
+	validate = validator.New()
+	id, _ := strconv.Atoi(c.Param("id"))
+	warehouse := model.WarehouseRequest{}
+	ctx := c.Request.Context()
+
+	if err := c.ShouldBindJSON(&warehouse); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := validate.Struct(warehouse); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := h.warehouseUsecase.Update(ctx, int64(id), warehouse); err != nil {
+		switch err.Error() {
+		case "code already exists", "the default warehouse must be active", "make another warehouse the default instead":
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		case "data not found":
+			utils.Response(c, 404, err.Error(), nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success update warehouse", nil)
+}
+
+func (h *WarehouseHandler) Delete(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	ctx := c.Request.Context()
+
+	if err := h.warehouseUsecase.Delete(ctx, int64(id)); err != nil {
+		switch err.Error() {
+		case "the default warehouse cannot be deleted", "warehouse still holds stock", "warehouse has pending orders",
+			"warehouse has open purchase orders":
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		case "data not found":
+			utils.Response(c, 404, err.Error(), nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success delete warehouse", nil)
+}
+
+func (h *WarehouseHandler) GetStock(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	ctx := c.Request.Context()
+
+	res, err := h.warehouseUsecase.GetStock(ctx, int64(id))
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			utils.Response(c, 404, "data not found", nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success", res)
+}
+
+func (h *WarehouseHandler) SetStock(c *gin.Context) {
+	validate = validator.New()
+	id, _ := strconv.Atoi(c.Param("id"))
+	userId := c.MustGet("user_id").(uint64)
+	stock := model.StockRequest{}
+	ctx := c.Request.Context()
+
+	if err := c.ShouldBindJSON(&stock); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := validate.Struct(stock); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := h.warehouseUsecase.SetStock(ctx, int64(id), userId, stock); err != nil {
+		switch err.Error() {
+		case "variant not found":
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		case "data not found":
+			utils.Response(c, 404, err.Error(), nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 200, "success set stock", nil)
+}
+
+func (h *WarehouseHandler) Transfer(c *gin.Context) {
+	validate = validator.New()
+	userId := c.MustGet("user_id").(uint64)
+	transfer := model.StockTransferRequest{}
+	ctx := c.Request.Context()
+
+	if err := c.ShouldBindJSON(&transfer); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	if err := validate.Struct(transfer); err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	res, err := h.warehouseUsecase.Transfer(ctx, userId, transfer)
+	if err != nil {
+		switch err.Error() {
+		case "warehouse not found", "variant not found", "not enough stock in the warehouse":
+			utils.Response(c, 400, err.Error(), nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	utils.Response(c, 201, "success transfer stock", res)
+}
+
+// GetMovements lists the stock movements of a warehouse, newest first. The
+// variant_id query narrows them to a single variant.
+func (h *WarehouseHandler) GetMovements(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	ctx := c.Request.Context()
+
+	query, err := utils.ParseListQuery(c, "id")
+	if err != nil {
+		utils.Response(c, 400, err.Error(), nil)
+		return
+	}
+
+	var variantId int64
+	if v := c.Query("variant_id"); v != "" {
+		if variantId, err = strconv.ParseInt(v, 10, 64); err != nil {
+			utils.Response(c, 400, "invalid variant_id", nil)
+			return
+		}
+	}
+
+	res, total, err := h.warehouseUsecase.GetMovements(ctx, int64(id), variantId, query)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			utils.Response(c, 404, "data not found", nil)
+			return
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		return
+	}
+
+	var lastId int64
+	if len(res) > 0 {
+		lastId = res[len(res)-1].Id
+	}
+
+	utils.ResponseList(c, 200, "success", res, utils.NewMeta(query, total, len(res), lastId))
+}
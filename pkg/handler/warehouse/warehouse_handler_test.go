@@ -0,0 +1,192 @@
+package warehouse
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/mocks"
+	"kanggo/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestInsert(t *testing.T) {
+	mockWarehouseUsecase := new(mocks.WarehouseUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockRequest := model.WarehouseRequest{Code: "JKT", Name: "Gudang Jakarta", Province: "DKI Jakarta", City: "Jakarta Barat"}
+		mockWarehouseUsecase.On("Insert", mock.Anything, mockRequest).Return(nil)
+
+		body, err := json.Marshal(mockRequest)
+		assert.Nil(t, err)
+
+		httpReq, err := http.NewRequest(http.MethodPost, "/api/v1/warehouse", bytes.NewReader(body))
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewWarehouseHandler(mockWarehouseUsecase)
+
+		r.POST("/api/v1/warehouse", h.Insert)
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusCreated, rr.Code)
+		assert.EqualValues(t, "success insert warehouse", resp.Message)
+		mockWarehouseUsecase.AssertExpectations(t)
+	})
+
+	t.Run("invalid postal code", func(t *testing.T) {
+		body := []byte(`{"code":"SBY","name":"Gudang Surabaya","province":"Jawa Timur","city":"Surabaya","postal_code":"601"}`)
+
+		httpReq, err := http.NewRequest(http.MethodPost, "/api/v1/warehouse", bytes.NewReader(body))
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewWarehouseHandler(mockWarehouseUsecase)
+
+		r.POST("/api/v1/warehouse", h.Insert)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	mockWarehouseUsecase := new(mocks.WarehouseUsecase)
+
+	t.Run("still holds stock", func(t *testing.T) {
+		mockWarehouseUsecase.On("Delete", mock.Anything, int64(2)).Return(errors.New("warehouse still holds stock"))
+
+		httpReq, err := http.NewRequest(http.MethodDelete, "/api/v1/warehouse/2", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewWarehouseHandler(mockWarehouseUsecase)
+
+		r.DELETE("/api/v1/warehouse/:id", h.Delete)
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusBadRequest, rr.Code)
+		mockWarehouseUsecase.AssertExpectations(t)
+	})
+}
+
+func TestTransfer(t *testing.T) {
+	mockWarehouseUsecase := new(mocks.WarehouseUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockRequest := model.StockTransferRequest{FromWarehouseId: 1, ToWarehouseId: 2, VariantId: 3, Qty: 5}
+		mockWarehouseUsecase.On("Transfer", mock.Anything, uint64(1), mockRequest).
+			Return(&model.StockTransferResponse{Reference: "TRF-000012", FromWarehouseId: 1, ToWarehouseId: 2, VariantId: 3, Qty: 5}, nil)
+
+		body, err := json.Marshal(mockRequest)
+		assert.Nil(t, err)
+
+		httpReq, err := http.NewRequest(http.MethodPost, "/api/v1/warehouse/transfer", bytes.NewReader(body))
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewWarehouseHandler(mockWarehouseUsecase)
+
+		r.POST("/api/v1/warehouse/transfer", func(c *gin.Context) {
+			c.Set("user_id", uint64(1))
+			h.Transfer(c)
+		})
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusCreated, rr.Code)
+		assert.EqualValues(t, "success transfer stock", resp.Message)
+		mockWarehouseUsecase.AssertExpectations(t)
+	})
+
+	t.Run("same warehouse", func(t *testing.T) {
+		body := []byte(`{"from_warehouse_id":1,"to_warehouse_id":1,"variant_id":3,"qty":5}`)
+
+		httpReq, err := http.NewRequest(http.MethodPost, "/api/v1/warehouse/transfer", bytes.NewReader(body))
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewWarehouseHandler(mockWarehouseUsecase)
+
+		r.POST("/api/v1/warehouse/transfer", func(c *gin.Context) {
+			c.Set("user_id", uint64(1))
+			h.Transfer(c)
+		})
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("not enough stock", func(t *testing.T) {
+		mockWarehouseUsecase.On("Transfer", mock.Anything, uint64(1), mock.MatchedBy(func(data model.StockTransferRequest) bool {
+			return data.Qty == 500
+		})).Return(nil, errors.New("not enough stock in the warehouse"))
+
+		body := []byte(`{"from_warehouse_id":1,"to_warehouse_id":2,"variant_id":3,"qty":500}`)
+
+		httpReq, err := http.NewRequest(http.MethodPost, "/api/v1/warehouse/transfer", bytes.NewReader(body))
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewWarehouseHandler(mockWarehouseUsecase)
+
+		r.POST("/api/v1/warehouse/transfer", func(c *gin.Context) {
+			c.Set("user_id", uint64(1))
+			h.Transfer(c)
+		})
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestGetMovements(t *testing.T) {
+	mockWarehouseUsecase := new(mocks.WarehouseUsecase)
+
+	t.Run("by variant", func(t *testing.T) {
+		query := model.ListQuery{Page: 1, Size: 1, Sort: "id"}
+		mockWarehouseUsecase.On("GetMovements", mock.Anything, int64(1), int64(3), query).
+			Return([]model.StockMovementResponse{{Id: 13, WarehouseId: 1, VariantId: 3, Qty: -5, Type: "transfer_out"}}, int64(4), nil)
+
+		httpReq, err := http.NewRequest(http.MethodGet, "/api/v1/warehouse/1/movements?variant_id=3&size=1", nil)
+		assert.Nil(t, err)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		h := NewWarehouseHandler(mockWarehouseUsecase)
+
+		r.GET("/api/v1/warehouse/:id/movements", h.GetMovements)
+		r.ServeHTTP(rr, httpReq)
+
+		var resp utils.Respond
+		err = json.Unmarshal(rr.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "13", resp.Meta.NextCursor)
+		mockWarehouseUsecase.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,155 @@
+// Package imaging checks uploaded images and makes their thumbnails.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+const (
+	// MaxUploadSize is the largest image file accepted, in bytes.
+	MaxUploadSize = 5 << 20
+	// MaxPixels keeps small files that decode to huge images out.
+	MaxPixels = 40_000_000
+	// ThumbnailSize is the longest side of a thumbnail, in pixels.
+	ThumbnailSize = 320
+)
+
+var (
+	ErrUnsupported = errors.New("unsupported image type")
+	ErrTooLarge    = errors.New("image is too large")
+)
+
+// extensions are the accepted content types and the file extension they are
+// stored with.
+var extensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+type Image struct {
+	ContentType string
+	Extension   string
+	Width       int
+	Height      int
+	Image       image.Image
+}
+
+// Decode checks the content of data, not its claimed type, and decodes it.
+func Decode(data []byte) (*Image, error) {
+	if len(data) > MaxUploadSize {
+		return nil, ErrTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	extension, ok := extensions[contentType]
+	if !ok {
+		return nil, ErrUnsupported
+	}
+
+	config, err := decodeConfig(contentType, data)
+	if err != nil {
+		return nil, ErrUnsupported
+	}
+
+	if config.Width*config.Height > MaxPixels {
+		return nil, ErrTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupported
+	}
+
+	return &Image{
+		ContentType: contentType,
+		Extension:   extension,
+		Width:       config.Width,
+		Height:      config.Height,
+		Image:       img,
+	}, nil
+}
+
+func decodeConfig(contentType string, data []byte) (image.Config, error) {
+	switch contentType {
+	case "image/png":
+		return png.DecodeConfig(bytes.NewReader(data))
+	case "image/gif":
+		return gif.DecodeConfig(bytes.NewReader(data))
+	default:
+		return jpeg.DecodeConfig(bytes.NewReader(data))
+	}
+}
+
+// Thumbnail scales src down to fit in a size x size square, averaging the
+// pixels each thumbnail pixel covers. Transparent parts are laid on white.
+// Images that already fit keep their size.
+func Thumbnail(src image.Image, size int) *image.RGBA {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	dstWidth, dstHeight := width, height
+	if width > size || height > size {
+		if width >= height {
+			dstWidth, dstHeight = size, max(1, height*size/width)
+		} else {
+			dstWidth, dstHeight = max(1, width*size/height), size
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < dstHeight; y++ {
+		y0 := bounds.Min.Y + y*height/dstHeight
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*height/dstHeight)
+
+		for x := 0; x < dstWidth; x++ {
+			x0 := bounds.Min.X + x*width/dstWidth
+			x1 := max(x0+1, bounds.Min.X+(x+1)*width/dstWidth)
+
+			var r, g, b, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					white := uint64(0xffff - ca)
+					r += uint64(cr) + white
+					g += uint64(cg) + white
+					b += uint64(cb) + white
+					n++
+				}
+			}
+
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(b / n >> 8),
+				A: 0xff,
+			})
+		}
+	}
+
+	return dst
+}
+
+// EncodeJPEG is used for every thumbnail, whatever the type of the original.
+func EncodeJPEG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,74 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("png", func(t *testing.T) {
+		img, err := Decode(encodePNG(t, image.NewRGBA(image.Rect(0, 0, 40, 20))))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "image/png", img.ContentType)
+		assert.Equal(t, ".png", img.Extension)
+		assert.Equal(t, 40, img.Width)
+		assert.Equal(t, 20, img.Height)
+	})
+
+	t.Run("not an image", func(t *testing.T) {
+		_, err := Decode([]byte("<html><body>semen</body></html>"))
+
+		assert.Equal(t, ErrUnsupported, err)
+	})
+
+	t.Run("file too large", func(t *testing.T) {
+		_, err := Decode(make([]byte, MaxUploadSize+1))
+
+		assert.Equal(t, ErrTooLarge, err)
+	})
+
+	t.Run("too many pixels", func(t *testing.T) {
+		_, err := Decode(encodePNG(t, image.NewGray(image.Rect(0, 0, 8000, 5001))))
+
+		assert.Equal(t, ErrTooLarge, err)
+	})
+}
+
+func TestThumbnail(t *testing.T) {
+	t.Run("keeps aspect ratio", func(t *testing.T) {
+		thumb := Thumbnail(image.NewRGBA(image.Rect(0, 0, 1000, 500)), 320)
+
+		assert.Equal(t, image.Rect(0, 0, 320, 160), thumb.Bounds())
+	})
+
+	t.Run("small images keep their size", func(t *testing.T) {
+		thumb := Thumbnail(image.NewRGBA(image.Rect(0, 0, 100, 300)), 320)
+
+		assert.Equal(t, image.Rect(0, 0, 100, 300), thumb.Bounds())
+	})
+
+	t.Run("averages and fills transparency with white", func(t *testing.T) {
+		src := image.NewRGBA(image.Rect(0, 0, 4, 2))
+		for x := 0; x < 2; x++ {
+			src.Set(x, 0, color.RGBA{A: 0xff})
+			src.Set(x, 1, color.RGBA{A: 0xff})
+		}
+
+		thumb := Thumbnail(src, 2)
+
+		assert.Equal(t, color.RGBA{A: 0xff}, thumb.RGBAAt(0, 0))
+		assert.Equal(t, color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}, thumb.RGBAAt(1, 0))
+	})
+}
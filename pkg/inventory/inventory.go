@@ -0,0 +1,78 @@
+// Package inventory decides which warehouse an order ships from.
+package inventory
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+var ErrNotEnough = errors.New("not enough product quantity")
+
+// Stock is the stock of a variant in a warehouse, with the warehouse's
+// region.
+type Stock struct {
+	ProductId   int64
+	VariantId   int64
+	WarehouseId int64
+	Code        string
+	Name        string
+	Province    string
+	City        string
+	Default     bool
+	Active      bool
+	Qty         int64
+}
+
+// Choose picks the warehouse to ship quantity of the variant to a province
+// and city from. Only active warehouses holding the whole quantity qualify;
+// of those one in the same city wins, then one in the same province, then
+// the one holding the most. Without any stock of the variant in a warehouse,
+// as before warehouses are set up, it returns nil and no error.
+func Choose(stocks []Stock, variantId, quantity int64, province, city string) (*Stock, error) {
+	candidates := []Stock{}
+	held := false
+	for _, stock := range stocks {
+		if stock.VariantId != variantId {
+			continue
+		}
+		held = true
+
+		if stock.Active && stock.Qty >= quantity {
+			candidates = append(candidates, stock)
+		}
+	}
+
+	if !held {
+		return nil, nil
+	}
+
+	if len(candidates) == 0 {
+		return nil, ErrNotEnough
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := nearness(candidates[i], province, city), nearness(candidates[j], province, city)
+		if a != b {
+			return a > b
+		}
+		if candidates[i].Qty != candidates[j].Qty {
+			return candidates[i].Qty > candidates[j].Qty
+		}
+		return candidates[i].WarehouseId < candidates[j].WarehouseId
+	})
+
+	return &candidates[0], nil
+}
+
+func nearness(stock Stock, province, city string) int {
+	if !strings.EqualFold(stock.Province, province) {
+		return 0
+	}
+
+	if strings.EqualFold(stock.City, city) {
+		return 2
+	}
+
+	return 1
+}
@@ -0,0 +1,53 @@
+package inventory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChoose(t *testing.T) {
+	stocks := []Stock{
+		{VariantId: 7, WarehouseId: 1, Province: "DKI Jakarta", City: "Jakarta Utara", Default: true, Active: true, Qty: 500},
+		{VariantId: 7, WarehouseId: 2, Province: "Jawa Barat", City: "Bekasi", Active: true, Qty: 40},
+		{VariantId: 7, WarehouseId: 3, Province: "Jawa Barat", City: "Bandung", Active: true, Qty: 120},
+		{VariantId: 7, WarehouseId: 4, Province: "Jawa Timur", City: "Surabaya", Active: false, Qty: 900},
+		{VariantId: 8, WarehouseId: 2, Province: "Jawa Barat", City: "Bekasi", Active: true, Qty: 1000},
+	}
+
+	tests := []struct {
+		name      string
+		quantity  int64
+		province  string
+		city      string
+		warehouse int64
+	}{
+		{"same city", 30, "Jawa Barat", "bekasi", 2},
+		{"same city without enough stock", 50, "Jawa Barat", "Bekasi", 3},
+		{"same province", 30, "Jawa Barat", "Bogor", 3},
+		{"most stock elsewhere", 30, "Bali", "Denpasar", 1},
+		{"inactive warehouse skipped", 30, "Jawa Timur", "Surabaya", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Choose(stocks, 7, tt.quantity, tt.province, tt.city)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.warehouse, res.WarehouseId)
+		})
+	}
+
+	t.Run("not enough anywhere", func(t *testing.T) {
+		_, err := Choose(stocks, 7, 600, "DKI Jakarta", "Jakarta Utara")
+
+		assert.Equal(t, ErrNotEnough, err)
+	})
+
+	t.Run("no warehouse stock", func(t *testing.T) {
+		res, err := Choose(stocks, 9, 1, "DKI Jakarta", "Jakarta Utara")
+
+		assert.NoError(t, err)
+		assert.Nil(t, res)
+	})
+}
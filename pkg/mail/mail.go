@@ -0,0 +1,80 @@
+package mail
+
+import (
+	"context"
+	"log"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+//go:generate mockery --name Mailer --case snake --output ../mocks --disable-version-string
+
+type (
+	// Mailer sends a plain text email to one address.
+	Mailer interface {
+		Send(ctx context.Context, to, subject, body string) error
+	}
+
+	SMTPConfig struct {
+		Host     string
+		Port     string
+		Username string
+		Password string
+		From     string
+	}
+
+	smtpMailer struct {
+		config SMTPConfig
+		send   func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+	}
+
+	logMailer struct {
+		logger *log.Logger
+	}
+)
+
+// NewSMTPMailer returns a mailer sending through the SMTP server. The server
+// is logged in to only when a username is set.
+func NewSMTPMailer(config SMTPConfig) Mailer {
+	return &smtpMailer{
+		config: config,
+		send:   smtp.SendMail,
+	}
+}
+
+func (m *smtpMailer) Send(ctx context.Context, to, subject, body string) error {
+	var auth smtp.Auth
+	if m.config.Username != "" {
+		auth = smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+	}
+
+	msg := "From: " + m.config.From + "\r\n" +
+		"To: " + header(to) + "\r\n" +
+		"Subject: " + header(subject) + "\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"\r\n" + body + "\r\n"
+
+	return m.send(net.JoinHostPort(m.config.Host, m.config.Port), auth, m.config.From, []string{to}, []byte(msg))
+}
+
+// header keeps a value on its header line, where a line break would start
+// another header.
+func header(value string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(value)
+}
+
+// NewLogMailer writes the emails to the logger, or to the standard logger
+// when it is nil, for when no SMTP server is set up.
+func NewLogMailer(logger *log.Logger) Mailer {
+	if logger == nil {
+		logger = log.Default()
+	}
+
+	return &logMailer{logger: logger}
+}
+
+func (m *logMailer) Send(ctx context.Context, to, subject, body string) error {
+	m.logger.Printf("mail to %s: %s\n%s", to, subject, body)
+	return nil
+}
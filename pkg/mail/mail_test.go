@@ -0,0 +1,45 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/smtp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSMTPMailer(t *testing.T) {
+	var addr, from string
+	var to []string
+	var msg []byte
+	m := &smtpMailer{
+		config: SMTPConfig{Host: "smtp.example.com", Port: "587", From: "akun@kanggo.id"},
+		send: func(a string, auth smtp.Auth, f string, t []string, b []byte) error {
+			addr, from, to, msg = a, f, t, b
+			return nil
+		},
+	}
+
+	err := m.Send(context.Background(), "bayu@gmail.com", "Confirm\r\nBcc: someone@example.com", "your code is 1234")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "smtp.example.com:587", addr)
+	assert.Equal(t, "akun@kanggo.id", from)
+	assert.Equal(t, []string{"bayu@gmail.com"}, to)
+	headers := strings.SplitN(string(msg), "\r\n\r\n", 2)[0]
+	assert.Contains(t, headers, "To: bayu@gmail.com")
+	assert.False(t, strings.Contains(headers, "\r\nBcc:"))
+	assert.True(t, strings.HasSuffix(string(msg), "\r\n\r\nyour code is 1234\r\n"))
+}
+
+func TestLogMailer(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := NewLogMailer(log.New(&buf, "", 0)).Send(context.Background(), "bayu@gmail.com", "Confirm", "your code is 1234")
+
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "mail to bayu@gmail.com: Confirm")
+}
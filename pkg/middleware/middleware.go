@@ -1,8 +1,11 @@
 package middleware
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"kanggo/config"
+	"kanggo/pkg/entity/schema"
 	"kanggo/utils"
 	"strconv"
 
@@ -10,7 +13,13 @@ import (
 	"github.com/golang-jwt/jwt"
 )
 
-func RoleAdmin() gin.HandlerFunc {
+// AccountLookup reads the account a token belongs to, which the user storage
+// does.
+type AccountLookup interface {
+	GetById(ctx context.Context, id uint64) (*schema.User, error)
+}
+
+func RoleAdmin(accounts AccountLookup) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		bearer := c.Request.Header.Get("Authorization")
 
@@ -42,12 +51,17 @@ func RoleAdmin() gin.HandlerFunc {
 
 		uid, _ := strconv.ParseUint(fmt.Sprintf("%.0f", value["user_id"]), 10, 32)
 
+		if _, ok := checkAccount(c, accounts, uid, true); !ok {
+			return
+		}
+
 		c.Set("user_id", uid)
+		c.Set("admin", true)
 		c.Next()
 	}
 }
 
-func RoleUser() gin.HandlerFunc {
+func RoleUser(accounts AccountLookup) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		bearer := c.Request.Header.Get("Authorization")
 
@@ -72,7 +86,58 @@ func RoleUser() gin.HandlerFunc {
 
 		uid, _ := strconv.ParseUint(fmt.Sprintf("%.0f", value["user_id"]), 10, 32)
 
+		role, ok := checkAccount(c, accounts, uid, false)
+		if !ok {
+			return
+		}
+
+		// the admin claim only counts while the account still has the role
 		c.Set("user_id", uid)
+		c.Set("admin", value["admin"] == true && (role == "" || role == "admin"))
 		c.Next()
 	}
 }
+
+// checkAccount rejects tokens belonging to suspended accounts, to accounts
+// that must reset their password, and admin tokens of users whose role has
+// since been revoked. A closed account is deleted, so its token is rejected
+// for having no row left. The built-in admin account has id 0 and no row
+// in the users table, so it is not checked and its role is returned empty.
+func checkAccount(c *gin.Context, accounts AccountLookup, uid uint64, admin bool) (string, bool) {
+	if uid == 0 {
+		return "", true
+	}
+
+	user, err := accounts.GetById(c.Request.Context(), uid)
+	if err != nil {
+		// the account was closed
+		if err == sql.ErrNoRows {
+			utils.Response(c, 401, "not authorized", nil)
+			c.Abort()
+			return "", false
+		}
+		utils.Response(c, 500, err.Error(), nil)
+		c.Abort()
+		return "", false
+	}
+
+	if user.Status == "suspended" {
+		utils.Response(c, 403, "account suspended", nil)
+		c.Abort()
+		return "", false
+	}
+
+	if admin && user.Role != "admin" {
+		utils.Response(c, 401, "not authorized", nil)
+		c.Abort()
+		return "", false
+	}
+
+	if user.PasswordResetRequired && !(c.Request.Method == "PUT" && c.FullPath() == "/api/v1/me/password") {
+		utils.Response(c, 403, "password reset required", nil)
+		c.Abort()
+		return "", false
+	}
+
+	return user.Role, true
+}
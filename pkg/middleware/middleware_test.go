@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"database/sql"
+	"kanggo/config"
+	"kanggo/pkg/entity/schema"
+	"kanggo/pkg/mocks"
+	"kanggo/utils"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMain(m *testing.M) {
+	config.EnvFile = &config.Env{ApiSecret: "secret"}
+	os.Exit(m.Run())
+}
+
+func TestRoleUser(t *testing.T) {
+	mockUserStorage := new(mocks.UserStorage)
+	expired := time.Now().Add(time.Hour).Unix()
+
+	cases := []struct {
+		name  string
+		user  *schema.User
+		err   error
+		code  int
+		admin bool
+	}{
+		{"active", &schema.User{Role: "user", Status: "active"}, nil, http.StatusOK, false},
+		{"revoked admin", &schema.User{Role: "user", Status: "active"}, nil, http.StatusOK, false},
+		{"admin", &schema.User{Role: "admin", Status: "active"}, nil, http.StatusOK, true},
+		{"suspended", &schema.User{Role: "user", Status: "suspended"}, nil, http.StatusForbidden, false},
+		{"password reset required", &schema.User{Role: "user", Status: "active", PasswordResetRequired: true}, nil, http.StatusForbidden, false},
+		{"deleted", nil, sql.ErrNoRows, http.StatusUnauthorized, false},
+	}
+
+	for i, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			id := int64(i + 1)
+			mockUserStorage.On("GetById", mock.Anything, uint64(id)).Return(tc.user, tc.err).Once()
+
+			token, err := utils.GenerateToken(id, expired, tc.name != "active")
+			assert.NoError(t, err)
+
+			httpReq, err := http.NewRequest(http.MethodGet, "/api/v1/me", nil)
+			assert.Nil(t, err)
+			httpReq.Header.Set("Authorization", token)
+
+			r := gin.Default()
+			rr := httptest.NewRecorder()
+
+			var admin bool
+			r.GET("/api/v1/me", RoleUser(mockUserStorage), func(c *gin.Context) {
+				admin = c.GetBool("admin")
+				c.Status(http.StatusOK)
+			})
+			r.ServeHTTP(rr, httpReq)
+
+			assert.EqualValues(t, tc.code, rr.Code)
+			assert.Equal(t, tc.admin, admin)
+		})
+	}
+
+	mockUserStorage.AssertExpectations(t)
+}
+
+func TestRoleAdmin(t *testing.T) {
+	mockUserStorage := new(mocks.UserStorage)
+	expired := time.Now().Add(time.Hour).Unix()
+
+	t.Run("role revoked", func(t *testing.T) {
+		mockUserStorage.On("GetById", mock.Anything, uint64(2)).Return(&schema.User{Role: "user", Status: "active"}, nil).Once()
+
+		token, err := utils.GenerateToken(2, expired, true)
+		assert.NoError(t, err)
+
+		httpReq, err := http.NewRequest(http.MethodGet, "/api/v1/user", nil)
+		assert.Nil(t, err)
+		httpReq.Header.Set("Authorization", token)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		r.GET("/api/v1/user", RoleAdmin(mockUserStorage), func(c *gin.Context) { c.Status(http.StatusOK) })
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusUnauthorized, rr.Code)
+		mockUserStorage.AssertExpectations(t)
+	})
+
+	t.Run("built-in admin", func(t *testing.T) {
+		token, err := utils.GenerateToken(0, expired, true)
+		assert.NoError(t, err)
+
+		httpReq, err := http.NewRequest(http.MethodGet, "/api/v1/user", nil)
+		assert.Nil(t, err)
+		httpReq.Header.Set("Authorization", token)
+
+		r := gin.Default()
+		rr := httptest.NewRecorder()
+
+		r.GET("/api/v1/user", RoleAdmin(mockUserStorage), func(c *gin.Context) { c.Status(http.StatusOK) })
+		r.ServeHTTP(rr, httpReq)
+
+		assert.EqualValues(t, http.StatusOK, rr.Code)
+		mockUserStorage.AssertNumberOfCalls(t, "GetById", 1)
+	})
+}
@@ -9,6 +9,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	schema "kanggo/pkg/entity/schema"
+
+	time "time"
 )
 
 // OrderStorage is an autogenerated mock type for the OrderStorage type
@@ -16,45 +18,96 @@ type OrderStorage struct {
 	mock.Mock
 }
 
-// GetAllOrder provides a mock function with given fields: ctx
-func (_m *OrderStorage) GetAllOrder(ctx context.Context) ([]model.OrderResponse, error) {
-	ret := _m.Called(ctx)
+// CancelOrder provides a mock function with given fields: ctx, orderId, actorId, actorRole, reason, version
+func (_m *OrderStorage) CancelOrder(ctx context.Context, orderId int64, actorId int64, actorRole string, reason string, version uint) error {
+	ret := _m.Called(ctx, orderId, actorId, actorRole, reason, version)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, string, string, uint) error); ok {
+		r0 = rf(ctx, orderId, actorId, actorRole, reason, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAllOrder provides a mock function with given fields: ctx, query
+func (_m *OrderStorage) GetAllOrder(ctx context.Context, query model.ListQuery) ([]model.OrderResponse, int64, error) {
+	ret := _m.Called(ctx, query)
 
 	var r0 []model.OrderResponse
-	if rf, ok := ret.Get(0).(func(context.Context) []model.OrderResponse); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, model.ListQuery) []model.OrderResponse); ok {
+		r0 = rf(ctx, query)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.OrderResponse)
 		}
 	}
 
-	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	var r1 int64
+	if rf, ok := ret.Get(1).(func(context.Context, model.ListQuery) int64); ok {
+		r1 = rf(ctx, query)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(int64)
 	}
 
-	return r0, r1
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, model.ListQuery) error); ok {
+		r2 = rf(ctx, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
-// GetAllOrderPerUser provides a mock function with given fields: ctx, userId
-func (_m *OrderStorage) GetAllOrderPerUser(ctx context.Context, userId uint64) ([]model.OrderResponse, error) {
-	ret := _m.Called(ctx, userId)
+// GetAllOrderPerUser provides a mock function with given fields: ctx, userId, query
+func (_m *OrderStorage) GetAllOrderPerUser(ctx context.Context, userId uint64, query model.ListQuery) ([]model.OrderResponse, int64, error) {
+	ret := _m.Called(ctx, userId, query)
 
 	var r0 []model.OrderResponse
-	if rf, ok := ret.Get(0).(func(context.Context, uint64) []model.OrderResponse); ok {
-		r0 = rf(ctx, userId)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, model.ListQuery) []model.OrderResponse); ok {
+		r0 = rf(ctx, userId, query)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.OrderResponse)
 		}
 	}
 
+	var r1 int64
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, model.ListQuery) int64); ok {
+		r1 = rf(ctx, userId, query)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, uint64, model.ListQuery) error); ok {
+		r2 = rf(ctx, userId, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetEvents provides a mock function with given fields: ctx, orderId
+func (_m *OrderStorage) GetEvents(ctx context.Context, orderId int64) ([]schema.OrderEvent, error) {
+	ret := _m.Called(ctx, orderId)
+
+	var r0 []schema.OrderEvent
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []schema.OrderEvent); ok {
+		r0 = rf(ctx, orderId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]schema.OrderEvent)
+		}
+	}
+
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
-		r1 = rf(ctx, userId)
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, orderId)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -85,13 +138,78 @@ func (_m *OrderStorage) GetOrderById(ctx context.Context, orderId int64, userId
 	return r0, r1
 }
 
-// InsertOrder provides a mock function with given fields: ctx, data, quantity
-func (_m *OrderStorage) InsertOrder(ctx context.Context, data schema.Order, quantity int64) error {
-	ret := _m.Called(ctx, data, quantity)
+// GetOrderIdByNumber provides a mock function with given fields: ctx, number
+func (_m *OrderStorage) GetOrderIdByNumber(ctx context.Context, number string) (int64, error) {
+	ret := _m.Called(ctx, number)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(ctx, number)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, number)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTaxReport provides a mock function with given fields: ctx, from, to
+func (_m *OrderStorage) GetTaxReport(ctx context.Context, from time.Time, to time.Time) ([]model.TaxReport, error) {
+	ret := _m.Called(ctx, from, to)
+
+	var r0 []model.TaxReport
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) []model.TaxReport); ok {
+		r0 = rf(ctx, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.TaxReport)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HasPendingOrder provides a mock function with given fields: ctx, userId, variantId
+func (_m *OrderStorage) HasPendingOrder(ctx context.Context, userId int64, variantId int64) (bool, error) {
+	ret := _m.Called(ctx, userId, variantId)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) bool); ok {
+		r0 = rf(ctx, userId, variantId)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, userId, variantId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InsertOrder provides a mock function with given fields: ctx, data, quantity, redemption
+func (_m *OrderStorage) InsertOrder(ctx context.Context, data schema.Order, quantity int64, redemption *schema.CouponRedemption) error {
+	ret := _m.Called(ctx, data, quantity, redemption)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, schema.Order, int64) error); ok {
-		r0 = rf(ctx, data, quantity)
+	if rf, ok := ret.Get(0).(func(context.Context, schema.Order, int64, *schema.CouponRedemption) error); ok {
+		r0 = rf(ctx, data, quantity, redemption)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -99,6 +217,27 @@ func (_m *OrderStorage) InsertOrder(ctx context.Context, data schema.Order, quan
 	return r0
 }
 
+// NextNumber provides a mock function with given fields: ctx, day
+func (_m *OrderStorage) NextNumber(ctx context.Context, day time.Time) (int64, error) {
+	ret := _m.Called(ctx, day)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, day)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, day)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdatePayment provides a mock function with given fields: ctx, data
 func (_m *OrderStorage) UpdatePayment(ctx context.Context, data schema.Order) error {
 	ret := _m.Called(ctx, data)
@@ -7,6 +7,8 @@ import (
 	model "kanggo/pkg/entity/model"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // OrderUsecase is an autogenerated mock type for the OrderUsecase type
@@ -14,45 +16,96 @@ type OrderUsecase struct {
 	mock.Mock
 }
 
-// GetAllOrder provides a mock function with given fields: ctx
-func (_m *OrderUsecase) GetAllOrder(ctx context.Context) ([]model.OrderResponse, error) {
-	ret := _m.Called(ctx)
+// CancelOrder provides a mock function with given fields: ctx, orderId, userId, admin, data
+func (_m *OrderUsecase) CancelOrder(ctx context.Context, orderId int64, userId uint64, admin bool, data model.CancelOrderRequest) error {
+	ret := _m.Called(ctx, orderId, userId, admin, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, uint64, bool, model.CancelOrderRequest) error); ok {
+		r0 = rf(ctx, orderId, userId, admin, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAllOrder provides a mock function with given fields: ctx, query
+func (_m *OrderUsecase) GetAllOrder(ctx context.Context, query model.ListQuery) ([]model.OrderResponse, int64, error) {
+	ret := _m.Called(ctx, query)
 
 	var r0 []model.OrderResponse
-	if rf, ok := ret.Get(0).(func(context.Context) []model.OrderResponse); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, model.ListQuery) []model.OrderResponse); ok {
+		r0 = rf(ctx, query)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.OrderResponse)
 		}
 	}
 
-	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	var r1 int64
+	if rf, ok := ret.Get(1).(func(context.Context, model.ListQuery) int64); ok {
+		r1 = rf(ctx, query)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(int64)
 	}
 
-	return r0, r1
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, model.ListQuery) error); ok {
+		r2 = rf(ctx, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
-// GetAllOrderPerUser provides a mock function with given fields: ctx, userId
-func (_m *OrderUsecase) GetAllOrderPerUser(ctx context.Context, userId uint64) ([]model.OrderResponse, error) {
-	ret := _m.Called(ctx, userId)
+// GetAllOrderPerUser provides a mock function with given fields: ctx, userId, query
+func (_m *OrderUsecase) GetAllOrderPerUser(ctx context.Context, userId uint64, query model.ListQuery) ([]model.OrderResponse, int64, error) {
+	ret := _m.Called(ctx, userId, query)
 
 	var r0 []model.OrderResponse
-	if rf, ok := ret.Get(0).(func(context.Context, uint64) []model.OrderResponse); ok {
-		r0 = rf(ctx, userId)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, model.ListQuery) []model.OrderResponse); ok {
+		r0 = rf(ctx, userId, query)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.OrderResponse)
 		}
 	}
 
+	var r1 int64
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, model.ListQuery) int64); ok {
+		r1 = rf(ctx, userId, query)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, uint64, model.ListQuery) error); ok {
+		r2 = rf(ctx, userId, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetEvents provides a mock function with given fields: ctx, orderId, userId, admin
+func (_m *OrderUsecase) GetEvents(ctx context.Context, orderId int64, userId uint64, admin bool) ([]model.OrderEventResponse, error) {
+	ret := _m.Called(ctx, orderId, userId, admin)
+
+	var r0 []model.OrderEventResponse
+	if rf, ok := ret.Get(0).(func(context.Context, int64, uint64, bool) []model.OrderEventResponse); ok {
+		r0 = rf(ctx, orderId, userId, admin)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.OrderEventResponse)
+		}
+	}
+
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
-		r1 = rf(ctx, userId)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, uint64, bool) error); ok {
+		r1 = rf(ctx, orderId, userId, admin)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -83,6 +136,50 @@ func (_m *OrderUsecase) GetOrderById(ctx context.Context, orderId int64, userId
 	return r0, r1
 }
 
+// GetOrderIdByNumber provides a mock function with given fields: ctx, number
+func (_m *OrderUsecase) GetOrderIdByNumber(ctx context.Context, number string) (int64, error) {
+	ret := _m.Called(ctx, number)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(ctx, number)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, number)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTaxReport provides a mock function with given fields: ctx, from, to
+func (_m *OrderUsecase) GetTaxReport(ctx context.Context, from time.Time, to time.Time) ([]model.TaxReport, error) {
+	ret := _m.Called(ctx, from, to)
+
+	var r0 []model.TaxReport
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) []model.TaxReport); ok {
+		r0 = rf(ctx, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.TaxReport)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // InsertOrder provides a mock function with given fields: ctx, data
 func (_m *OrderUsecase) InsertOrder(ctx context.Context, data model.OrderRequest) error {
 	ret := _m.Called(ctx, data)
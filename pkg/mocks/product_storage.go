@@ -4,10 +4,17 @@ package mocks
 
 import (
 	context "context"
+	inventory "kanggo/pkg/inventory"
 
 	mock "github.com/stretchr/testify/mock"
 
+	model "kanggo/pkg/entity/model"
+
+	product "kanggo/pkg/storage/product"
+
 	schema "kanggo/pkg/entity/schema"
+
+	time "time"
 )
 
 // ProductStorage is an autogenerated mock type for the ProductStorage type
@@ -15,20 +22,41 @@ type ProductStorage struct {
 	mock.Mock
 }
 
-// CheckQty provides a mock function with given fields: ctx, productId, amount
-func (_m *ProductStorage) CheckQty(ctx context.Context, productId int64, amount int64) (bool, error) {
-	ret := _m.Called(ctx, productId, amount)
+// ApplyPrices provides a mock function with given fields: ctx, at
+func (_m *ProductStorage) ApplyPrices(ctx context.Context, at time.Time) (int64, error) {
+	ret := _m.Called(ctx, at)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, at)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CheckQty provides a mock function with given fields: ctx, variantId, amount
+func (_m *ProductStorage) CheckQty(ctx context.Context, variantId int64, amount int64) (bool, error) {
+	ret := _m.Called(ctx, variantId, amount)
 
 	var r0 bool
 	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) bool); ok {
-		r0 = rf(ctx, productId, amount)
+		r0 = rf(ctx, variantId, amount)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
-		r1 = rf(ctx, productId, amount)
+		r1 = rf(ctx, variantId, amount)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -36,13 +64,13 @@ func (_m *ProductStorage) CheckQty(ctx context.Context, productId int64, amount
 	return r0, r1
 }
 
-// Delete provides a mock function with given fields: ctx, id
-func (_m *ProductStorage) Delete(ctx context.Context, id int64) error {
-	ret := _m.Called(ctx, id)
+// Delete provides a mock function with given fields: ctx, id, version
+func (_m *ProductStorage) Delete(ctx context.Context, id int64, version uint) error {
+	ret := _m.Called(ctx, id, version)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, uint) error); ok {
+		r0 = rf(ctx, id, version)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -50,22 +78,99 @@ func (_m *ProductStorage) Delete(ctx context.Context, id int64) error {
 	return r0
 }
 
-// GetAll provides a mock function with given fields: ctx
-func (_m *ProductStorage) GetAll(ctx context.Context) ([]schema.Product, error) {
-	ret := _m.Called(ctx)
+// DeleteImage provides a mock function with given fields: ctx, productId, id
+func (_m *ProductStorage) DeleteImage(ctx context.Context, productId int64, id int64) error {
+	ret := _m.Called(ctx, productId, id)
 
-	var r0 []schema.Product
-	if rf, ok := ret.Get(0).(func(context.Context) []schema.Product); ok {
-		r0 = rf(ctx)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, productId, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeletePrice provides a mock function with given fields: ctx, productId, id
+func (_m *ProductStorage) DeletePrice(ctx context.Context, productId int64, id int64) error {
+	ret := _m.Called(ctx, productId, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, productId, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteVariant provides a mock function with given fields: ctx, productId, id
+func (_m *ProductStorage) DeleteVariant(ctx context.Context, productId int64, id int64) error {
+	ret := _m.Called(ctx, productId, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, productId, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EffectivePrice provides a mock function with given fields: ctx, variantId, at
+func (_m *ProductStorage) EffectivePrice(ctx context.Context, variantId int64, at time.Time) (float64, error) {
+	ret := _m.Called(ctx, variantId, at)
+
+	var r0 float64
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) float64); ok {
+		r0 = rf(ctx, variantId, at)
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Time) error); ok {
+		r1 = rf(ctx, variantId, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Export provides a mock function with given fields: ctx, each
+func (_m *ProductStorage) Export(ctx context.Context, each func(schema.Product, schema.ProductVariant) error) error {
+	ret := _m.Called(ctx, each)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(schema.Product, schema.ProductVariant) error) error); ok {
+		r0 = rf(ctx, each)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindSkus provides a mock function with given fields: ctx, skus
+func (_m *ProductStorage) FindSkus(ctx context.Context, skus []string) ([]string, error) {
+	ret := _m.Called(ctx, skus)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []string); ok {
+		r0 = rf(ctx, skus)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]schema.Product)
+			r0 = ret.Get(0).([]string)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, skus)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -73,6 +178,36 @@ func (_m *ProductStorage) GetAll(ctx context.Context) ([]schema.Product, error)
 	return r0, r1
 }
 
+// GetAll provides a mock function with given fields: ctx, query
+func (_m *ProductStorage) GetAll(ctx context.Context, query model.ListQuery) ([]schema.Product, int64, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []schema.Product
+	if rf, ok := ret.Get(0).(func(context.Context, model.ListQuery) []schema.Product); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]schema.Product)
+		}
+	}
+
+	var r1 int64
+	if rf, ok := ret.Get(1).(func(context.Context, model.ListQuery) int64); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, model.ListQuery) error); ok {
+		r2 = rf(ctx, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetById provides a mock function with given fields: ctx, id
 func (_m *ProductStorage) GetById(ctx context.Context, id int64) (*schema.Product, error) {
 	ret := _m.Called(ctx, id)
@@ -96,12 +231,332 @@ func (_m *ProductStorage) GetById(ctx context.Context, id int64) (*schema.Produc
 	return r0, r1
 }
 
-// Insert provides a mock function with given fields: ctx, data
-func (_m *ProductStorage) Insert(ctx context.Context, data schema.Product) error {
+// GetImageById provides a mock function with given fields: ctx, id
+func (_m *ProductStorage) GetImageById(ctx context.Context, id int64) (*schema.ProductImage, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *schema.ProductImage
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *schema.ProductImage); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*schema.ProductImage)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetImages provides a mock function with given fields: ctx, productIds
+func (_m *ProductStorage) GetImages(ctx context.Context, productIds []int64) ([]schema.ProductImage, error) {
+	ret := _m.Called(ctx, productIds)
+
+	var r0 []schema.ProductImage
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) []schema.ProductImage); ok {
+		r0 = rf(ctx, productIds)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]schema.ProductImage)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
+		r1 = rf(ctx, productIds)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetImportJob provides a mock function with given fields: ctx, id
+func (_m *ProductStorage) GetImportJob(ctx context.Context, id int64) (*schema.ProductImportJob, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *schema.ProductImportJob
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *schema.ProductImportJob); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*schema.ProductImportJob)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOptions provides a mock function with given fields: ctx, productId
+func (_m *ProductStorage) GetOptions(ctx context.Context, productId int64) ([]schema.ProductOption, error) {
+	ret := _m.Called(ctx, productId)
+
+	var r0 []schema.ProductOption
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []schema.ProductOption); ok {
+		r0 = rf(ctx, productId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]schema.ProductOption)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, productId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPrices provides a mock function with given fields: ctx, productId
+func (_m *ProductStorage) GetPrices(ctx context.Context, productId int64) ([]schema.ProductPrice, error) {
+	ret := _m.Called(ctx, productId)
+
+	var r0 []schema.ProductPrice
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []schema.ProductPrice); ok {
+		r0 = rf(ctx, productId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]schema.ProductPrice)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, productId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStock provides a mock function with given fields: ctx, productIds
+func (_m *ProductStorage) GetStock(ctx context.Context, productIds []int64) ([]inventory.Stock, error) {
+	ret := _m.Called(ctx, productIds)
+
+	var r0 []inventory.Stock
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) []inventory.Stock); ok {
+		r0 = rf(ctx, productIds)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]inventory.Stock)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
+		r1 = rf(ctx, productIds)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTrash provides a mock function with given fields: ctx, query
+func (_m *ProductStorage) GetTrash(ctx context.Context, query model.ListQuery) ([]schema.Product, int64, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []schema.Product
+	if rf, ok := ret.Get(0).(func(context.Context, model.ListQuery) []schema.Product); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]schema.Product)
+		}
+	}
+
+	var r1 int64
+	if rf, ok := ret.Get(1).(func(context.Context, model.ListQuery) int64); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, model.ListQuery) error); ok {
+		r2 = rf(ctx, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetVariantById provides a mock function with given fields: ctx, id
+func (_m *ProductStorage) GetVariantById(ctx context.Context, id int64) (*schema.ProductVariant, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *schema.ProductVariant
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *schema.ProductVariant); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*schema.ProductVariant)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetVariants provides a mock function with given fields: ctx, productId
+func (_m *ProductStorage) GetVariants(ctx context.Context, productId int64) ([]schema.ProductVariant, error) {
+	ret := _m.Called(ctx, productId)
+
+	var r0 []schema.ProductVariant
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []schema.ProductVariant); ok {
+		r0 = rf(ctx, productId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]schema.ProductVariant)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, productId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Import provides a mock function with given fields: ctx, rows
+func (_m *ProductStorage) Import(ctx context.Context, rows []product.ImportRow) ([]product.ImportResult, error) {
+	ret := _m.Called(ctx, rows)
+
+	var r0 []product.ImportResult
+	if rf, ok := ret.Get(0).(func(context.Context, []product.ImportRow) []product.ImportResult); ok {
+		r0 = rf(ctx, rows)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]product.ImportResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []product.ImportRow) error); ok {
+		r1 = rf(ctx, rows)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Insert provides a mock function with given fields: ctx, data, sku
+func (_m *ProductStorage) Insert(ctx context.Context, data schema.Product, sku string) error {
+	ret := _m.Called(ctx, data, sku)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, schema.Product, string) error); ok {
+		r0 = rf(ctx, data, sku)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertImage provides a mock function with given fields: ctx, data
+func (_m *ProductStorage) InsertImage(ctx context.Context, data schema.ProductImage) (*schema.ProductImage, error) {
+	ret := _m.Called(ctx, data)
+
+	var r0 *schema.ProductImage
+	if rf, ok := ret.Get(0).(func(context.Context, schema.ProductImage) *schema.ProductImage); ok {
+		r0 = rf(ctx, data)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*schema.ProductImage)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, schema.ProductImage) error); ok {
+		r1 = rf(ctx, data)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InsertImportJob provides a mock function with given fields: ctx, data
+func (_m *ProductStorage) InsertImportJob(ctx context.Context, data schema.ProductImportJob) (*schema.ProductImportJob, error) {
+	ret := _m.Called(ctx, data)
+
+	var r0 *schema.ProductImportJob
+	if rf, ok := ret.Get(0).(func(context.Context, schema.ProductImportJob) *schema.ProductImportJob); ok {
+		r0 = rf(ctx, data)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*schema.ProductImportJob)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, schema.ProductImportJob) error); ok {
+		r1 = rf(ctx, data)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InsertPrice provides a mock function with given fields: ctx, data
+func (_m *ProductStorage) InsertPrice(ctx context.Context, data schema.ProductPrice) (*schema.ProductPrice, error) {
+	ret := _m.Called(ctx, data)
+
+	var r0 *schema.ProductPrice
+	if rf, ok := ret.Get(0).(func(context.Context, schema.ProductPrice) *schema.ProductPrice); ok {
+		r0 = rf(ctx, data)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*schema.ProductPrice)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, schema.ProductPrice) error); ok {
+		r1 = rf(ctx, data)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InsertVariant provides a mock function with given fields: ctx, data
+func (_m *ProductStorage) InsertVariant(ctx context.Context, data schema.ProductVariant) error {
 	ret := _m.Called(ctx, data)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, schema.Product) error); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, schema.ProductVariant) error); ok {
 		r0 = rf(ctx, data)
 	} else {
 		r0 = ret.Error(0)
@@ -110,6 +565,92 @@ func (_m *ProductStorage) Insert(ctx context.Context, data schema.Product) error
 	return r0
 }
 
+// Patch provides a mock function with given fields: ctx, id, version, values
+func (_m *ProductStorage) Patch(ctx context.Context, id int64, version uint, values map[string]interface{}) error {
+	ret := _m.Called(ctx, id, version, values)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, uint, map[string]interface{}) error); ok {
+		r0 = rf(ctx, id, version, values)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Purge provides a mock function with given fields: ctx, before
+func (_m *ProductStorage) Purge(ctx context.Context, before time.Time) (int64, []schema.ProductImage, error) {
+	ret := _m.Called(ctx, before)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 []schema.ProductImage
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) []schema.ProductImage); ok {
+		r1 = rf(ctx, before)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]schema.ProductImage)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, time.Time) error); ok {
+		r2 = rf(ctx, before)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ReorderImages provides a mock function with given fields: ctx, productId, ids
+func (_m *ProductStorage) ReorderImages(ctx context.Context, productId int64, ids []int64) error {
+	ret := _m.Called(ctx, productId, ids)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []int64) error); ok {
+		r0 = rf(ctx, productId, ids)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Restore provides a mock function with given fields: ctx, id
+func (_m *ProductStorage) Restore(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetOptions provides a mock function with given fields: ctx, productId, options
+func (_m *ProductStorage) SetOptions(ctx context.Context, productId int64, options []schema.ProductOption) error {
+	ret := _m.Called(ctx, productId, options)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []schema.ProductOption) error); ok {
+		r0 = rf(ctx, productId, options)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, data
 func (_m *ProductStorage) Update(ctx context.Context, data schema.Product) error {
 	ret := _m.Called(ctx, data)
@@ -123,3 +664,31 @@ func (_m *ProductStorage) Update(ctx context.Context, data schema.Product) error
 
 	return r0
 }
+
+// UpdateImportJob provides a mock function with given fields: ctx, data
+func (_m *ProductStorage) UpdateImportJob(ctx context.Context, data schema.ProductImportJob) error {
+	ret := _m.Called(ctx, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, schema.ProductImportJob) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateVariant provides a mock function with given fields: ctx, data
+func (_m *ProductStorage) UpdateVariant(ctx context.Context, data schema.ProductVariant) error {
+	ret := _m.Called(ctx, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, schema.ProductVariant) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
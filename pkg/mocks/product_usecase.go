@@ -7,6 +7,10 @@ import (
 	model "kanggo/pkg/entity/model"
 
 	mock "github.com/stretchr/testify/mock"
+
+	search "kanggo/pkg/search"
+
+	sheet "kanggo/pkg/sheet"
 )
 
 // ProductUsecase is an autogenerated mock type for the ProductUsecase type
@@ -14,13 +18,34 @@ type ProductUsecase struct {
 	mock.Mock
 }
 
-// Delete provides a mock function with given fields: ctx, id
-func (_m *ProductUsecase) Delete(ctx context.Context, id int64) error {
-	ret := _m.Called(ctx, id)
+// ApplyPrices provides a mock function with given fields: ctx
+func (_m *ProductUsecase) ApplyPrices(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CancelPrice provides a mock function with given fields: ctx, productId, id
+func (_m *ProductUsecase) CancelPrice(ctx context.Context, productId int64, id int64) error {
+	ret := _m.Called(ctx, productId, id)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, productId, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -28,27 +53,90 @@ func (_m *ProductUsecase) Delete(ctx context.Context, id int64) error {
 	return r0
 }
 
-// GetAll provides a mock function with given fields: ctx
-func (_m *ProductUsecase) GetAll(ctx context.Context) ([]model.ProductResponse, error) {
-	ret := _m.Called(ctx)
+// Delete provides a mock function with given fields: ctx, id, version
+func (_m *ProductUsecase) Delete(ctx context.Context, id int64, version uint) error {
+	ret := _m.Called(ctx, id, version)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, uint) error); ok {
+		r0 = rf(ctx, id, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteImage provides a mock function with given fields: ctx, productId, id
+func (_m *ProductUsecase) DeleteImage(ctx context.Context, productId int64, id int64) error {
+	ret := _m.Called(ctx, productId, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, productId, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteVariant provides a mock function with given fields: ctx, productId, id
+func (_m *ProductUsecase) DeleteVariant(ctx context.Context, productId int64, id int64) error {
+	ret := _m.Called(ctx, productId, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, productId, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExportProducts provides a mock function with given fields: ctx, w
+func (_m *ProductUsecase) ExportProducts(ctx context.Context, w sheet.Writer) error {
+	ret := _m.Called(ctx, w)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, sheet.Writer) error); ok {
+		r0 = rf(ctx, w)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAll provides a mock function with given fields: ctx, query
+func (_m *ProductUsecase) GetAll(ctx context.Context, query model.ListQuery) ([]model.ProductResponse, int64, error) {
+	ret := _m.Called(ctx, query)
 
 	var r0 []model.ProductResponse
-	if rf, ok := ret.Get(0).(func(context.Context) []model.ProductResponse); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, model.ListQuery) []model.ProductResponse); ok {
+		r0 = rf(ctx, query)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.ProductResponse)
 		}
 	}
 
-	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	var r1 int64
+	if rf, ok := ret.Get(1).(func(context.Context, model.ListQuery) int64); ok {
+		r1 = rf(ctx, query)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(int64)
 	}
 
-	return r0, r1
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, model.ListQuery) error); ok {
+		r2 = rf(ctx, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
 // GetById provides a mock function with given fields: ctx, id
@@ -74,6 +162,174 @@ func (_m *ProductUsecase) GetById(ctx context.Context, id int64) (*model.Product
 	return r0, r1
 }
 
+// GetImages provides a mock function with given fields: ctx, productId
+func (_m *ProductUsecase) GetImages(ctx context.Context, productId int64) ([]model.ProductImageResponse, error) {
+	ret := _m.Called(ctx, productId)
+
+	var r0 []model.ProductImageResponse
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []model.ProductImageResponse); ok {
+		r0 = rf(ctx, productId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.ProductImageResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, productId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetImportJob provides a mock function with given fields: ctx, id
+func (_m *ProductUsecase) GetImportJob(ctx context.Context, id int64) (*model.ProductImportJobResponse, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *model.ProductImportJobResponse
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *model.ProductImportJobResponse); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ProductImportJobResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOptions provides a mock function with given fields: ctx, productId
+func (_m *ProductUsecase) GetOptions(ctx context.Context, productId int64) ([]model.ProductOptionResponse, error) {
+	ret := _m.Called(ctx, productId)
+
+	var r0 []model.ProductOptionResponse
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []model.ProductOptionResponse); ok {
+		r0 = rf(ctx, productId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.ProductOptionResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, productId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPrices provides a mock function with given fields: ctx, productId
+func (_m *ProductUsecase) GetPrices(ctx context.Context, productId int64) ([]model.ProductPriceResponse, error) {
+	ret := _m.Called(ctx, productId)
+
+	var r0 []model.ProductPriceResponse
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []model.ProductPriceResponse); ok {
+		r0 = rf(ctx, productId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.ProductPriceResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, productId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTrash provides a mock function with given fields: ctx, query
+func (_m *ProductUsecase) GetTrash(ctx context.Context, query model.ListQuery) ([]model.ProductResponse, int64, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []model.ProductResponse
+	if rf, ok := ret.Get(0).(func(context.Context, model.ListQuery) []model.ProductResponse); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.ProductResponse)
+		}
+	}
+
+	var r1 int64
+	if rf, ok := ret.Get(1).(func(context.Context, model.ListQuery) int64); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, model.ListQuery) error); ok {
+		r2 = rf(ctx, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetVariants provides a mock function with given fields: ctx, productId
+func (_m *ProductUsecase) GetVariants(ctx context.Context, productId int64) ([]model.ProductVariantResponse, error) {
+	ret := _m.Called(ctx, productId)
+
+	var r0 []model.ProductVariantResponse
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []model.ProductVariantResponse); ok {
+		r0 = rf(ctx, productId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.ProductVariantResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, productId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ImportProducts provides a mock function with given fields: ctx, filename, data, request, userId
+func (_m *ProductUsecase) ImportProducts(ctx context.Context, filename string, data []byte, request model.ProductImportRequest, userId int64) (*model.ProductImportJobResponse, error) {
+	ret := _m.Called(ctx, filename, data, request, userId)
+
+	var r0 *model.ProductImportJobResponse
+	if rf, ok := ret.Get(0).(func(context.Context, string, []byte, model.ProductImportRequest, int64) *model.ProductImportJobResponse); ok {
+		r0 = rf(ctx, filename, data, request, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ProductImportJobResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, []byte, model.ProductImportRequest, int64) error); ok {
+		r1 = rf(ctx, filename, data, request, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Insert provides a mock function with given fields: ctx, data
 func (_m *ProductUsecase) Insert(ctx context.Context, data model.ProductRequest) error {
 	ret := _m.Called(ctx, data)
@@ -88,6 +344,150 @@ func (_m *ProductUsecase) Insert(ctx context.Context, data model.ProductRequest)
 	return r0
 }
 
+// InsertVariant provides a mock function with given fields: ctx, productId, data
+func (_m *ProductUsecase) InsertVariant(ctx context.Context, productId int64, data model.ProductVariantRequest) error {
+	ret := _m.Called(ctx, productId, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, model.ProductVariantRequest) error); ok {
+		r0 = rf(ctx, productId, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Patch provides a mock function with given fields: ctx, id, data
+func (_m *ProductUsecase) Patch(ctx context.Context, id uint, data model.ProductPatchRequest) error {
+	ret := _m.Called(ctx, id, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, model.ProductPatchRequest) error); ok {
+		r0 = rf(ctx, id, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Purge provides a mock function with given fields: ctx
+func (_m *ProductUsecase) Purge(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReorderImages provides a mock function with given fields: ctx, productId, data
+func (_m *ProductUsecase) ReorderImages(ctx context.Context, productId int64, data model.ProductImageOrderRequest) error {
+	ret := _m.Called(ctx, productId, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, model.ProductImageOrderRequest) error); ok {
+		r0 = rf(ctx, productId, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Restore provides a mock function with given fields: ctx, id
+func (_m *ProductUsecase) Restore(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SchedulePrice provides a mock function with given fields: ctx, productId, data
+func (_m *ProductUsecase) SchedulePrice(ctx context.Context, productId int64, data model.ProductPriceRequest) (*model.ProductPriceResponse, error) {
+	ret := _m.Called(ctx, productId, data)
+
+	var r0 *model.ProductPriceResponse
+	if rf, ok := ret.Get(0).(func(context.Context, int64, model.ProductPriceRequest) *model.ProductPriceResponse); ok {
+		r0 = rf(ctx, productId, data)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ProductPriceResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, model.ProductPriceRequest) error); ok {
+		r1 = rf(ctx, productId, data)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Search provides a mock function with given fields: ctx, query, category
+func (_m *ProductUsecase) Search(ctx context.Context, query search.Query, category string) (*model.ProductSearchResponse, int64, error) {
+	ret := _m.Called(ctx, query, category)
+
+	var r0 *model.ProductSearchResponse
+	if rf, ok := ret.Get(0).(func(context.Context, search.Query, string) *model.ProductSearchResponse); ok {
+		r0 = rf(ctx, query, category)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ProductSearchResponse)
+		}
+	}
+
+	var r1 int64
+	if rf, ok := ret.Get(1).(func(context.Context, search.Query, string) int64); ok {
+		r1 = rf(ctx, query, category)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, search.Query, string) error); ok {
+		r2 = rf(ctx, query, category)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// SetOptions provides a mock function with given fields: ctx, productId, data
+func (_m *ProductUsecase) SetOptions(ctx context.Context, productId int64, data model.ProductOptionsRequest) error {
+	ret := _m.Called(ctx, productId, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, model.ProductOptionsRequest) error); ok {
+		r0 = rf(ctx, productId, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, id, data
 func (_m *ProductUsecase) Update(ctx context.Context, id uint, data model.ProductRequest) error {
 	ret := _m.Called(ctx, id, data)
@@ -101,3 +501,40 @@ func (_m *ProductUsecase) Update(ctx context.Context, id uint, data model.Produc
 
 	return r0
 }
+
+// UpdateVariant provides a mock function with given fields: ctx, productId, id, data
+func (_m *ProductUsecase) UpdateVariant(ctx context.Context, productId int64, id int64, data model.ProductVariantRequest) error {
+	ret := _m.Called(ctx, productId, id, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, model.ProductVariantRequest) error); ok {
+		r0 = rf(ctx, productId, id, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UploadImage provides a mock function with given fields: ctx, productId, data
+func (_m *ProductUsecase) UploadImage(ctx context.Context, productId int64, data []byte) (*model.ProductImageResponse, error) {
+	ret := _m.Called(ctx, productId, data)
+
+	var r0 *model.ProductImageResponse
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []byte) *model.ProductImageResponse); ok {
+		r0 = rf(ctx, productId, data)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ProductImageResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, []byte) error); ok {
+		r1 = rf(ctx, productId, data)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -4,9 +4,13 @@ package mocks
 
 import (
 	context "context"
-	schema "kanggo/pkg/entity/schema"
+	model "kanggo/pkg/entity/model"
 
 	mock "github.com/stretchr/testify/mock"
+
+	schema "kanggo/pkg/entity/schema"
+
+	time "time"
 )
 
 // UserStorage is an autogenerated mock type for the UserStorage type
@@ -14,6 +18,64 @@ type UserStorage struct {
 	mock.Mock
 }
 
+// ConfirmEmail provides a mock function with given fields: ctx, id, tokenHash
+func (_m *UserStorage) ConfirmEmail(ctx context.Context, id uint64, tokenHash string) error {
+	ret := _m.Called(ctx, id, tokenHash)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string) error); ok {
+		r0 = rf(ctx, id, tokenHash)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *UserStorage) Delete(ctx context.Context, id uint64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAll provides a mock function with given fields: ctx, search, limit, offset
+func (_m *UserStorage) GetAll(ctx context.Context, search string, limit int, offset int) ([]schema.User, int64, error) {
+	ret := _m.Called(ctx, search, limit, offset)
+
+	var r0 []schema.User
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) []schema.User); ok {
+		r0 = rf(ctx, search, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]schema.User)
+		}
+	}
+
+	var r1 int64
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int) int64); ok {
+		r1 = rf(ctx, search, limit, offset)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string, int, int) error); ok {
+		r2 = rf(ctx, search, limit, offset)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetByEmail provides a mock function with given fields: ctx, email
 func (_m *UserStorage) GetByEmail(ctx context.Context, email string) (*schema.User, error) {
 	ret := _m.Called(ctx, email)
@@ -37,6 +99,52 @@ func (_m *UserStorage) GetByEmail(ctx context.Context, email string) (*schema.Us
 	return r0, r1
 }
 
+// GetById provides a mock function with given fields: ctx, id
+func (_m *UserStorage) GetById(ctx context.Context, id uint64) (*schema.User, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *schema.User
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) *schema.User); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*schema.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrderSummary provides a mock function with given fields: ctx, id
+func (_m *UserStorage) GetOrderSummary(ctx context.Context, id uint64) (*model.OrderSummary, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *model.OrderSummary
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) *model.OrderSummary); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.OrderSummary)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Insert provides a mock function with given fields: ctx, data
 func (_m *UserStorage) Insert(ctx context.Context, data schema.User) error {
 	ret := _m.Called(ctx, data)
@@ -50,3 +158,87 @@ func (_m *UserStorage) Insert(ctx context.Context, data schema.User) error {
 
 	return r0
 }
+
+// SetPasswordResetRequired provides a mock function with given fields: ctx, id
+func (_m *UserStorage) SetPasswordResetRequired(ctx context.Context, id uint64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetPendingEmail provides a mock function with given fields: ctx, id, email, tokenHash, expiresAt
+func (_m *UserStorage) SetPendingEmail(ctx context.Context, id uint64, email string, tokenHash string, expiresAt time.Time) error {
+	ret := _m.Called(ctx, id, email, tokenHash, expiresAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string, string, time.Time) error); ok {
+		r0 = rf(ctx, id, email, tokenHash, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, data
+func (_m *UserStorage) Update(ctx context.Context, data schema.User) error {
+	ret := _m.Called(ctx, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, schema.User) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePassword provides a mock function with given fields: ctx, id, password
+func (_m *UserStorage) UpdatePassword(ctx context.Context, id uint64, password string) error {
+	ret := _m.Called(ctx, id, password)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string) error); ok {
+		r0 = rf(ctx, id, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateRole provides a mock function with given fields: ctx, id, role
+func (_m *UserStorage) UpdateRole(ctx context.Context, id uint64, role string) error {
+	ret := _m.Called(ctx, id, role)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string) error); ok {
+		r0 = rf(ctx, id, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateStatus provides a mock function with given fields: ctx, id, status
+func (_m *UserStorage) UpdateStatus(ctx context.Context, id uint64, status string) error {
+	ret := _m.Called(ctx, id, status)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string) error); ok {
+		r0 = rf(ctx, id, status)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -14,6 +14,71 @@ type UserUsecase struct {
 	mock.Mock
 }
 
+// ConfirmEmail provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserUsecase) ConfirmEmail(_a0 context.Context, _a1 uint64, _a2 model.VerifyEmailRequest) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, model.VerifyEmailRequest) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserUsecase) Delete(_a0 context.Context, _a1 uint64, _a2 model.DeleteAccountRequest) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, model.DeleteAccountRequest) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ForcePasswordReset provides a mock function with given fields: ctx, id
+func (_m *UserUsecase) ForcePasswordReset(ctx context.Context, id uint64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAll provides a mock function with given fields: ctx, search, page, size
+func (_m *UserUsecase) GetAll(ctx context.Context, search string, page int, size int) (*model.UserListResponse, error) {
+	ret := _m.Called(ctx, search, page, size)
+
+	var r0 *model.UserListResponse
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) *model.UserListResponse); ok {
+		r0 = rf(ctx, search, page, size)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.UserListResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int) error); ok {
+		r1 = rf(ctx, search, page, size)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetByEmail provides a mock function with given fields: _a0, _a1
 func (_m *UserUsecase) GetByEmail(_a0 context.Context, _a1 string) (*model.UserResponse, error) {
 	ret := _m.Called(_a0, _a1)
@@ -37,6 +102,52 @@ func (_m *UserUsecase) GetByEmail(_a0 context.Context, _a1 string) (*model.UserR
 	return r0, r1
 }
 
+// GetById provides a mock function with given fields: _a0, _a1
+func (_m *UserUsecase) GetById(_a0 context.Context, _a1 uint64) (*model.UserResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *model.UserResponse
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) *model.UserResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.UserResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDetail provides a mock function with given fields: ctx, id
+func (_m *UserUsecase) GetDetail(ctx context.Context, id uint64) (*model.UserDetailResponse, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *model.UserDetailResponse
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) *model.UserDetailResponse); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.UserDetailResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Insert provides a mock function with given fields: _a0, _a1
 func (_m *UserUsecase) Insert(_a0 context.Context, _a1 model.RegisterRequest) error {
 	ret := _m.Called(_a0, _a1)
@@ -50,3 +161,73 @@ func (_m *UserUsecase) Insert(_a0 context.Context, _a1 model.RegisterRequest) er
 
 	return r0
 }
+
+// Reactivate provides a mock function with given fields: ctx, id
+func (_m *UserUsecase) Reactivate(ctx context.Context, id uint64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Suspend provides a mock function with given fields: ctx, id
+func (_m *UserUsecase) Suspend(ctx context.Context, id uint64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePassword provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserUsecase) UpdatePassword(_a0 context.Context, _a1 uint64, _a2 model.ChangePasswordRequest) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, model.ChangePasswordRequest) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateProfile provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserUsecase) UpdateProfile(_a0 context.Context, _a1 uint64, _a2 model.UpdateProfileRequest) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, model.UpdateProfileRequest) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateRole provides a mock function with given fields: ctx, id, req
+func (_m *UserUsecase) UpdateRole(ctx context.Context, id uint64, req model.UpdateRoleRequest) error {
+	ret := _m.Called(ctx, id, req)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, model.UpdateRoleRequest) error); ok {
+		r0 = rf(ctx, id, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
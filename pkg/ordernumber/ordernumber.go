@@ -0,0 +1,120 @@
+package ordernumber
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// DefaultFormat gives numbers like KG-20261018-000123.
+const DefaultFormat = "KG-{YYYY}{MM}{DD}-{SEQ:6}"
+
+var (
+	ErrNoSequence = errors.New("order number format must contain {SEQ}")
+	ErrNumeric    = errors.New("order number format must contain a non-digit character")
+	ErrNoDate     = errors.New("order number format must contain a year, {MM} and {DD}")
+)
+
+// Format renders order numbers from a pattern of literal text and the
+// placeholders {YYYY}, {YY}, {MM}, {DD} and {SEQ} or {SEQ:n}, where n pads
+// the daily sequence with zeros. A number always contains a non-digit
+// character so it can never be mistaken for an order id, and the full date,
+// since the sequence starts over every day.
+type Format struct {
+	parts []part
+}
+
+type part struct {
+	literal string
+	token   string
+	width   int
+}
+
+func Parse(pattern string) (Format, error) {
+	var f Format
+	var sequence, nonDigit bool
+	tokens := map[string]bool{}
+
+	rest := pattern
+	for rest != "" {
+		start := strings.Index(rest, "{")
+		if start < 0 {
+			f.parts = append(f.parts, part{literal: rest})
+			break
+		}
+		if start > 0 {
+			f.parts = append(f.parts, part{literal: rest[:start]})
+		}
+
+		end := strings.Index(rest[start:], "}")
+		if end < 0 {
+			return Format{}, fmt.Errorf("unclosed placeholder in order number format %q", pattern)
+		}
+		token := rest[start+1 : start+end]
+		rest = rest[start+end+1:]
+
+		switch {
+		case token == "YYYY" || token == "YY" || token == "MM" || token == "DD":
+			tokens[token] = true
+			f.parts = append(f.parts, part{token: token})
+		case token == "SEQ":
+			sequence = true
+			f.parts = append(f.parts, part{token: "SEQ", width: 1})
+		case strings.HasPrefix(token, "SEQ:"):
+			width, err := strconv.Atoi(token[4:])
+			if err != nil || width < 1 || width > 12 {
+				return Format{}, fmt.Errorf("invalid sequence width in order number format %q", pattern)
+			}
+			sequence = true
+			f.parts = append(f.parts, part{token: "SEQ", width: width})
+		default:
+			return Format{}, fmt.Errorf("unknown placeholder {%s} in order number format %q", token, pattern)
+		}
+	}
+
+	for _, p := range f.parts {
+		if strings.IndexFunc(p.literal, func(r rune) bool { return !unicode.IsDigit(r) }) >= 0 {
+			nonDigit = true
+		}
+	}
+
+	if !sequence {
+		return Format{}, ErrNoSequence
+	}
+
+	if !nonDigit {
+		return Format{}, ErrNumeric
+	}
+
+	if !(tokens["YYYY"] || tokens["YY"]) || !tokens["MM"] || !tokens["DD"] {
+		return Format{}, ErrNoDate
+	}
+
+	return f, nil
+}
+
+// Number renders the number of the seq-th order of the day at.
+func (f Format) Number(at time.Time, seq int64) string {
+	var b strings.Builder
+	for _, p := range f.parts {
+		switch p.token {
+		case "":
+			b.WriteString(p.literal)
+		case "YYYY":
+			fmt.Fprintf(&b, "%04d", at.Year())
+		case "YY":
+			fmt.Fprintf(&b, "%02d", at.Year()%100)
+		case "MM":
+			fmt.Fprintf(&b, "%02d", int(at.Month()))
+		case "DD":
+			fmt.Fprintf(&b, "%02d", at.Day())
+		case "SEQ":
+			fmt.Fprintf(&b, "%0*d", p.width, seq)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,50 @@
+package ordernumber
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNumber(t *testing.T) {
+	at := time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)
+
+	t.Run("default", func(t *testing.T) {
+		f, err := Parse(DefaultFormat)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "KG-20261018-000123", f.Number(at, 123))
+		assert.Equal(t, "KG-20261018-1234567", f.Number(at, 1234567))
+	})
+
+	t.Run("custom", func(t *testing.T) {
+		f, err := Parse("ORD/{YY}{MM}/{DD}/{SEQ}")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ORD/2610/18/7", f.Number(at, 7))
+	})
+}
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		pattern string
+		err     string
+	}{
+		{"KG-{YYYY}{MM}{DD}", ErrNoSequence.Error()},
+		{"{YYYY}{MM}{DD}{SEQ:6}", ErrNumeric.Error()},
+		{"KG-{SEQ:x}", `invalid sequence width in order number format "KG-{SEQ:x}"`},
+		{"KG-{HH}-{SEQ}", `unknown placeholder {HH} in order number format "KG-{HH}-{SEQ}"`},
+		{"KG-{SEQ", `unclosed placeholder in order number format "KG-{SEQ"`},
+		{"KG-{SEQ:6}", ErrNoDate.Error()},
+		{"KG-{YYYY}{MM}-{SEQ}", ErrNoDate.Error()},
+		{"KG-{MM}{DD}-{SEQ}", ErrNoDate.Error()},
+		{"KG-{YY}{DD}-{SEQ}", ErrNoDate.Error()},
+	}
+
+	for _, tc := range cases {
+		_, err := Parse(tc.pattern)
+
+		assert.EqualError(t, err, tc.err, tc.pattern)
+	}
+}
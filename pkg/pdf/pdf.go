@@ -0,0 +1,140 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A4 in points
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+type Font string
+
+const (
+	Helvetica     Font = "F1"
+	HelveticaBold Font = "F2"
+)
+
+// Document is a minimal PDF writer for text documents such as invoices. It
+// only uses the standard Helvetica fonts, so no font files are embedded. Text
+// is WinAnsi encoded; characters outside Latin-1 are printed as '?'.
+// Coordinates are in points from the top-left corner of the page.
+type Document struct {
+	pages []*bytes.Buffer
+}
+
+func New() *Document {
+	d := &Document{}
+	d.AddPage()
+	return d
+}
+
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+}
+
+func (d *Document) current() *bytes.Buffer {
+	return d.pages[len(d.pages)-1]
+}
+
+func (d *Document) Text(x, y float64, font Font, size float64, s string) {
+	fmt.Fprintf(d.current(), "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n",
+		font, size, x, PageHeight-y, escape(s))
+}
+
+// TextRight draws s so that it ends at x.
+func (d *Document) TextRight(x, y float64, font Font, size float64, s string) {
+	d.Text(x-Width(s, size), y, font, size, s)
+}
+
+func (d *Document) Line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(d.current(), "0.5 w %.2f %.2f m %.2f %.2f l S\n",
+		x1, PageHeight-y1, x2, PageHeight-y2)
+}
+
+// Bytes assembles the document: catalog, page tree, the two fonts and a
+// page plus content stream per page, followed by the cross-reference table.
+func (d *Document) Bytes() []byte {
+	var buf bytes.Buffer
+	var offsets []int
+
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+i*2)
+	}
+
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			PageWidth, PageHeight, 6+i*2))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.Bytes()
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(byte(r))
+		case r >= 32 && r < 127, r >= 160 && r <= 255:
+			b.WriteByte(byte(r))
+		default:
+			b.WriteByte('?')
+		}
+	}
+
+	return b.String()
+}
+
+// helvetica holds the Helvetica glyph widths of ASCII 32-126 in 1/1000 em.
+// Helvetica-Bold has the same widths for digits and punctuation, which is
+// what gets right-aligned.
+var helvetica = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// Width returns the width of s in points when set in Helvetica at size.
+func Width(s string, size float64) float64 {
+	var units int
+	for _, r := range s {
+		if r >= 32 && r < 127 {
+			units += helvetica[r-32]
+		} else {
+			units += 556
+		}
+	}
+
+	return float64(units) * size / 1000
+}
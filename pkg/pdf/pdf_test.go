@@ -0,0 +1,46 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBytes(t *testing.T) {
+	d := New()
+	d.Text(40, 40, HelveticaBold, 18, "INVOICE (copy)")
+	d.Line(40, 50, 555, 50)
+	d.AddPage()
+	d.TextRight(555, 40, Helvetica, 10, "Rp 1.000,00")
+
+	out := d.Bytes()
+
+	assert.True(t, bytes.HasPrefix(out, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(out, []byte("%%EOF\n")))
+	assert.Contains(t, string(out), "/Count 2")
+	assert.Contains(t, string(out), `(INVOICE \(copy\)) Tj`)
+
+	// every xref entry must point at the start of its object
+	start := bytes.LastIndex(out, []byte("startxref\n"))
+	xref, err := strconv.Atoi(string(bytes.Fields(out[start+len("startxref\n"):])[0]))
+	assert.Nil(t, err)
+	entries := bytes.Split(out[xref:], []byte("\n"))[3:11]
+	for i, entry := range entries {
+		offset, err := strconv.Atoi(string(entry[:10]))
+		assert.Nil(t, err)
+		assert.True(t, bytes.HasPrefix(out[offset:], []byte(fmt.Sprintf("%d 0 obj", i+1))))
+	}
+}
+
+func TestWidth(t *testing.T) {
+	assert.InDelta(t, 5.56, Width("0", 10), 0.001)
+	assert.InDelta(t, 0, Width("", 10), 0.001)
+}
+
+func TestEscape(t *testing.T) {
+	assert.Equal(t, `a\\b \(c\) d?`, escape("a\\b (c) d€"))
+	assert.Equal(t, "caf\xe9", escape("café"))
+}
@@ -0,0 +1,118 @@
+package promotion
+
+import (
+	"errors"
+	"kanggo/pkg/entity/schema"
+	"math"
+	"time"
+)
+
+var (
+	ErrInactive      = errors.New("coupon is not active")
+	ErrNotStarted    = errors.New("coupon is not yet valid")
+	ErrExpired       = errors.New("coupon has expired")
+	ErrMinSpend      = errors.New("minimum spend not reached")
+	ErrNotApplicable = errors.New("coupon does not apply to this product")
+	ErrUsageLimit    = errors.New("coupon usage limit reached")
+)
+
+// Scope is what a coupon is restricted to: the listed products and the
+// products of the listed categories. An empty scope covers every product.
+type Scope struct {
+	ProductIds  []int64
+	CategoryIds []int64
+}
+
+// Covers reports whether the scope includes the product. categoryIds are the
+// categories of the product together with their ancestors.
+func (s Scope) Covers(productId int64, categoryIds []int64) bool {
+	if len(s.ProductIds) == 0 && len(s.CategoryIds) == 0 {
+		return true
+	}
+
+	if contains(s.ProductIds, productId) {
+		return true
+	}
+
+	for _, id := range categoryIds {
+		if contains(s.CategoryIds, id) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Discount returns the discount a coupon gives on an order line.
+// categoryIds are the categories of the product with their ancestors and
+// only matter when the scope lists categories. userRedemptions is how often
+// the user already used the coupon. Usage limits checked here are advisory;
+// the order transaction enforces them again with the coupon row locked.
+func Discount(coupon schema.Coupon, scope Scope, userRedemptions int64,
+	productId int64, categoryIds []int64, amount float64, at time.Time) (float64, error) {
+	if !coupon.Active {
+		return 0, ErrInactive
+	}
+
+	if at.Before(coupon.StartsAt) {
+		return 0, ErrNotStarted
+	}
+
+	if coupon.EndsAt != nil && !at.Before(*coupon.EndsAt) {
+		return 0, ErrExpired
+	}
+
+	if coupon.UsageLimit > 0 && coupon.UsedCount >= coupon.UsageLimit {
+		return 0, ErrUsageLimit
+	}
+
+	if coupon.PerUserLimit > 0 && userRedemptions >= coupon.PerUserLimit {
+		return 0, ErrUsageLimit
+	}
+
+	if !scope.Covers(productId, categoryIds) {
+		return 0, ErrNotApplicable
+	}
+
+	if amount < coupon.MinSpend {
+		return 0, ErrMinSpend
+	}
+
+	var discount float64
+	switch coupon.DiscountType {
+	case "percentage":
+		discount = amount * coupon.Value / 100
+		if coupon.MaxDiscount > 0 && discount > coupon.MaxDiscount {
+			discount = coupon.MaxDiscount
+		}
+	default:
+		discount = coupon.Value
+	}
+
+	if discount > amount {
+		discount = amount
+	}
+
+	return math.Round(discount*100) / 100, nil
+}
+
+func contains(ids []int64, id int64) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsRejection reports whether err is one of the reasons a coupon is refused,
+// as opposed to a storage failure.
+func IsRejection(err error) bool {
+	switch err {
+	case ErrInactive, ErrNotStarted, ErrExpired, ErrMinSpend, ErrNotApplicable, ErrUsageLimit:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,97 @@
+package promotion
+
+import (
+	"kanggo/pkg/entity/schema"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscount(t *testing.T) {
+	now := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	endsAt := time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)
+	coupon := schema.Coupon{
+		Code:         "HEMAT10",
+		DiscountType: "percentage",
+		Value:        10,
+		MaxDiscount:  15000,
+		MinSpend:     50000,
+		UsageLimit:   100,
+		PerUserLimit: 1,
+		StartsAt:     time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC),
+		EndsAt:       &endsAt,
+		Active:       true,
+	}
+
+	t.Run("percentage", func(t *testing.T) {
+		discount, err := Discount(coupon, Scope{}, 0, 1, nil, 100000, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, float64(10000), discount)
+	})
+
+	t.Run("percentage capped", func(t *testing.T) {
+		discount, err := Discount(coupon, Scope{}, 0, 1, nil, 500000, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, float64(15000), discount)
+	})
+
+	t.Run("fixed never exceeds amount", func(t *testing.T) {
+		fixed := coupon
+		fixed.DiscountType = "fixed"
+		fixed.Value = 80000
+
+		discount, err := Discount(fixed, Scope{}, 0, 1, nil, 60000, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, float64(60000), discount)
+	})
+
+	t.Run("rejections", func(t *testing.T) {
+		inactive := coupon
+		inactive.Active = false
+		exhausted := coupon
+		exhausted.UsedCount = 100
+
+		cases := []struct {
+			name   string
+			coupon schema.Coupon
+			scope  Scope
+			used   int64
+			amount float64
+			at     time.Time
+			err    error
+		}{
+			{"inactive", inactive, Scope{}, 0, 100000, now, ErrInactive},
+			{"not started", coupon, Scope{}, 0, 100000, coupon.StartsAt.Add(-time.Second), ErrNotStarted},
+			{"expired", coupon, Scope{}, 0, 100000, endsAt, ErrExpired},
+			{"global limit", exhausted, Scope{}, 0, 100000, now, ErrUsageLimit},
+			{"per user limit", coupon, Scope{}, 1, 100000, now, ErrUsageLimit},
+			{"other product", coupon, Scope{ProductIds: []int64{2, 3}}, 0, 100000, now, ErrNotApplicable},
+			{"other category", coupon, Scope{CategoryIds: []int64{9}}, 0, 100000, now, ErrNotApplicable},
+			{"min spend", coupon, Scope{}, 0, 49999, now, ErrMinSpend},
+		}
+
+		for _, tc := range cases {
+			_, err := Discount(tc.coupon, tc.scope, tc.used, 1, []int64{5, 4}, tc.amount, tc.at)
+
+			assert.Equal(t, tc.err, err, tc.name)
+			assert.True(t, IsRejection(err), tc.name)
+		}
+	})
+}
+
+func TestScopeCovers(t *testing.T) {
+	assert.True(t, Scope{}.Covers(1, nil))
+	assert.True(t, Scope{ProductIds: []int64{1}}.Covers(1, nil))
+	assert.False(t, Scope{ProductIds: []int64{2}}.Covers(1, []int64{4}))
+
+	// a coupon for a parent category covers the products of its subcategories,
+	// whose ancestors are passed along
+	scope := Scope{ProductIds: []int64{2}, CategoryIds: []int64{4}}
+	assert.True(t, scope.Covers(1, []int64{7, 4}))
+	assert.True(t, scope.Covers(2, nil))
+	assert.False(t, scope.Covers(1, []int64{7}))
+}
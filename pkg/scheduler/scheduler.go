@@ -0,0 +1,32 @@
+// Package scheduler runs background jobs at a fixed interval.
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Job is one run of a background job.
+type Job func(ctx context.Context) error
+
+// Every runs job once right away and then at every interval until ctx is
+// done. A failed run is logged and the job is tried again at the next tick,
+// so a job has to be safe to repeat. Every blocks, so call it in its own
+// goroutine.
+func Every(ctx context.Context, name string, interval time.Duration, job Job) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := job(ctx); err != nil {
+			log.Printf("scheduler: %s: %v", name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
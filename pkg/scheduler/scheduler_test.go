@@ -0,0 +1,42 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvery(t *testing.T) {
+	t.Run("runs until cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		runs := 0
+
+		Every(ctx, "test", time.Millisecond, func(ctx context.Context) error {
+			runs++
+			if runs == 3 {
+				cancel()
+			}
+			return nil
+		})
+
+		assert.Equal(t, 3, runs)
+	})
+
+	t.Run("keeps going after a failure", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		runs := 0
+
+		Every(ctx, "test", time.Millisecond, func(ctx context.Context) error {
+			runs++
+			if runs == 2 {
+				cancel()
+			}
+			return errors.New("database is down")
+		})
+
+		assert.Equal(t, 2, runs)
+	})
+}
@@ -0,0 +1,405 @@
+package search
+
+import (
+	"context"
+	"html"
+	"kanggo/pkg/entity/schema"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+//go:generate mockery --name Searcher --case snake --output ../mocks --disable-version-string
+
+type (
+	// Searcher finds the products matching a free text query. The SQL
+	// searcher is the only implementation for now; a dedicated search engine
+	// can be plugged in by implementing this interface.
+	Searcher interface {
+		Search(ctx context.Context, query Query) (*Result, error)
+	}
+
+	// Query is a search request. Page starts from 1. InStock, MinPrice,
+	// MaxPrice and CategoryIds narrow the hits but not the facets.
+	Query struct {
+		Text        string
+		Page        int
+		Size        int
+		InStock     *bool
+		MinPrice    *float64
+		MaxPrice    *float64
+		CategoryIds []int64
+	}
+
+	// Candidate is a product the ranking looks at, with the categories it is
+	// assigned to.
+	Candidate struct {
+		Product    schema.Product
+		Categories []schema.Category
+	}
+
+	Result struct {
+		Hits   []Hit
+		Total  int64
+		Facets Facets
+	}
+
+	// Hit is a matching product. Highlights holds the matching fields with
+	// the matched words wrapped in <em>, HTML escaped.
+	Hit struct {
+		Product    schema.Product
+		Score      float64
+		Highlights map[string]string
+	}
+
+	// Facets counts every product matching the text, before the filters of
+	// the query are applied.
+	Facets struct {
+		Categories []CategoryCount
+		PriceBands []PriceBand
+		InStock    int64
+		OutOfStock int64
+	}
+
+	// CategoryCount counts the products directly assigned to a category.
+	CategoryCount struct {
+		Id    int64
+		Name  string
+		Slug  string
+		Count int64
+	}
+
+	// PriceBand counts the products priced from Min (inclusive) to Max
+	// (exclusive). A Max of 0 means no upper bound.
+	PriceBand struct {
+		Min   float64
+		Max   float64
+		Count int64
+	}
+)
+
+// DefaultPriceBands are the upper bounds of the price facet bands, in rupiah.
+var DefaultPriceBands = []float64{50000, 100000, 500000, 1000000}
+
+const (
+	// MaxTerms is the number of query words taken into account.
+	MaxTerms = 10
+
+	// snippetLength is the number of characters of a highlighted description.
+	snippetLength = 160
+)
+
+// Terms splits a query into lower case words of at least two characters,
+// without duplicates.
+func Terms(text string) []string {
+	terms := []string{}
+	seen := map[string]bool{}
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), isSeparator) {
+		if len([]rune(word)) < 2 || seen[word] {
+			continue
+		}
+		seen[word] = true
+		terms = append(terms, word)
+		if len(terms) == MaxTerms {
+			break
+		}
+	}
+
+	return terms
+}
+
+// Prefix is the part of a term a candidate has to start with. Long terms are
+// cut short so a typo in their last two letters still finds the product.
+func Prefix(term string) string {
+	runes := []rune(term)
+	if len(runes) <= 4 {
+		return term
+	}
+
+	return string(runes[:len(runes)-2])
+}
+
+// Rank scores the candidates against the terms of the query, counts the
+// facets, applies the filters and returns the requested page. Candidates
+// matching none of the terms are dropped.
+func Rank(candidates []Candidate, query Query, bands []float64) *Result {
+	terms := Terms(query.Text)
+	phrase := strings.Join(terms, " ")
+
+	result := &Result{Hits: []Hit{}}
+	result.Facets.Categories = []CategoryCount{}
+	categories := map[int64]int{}
+	filter := map[int64]bool{}
+	for _, id := range query.CategoryIds {
+		filter[id] = true
+	}
+
+	for i := 0; i <= len(bands); i++ {
+		band := PriceBand{}
+		if i > 0 {
+			band.Min = bands[i-1]
+		}
+		if i < len(bands) {
+			band.Max = bands[i]
+		}
+		result.Facets.PriceBands = append(result.Facets.PriceBands, band)
+	}
+
+	hits := []Hit{}
+	for _, candidate := range candidates {
+		product := candidate.Product
+		name := words(product.Name)
+		description := words(product.Description)
+
+		var score float64
+		for _, term := range terms {
+			score += 2*match(term, name) + match(term, description)
+		}
+		if score == 0 {
+			continue
+		}
+		if len(terms) > 1 && strings.Contains(strings.Join(name, " "), phrase) {
+			score += 1
+		}
+
+		for i := range result.Facets.PriceBands {
+			band := &result.Facets.PriceBands[i]
+			if product.Price >= band.Min && (band.Max == 0 || product.Price < band.Max) {
+				band.Count++
+				break
+			}
+		}
+		if product.Qty > 0 {
+			result.Facets.InStock++
+		} else {
+			result.Facets.OutOfStock++
+		}
+
+		inCategory := len(filter) == 0
+		for _, category := range candidate.Categories {
+			id := int64(category.Id)
+			i, ok := categories[id]
+			if !ok {
+				i = len(result.Facets.Categories)
+				categories[id] = i
+				result.Facets.Categories = append(result.Facets.Categories,
+					CategoryCount{Id: id, Name: category.Name, Slug: category.Slug})
+			}
+			result.Facets.Categories[i].Count++
+			inCategory = inCategory || filter[id]
+		}
+
+		if !inCategory {
+			continue
+		}
+
+		if query.InStock != nil && (product.Qty > 0) != *query.InStock {
+			continue
+		}
+		if query.MinPrice != nil && product.Price < *query.MinPrice {
+			continue
+		}
+		if query.MaxPrice != nil && product.Price > *query.MaxPrice {
+			continue
+		}
+
+		hits = append(hits, Hit{Product: product, Score: score})
+	}
+
+	sort.SliceStable(result.Facets.Categories, func(i, j int) bool {
+		a, b := result.Facets.Categories[i], result.Facets.Categories[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Name < b.Name
+	})
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Product.Id < hits[j].Product.Id
+	})
+
+	result.Total = int64(len(hits))
+	start := (query.Page - 1) * query.Size
+	if start < 0 || start > len(hits) {
+		start = len(hits)
+	}
+	end := start + query.Size
+	if end > len(hits) {
+		end = len(hits)
+	}
+
+	for _, hit := range hits[start:end] {
+		hit.Highlights = map[string]string{}
+		if text, ok := Highlight(hit.Product.Name, terms, 0); ok {
+			hit.Highlights["name"] = text
+		}
+		if text, ok := Highlight(hit.Product.Description, terms, snippetLength); ok {
+			hit.Highlights["description"] = text
+		}
+		result.Hits = append(result.Hits, hit)
+	}
+
+	return result
+}
+
+// Highlight wraps the words of text matching any of the terms in <em> and
+// escapes the rest. When length is above 0 and the text is longer, only a
+// snippet of about length characters around the first match is kept. ok is
+// false when nothing matched.
+func Highlight(text string, terms []string, length int) (highlighted string, ok bool) {
+	runes := []rune(text)
+
+	type span struct{ start, end int }
+	spans := []span{}
+	for start := 0; start < len(runes); {
+		if isSeparator(runes[start]) {
+			start++
+			continue
+		}
+		end := start
+		for end < len(runes) && !isSeparator(runes[end]) {
+			end++
+		}
+		word := strings.ToLower(string(runes[start:end]))
+		for _, term := range terms {
+			if match(term, []string{word}) > 0 {
+				spans = append(spans, span{start, end})
+				break
+			}
+		}
+		start = end
+	}
+
+	if len(spans) == 0 {
+		return "", false
+	}
+
+	from, to := 0, len(runes)
+	if length > 0 && len(runes) > length {
+		from = spans[0].start - length/3
+		if from < 0 {
+			from = 0
+		}
+		to = from + length
+		if to > len(runes) {
+			to = len(runes)
+			from = to - length
+		}
+		// do not cut words in half
+		for from > 0 && !isSeparator(runes[from-1]) {
+			from--
+		}
+		for to < len(runes) && !isSeparator(runes[to]) {
+			to++
+		}
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	last := from
+	for _, s := range spans {
+		if s.start < from || s.end > to {
+			continue
+		}
+		b.WriteString(html.EscapeString(string(runes[last:s.start])))
+		b.WriteString("<em>")
+		b.WriteString(html.EscapeString(string(runes[s.start:s.end])))
+		b.WriteString("</em>")
+		last = s.end
+	}
+	b.WriteString(html.EscapeString(string(runes[last:to])))
+	if to < len(runes) {
+		b.WriteString("…")
+	}
+
+	return strings.TrimSpace(b.String()), true
+}
+
+// match tells how well a term matches the best of the words: 1 for the same
+// word, 0.8 for a word starting with the term and 0.5 for a word within the
+// allowed number of typos, either as a whole or in its first letters.
+func match(term string, words []string) float64 {
+	best := 0.0
+	for _, word := range words {
+		switch {
+		case word == term:
+			return 1
+		case strings.HasPrefix(word, term):
+			best = 0.8
+		case best < 0.5 && typos(term, word):
+			best = 0.5
+		}
+	}
+
+	return best
+}
+
+// typos tells whether word, or its beginning as long as term, is within the
+// number of typos allowed for term: none up to three letters, one up to
+// seven and two above.
+func typos(term, word string) bool {
+	a, b := []rune(term), []rune(word)
+	allowed := 2
+	switch {
+	case len(a) <= 3:
+		return false
+	case len(a) <= 7:
+		allowed = 1
+	}
+
+	if distance(a, b) <= allowed {
+		return true
+	}
+	if len(b) > len(a) {
+		return distance(a, b[:len(a)]) <= allowed
+	}
+
+	return false
+}
+
+// distance is the Levenshtein distance of a and b.
+func distance(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
+
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), isSeparator)
+}
+
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+func min(values ...int) int {
+	res := values[0]
+	for _, v := range values[1:] {
+		if v < res {
+			res = v
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,168 @@
+package search
+
+import (
+	"testing"
+
+	"kanggo/pkg/entity/schema"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	semen   = schema.Category{Base: schema.Base{Id: 10}, Name: "Semen", Slug: "semen"}
+	cat     = schema.Category{Base: schema.Base{Id: 11}, Name: "Cat", Slug: "cat"}
+	keramik = schema.Category{Base: schema.Base{Id: 12}, Name: "Keramik", Slug: "keramik"}
+)
+
+var products = []Candidate{
+	{Product: schema.Product{
+		Base:        schema.Base{Id: 1},
+		Name:        "Semen Tiga Roda 50kg",
+		Description: "Semen portland untuk pondasi & cor beton.",
+		Price:       65000,
+		Qty:         100,
+	}, Categories: []schema.Category{semen}},
+	{Product: schema.Product{
+		Base:        schema.Base{Id: 2},
+		Name:        "Cat Tembok Putih",
+		Description: "Cat dinding interior, cepat kering. Cocok untuk plester semen.",
+		Price:       120000,
+		Qty:         0,
+	}, Categories: []schema.Category{cat}},
+	{Product: schema.Product{
+		Base:        schema.Base{Id: 3},
+		Name:        "Semen Putih 40kg",
+		Description: "Semen putih untuk nat keramik.",
+		Price:       45000,
+		Qty:         20,
+	}, Categories: []schema.Category{semen, keramik}},
+	{Product: schema.Product{
+		Base:  schema.Base{Id: 4},
+		Name:  "Pasir Beton",
+		Price: 300000,
+		Qty:   5,
+	}},
+}
+
+func TestTerms(t *testing.T) {
+	assert.Equal(t, []string{"semen", "tiga", "roda"}, Terms("  Semen, tiga-RODA semen a"))
+	assert.Empty(t, Terms("a ! ?"))
+}
+
+func TestPrefix(t *testing.T) {
+	assert.Equal(t, "cat", Prefix("cat"))
+	assert.Equal(t, "pasi", Prefix("pasi"))
+	assert.Equal(t, "sem", Prefix("semen"))
+	assert.Equal(t, "keram", Prefix("keramik"))
+}
+
+func TestRank(t *testing.T) {
+	t.Run("name matches rank first", func(t *testing.T) {
+		res := Rank(products, Query{Text: "semen", Page: 1, Size: 10}, DefaultPriceBands)
+
+		assert.EqualValues(t, 3, res.Total)
+		assert.EqualValues(t, 1, res.Hits[0].Product.Id)
+		assert.EqualValues(t, 3, res.Hits[1].Product.Id)
+		assert.EqualValues(t, 2, res.Hits[2].Product.Id)
+		assert.Equal(t, "<em>Semen</em> Tiga Roda 50kg", res.Hits[0].Highlights["name"])
+		assert.Equal(t, "<em>Semen</em> portland untuk pondasi &amp; cor beton.", res.Hits[0].Highlights["description"])
+		_, ok := res.Hits[2].Highlights["name"]
+		assert.False(t, ok)
+	})
+
+	t.Run("phrase in name ranks above scattered terms", func(t *testing.T) {
+		res := Rank(products, Query{Text: "semen putih", Page: 1, Size: 10}, DefaultPriceBands)
+
+		assert.EqualValues(t, 3, res.Hits[0].Product.Id)
+	})
+
+	t.Run("typo and prefix", func(t *testing.T) {
+		res := Rank(products, Query{Text: "cemen", Page: 1, Size: 10}, DefaultPriceBands)
+		assert.EqualValues(t, 3, res.Total)
+
+		res = Rank(products, Query{Text: "kerami", Page: 1, Size: 10}, DefaultPriceBands)
+		assert.EqualValues(t, 1, res.Total)
+		assert.EqualValues(t, 3, res.Hits[0].Product.Id)
+		assert.Equal(t, "Semen putih untuk nat <em>keramik</em>.", res.Hits[0].Highlights["description"])
+
+		res = Rank(products, Query{Text: "cet", Page: 1, Size: 10}, DefaultPriceBands)
+		assert.EqualValues(t, 0, res.Total)
+	})
+
+	t.Run("facets ignore filters", func(t *testing.T) {
+		inStock := true
+		res := Rank(products, Query{Text: "semen", Page: 1, Size: 10, InStock: &inStock}, DefaultPriceBands)
+
+		assert.EqualValues(t, 2, res.Total)
+		assert.EqualValues(t, 2, res.Facets.InStock)
+		assert.EqualValues(t, 1, res.Facets.OutOfStock)
+		assert.Equal(t, []PriceBand{
+			{Min: 0, Max: 50000, Count: 1},
+			{Min: 50000, Max: 100000, Count: 1},
+			{Min: 100000, Max: 500000, Count: 1},
+			{Min: 500000, Max: 1000000, Count: 0},
+			{Min: 1000000, Max: 0, Count: 0},
+		}, res.Facets.PriceBands)
+	})
+
+	t.Run("category facet and filter", func(t *testing.T) {
+		res := Rank(products, Query{Text: "semen", Page: 1, Size: 10, CategoryIds: []int64{12}}, DefaultPriceBands)
+
+		assert.EqualValues(t, 1, res.Total)
+		assert.EqualValues(t, 3, res.Hits[0].Product.Id)
+		assert.Equal(t, []CategoryCount{
+			{Id: 10, Name: "Semen", Slug: "semen", Count: 2},
+			{Id: 11, Name: "Cat", Slug: "cat", Count: 1},
+			{Id: 12, Name: "Keramik", Slug: "keramik", Count: 1},
+		}, res.Facets.Categories)
+	})
+
+	t.Run("price filter and paging", func(t *testing.T) {
+		max := 100000.0
+		res := Rank(products, Query{Text: "semen", Page: 2, Size: 1, MaxPrice: &max}, DefaultPriceBands)
+
+		assert.EqualValues(t, 2, res.Total)
+		assert.Len(t, res.Hits, 1)
+		assert.EqualValues(t, 3, res.Hits[0].Product.Id)
+
+		res = Rank(products, Query{Text: "semen", Page: 5, Size: 1}, DefaultPriceBands)
+		assert.Empty(t, res.Hits)
+	})
+}
+
+func TestHighlightSnippet(t *testing.T) {
+	text := "Lorem ipsum dolor sit amet consectetur adipiscing elit sed do eiusmod tempor incididunt ut labore et dolore magna aliqua baja ringan anti karat ut enim ad minim veniam quis nostrud exercitation ullamco laboris nisi ut aliquip"
+
+	res, ok := Highlight(text, []string{"baja"}, 60)
+
+	assert.True(t, ok)
+	assert.Contains(t, res, "<em>baja</em> ringan")
+	assert.True(t, len([]rune(res)) < len([]rune(text)))
+	assert.Equal(t, "…", string([]rune(res)[0]))
+	assert.Equal(t, "…", string([]rune(res)[len([]rune(res))-1]))
+}
+
+func TestCandidateQuery(t *testing.T) {
+	fulltext := &sqlSearcher{mode: ModeFullText}
+	qry, args := fulltext.candidateQuery([]string{"semen", "roda"})
+	assert.Contains(t, qry, "status = 'active' AND MATCH(name, description) AGAINST (? IN BOOLEAN MODE)")
+	assert.Equal(t, []interface{}{"sem* roda*", "semen roda", MaxCandidates}, args)
+
+	like := &sqlSearcher{mode: ModeLike}
+	qry, args = like.candidateQuery([]string{"semen"})
+	assert.Contains(t, qry, "LOWER(name) LIKE ?")
+	assert.Equal(t, []interface{}{"%sem%", "%sem%", MaxCandidates}, args)
+}
+
+func TestParseMode(t *testing.T) {
+	mode, err := ParseMode("")
+	assert.NoError(t, err)
+	assert.Equal(t, ModeFullText, mode)
+
+	mode, err = ParseMode("LIKE")
+	assert.NoError(t, err)
+	assert.Equal(t, ModeLike, mode)
+
+	_, err = ParseMode("elastic")
+	assert.Error(t, err)
+}
@@ -0,0 +1,157 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"kanggo/pkg/entity/schema"
+	"strings"
+)
+
+// Mode is how the SQL searcher finds candidates.
+type Mode string
+
+const (
+	// ModeFullText uses the MySQL FULLTEXT index on products(name,
+	// description).
+	ModeFullText Mode = "fulltext"
+	// ModeLike scans with LIKE, for databases without FULLTEXT support.
+	ModeLike Mode = "like"
+
+	// MaxCandidates is the number of products fetched from the database for
+	// ranking. The best matches of the database come first.
+	MaxCandidates = 1000
+)
+
+type sqlSearcher struct {
+	db    *sql.DB
+	mode  Mode
+	bands []float64
+}
+
+// ParseMode reads the SEARCH_MODE setting, full text by default.
+func ParseMode(value string) (Mode, error) {
+	switch Mode(strings.ToLower(value)) {
+	case "", ModeFullText:
+		return ModeFullText, nil
+	case ModeLike:
+		return ModeLike, nil
+	}
+
+	return "", errors.New("unknown search mode " + value)
+}
+
+// NewSQLSearcher searches the products table. The database narrows the
+// products down to those containing the prefix of a term, which are then
+// ranked by Rank.
+func NewSQLSearcher(db *sql.DB, mode Mode) Searcher {
+	return &sqlSearcher{
+		db:    db,
+		mode:  mode,
+		bands: DefaultPriceBands,
+	}
+}
+
+func (s *sqlSearcher) Search(ctx context.Context, query Query) (*Result, error) {
+	terms := Terms(query.Text)
+	if len(terms) == 0 {
+		return Rank(nil, query, s.bands), nil
+	}
+
+	qry, args := s.candidateQuery(terms)
+	rows, err := s.db.QueryContext(ctx, qry, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	candidates := []Candidate{}
+	index := map[uint]int{}
+	for rows.Next() {
+		var res schema.Product
+		if err := rows.Scan(&res.Id, &res.CreatedAt, &res.UpdatedAt, &res.Name, &res.Description, &res.Price, &res.Qty,
+			&res.Weight, &res.Length, &res.Width, &res.Height, &res.TaxCategory,
+			&res.Unit, &res.Brand, &res.Barcode, &res.MinOrderQty, &res.Status, &res.Version, &res.ReorderLevel, &res.RatingCount, &res.RatingTotal); err != nil {
+			return nil, err
+		}
+		index[res.Id] = len(candidates)
+		candidates = append(candidates, Candidate{Product: res})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := s.loadCategories(ctx, candidates, index); err != nil {
+		return nil, err
+	}
+
+	return Rank(candidates, query, s.bands), nil
+}
+
+func (s *sqlSearcher) loadCategories(ctx context.Context, candidates []Candidate, index map[uint]int) error {
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	args := []interface{}{}
+	for i := range candidates {
+		args = append(args, candidates[i].Product.Id)
+	}
+
+	qry := `SELECT pc.product_id, c.id, c.name, c.slug
+	FROM product_categories pc
+	JOIN categories c ON c.id = pc.category_id
+	WHERE pc.product_id IN (?` + strings.Repeat(", ?", len(args)-1) + `)`
+
+	rows, err := s.db.QueryContext(ctx, qry, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productId uint
+		var res schema.Category
+		if err := rows.Scan(&productId, &res.Id, &res.Name, &res.Slug); err != nil {
+			return err
+		}
+		i := index[productId]
+		candidates[i].Categories = append(candidates[i].Categories, res)
+	}
+
+	return rows.Err()
+}
+
+// candidateQuery selects the active products matching any of the terms.
+// Draft, archived and deleted products are never found.
+func (s *sqlSearcher) candidateQuery(terms []string) (string, []interface{}) {
+	columns := `SELECT id, created_at, updated_at, name, COALESCE(description,""), price, qty, weight, length, width, height, tax_category,
+	unit, COALESCE(brand,""), COALESCE(barcode,""), min_order_qty, status, version, reorder_level, rating_count, rating_total
+	FROM products`
+
+	prefixes := make([]string, len(terms))
+	for i := range terms {
+		prefixes[i] = Prefix(terms[i])
+	}
+
+	if s.mode == ModeLike {
+		where := []string{}
+		args := []interface{}{}
+		for _, prefix := range prefixes {
+			where = append(where, "LOWER(name) LIKE ? OR LOWER(description) LIKE ?")
+			args = append(args, "%"+prefix+"%", "%"+prefix+"%")
+		}
+
+		return columns + `
+	WHERE deleted_at IS NULL AND status = 'active' AND (` + strings.Join(where, " OR ") + `)
+	ORDER BY id
+	LIMIT ?`, append(args, MaxCandidates)
+	}
+
+	// terms only hold letters and digits, so they are safe in a boolean
+	// mode expression
+	return columns + `
+	WHERE deleted_at IS NULL AND status = 'active' AND MATCH(name, description) AGAINST (? IN BOOLEAN MODE)
+	ORDER BY MATCH(name, description) AGAINST (? IN BOOLEAN MODE) DESC, id
+	LIMIT ?`, []interface{}{strings.Join(prefixes, "* ") + "*", strings.Join(terms, " "), MaxCandidates}
+}
@@ -0,0 +1,148 @@
+// Package sheet reads and writes the CSV and XLSX files used to import and
+// export data in bulk. Only the first worksheet of an XLSX file is read, and
+// every cell is read and written as text.
+package sheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+type Format string
+
+const (
+	CSV  Format = "csv"
+	XLSX Format = "xlsx"
+)
+
+// MaxFileSize is the largest file accepted, in bytes.
+const MaxFileSize = 20 << 20
+
+var (
+	ErrFormat = errors.New("file must be .csv or .xlsx")
+	ErrEmpty  = errors.New("file has no rows")
+)
+
+// ParseFormat accepts csv and xlsx, with or without the leading dot.
+func ParseFormat(s string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimPrefix(s, "."))) {
+	case CSV:
+		return CSV, nil
+	case XLSX:
+		return XLSX, nil
+	}
+
+	return "", ErrFormat
+}
+
+// FormatOf tells the format of a file by its extension.
+func FormatOf(filename string) (Format, error) {
+	return ParseFormat(filepath.Ext(filename))
+}
+
+func (f Format) ContentType() string {
+	if f == XLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+
+	return "text/csv"
+}
+
+// Read returns the rows of the file, empty ones included, so that the n-th
+// row is on line n. Rows may differ in length; trailing empty rows are
+// dropped.
+func Read(data []byte, format Format) ([][]string, error) {
+	var rows [][]string
+	var err error
+
+	switch format {
+	case CSV:
+		rows, err = readCSV(data)
+	case XLSX:
+		rows, err = readXLSX(data)
+	default:
+		return nil, ErrFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for len(rows) > 0 && blank(rows[len(rows)-1]) {
+		rows = rows[:len(rows)-1]
+	}
+	if len(rows) == 0 {
+		return nil, ErrEmpty
+	}
+
+	return rows, nil
+}
+
+func readCSV(data []byte) ([][]string, error) {
+	// spreadsheet programs often save CSV with a byte order mark
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	rows := [][]string{}
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// the reader skips empty lines; keep them so that a row stays at
+		// the index of the line it starts on
+		line, _ := r.FieldPos(0)
+		for len(rows) < line-1 {
+			rows = append(rows, []string{})
+		}
+		rows = append(rows, row)
+	}
+}
+
+func blank(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Writer writes rows to a file as they come, so a large export does not
+// have to be held in memory. Close must be called to finish the file.
+type Writer interface {
+	Write(row []string) error
+	Close() error
+}
+
+func NewWriter(w io.Writer, format Format) Writer {
+	if format == XLSX {
+		return newXLSXWriter(w)
+	}
+
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) Write(row []string) error {
+	return c.w.Write(row)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
@@ -0,0 +1,106 @@
+package sheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatOf(t *testing.T) {
+	format, err := FormatOf("Katalog.XLSX")
+	assert.NoError(t, err)
+	assert.Equal(t, XLSX, format)
+
+	_, err = FormatOf("katalog.xls")
+	assert.Equal(t, ErrFormat, err)
+}
+
+func TestReadCSV(t *testing.T) {
+	data := []byte("\xef\xbb\xbfsku,name,price\nSEMEN-50KG,\"Semen 50kg, Tiga Roda\",65000\n\nPASIR-1M3,Pasir\n\n,,\n")
+
+	rows, err := Read(data, CSV)
+
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"sku", "name", "price"},
+		{"SEMEN-50KG", "Semen 50kg, Tiga Roda", "65000"},
+		{},
+		{"PASIR-1M3", "Pasir"},
+	}, rows)
+}
+
+func TestReadEmpty(t *testing.T) {
+	_, err := Read([]byte("\n ,\n"), CSV)
+
+	assert.Equal(t, ErrEmpty, err)
+}
+
+func TestWriteAndReadXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, XLSX)
+	assert.NoError(t, w.Write([]string{"sku", "name", "price"}))
+	assert.NoError(t, w.Write([]string{"SEMEN-50KG", "Semen <50kg> & pasir", "65000"}))
+	assert.NoError(t, w.Close())
+
+	rows, err := Read(buf.Bytes(), XLSX)
+
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"sku", "name", "price"},
+		{"SEMEN-50KG", "Semen <50kg> & pasir", "65000"},
+	}, rows)
+}
+
+// TestReadXLSXSharedStrings reads a worksheet the way spreadsheet programs
+// save it: shared and rich text strings, numbers and skipped empty cells.
+func TestReadXLSXSharedStrings(t *testing.T) {
+	parts := map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+			`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Produk" sheetId="1" r:id="rId3"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId3" Target="/xl/worksheets/produk.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<sst><si><t>sku</t></si><si><t>price</t></si>` +
+			`<si><r><t>SEMEN</t></r><r><t>-50KG</t></r></si></sst>`,
+		"xl/worksheets/produk.xml": `<worksheet><sheetData>` +
+			`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1" t="s"><v>1</v></c></row>` +
+			`<row r="2"><c r="A2" t="s"><v>2</v></c><c r="C2"><v>65000</v></c></row>` +
+			`</sheetData></worksheet>`,
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, content := range parts {
+		part, err := archive.Create(name)
+		assert.NoError(t, err)
+		_, err = part.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, archive.Close())
+
+	rows, err := Read(buf.Bytes(), XLSX)
+
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"sku", "", "price"},
+		{"SEMEN-50KG", "", "65000"},
+	}, rows)
+}
+
+func TestReadInvalidXLSX(t *testing.T) {
+	_, err := Read([]byte("sku,name"), XLSX)
+
+	assert.EqualError(t, err, "invalid xlsx file")
+}
+
+func TestColumnName(t *testing.T) {
+	for index, name := range map[int]string{0: "A", 25: "Z", 26: "AA", 701: "ZZ", 702: "AAA"} {
+		assert.Equal(t, name, columnName(index))
+
+		got, err := columnIndex(name + "7")
+		assert.NoError(t, err)
+		assert.Equal(t, index, got)
+	}
+}
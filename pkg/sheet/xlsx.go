@@ -0,0 +1,318 @@
+package sheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// maxPartSize keeps a small XLSX file that unzips to a huge worksheet out.
+const maxPartSize = 200 << 20
+
+var errXLSX = errors.New("invalid xlsx file")
+
+type (
+	xlsxWorkbook struct {
+		Sheets []struct {
+			Id string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+
+	xlsxRelationships struct {
+		Relationships []struct {
+			Id     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+
+	// xlsxText is a shared or inline string, either plain or in rich text
+	// runs.
+	xlsxText struct {
+		Text string `xml:"t"`
+		Runs []struct {
+			Text string `xml:"t"`
+		} `xml:"r"`
+	}
+
+	xlsxSharedStrings struct {
+		Items []xlsxText `xml:"si"`
+	}
+
+	xlsxWorksheet struct {
+		Rows []struct {
+			Cells []struct {
+				Ref    string   `xml:"r,attr"`
+				Type   string   `xml:"t,attr"`
+				Value  string   `xml:"v"`
+				Inline xlsxText `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+)
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+
+	var s strings.Builder
+	for _, run := range t.Runs {
+		s.WriteString(run.Text)
+	}
+	return s.String()
+}
+
+func readXLSX(data []byte) ([][]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, errXLSX
+	}
+
+	parts := map[string]*zip.File{}
+	for _, file := range archive.File {
+		parts[file.Name] = file
+	}
+
+	sheet, err := firstSheet(parts)
+	if err != nil {
+		return nil, err
+	}
+
+	var shared xlsxSharedStrings
+	if _, ok := parts["xl/sharedStrings.xml"]; ok {
+		if err := readPart(parts, "xl/sharedStrings.xml", &shared); err != nil {
+			return nil, err
+		}
+	}
+
+	var worksheet xlsxWorksheet
+	if err := readPart(parts, sheet, &worksheet); err != nil {
+		return nil, err
+	}
+
+	rows := [][]string{}
+	for _, r := range worksheet.Rows {
+		row := []string{}
+		for _, cell := range r.Cells {
+			column := len(row)
+			if cell.Ref != "" {
+				if column, err = columnIndex(cell.Ref); err != nil {
+					return nil, err
+				}
+			}
+
+			var value string
+			switch cell.Type {
+			case "s":
+				i, err := strconv.Atoi(cell.Value)
+				if err != nil || i < 0 || i >= len(shared.Items) {
+					return nil, errXLSX
+				}
+				value = shared.Items[i].String()
+			case "inlineStr":
+				value = cell.Inline.String()
+			default:
+				value = cell.Value
+			}
+
+			for len(row) < column {
+				row = append(row, "")
+			}
+			if column < len(row) {
+				row[column] = value
+			} else {
+				row = append(row, value)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// firstSheet finds the part of the first worksheet through the workbook and
+// its relationships.
+func firstSheet(parts map[string]*zip.File) (string, error) {
+	var workbook xlsxWorkbook
+	if err := readPart(parts, "xl/workbook.xml", &workbook); err != nil {
+		return "", err
+	}
+
+	var rels xlsxRelationships
+	if err := readPart(parts, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return "", err
+	}
+
+	if len(workbook.Sheets) == 0 {
+		return "", ErrEmpty
+	}
+
+	for _, rel := range rels.Relationships {
+		if rel.Id != workbook.Sheets[0].Id {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+
+	return "", errXLSX
+}
+
+func readPart(parts map[string]*zip.File, name string, v interface{}) error {
+	file, ok := parts[name]
+	if !ok {
+		return errXLSX
+	}
+
+	r, err := file.Open()
+	if err != nil {
+		return errXLSX
+	}
+	defer r.Close()
+
+	if err := xml.NewDecoder(io.LimitReader(r, maxPartSize)).Decode(v); err != nil {
+		return errXLSX
+	}
+
+	return nil
+}
+
+// columnIndex turns the column letters of a cell reference such as AB12
+// into a zero-based index.
+func columnIndex(ref string) (int, error) {
+	index := 0
+	letters := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		index = index*26 + int(r-'A') + 1
+		letters++
+	}
+
+	if letters == 0 || letters > 3 {
+		return 0, errXLSX
+	}
+
+	return index - 1, nil
+}
+
+func columnName(index int) string {
+	name := ""
+	for index++; index > 0; index = (index - 1) / 26 {
+		name = string(rune('A'+(index-1)%26)) + name
+	}
+	return name
+}
+
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`
+
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+
+	xlsxWorkbookPart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`
+
+	xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+
+	xlsxSheetEnd = `</sheetData></worksheet>`
+)
+
+// xlsxWriter writes a workbook with a single worksheet of inline strings.
+// The worksheet is the last part of the archive, so rows go straight into
+// it.
+type xlsxWriter struct {
+	archive *zip.Writer
+	sheet   io.Writer
+	rows    int
+	err     error
+}
+
+func newXLSXWriter(w io.Writer) *xlsxWriter {
+	x := &xlsxWriter{archive: zip.NewWriter(w)}
+
+	for _, part := range []struct{ name, content string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", xlsxWorkbookPart},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	} {
+		if x.err = x.writePart(part.name, part.content); x.err != nil {
+			return x
+		}
+	}
+
+	if x.sheet, x.err = x.archive.Create("xl/worksheets/sheet1.xml"); x.err != nil {
+		return x
+	}
+	_, x.err = io.WriteString(x.sheet, xlsxSheetStart)
+
+	return x
+}
+
+func (x *xlsxWriter) writePart(name, content string) error {
+	part, err := x.archive.Create(name)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(part, content)
+	return err
+}
+
+func (x *xlsxWriter) Write(row []string) error {
+	if x.err != nil {
+		return x.err
+	}
+
+	x.rows++
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<row r="%d">`, x.rows)
+	for i, value := range row {
+		fmt.Fprintf(&b, `<c r="%s%d" t="inlineStr"><is><t xml:space="preserve">`, columnName(i), x.rows)
+		if x.err = xml.EscapeText(&b, []byte(value)); x.err != nil {
+			return x.err
+		}
+		b.WriteString(`</t></is></c>`)
+	}
+	b.WriteString(`</row>`)
+
+	_, x.err = x.sheet.Write(b.Bytes())
+	return x.err
+}
+
+func (x *xlsxWriter) Close() error {
+	if x.err != nil {
+		return x.err
+	}
+
+	if _, err := io.WriteString(x.sheet, xlsxSheetEnd); err != nil {
+		return err
+	}
+
+	return x.archive.Close()
+}
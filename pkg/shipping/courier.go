@@ -0,0 +1,64 @@
+package shipping
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type courierProvider struct {
+	name    string
+	baseUrl string
+	client  *http.Client
+}
+
+// NewCourierProvider returns a provider that asks a courier's rate API. The
+// API receives a RateRequest as JSON on POST {baseUrl}/rates and answers with
+// {"rates": [{"service": "REG", "cost": 9000, "etd": "2-3"}]}.
+func NewCourierProvider(name, baseUrl string) ShippingRateProvider {
+	return &courierProvider{
+		name:    name,
+		baseUrl: strings.TrimRight(baseUrl, "/"),
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (p *courierProvider) Quote(ctx context.Context, req RateRequest) ([]RateOption, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseUrl+"/rates", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	res, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("courier %s returned status %d", p.name, res.StatusCode)
+	}
+
+	var result struct {
+		Rates []RateOption `json:"rates"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	for i := range result.Rates {
+		result.Rates[i].Courier = p.name
+	}
+
+	return result.Rates, nil
+}
@@ -0,0 +1,92 @@
+package shipping
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+)
+
+//go:generate mockery --name ShippingRateProvider --case snake --output ../mocks --disable-version-string
+
+type (
+	// ShippingRateProvider returns the delivery options a courier offers for a
+	// parcel. Weight is in grams.
+	ShippingRateProvider interface {
+		Quote(ctx context.Context, req RateRequest) ([]RateOption, error)
+	}
+
+	Region struct {
+		Province string `json:"province"`
+		City     string `json:"city"`
+	}
+
+	RateRequest struct {
+		Origin      Region `json:"origin"`
+		Destination Region `json:"destination"`
+		Weight      int64  `json:"weight"`
+	}
+
+	RateOption struct {
+		Courier string  `json:"courier"`
+		Service string  `json:"service"`
+		Cost    float64 `json:"cost"`
+		Etd     string  `json:"etd"`
+	}
+
+	// Providers asks every provider for a quote and merges the options,
+	// cheapest first. A provider that fails is left out, so one courier
+	// being down does not stop checkout with the others.
+	Providers []ShippingRateProvider
+)
+
+var ErrNoRate = errors.New("shipping option not available")
+
+func (p Providers) Quote(ctx context.Context, req RateRequest) ([]RateOption, error) {
+	var failure error
+	options := []RateOption{}
+	for _, provider := range p {
+		res, err := provider.Quote(ctx, req)
+		if err != nil {
+			if failure == nil {
+				failure = err
+			}
+			continue
+		}
+		options = append(options, res...)
+	}
+
+	// without any option the failure is the likely reason, so it is returned
+	if len(options) == 0 && failure != nil {
+		return nil, failure
+	}
+
+	sort.SliceStable(options, func(i, j int) bool {
+		return options[i].Cost < options[j].Cost
+	})
+
+	return options, nil
+}
+
+// Find returns the option of the given courier and service.
+func Find(options []RateOption, courier, service string) (*RateOption, error) {
+	for i := range options {
+		if options[i].Courier == courier && options[i].Service == service {
+			return &options[i], nil
+		}
+	}
+
+	return nil, ErrNoRate
+}
+
+// ChargeableWeight returns the weight in grams couriers bill for an item: the
+// larger of its actual weight and its volumetric weight (length x width x
+// height in cm divided by 6000, in kg).
+func ChargeableWeight(weight, length, width, height, quantity int64) int64 {
+	volumetric := int64(math.Ceil(float64(length*width*height) / 6))
+	if volumetric > weight {
+		weight = volumetric
+	}
+
+	return weight * quantity
+}
@@ -0,0 +1,132 @@
+package shipping_test
+
+import (
+	"context"
+	"testing"
+
+	"kanggo/pkg/shipping"
+	"kanggo/pkg/shipping/shippingtest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var rates = []shipping.TableRate{
+	{Courier: "jne", Service: "REG", Origin: "DKI Jakarta", Destination: "Jawa Barat", PricePerKg: 11000, MinWeight: 1000, Etd: "2-3 days"},
+	{Courier: "jne", Service: "REG", Origin: "*", Destination: "*", PricePerKg: 25000, MinWeight: 1000, Etd: "3-6 days"},
+	{Courier: "jne", Service: "CARGO", Origin: "*", Destination: "*", PricePerKg: 6000, MinWeight: 10000, Etd: "5-8 days"},
+	{Courier: "jne", Service: "YES", Origin: "Jawa Timur", Destination: "*", PricePerKg: 30000, MinWeight: 1000, Etd: "1 day"},
+}
+
+func TestTableProvider(t *testing.T) {
+	p := shipping.NewTableProvider(rates)
+	ctx := context.Background()
+
+	t.Run("exact region wins over wildcard", func(t *testing.T) {
+		options, err := p.Quote(ctx, shipping.RateRequest{
+			Origin:      shipping.Region{Province: "DKI Jakarta"},
+			Destination: shipping.Region{Province: "jawa barat"},
+			Weight:      2500,
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, options, 2)
+
+		reg, err := shipping.Find(options, "jne", "REG")
+		assert.NoError(t, err)
+		assert.Equal(t, float64(33000), reg.Cost)
+		assert.Equal(t, "2-3 days", reg.Etd)
+
+		cargo, err := shipping.Find(options, "jne", "CARGO")
+		assert.NoError(t, err)
+		assert.Equal(t, float64(60000), cargo.Cost)
+	})
+
+	t.Run("wildcard fallback", func(t *testing.T) {
+		options, err := p.Quote(ctx, shipping.RateRequest{
+			Origin:      shipping.Region{Province: "DKI Jakarta"},
+			Destination: shipping.Region{Province: "Bali"},
+			Weight:      400,
+		})
+
+		assert.NoError(t, err)
+
+		reg, err := shipping.Find(options, "jne", "REG")
+		assert.NoError(t, err)
+		assert.Equal(t, float64(25000), reg.Cost)
+
+		_, err = shipping.Find(options, "jne", "YES")
+		assert.Equal(t, shipping.ErrNoRate, err)
+	})
+}
+
+func TestCourierProvider(t *testing.T) {
+	stub := shippingtest.NewCourierStub(
+		shippingtest.StubRate{Service: "GOKIL", PricePerKg: 5000, Etd: "4-7 days"},
+		shippingtest.StubRate{Service: "BEST", PricePerKg: 15000, Etd: "1 day"},
+	)
+	defer stub.Close()
+
+	p := shipping.Providers{
+		shipping.NewCourierProvider("sicepat", stub.URL),
+		shipping.NewTableProvider(rates),
+	}
+
+	options, err := p.Quote(context.Background(), shipping.RateRequest{
+		Origin:      shipping.Region{Province: "DKI Jakarta"},
+		Destination: shipping.Region{Province: "Jawa Barat"},
+		Weight:      12000,
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, options, 4)
+	assert.Equal(t, "sicepat", options[0].Courier)
+	assert.Equal(t, "GOKIL", options[0].Service)
+	assert.Equal(t, float64(60000), options[0].Cost)
+
+	best, err := shipping.Find(options, "sicepat", "BEST")
+	assert.NoError(t, err)
+	assert.Equal(t, float64(180000), best.Cost)
+}
+
+func TestProvidersFailure(t *testing.T) {
+	down := shippingtest.NewCourierStub()
+	down.Close()
+
+	req := shipping.RateRequest{
+		Origin:      shipping.Region{Province: "DKI Jakarta"},
+		Destination: shipping.Region{Province: "Jawa Barat"},
+		Weight:      12000,
+	}
+
+	t.Run("other providers still quote", func(t *testing.T) {
+		p := shipping.Providers{
+			shipping.NewCourierProvider("sicepat", down.URL),
+			shipping.NewTableProvider(rates),
+		}
+
+		options, err := p.Quote(context.Background(), req)
+
+		assert.NoError(t, err)
+		assert.Len(t, options, 2)
+		assert.Equal(t, "jne", options[0].Courier)
+	})
+
+	t.Run("no options left", func(t *testing.T) {
+		p := shipping.Providers{
+			shipping.NewCourierProvider("sicepat", down.URL),
+			shipping.NewTableProvider(nil),
+		}
+
+		options, err := p.Quote(context.Background(), req)
+
+		assert.Error(t, err)
+		assert.Nil(t, options)
+	})
+}
+
+func TestChargeableWeight(t *testing.T) {
+	// 50kg cement bag, small volume: actual weight counts
+	assert.Equal(t, int64(100000), shipping.ChargeableWeight(50000, 60, 40, 12, 2))
+	// light but bulky pipe: volumetric weight counts
+	assert.Equal(t, int64(4000), shipping.ChargeableWeight(1500, 400, 10, 6, 1))
+}
@@ -0,0 +1,52 @@
+// Package shippingtest provides a fake courier rate API for tests and local
+// development.
+package shippingtest
+
+import (
+	"encoding/json"
+	"kanggo/pkg/shipping"
+	"math"
+	"net/http"
+	"net/http/httptest"
+)
+
+// StubRate is a flat price per started kilogram for one service.
+type StubRate struct {
+	Service    string
+	PricePerKg float64
+	Etd        string
+}
+
+// NewCourierStub starts a server speaking the rate API expected by
+// shipping.NewCourierProvider. Callers must Close it.
+func NewCourierStub(rates ...StubRate) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rates", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req shipping.RateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Weight <= 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		kg := math.Max(1, math.Ceil(float64(req.Weight)/1000))
+
+		options := []shipping.RateOption{}
+		for _, rate := range rates {
+			options = append(options, shipping.RateOption{
+				Service: rate.Service,
+				Cost:    kg * rate.PricePerKg,
+				Etd:     rate.Etd,
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"rates": options})
+	})
+
+	return httptest.NewServer(mux)
+}
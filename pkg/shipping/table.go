@@ -0,0 +1,124 @@
+package shipping
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"os"
+	"strings"
+)
+
+type (
+	// TableRate is the price of one courier service between two provinces.
+	// "*" matches any province.
+	TableRate struct {
+		Courier     string  `json:"courier"`
+		Service     string  `json:"service"`
+		Origin      string  `json:"origin"`
+		Destination string  `json:"destination"`
+		PricePerKg  float64 `json:"price_per_kg"`
+		MinWeight   int64   `json:"min_weight"`
+		Etd         string  `json:"etd"`
+	}
+
+	tableProvider struct {
+		rates []TableRate
+	}
+)
+
+func NewTableProvider(rates []TableRate) ShippingRateProvider {
+	return &tableProvider{
+		rates: rates,
+	}
+}
+
+// LoadTableProvider reads the rate table from a JSON file of the form
+// {"rates": [TableRate, ...]}.
+func LoadTableProvider(path string) (ShippingRateProvider, error) {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var table struct {
+		Rates []TableRate `json:"rates"`
+	}
+	if err := json.Unmarshal(file, &table); err != nil {
+		return nil, err
+	}
+
+	return NewTableProvider(table.Rates), nil
+}
+
+func (t *tableProvider) Quote(ctx context.Context, req RateRequest) ([]RateOption, error) {
+	type key struct {
+		courier string
+		service string
+	}
+
+	best := map[key]TableRate{}
+	score := map[key]int{}
+	order := []key{}
+	for _, rate := range t.rates {
+		s, ok := matchRegion(rate, req)
+		if !ok {
+			continue
+		}
+
+		k := key{rate.Courier, rate.Service}
+		prev, seen := score[k]
+		if !seen {
+			order = append(order, k)
+		}
+		if !seen || s > prev {
+			best[k] = rate
+			score[k] = s
+		}
+	}
+
+	options := []RateOption{}
+	for _, k := range order {
+		rate := best[k]
+
+		weight := req.Weight
+		if weight < rate.MinWeight {
+			weight = rate.MinWeight
+		}
+
+		// couriers charge per started kilogram
+		kg := math.Ceil(float64(weight) / 1000)
+		if kg < 1 {
+			kg = 1
+		}
+
+		options = append(options, RateOption{
+			Courier: rate.Courier,
+			Service: rate.Service,
+			Cost:    kg * rate.PricePerKg,
+			Etd:     rate.Etd,
+		})
+	}
+
+	return options, nil
+}
+
+// matchRegion reports whether the rate applies to the request and how
+// specific the match is, so an exact province pair wins over a wildcard.
+func matchRegion(rate TableRate, req RateRequest) (int, bool) {
+	s := 0
+	switch {
+	case strings.EqualFold(rate.Origin, req.Origin.Province):
+		s += 2
+	case rate.Origin != "*":
+		return 0, false
+	}
+
+	switch {
+	case strings.EqualFold(rate.Destination, req.Destination.Province):
+		s++
+	case rate.Destination != "*":
+		return 0, false
+	}
+
+	return s, true
+}
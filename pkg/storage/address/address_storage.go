@@ -0,0 +1,195 @@
+package address
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"kanggo/pkg/entity/schema"
+
+	"gorm.io/gorm"
+)
+
+//go:generate mockery --name AddressStorage --case snake --output ../../mocks --disable-version-string
+
+type (
+	AddressStorage interface {
+		Insert(ctx context.Context, data schema.Address) error
+		Update(ctx context.Context, data schema.Address) error
+		GetAll(ctx context.Context, userId uint64) ([]schema.Address, error)
+		GetById(ctx context.Context, id int64, userId uint64) (*schema.Address, error)
+		Delete(ctx context.Context, id int64, userId uint64) error
+		SetDefault(ctx context.Context, id int64, userId uint64) error
+	}
+
+	addressStorage struct {
+		Native *sql.DB
+		Gorm   *gorm.DB
+	}
+)
+
+func NewAddressStorage(native *sql.DB, gorm *gorm.DB) AddressStorage {
+	return &addressStorage{
+		Native: native,
+		Gorm:   gorm,
+	}
+}
+
+func (a *addressStorage) Insert(ctx context.Context, data schema.Address) error {
+	var count int64
+
+	tx := a.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Model(&schema.Address{}).Where("user_id = ?", data.UserId).
+		Count(&count).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// the first address of a user is always the default one
+	if count == 0 {
+		data.IsDefault = true
+	}
+
+	if data.IsDefault {
+		if err := tx.WithContext(ctx).Model(&schema.Address{}).Where("user_id = ?", data.UserId).
+			Update("is_default", false).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := tx.WithContext(ctx).Create(&data).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func (a *addressStorage) Update(ctx context.Context, data schema.Address) error {
+	result := a.Gorm.WithContext(ctx).Model(&schema.Address{}).
+		Where("id = ? AND user_id = ?", data.Id, data.UserId).
+		Updates(map[string]interface{}{
+			"recipient":   data.Recipient,
+			"phone":       data.Phone,
+			"street":      data.Street,
+			"province":    data.Province,
+			"city":        data.City,
+			"district":    data.District,
+			"postal_code": data.PostalCode,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return nil
+}
+
+func (a *addressStorage) GetAll(ctx context.Context, userId uint64) ([]schema.Address, error) {
+	qry := `SELECT id, created_at, updated_at, user_id, recipient, phone, street,
+	province, city, district, postal_code, is_default
+	FROM addresses
+	WHERE user_id = ?
+	ORDER BY is_default DESC, id
+	`
+
+	rows, err := a.Native.QueryContext(ctx, qry, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	addresses := []schema.Address{}
+	for rows.Next() {
+		var res schema.Address
+		if err := rows.Scan(&res.Id, &res.CreatedAt, &res.UpdatedAt, &res.UserId, &res.Recipient,
+			&res.Phone, &res.Street, &res.Province, &res.City, &res.District,
+			&res.PostalCode, &res.IsDefault); err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, res)
+	}
+
+	return addresses, nil
+}
+
+func (a *addressStorage) GetById(ctx context.Context, id int64, userId uint64) (*schema.Address, error) {
+	address := schema.Address{}
+	qry := `SELECT id, created_at, updated_at, user_id, recipient, phone, street,
+	province, city, district, postal_code, is_default
+	FROM addresses
+	WHERE id = ? AND user_id = ?
+	`
+
+	res := a.Native.QueryRowContext(ctx, qry, id, userId)
+	if err := res.Scan(&address.Id, &address.CreatedAt, &address.UpdatedAt, &address.UserId,
+		&address.Recipient, &address.Phone, &address.Street, &address.Province,
+		&address.City, &address.District, &address.PostalCode, &address.IsDefault); err != nil {
+		return nil, err
+	}
+
+	return &address, nil
+}
+
+func (a *addressStorage) Delete(ctx context.Context, id int64, userId uint64) error {
+	result := a.Gorm.WithContext(ctx).Where("user_id = ?", userId).Delete(schema.Address{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("data not found")
+	}
+
+	return nil
+}
+
+func (a *addressStorage) SetDefault(ctx context.Context, id int64, userId uint64) error {
+	tx := a.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Model(&schema.Address{}).Where("user_id = ? AND id <> ?", userId, id).
+		Update("is_default", false).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	result := tx.WithContext(ctx).Model(&schema.Address{}).Where("id = ? AND user_id = ?", id, userId).
+		Update("is_default", true)
+	if result.Error != nil {
+		tx.Rollback()
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		var count int64
+		if err := tx.WithContext(ctx).Model(&schema.Address{}).Where("id = ? AND user_id = ?", id, userId).
+			Count(&count).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+		if count == 0 {
+			tx.Rollback()
+			return errors.New("data not found")
+		}
+	}
+
+	return tx.Commit().Error
+}
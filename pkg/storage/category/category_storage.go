@@ -0,0 +1,402 @@
+package category
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"kanggo/pkg/entity/schema"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate mockery --name CategoryStorage --case snake --output ../../mocks --disable-version-string
+
+type (
+	CategoryStorage interface {
+		Insert(ctx context.Context, data schema.Category) error
+		Update(ctx context.Context, data schema.Category) error
+		GetAll(ctx context.Context) ([]schema.Category, error)
+		GetById(ctx context.Context, id int64) (*schema.Category, error)
+		GetBySlug(ctx context.Context, slug string) (*schema.Category, error)
+		Delete(ctx context.Context, id int64) error
+		GetSubtreeIds(ctx context.Context, id int64) ([]int64, error)
+		SetProductCategories(ctx context.Context, productId int64, categoryIds []int64) error
+		GetProductCategories(ctx context.Context, productId int64) ([]schema.Category, error)
+		GetProductCategoryIds(ctx context.Context, productId int64) ([]int64, error)
+	}
+
+	categoryStorage struct {
+		Native *sql.DB
+		Gorm   *gorm.DB
+	}
+)
+
+func NewCategoryStorage(native *sql.DB, gorm *gorm.DB) CategoryStorage {
+	return &categoryStorage{
+		Native: native,
+		Gorm:   gorm,
+	}
+}
+
+func (s *categoryStorage) Insert(ctx context.Context, data schema.Category) error {
+	tx := s.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	if err := checkPlacement(tx.WithContext(ctx), data); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Create(&data).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func (s *categoryStorage) Update(ctx context.Context, data schema.Category) error {
+	tx := s.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	if err := checkPlacement(tx.WithContext(ctx), data); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// select every column so a category can be moved back to the root
+	result := tx.WithContext(ctx).Model(&schema.Category{Base: schema.Base{Id: data.Id}}).
+		Select("parent_id", "name", "slug", "sort_order").
+		Updates(&data)
+	if result.Error != nil {
+		tx.Rollback()
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return errors.New("data not found")
+	}
+
+	return tx.Commit().Error
+}
+
+// checkPlacement locks the category tree and checks that the slug is free
+// and that the parent exists and is not the category itself or one of its
+// subcategories. Locking every row keeps two concurrent moves from building a
+// cycle together.
+func checkPlacement(tx *gorm.DB, data schema.Category) error {
+	categories := []schema.Category{}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "parent_id", "slug").Find(&categories).Error; err != nil {
+		return err
+	}
+
+	parents := map[int64]*int64{}
+	for _, category := range categories {
+		if category.Slug == data.Slug && category.Id != data.Id {
+			return errors.New("slug already exists")
+		}
+		parents[int64(category.Id)] = category.ParentId
+	}
+
+	for parent := data.ParentId; parent != nil; parent = parents[*parent] {
+		if data.Id != 0 && *parent == int64(data.Id) {
+			return errors.New("category cannot be moved under itself")
+		}
+		if _, ok := parents[*parent]; !ok {
+			return errors.New("parent category not found")
+		}
+	}
+
+	return nil
+}
+
+func (s *categoryStorage) GetAll(ctx context.Context) ([]schema.Category, error) {
+	qry := `SELECT id, created_at, updated_at, parent_id, name, slug, sort_order
+	FROM categories
+	ORDER BY sort_order, name, id
+	`
+
+	rows, err := s.Native.QueryContext(ctx, qry)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := []schema.Category{}
+	for rows.Next() {
+		var res schema.Category
+		if err := rows.Scan(&res.Id, &res.CreatedAt, &res.UpdatedAt, &res.ParentId,
+			&res.Name, &res.Slug, &res.SortOrder); err != nil {
+			return nil, err
+		}
+		categories = append(categories, res)
+	}
+
+	return categories, nil
+}
+
+func (s *categoryStorage) GetById(ctx context.Context, id int64) (*schema.Category, error) {
+	return s.getBy(ctx, "id", id)
+}
+
+func (s *categoryStorage) GetBySlug(ctx context.Context, slug string) (*schema.Category, error) {
+	return s.getBy(ctx, "slug", slug)
+}
+
+func (s *categoryStorage) getBy(ctx context.Context, column string, value interface{}) (*schema.Category, error) {
+	category := schema.Category{}
+	qry := `SELECT id, created_at, updated_at, parent_id, name, slug, sort_order
+	FROM categories WHERE ` + column + ` = ?`
+
+	res := s.Native.QueryRowContext(ctx, qry, value)
+	if err := res.Scan(&category.Id, &category.CreatedAt, &category.UpdatedAt, &category.ParentId,
+		&category.Name, &category.Slug, &category.SortOrder); err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+// Delete removes an empty category. Categories with subcategories, products
+// or coupons targeting them have to be emptied first.
+func (s *categoryStorage) Delete(ctx context.Context, id int64) error {
+	var children, products, coupons int64
+
+	tx := s.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Model(&schema.Category{}).Where("parent_id = ?", id).
+		Count(&children).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Model(&schema.ProductCategory{}).Where("category_id = ?", id).
+		Count(&products).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if children > 0 || products > 0 {
+		tx.Rollback()
+		return errors.New("category is not empty")
+	}
+
+	// dropping the category from a coupon would widen the coupon to every
+	// product if it was its only target
+	if err := tx.WithContext(ctx).Model(&schema.CouponCategory{}).Where("category_id = ?", id).
+		Count(&coupons).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if coupons > 0 {
+		tx.Rollback()
+		return errors.New("category is used by a coupon")
+	}
+
+	result := tx.WithContext(ctx).Delete(schema.Category{}, id)
+	if result.Error != nil {
+		tx.Rollback()
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return errors.New("data not found")
+	}
+
+	return tx.Commit().Error
+}
+
+// GetSubtreeIds returns the id of the category and of all its subcategories.
+func (s *categoryStorage) GetSubtreeIds(ctx context.Context, id int64) ([]int64, error) {
+	parents, err := s.parents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	children := map[int64][]int64{}
+	for child, parent := range parents {
+		if parent != nil {
+			children[*parent] = append(children[*parent], child)
+		}
+	}
+
+	ids := []int64{id}
+	for i := 0; i < len(ids); i++ {
+		ids = append(ids, children[ids[i]]...)
+	}
+
+	return ids, nil
+}
+
+// SetProductCategories replaces the categories of a product.
+func (s *categoryStorage) SetProductCategories(ctx context.Context, productId int64, categoryIds []int64) error {
+	var count int64
+
+	tx := s.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Model(&schema.Product{}).Where("id = ?", productId).
+		Count(&count).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if count == 0 {
+		tx.Rollback()
+		return errors.New("product not found")
+	}
+
+	if len(categoryIds) > 0 {
+		if err := tx.WithContext(ctx).Model(&schema.Category{}).Where("id IN ?", categoryIds).
+			Count(&count).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if count != int64(len(categoryIds)) {
+			tx.Rollback()
+			return errors.New("category not found")
+		}
+	}
+
+	if err := tx.WithContext(ctx).Where("product_id = ?", productId).Delete(&schema.ProductCategory{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if len(categoryIds) > 0 {
+		assignments := []schema.ProductCategory{}
+		for _, id := range categoryIds {
+			assignments = append(assignments, schema.ProductCategory{ProductId: productId, CategoryId: id})
+		}
+
+		if err := tx.WithContext(ctx).Create(&assignments).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+func (s *categoryStorage) GetProductCategories(ctx context.Context, productId int64) ([]schema.Category, error) {
+	qry := `SELECT c.id, c.created_at, c.updated_at, c.parent_id, c.name, c.slug, c.sort_order
+	FROM product_categories pc
+	JOIN categories c ON c.id = pc.category_id
+	WHERE pc.product_id = ?
+	ORDER BY c.sort_order, c.name, c.id
+	`
+
+	rows, err := s.Native.QueryContext(ctx, qry, productId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := []schema.Category{}
+	for rows.Next() {
+		var res schema.Category
+		if err := rows.Scan(&res.Id, &res.CreatedAt, &res.UpdatedAt, &res.ParentId,
+			&res.Name, &res.Slug, &res.SortOrder); err != nil {
+			return nil, err
+		}
+		categories = append(categories, res)
+	}
+
+	return categories, nil
+}
+
+// GetProductCategoryIds returns the categories of a product together with all
+// their ancestors, so a product in "Semen" also counts as being in its parent
+// "Bahan Bangunan".
+func (s *categoryStorage) GetProductCategoryIds(ctx context.Context, productId int64) ([]int64, error) {
+	categories, err := s.GetProductCategories(ctx, productId)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(categories) == 0 {
+		return []int64{}, nil
+	}
+
+	parents, err := s.parents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[int64]bool{}
+	ids := []int64{}
+	for _, category := range categories {
+		id := int64(category.Id)
+		for !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+			parent := parents[id]
+			if parent == nil {
+				break
+			}
+			id = *parent
+		}
+	}
+
+	return ids, nil
+}
+
+// parents maps every category to its parent.
+func (s *categoryStorage) parents(ctx context.Context) (map[int64]*int64, error) {
+	rows, err := s.Native.QueryContext(ctx, `SELECT id, parent_id FROM categories`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	parents := map[int64]*int64{}
+	for rows.Next() {
+		var id int64
+		var parent *int64
+		if err := rows.Scan(&id, &parent); err != nil {
+			return nil, err
+		}
+		parents[id] = parent
+	}
+
+	return parents, nil
+}
@@ -0,0 +1,278 @@
+package coupon
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"kanggo/pkg/entity/schema"
+	"kanggo/pkg/promotion"
+
+	"gorm.io/gorm"
+)
+
+//go:generate mockery --name CouponStorage --case snake --output ../../mocks --disable-version-string
+
+type (
+	CouponStorage interface {
+		Insert(ctx context.Context, data schema.Coupon, scope promotion.Scope) error
+		Update(ctx context.Context, data schema.Coupon, scope promotion.Scope) error
+		GetAll(ctx context.Context) ([]schema.Coupon, error)
+		GetById(ctx context.Context, id int64) (*schema.Coupon, error)
+		GetByCode(ctx context.Context, code string) (*schema.Coupon, error)
+		GetScope(ctx context.Context, couponId int64) (promotion.Scope, error)
+		CountUserRedemptions(ctx context.Context, couponId int64, userId uint64) (int64, error)
+		Delete(ctx context.Context, id int64) error
+	}
+
+	couponStorage struct {
+		Native *sql.DB
+		Gorm   *gorm.DB
+	}
+)
+
+func NewCouponStorage(native *sql.DB, gorm *gorm.DB) CouponStorage {
+	return &couponStorage{
+		Native: native,
+		Gorm:   gorm,
+	}
+}
+
+func (s *couponStorage) Insert(ctx context.Context, data schema.Coupon, scope promotion.Scope) error {
+	tx := s.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Create(&data).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := insertScope(tx.WithContext(ctx), int64(data.Id), scope); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func (s *couponStorage) Update(ctx context.Context, data schema.Coupon, scope promotion.Scope) error {
+	tx := s.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	// select every column so zero values like active=false or no end date
+	// are written too; used_count is owned by redemptions
+	result := tx.WithContext(ctx).Model(&schema.Coupon{Base: schema.Base{Id: data.Id}}).
+		Select("code", "description", "discount_type", "value", "max_discount", "min_spend",
+			"usage_limit", "per_user_limit", "starts_at", "ends_at", "active").
+		Updates(&data)
+	if result.Error != nil {
+		tx.Rollback()
+		return result.Error
+	}
+
+	if err := tx.WithContext(ctx).Where("coupon_id = ?", data.Id).Delete(&schema.CouponProduct{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Where("coupon_id = ?", data.Id).Delete(&schema.CouponCategory{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := insertScope(tx.WithContext(ctx), int64(data.Id), scope); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func (s *couponStorage) GetAll(ctx context.Context) ([]schema.Coupon, error) {
+	qry := `SELECT id, created_at, updated_at, code, COALESCE(description,""), discount_type, value,
+	max_discount, min_spend, usage_limit, per_user_limit, used_count, starts_at, ends_at, active
+	FROM coupons
+	ORDER BY id DESC
+	`
+
+	rows, err := s.Native.QueryContext(ctx, qry)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	coupons := []schema.Coupon{}
+	for rows.Next() {
+		var res schema.Coupon
+		if err := rows.Scan(&res.Id, &res.CreatedAt, &res.UpdatedAt, &res.Code, &res.Description,
+			&res.DiscountType, &res.Value, &res.MaxDiscount, &res.MinSpend, &res.UsageLimit,
+			&res.PerUserLimit, &res.UsedCount, &res.StartsAt, &res.EndsAt, &res.Active); err != nil {
+			return nil, err
+		}
+		coupons = append(coupons, res)
+	}
+
+	return coupons, nil
+}
+
+func (s *couponStorage) GetById(ctx context.Context, id int64) (*schema.Coupon, error) {
+	return s.getBy(ctx, "id", id)
+}
+
+func (s *couponStorage) GetByCode(ctx context.Context, code string) (*schema.Coupon, error) {
+	return s.getBy(ctx, "code", code)
+}
+
+func (s *couponStorage) getBy(ctx context.Context, column string, value interface{}) (*schema.Coupon, error) {
+	coupon := schema.Coupon{}
+	qry := `SELECT id, created_at, updated_at, code, COALESCE(description,""), discount_type, value,
+	max_discount, min_spend, usage_limit, per_user_limit, used_count, starts_at, ends_at, active
+	FROM coupons WHERE ` + column + ` = ?`
+
+	res := s.Native.QueryRowContext(ctx, qry, value)
+	if err := res.Scan(&coupon.Id, &coupon.CreatedAt, &coupon.UpdatedAt, &coupon.Code, &coupon.Description,
+		&coupon.DiscountType, &coupon.Value, &coupon.MaxDiscount, &coupon.MinSpend, &coupon.UsageLimit,
+		&coupon.PerUserLimit, &coupon.UsedCount, &coupon.StartsAt, &coupon.EndsAt, &coupon.Active); err != nil {
+		return nil, err
+	}
+
+	return &coupon, nil
+}
+
+func (s *couponStorage) GetScope(ctx context.Context, couponId int64) (promotion.Scope, error) {
+	scope := promotion.Scope{}
+	var err error
+
+	scope.ProductIds, err = s.getIds(ctx, `SELECT product_id FROM coupon_products WHERE coupon_id = ? ORDER BY product_id`, couponId)
+	if err != nil {
+		return scope, err
+	}
+
+	scope.CategoryIds, err = s.getIds(ctx, `SELECT category_id FROM coupon_categories WHERE coupon_id = ? ORDER BY category_id`, couponId)
+	if err != nil {
+		return scope, err
+	}
+
+	return scope, nil
+}
+
+func (s *couponStorage) getIds(ctx context.Context, qry string, couponId int64) ([]int64, error) {
+	rows, err := s.Native.QueryContext(ctx, qry, couponId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func (s *couponStorage) CountUserRedemptions(ctx context.Context, couponId int64, userId uint64) (int64, error) {
+	var count int64
+	qry := `SELECT COUNT(*) FROM coupon_redemptions WHERE coupon_id = ? AND user_id = ?`
+
+	if err := s.Native.QueryRowContext(ctx, qry, couponId, userId).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (s *couponStorage) Delete(ctx context.Context, id int64) error {
+	var count int64
+
+	tx := s.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	// redeemed coupons stay for the order history, deactivate them instead
+	if err := tx.WithContext(ctx).Model(&schema.CouponRedemption{}).Where("coupon_id = ?", id).
+		Count(&count).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if count > 0 {
+		tx.Rollback()
+		return errors.New("coupon has been redeemed")
+	}
+
+	if err := tx.WithContext(ctx).Where("coupon_id = ?", id).Delete(&schema.CouponProduct{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Where("coupon_id = ?", id).Delete(&schema.CouponCategory{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	result := tx.WithContext(ctx).Delete(schema.Coupon{}, id)
+	if result.Error != nil {
+		tx.Rollback()
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return errors.New("data not found")
+	}
+
+	return tx.Commit().Error
+}
+
+func insertScope(tx *gorm.DB, couponId int64, scope promotion.Scope) error {
+	if len(scope.ProductIds) > 0 {
+		products := []schema.CouponProduct{}
+		for _, id := range scope.ProductIds {
+			products = append(products, schema.CouponProduct{CouponId: couponId, ProductId: id})
+		}
+
+		if err := tx.Create(&products).Error; err != nil {
+			return err
+		}
+	}
+
+	if len(scope.CategoryIds) > 0 {
+		categories := []schema.CouponCategory{}
+		for _, id := range scope.CategoryIds {
+			categories = append(categories, schema.CouponCategory{CouponId: couponId, CategoryId: id})
+		}
+
+		if err := tx.Create(&categories).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
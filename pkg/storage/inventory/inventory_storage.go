@@ -0,0 +1,137 @@
+package inventory
+
+import (
+	"context"
+	"database/sql"
+	"kanggo/pkg/alert"
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/entity/schema"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:generate mockery --name InventoryStorage --case snake --output ../../mocks --disable-version-string
+
+type (
+	InventoryStorage interface {
+		GetLowStock(ctx context.Context, query model.ListQuery) ([]model.LowStockResponse, int64, error)
+		GetPendingAlerts(ctx context.Context, limit int) ([]alert.Alert, error)
+		MarkNotified(ctx context.Context, ids []int64) error
+	}
+
+	inventoryStorage struct {
+		Native *sql.DB
+		Gorm   *gorm.DB
+	}
+)
+
+func NewInventoryStorage(native *sql.DB, gorm *gorm.DB) InventoryStorage {
+	return &inventoryStorage{
+		Native: native,
+		Gorm:   gorm,
+	}
+}
+
+// lowStockSorts maps the sort keys of the low stock report to columns.
+var lowStockSorts = map[string]string{
+	"qty":  "p.qty",
+	"id":   "p.id",
+	"name": "p.name",
+}
+
+// GetLowStock returns a page of the products at or below their reorder
+// level. Archived products are no longer restocked and are left out.
+func (i *inventoryStorage) GetLowStock(ctx context.Context, query model.ListQuery) ([]model.LowStockResponse, int64, error) {
+	var total int64
+	where := []string{"p.deleted_at IS NULL", "p.status <> ?", "p.qty <= p.reorder_level"}
+	args := []interface{}{schema.ProductArchived}
+
+	if err := i.Native.QueryRowContext(ctx, `SELECT COUNT(*) FROM products p WHERE `+strings.Join(where, " AND "), args...).
+		Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	column, ok := lowStockSorts[query.Sort]
+	if !ok {
+		column = "p.qty"
+	}
+	direction := "ASC"
+	if query.Desc {
+		direction = "DESC"
+	}
+
+	if query.Cursor > 0 {
+		if query.Desc {
+			where = append(where, "p.id < ?")
+		} else {
+			where = append(where, "p.id > ?")
+		}
+		args = append(args, query.Cursor)
+	}
+
+	qry := `SELECT p.id, p.name, p.status, p.qty, p.reorder_level, a.created_at
+	FROM products p
+	LEFT JOIN stock_alerts a ON a.product_id = p.id AND a.resolved_at IS NULL
+	WHERE ` + strings.Join(where, " AND ") + `
+	ORDER BY ` + column + ` ` + direction + `, p.id ` + direction + `
+	LIMIT ? OFFSET ?`
+
+	rows, err := i.Native.QueryContext(ctx, qry, append(args, query.Size, query.Offset())...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	results := []model.LowStockResponse{}
+	for rows.Next() {
+		var res model.LowStockResponse
+		var alertedAt sql.NullTime
+		if err := rows.Scan(&res.ProductId, &res.Name, &res.Status, &res.Qty, &res.ReorderLevel, &alertedAt); err != nil {
+			return nil, 0, err
+		}
+		if alertedAt.Valid {
+			res.AlertedAt = &alertedAt.Time
+		}
+		results = append(results, res)
+	}
+
+	return results, total, rows.Err()
+}
+
+// GetPendingAlerts returns the oldest open alerts the notifiers have not
+// been told about. An alert resolved before it was delivered is dropped.
+func (i *inventoryStorage) GetPendingAlerts(ctx context.Context, limit int) ([]alert.Alert, error) {
+	qry := `SELECT a.id, a.product_id, p.name, a.qty, a.reorder_level, a.created_at
+	FROM stock_alerts a JOIN products p ON p.id = a.product_id
+	WHERE a.notified_at IS NULL AND a.resolved_at IS NULL
+	ORDER BY a.id
+	LIMIT ?`
+
+	rows, err := i.Native.QueryContext(ctx, qry, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []alert.Alert{}
+	for rows.Next() {
+		var res alert.Alert
+		if err := rows.Scan(&res.Id, &res.ProductId, &res.ProductName, &res.Qty, &res.ReorderLevel, &res.RaisedAt); err != nil {
+			return nil, err
+		}
+		results = append(results, res)
+	}
+
+	return results, rows.Err()
+}
+
+func (i *inventoryStorage) MarkNotified(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return i.Gorm.WithContext(ctx).Model(&schema.StockAlert{}).Where("id IN ?", ids).
+		Update("notified_at", time.Now()).Error
+}
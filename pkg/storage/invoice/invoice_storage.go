@@ -0,0 +1,86 @@
+package invoice
+
+import (
+	"context"
+	"database/sql"
+	"kanggo/pkg/entity/schema"
+
+	"gorm.io/gorm"
+)
+
+//go:generate mockery --name InvoiceStorage --case snake --output ../../mocks --disable-version-string
+
+// Invoices are issued by the order storage together with the payment, so this
+// storage only reads them.
+type (
+	InvoiceStorage interface {
+		GetByOrderId(ctx context.Context, orderId int64) (*schema.Invoice, error)
+		GetLines(ctx context.Context, invoiceId int64) ([]schema.InvoiceLine, error)
+	}
+
+	invoiceStorage struct {
+		Native *sql.DB
+		Gorm   *gorm.DB
+	}
+)
+
+func NewInvoiceStorage(native *sql.DB, gorm *gorm.DB) InvoiceStorage {
+	return &invoiceStorage{
+		Native: native,
+		Gorm:   gorm,
+	}
+}
+
+func (i *invoiceStorage) GetByOrderId(ctx context.Context, orderId int64) (*schema.Invoice, error) {
+	invoice := schema.Invoice{}
+	qry := `SELECT id, created_at, updated_at, number, year, sequence, order_id, COALESCE(order_number,""), user_id,
+	COALESCE(buyer_name,""), COALESCE(buyer_email,""),
+	COALESCE(shipping_recipient,""), COALESCE(shipping_phone,""), COALESCE(shipping_street,""),
+	COALESCE(shipping_province,""), COALESCE(shipping_city,""), COALESCE(shipping_district,""),
+	COALESCE(shipping_postal_code,""), COALESCE(courier,""), COALESCE(service,""),
+	subtotal, discount_amount, net_amount, tax_rate, tax_amount, tax_inclusive,
+	shipping_cost, total, issued_at
+	FROM invoices WHERE order_id = ?`
+
+	res := i.Native.QueryRowContext(ctx, qry, orderId)
+	if err := res.Scan(&invoice.Id, &invoice.CreatedAt, &invoice.UpdatedAt, &invoice.Number,
+		&invoice.Year, &invoice.Sequence, &invoice.OrderId, &invoice.OrderNumber, &invoice.UserId,
+		&invoice.BuyerName, &invoice.BuyerEmail,
+		&invoice.Shipping.Recipient, &invoice.Shipping.Phone, &invoice.Shipping.Street,
+		&invoice.Shipping.Province, &invoice.Shipping.City, &invoice.Shipping.District,
+		&invoice.Shipping.PostalCode, &invoice.Courier, &invoice.Service,
+		&invoice.Subtotal, &invoice.DiscountAmount, &invoice.NetAmount, &invoice.TaxRate,
+		&invoice.TaxAmount, &invoice.TaxInclusive, &invoice.ShippingCost, &invoice.Total,
+		&invoice.IssuedAt); err != nil {
+		return nil, err
+	}
+
+	return &invoice, nil
+}
+
+func (i *invoiceStorage) GetLines(ctx context.Context, invoiceId int64) ([]schema.InvoiceLine, error) {
+	qry := `SELECT id, created_at, updated_at, invoice_id, product_id, COALESCE(description,""),
+	quantity, unit_price, amount
+	FROM invoice_lines
+	WHERE invoice_id = ?
+	ORDER BY id
+	`
+
+	rows, err := i.Native.QueryContext(ctx, qry, invoiceId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lines := []schema.InvoiceLine{}
+	for rows.Next() {
+		var res schema.InvoiceLine
+		if err := rows.Scan(&res.Id, &res.CreatedAt, &res.UpdatedAt, &res.InvoiceId, &res.ProductId,
+			&res.Description, &res.Quantity, &res.UnitPrice, &res.Amount); err != nil {
+			return nil, err
+		}
+		lines = append(lines, res)
+	}
+
+	return lines, nil
+}
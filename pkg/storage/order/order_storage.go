@@ -3,23 +3,34 @@ package order
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"kanggo/pkg/entity/model"
 	"kanggo/pkg/entity/schema"
+	"kanggo/pkg/promotion"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 //go:generate mockery --name OrderStorage --case snake --output ../../mocks --disable-version-string
 
 type (
 	OrderStorage interface {
-		InsertOrder(ctx context.Context, data schema.Order, quantity int64) error
-		GetAllOrder(ctx context.Context) ([]model.OrderResponse, error)
-		GetAllOrderPerUser(ctx context.Context, userId uint64) ([]model.OrderResponse, error)
+		InsertOrder(ctx context.Context, data schema.Order, quantity int64, redemption *schema.CouponRedemption) error
+		GetAllOrder(ctx context.Context, query model.ListQuery) ([]model.OrderResponse, int64, error)
+		GetAllOrderPerUser(ctx context.Context, userId uint64, query model.ListQuery) ([]model.OrderResponse, int64, error)
 		GetOrderById(ctx context.Context, orderId int64, userId uint64) (*model.OrderResponse, error)
+		GetOrderIdByNumber(ctx context.Context, number string) (int64, error)
+		HasPendingOrder(ctx context.Context, userId int64, variantId int64) (bool, error)
+		NextNumber(ctx context.Context, day time.Time) (int64, error)
+		CancelOrder(ctx context.Context, orderId int64, actorId int64, actorRole string, reason string, version uint) error
+		GetEvents(ctx context.Context, orderId int64) ([]schema.OrderEvent, error)
 		UpdatePayment(ctx context.Context, data schema.Order) error
+		GetTaxReport(ctx context.Context, from, to time.Time) ([]model.TaxReport, error)
 	}
 
 	orderStorage struct {
@@ -35,15 +46,20 @@ func NewOrderStorage(native *sql.DB, gorm *gorm.DB) OrderStorage {
 	}
 }
 
-func (o *orderStorage) InsertOrder(ctx context.Context, data schema.Order, quantity int64) error {
-	var product schema.Product
-	var qty int64
+// InsertOrder places the order, merging it into a pending order of the same
+// variant, and records the coupon redemption and the order event in the same
+// transaction. Only a new order needs a number, a merged one keeps its own.
+func (o *orderStorage) InsertOrder(ctx context.Context, data schema.Order, quantity int64, redemption *schema.CouponRedemption) error {
 	var amount float64
-	var newQty int64
+	var orderId int64
+	var event schema.OrderEvent
+	var payload map[string]interface{}
 
 	type id struct {
-		UserId    int64
-		ProductId int64
+		Id          int64
+		UserId      int64
+		VariantId   int64
+		WarehouseId int64
 	}
 
 	var ids id
@@ -59,32 +75,41 @@ func (o *orderStorage) InsertOrder(ctx context.Context, data schema.Order, quant
 		return err
 	}
 
-	_ = tx.WithContext(ctx).Where("user_id = ? and product_id=? and status <> 'paid' ", data.UserId, data.ProductId).
-		Select("user_id", "product_id").First(&data).Scan(&ids).Error
+	// the pending order is only read into ids, data keeps the warehouse
+	// chosen for this order
+	_ = tx.WithContext(ctx).Model(&schema.Order{}).Where("user_id = ? and variant_id = ? and status = 'pending'", data.UserId, data.VariantId).
+		Select("id", "user_id", "variant_id", "warehouse_id").Order("id").Limit(1).Scan(&ids).Error
 
-	if ids.ProductId == 0 && ids.UserId == 0 {
-		if err := tx.WithContext(ctx).Create(&data).Error; err != nil {
+	if ids.VariantId == 0 && ids.UserId == 0 {
+		// the pending order was paid or cancelled since the caller looked
+		if data.Number == "" {
 			tx.Rollback()
-			return err
+			return errors.New("order number required")
 		}
-
-		if err := tx.WithContext(ctx).Where("id = ?", data.ProductId).Select("qty").
-			First(&product).Scan(&qty).Error; err != nil {
+		if err := tx.WithContext(ctx).Create(&data).Error; err != nil {
 			tx.Rollback()
 			return err
 		}
-
-		newQty = qty - quantity
-
-		if err := tx.WithContext(ctx).Model(&product).Where("id=?", data.ProductId).
-			Update("qty", newQty).Error; err != nil {
-			tx.Rollback()
-			return err
+		orderId = int64(data.Id)
+		event = schema.OrderEvent{Type: schema.OrderEventCreated, ToStatus: "pending"}
+		payload = map[string]interface{}{
+			"number":        data.Number,
+			"quantity":      quantity,
+			"amount":        data.Amount,
+			"shipping_cost": data.ShippingCost,
 		}
 
 	} else {
+		orderId = ids.Id
 		addAmount := data.Amount
-		if err := tx.WithContext(ctx).Where("user_id = ? and product_id=? and status <> 'paid'", ids.UserId, ids.ProductId).Select("amount").
+
+		// a pending order ships from one warehouse, so the added quantity
+		// comes from the warehouse the order already has. An order placed
+		// before warehouses were set up takes the one chosen now.
+		if ids.WarehouseId > 0 {
+			data.WarehouseId = ids.WarehouseId
+		}
+		if err := tx.WithContext(ctx).Where("user_id = ? and variant_id = ? and status = 'pending'", ids.UserId, ids.VariantId).Select("amount").
 			First(&data).Scan(&amount).Error; err != nil {
 			tx.Rollback()
 			return err
@@ -92,110 +117,373 @@ func (o *orderStorage) InsertOrder(ctx context.Context, data schema.Order, quant
 
 		newAmount := amount + addAmount
 		data.Amount = newAmount
+		// the merge silently changes the amount of an existing order, so it
+		// is recorded with the amounts before and after
+		event = schema.OrderEvent{Type: schema.OrderEventAmountChanged, FromStatus: "pending", ToStatus: "pending"}
+		payload = map[string]interface{}{
+			"reason":              "merged",
+			"added_quantity":      quantity,
+			"added_amount":        addAmount,
+			"added_shipping_cost": data.ShippingCost,
+			"previous_amount":     amount,
+			"amount":              newAmount,
+		}
+
+		if err := tx.WithContext(ctx).Model(&data).Where("user_id = ? and variant_id = ? and status = 'pending'", ids.UserId, ids.VariantId).Update("amount", newAmount).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
 
-		if err := tx.WithContext(ctx).Model(&data).Where("user_id = ? and product_id=? and status <> 'paid'", ids.UserId, ids.ProductId).Update("amount", newAmount).Error; err != nil {
+		// the order is still unpaid, so it ships to the most recently chosen
+		// address and courier; the added items are charged their own shipping
+		if err := tx.WithContext(ctx).Model(&schema.Order{}).Where("user_id = ? and variant_id = ? and status = 'pending'", ids.UserId, ids.VariantId).
+			Updates(schema.Order{Shipping: data.Shipping, Courier: data.Courier, Service: data.Service}).Error; err != nil {
 			tx.Rollback()
 			return err
 		}
 
-		if err := tx.WithContext(ctx).Where("id = ?", data.ProductId).Select("qty").
-			First(&product).Scan(&qty).Error; err != nil {
+		if err := tx.WithContext(ctx).Model(&schema.Order{}).Where("user_id = ? and variant_id = ? and status = 'pending'", ids.UserId, ids.VariantId).
+			Updates(map[string]interface{}{
+				"shipping_cost":   gorm.Expr("shipping_cost + ?", data.ShippingCost),
+				"net_amount":      gorm.Expr("net_amount + ?", data.NetAmount),
+				"tax_amount":      gorm.Expr("tax_amount + ?", data.TaxAmount),
+				"discount_amount": gorm.Expr("discount_amount + ?", data.DiscountAmount),
+				"quantity":        gorm.Expr("quantity + ?", quantity),
+				"tax_rate":        data.TaxRate,
+				"tax_inclusive":   data.TaxInclusive,
+				"warehouse_id":    data.WarehouseId,
+				"version":         gorm.Expr("version + 1"),
+			}).Error; err != nil {
 			tx.Rollback()
 			return err
 		}
 
-		newQty = qty - quantity
+	}
+
+	if err := reserveStock(tx.WithContext(ctx), data.ProductId, data.VariantId, data.WarehouseId, orderId, quantity); err != nil {
+		tx.Rollback()
+		return err
+	}
 
-		if err := tx.WithContext(ctx).Model(&product).Where("id=?", data.ProductId).
-			Update("qty", newQty).Error; err != nil {
+	if err := checkReorder(tx.WithContext(ctx), data.ProductId, quantity); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if redemption != nil {
+		redemption.OrderId = orderId
+		if err := redeemCoupon(tx.WithContext(ctx), *redemption); err != nil {
 			tx.Rollback()
 			return err
 		}
+	}
 
+	if redemption != nil {
+		payload["coupon_id"] = redemption.CouponId
+		payload["discount"] = redemption.Discount
+	}
+	event.OrderId = orderId
+	event.ActorId = data.UserId
+	event.ActorRole = "user"
+	event.Payload = eventPayload(payload)
+	if err := tx.WithContext(ctx).Create(&event).Error; err != nil {
+		tx.Rollback()
+		return err
 	}
 
 	return tx.Commit().Error
 }
 
-func (o *orderStorage) GetAllOrder(ctx context.Context) ([]model.OrderResponse, error) {
-	qry := `SELECT o.id, COALESCE(o.user_id,0), COALESCE(u.name,""), COALESCE(o.product_id,0),
-	COALESCE(p.name,""), COALESCE(o.amount,0), COALESCE(o.status,"")
-	FROM orders as o 
-	LEFT JOIN products as p ON p.id = o.product_id
-	LEFT JOIN users as u ON u.id = o.user_id
-	`
+// reserveStock takes the ordered quantity off the warehouse, when the order
+// has one, the variant and the product. The conditional updates fail instead
+// of letting concurrent orders oversell the variant.
+func reserveStock(tx *gorm.DB, productId, variantId, warehouseId, orderId, quantity int64) error {
+	if warehouseId > 0 {
+		result := tx.Model(&schema.WarehouseStock{}).
+			Where("warehouse_id = ? AND variant_id = ? AND qty >= ?", warehouseId, variantId, quantity).
+			Update("qty", gorm.Expr("qty - ?", quantity))
+		if result.Error != nil {
+			return result.Error
+		}
 
-	rows, err := o.Native.QueryContext(ctx, qry)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, err
+		if result.RowsAffected == 0 {
+			return errors.New("not enough product quantity")
+		}
+
+		if err := tx.Create(&schema.StockMovement{
+			WarehouseId: warehouseId,
+			VariantId:   variantId,
+			ProductId:   productId,
+			Qty:         -quantity,
+			Type:        schema.MovementOrder,
+			OrderId:     orderId,
+		}).Error; err != nil {
+			return err
 		}
-		return nil, err
 	}
 
-	products := []model.OrderResponse{}
-	for rows.Next() {
-		var res model.OrderResponse
-		if err := rows.Scan(&res.OrderId, &res.UserId, &res.UserName,
-			&res.ProductId, &res.ProductName, &res.Amount, &res.Status); err != nil {
-			return nil, err
+	result := tx.Model(&schema.ProductVariant{}).Where("id = ? AND qty >= ?", variantId, quantity).
+		Update("qty", gorm.Expr("qty - ?", quantity))
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("not enough product quantity")
+	}
+
+	return tx.Unscoped().Model(&schema.Product{}).Where("id = ?", productId).
+		Updates(map[string]interface{}{"qty": gorm.Expr("qty - ?", quantity), "version": gorm.Expr("version + 1")}).Error
+}
+
+// checkReorder raises a stock alert when the order took the product down to
+// its reorder level. The product row is locked by the decrement, so the
+// check cannot race another order. An open alert is kept until the product
+// is seen above its level, or until it is found to have been restocked
+// since, when the stock before this order was above the level.
+func checkReorder(tx *gorm.DB, productId, quantity int64) error {
+	var product schema.Product
+	var open schema.StockAlert
+
+	if err := tx.Unscoped().Select("id", "qty", "reorder_level").First(&product, productId).Error; err != nil {
+		return err
+	}
+
+	err := tx.Where("product_id = ? AND resolved_at IS NULL", productId).Order("id DESC").First(&open).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	hasOpen := err == nil
+
+	if product.Qty > product.ReorderLevel {
+		if hasOpen {
+			return resolveAlerts(tx, productId)
+		}
+		return nil
+	}
+
+	if hasOpen {
+		if product.Qty+quantity <= product.ReorderLevel {
+			return nil
+		}
+		if err := resolveAlerts(tx, productId); err != nil {
+			return err
+		}
+	}
+
+	return tx.Create(&schema.StockAlert{
+		ProductId:    productId,
+		Qty:          product.Qty,
+		ReorderLevel: product.ReorderLevel,
+	}).Error
+}
+
+func resolveAlerts(tx *gorm.DB, productId int64) error {
+	return tx.Model(&schema.StockAlert{}).Where("product_id = ? AND resolved_at IS NULL", productId).
+		Update("resolved_at", time.Now()).Error
+}
+
+func eventPayload(v interface{}) string {
+	payload, _ := json.Marshal(v)
+	return string(payload)
+}
+
+// redeemCoupon locks the coupon row so concurrent orders cannot push it past
+// its global or per-user usage limit.
+func redeemCoupon(tx *gorm.DB, redemption schema.CouponRedemption) error {
+	var coupon schema.Coupon
+	var used int64
+
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", redemption.CouponId).
+		First(&coupon).Error; err != nil {
+		return err
+	}
+
+	if coupon.UsageLimit > 0 && coupon.UsedCount >= coupon.UsageLimit {
+		return promotion.ErrUsageLimit
+	}
+
+	if coupon.PerUserLimit > 0 {
+		if err := tx.Model(&schema.CouponRedemption{}).
+			Where("coupon_id = ? AND user_id = ?", redemption.CouponId, redemption.UserId).
+			Count(&used).Error; err != nil {
+			return err
+		}
+
+		if used >= coupon.PerUserLimit {
+			return promotion.ErrUsageLimit
 		}
-		products = append(products, res)
 	}
 
-	if len(products) == 0 {
-		return nil, errors.New("data not found")
+	if err := tx.Model(&schema.Coupon{}).Where("id = ?", redemption.CouponId).
+		Update("used_count", gorm.Expr("used_count + 1")).Error; err != nil {
+		return err
 	}
-	return products, nil
+
+	return tx.Create(&redemption).Error
+}
+
+// orderSorts maps the sort keys of the order lists to columns.
+var orderSorts = map[string]string{
+	"id":         "o.id",
+	"created_at": "o.created_at",
+	"amount":     "o.amount",
+	"status":     "o.status",
 }
 
-func (o *orderStorage) GetAllOrderPerUser(ctx context.Context, userId uint64) ([]model.OrderResponse, error) {
-	qry := `SELECT o.id, COALESCE(o.user_id,0), COALESCE(u.name,""), COALESCE(o.product_id,0),
-	COALESCE(p.name,""), COALESCE(o.amount,0), COALESCE(o.status,"")
+func (o *orderStorage) GetAllOrder(ctx context.Context, query model.ListQuery) ([]model.OrderResponse, int64, error) {
+	return o.listOrders(ctx, query, nil)
+}
+
+func (o *orderStorage) GetAllOrderPerUser(ctx context.Context, userId uint64, query model.ListQuery) ([]model.OrderResponse, int64, error) {
+	return o.listOrders(ctx, query, &userId)
+}
+
+// listOrders returns a page of the orders matching the query, of one user
+// when userId is set, and the total number of matching orders.
+func (o *orderStorage) listOrders(ctx context.Context, query model.ListQuery, userId *uint64) ([]model.OrderResponse, int64, error) {
+	var total int64
+	where := []string{"1 = 1"}
+	args := []interface{}{}
+
+	if userId != nil {
+		where = append(where, "o.user_id = ?")
+		args = append(args, *userId)
+	}
+	if query.Status != "" {
+		where = append(where, "o.status = ?")
+		args = append(args, query.Status)
+	}
+	if query.From != nil {
+		where = append(where, "o.created_at >= ?")
+		args = append(args, *query.From)
+	}
+	if query.To != nil {
+		where = append(where, "o.created_at < ?")
+		args = append(args, *query.To)
+	}
+	if query.MinPrice != nil {
+		where = append(where, "o.amount >= ?")
+		args = append(args, *query.MinPrice)
+	}
+	if query.MaxPrice != nil {
+		where = append(where, "o.amount <= ?")
+		args = append(args, *query.MaxPrice)
+	}
+
+	if err := o.Native.QueryRowContext(ctx, `SELECT COUNT(*) FROM orders as o WHERE `+strings.Join(where, " AND "), args...).
+		Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	column, ok := orderSorts[query.Sort]
+	if !ok {
+		column = "o.id"
+	}
+	direction := "ASC"
+	if query.Desc {
+		direction = "DESC"
+	}
+
+	if query.Cursor > 0 {
+		if query.Desc {
+			where = append(where, "o.id < ?")
+		} else {
+			where = append(where, "o.id > ?")
+		}
+		args = append(args, query.Cursor)
+	}
+
+	qry := `SELECT o.id, COALESCE(o.number,""), COALESCE(o.user_id,0), COALESCE(u.name,""), COALESCE(o.product_id,0),
+	COALESCE(p.name,""), o.variant_id, COALESCE(o.sku,""), COALESCE(o.amount,0), COALESCE(o.status,""),
+	COALESCE(o.shipping_recipient,""), COALESCE(o.shipping_phone,""), COALESCE(o.shipping_street,""),
+	COALESCE(o.shipping_province,""), COALESCE(o.shipping_city,""), COALESCE(o.shipping_district,""),
+	COALESCE(o.shipping_postal_code,""), COALESCE(o.courier,""), COALESCE(o.service,""),
+	COALESCE(o.shipping_cost,0), COALESCE(o.net_amount,0), COALESCE(o.tax_rate,0),
+	COALESCE(o.tax_amount,0), COALESCE(o.tax_inclusive,0), COALESCE(o.discount_amount,0), o.warehouse_id, o.version
 	FROM orders as o 
 	LEFT JOIN products as p ON p.id = o.product_id
 	LEFT JOIN users as u ON u.id = o.user_id
-	WHERE o.user_id = ?
-	`
+	WHERE ` + strings.Join(where, " AND ") + `
+	ORDER BY ` + column + ` ` + direction + `, o.id ` + direction + `
+	LIMIT ? OFFSET ?`
 
-	rows, err := o.Native.QueryContext(ctx, qry, userId)
+	rows, err := o.Native.QueryContext(ctx, qry, append(args, query.Size, query.Offset())...)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, err
-		}
-		return nil, err
+		return nil, 0, err
 	}
+	defer rows.Close()
 
-	products := []model.OrderResponse{}
+	orders := []model.OrderResponse{}
 	for rows.Next() {
 		var res model.OrderResponse
-		if err := rows.Scan(&res.OrderId, &res.UserId, &res.UserName,
-			&res.ProductId, &res.ProductName, &res.Amount, &res.Status); err != nil {
-			return nil, err
+		if err := rows.Scan(&res.OrderId, &res.OrderNumber, &res.UserId, &res.UserName,
+			&res.ProductId, &res.ProductName, &res.VariantId, &res.Sku, &res.Amount, &res.Status,
+			&res.Shipping.Recipient, &res.Shipping.Phone, &res.Shipping.Street, &res.Shipping.Province,
+			&res.Shipping.City, &res.Shipping.District, &res.Shipping.PostalCode,
+			&res.Courier, &res.Service, &res.ShippingCost,
+			&res.NetAmount, &res.TaxRate, &res.TaxAmount, &res.TaxInclusive, &res.DiscountAmount, &res.WarehouseId, &res.Version); err != nil {
+			return nil, 0, err
 		}
-		products = append(products, res)
+		orders = append(orders, res)
 	}
 
-	if len(products) == 0 {
-		return nil, errors.New("data not found")
-	}
-	return products, nil
+	return orders, total, nil
 }
 
 func (o *orderStorage) GetOrderById(ctx context.Context, orderId int64, userId uint64) (*model.OrderResponse, error) {
+	return o.getOrder(ctx, "o.id = ?", orderId, userId)
+}
+
+// GetOrderIdByNumber finds the order of a number for any user, so the lookup
+// by id that follows still decides who may see it.
+func (o *orderStorage) GetOrderIdByNumber(ctx context.Context, number string) (int64, error) {
+	var id int64
+
+	if err := o.Native.QueryRowContext(ctx, `SELECT id FROM orders WHERE number = ?`, number).Scan(&id); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// HasPendingOrder tells whether an order of the variant would merge into a
+// pending order of the user.
+func (o *orderStorage) HasPendingOrder(ctx context.Context, userId int64, variantId int64) (bool, error) {
+	var count int64
+
+	if err := o.Gorm.WithContext(ctx).Model(&schema.Order{}).
+		Where("user_id = ? and variant_id = ? and status = 'pending'", userId, variantId).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (o *orderStorage) getOrder(ctx context.Context, where string, key interface{}, userId uint64) (*model.OrderResponse, error) {
 	var result model.OrderResponse
 
-	qry := `SELECT o.id, COALESCE(o.user_id,0), COALESCE(u.name,""), COALESCE(o.product_id,0),
-	COALESCE(p.name,""), COALESCE(o.amount,0), COALESCE(o.status,"")
+	qry := `SELECT o.id, COALESCE(o.number,""), COALESCE(o.user_id,0), COALESCE(u.name,""), COALESCE(o.product_id,0),
+	COALESCE(p.name,""), o.variant_id, COALESCE(o.sku,""), COALESCE(o.amount,0), COALESCE(o.status,""),
+	COALESCE(o.shipping_recipient,""), COALESCE(o.shipping_phone,""), COALESCE(o.shipping_street,""),
+	COALESCE(o.shipping_province,""), COALESCE(o.shipping_city,""), COALESCE(o.shipping_district,""),
+	COALESCE(o.shipping_postal_code,""), COALESCE(o.courier,""), COALESCE(o.service,""),
+	COALESCE(o.shipping_cost,0), COALESCE(o.net_amount,0), COALESCE(o.tax_rate,0),
+	COALESCE(o.tax_amount,0), COALESCE(o.tax_inclusive,0), COALESCE(o.discount_amount,0), o.warehouse_id, o.version
 	FROM orders as o 
 	LEFT JOIN products as p ON p.id = o.product_id
 	LEFT JOIN users as u ON u.id = o.user_id
-	WHERE o.id = ? AND o.user_id = ?
+	WHERE ` + where + ` AND o.user_id = ?
 	`
 
-	res := o.Native.QueryRowContext(ctx, qry, orderId, userId)
-	if err := res.Scan(&result.OrderId, &result.UserId, &result.UserName,
-		&result.ProductId, &result.ProductName, &result.Amount, &result.Status); err != nil {
+	res := o.Native.QueryRowContext(ctx, qry, key, userId)
+	if err := res.Scan(&result.OrderId, &result.OrderNumber, &result.UserId, &result.UserName,
+		&result.ProductId, &result.ProductName, &result.VariantId, &result.Sku, &result.Amount, &result.Status,
+		&result.Shipping.Recipient, &result.Shipping.Phone, &result.Shipping.Street, &result.Shipping.Province,
+		&result.Shipping.City, &result.Shipping.District, &result.Shipping.PostalCode,
+		&result.Courier, &result.Service, &result.ShippingCost,
+		&result.NetAmount, &result.TaxRate, &result.TaxAmount, &result.TaxInclusive, &result.DiscountAmount, &result.WarehouseId, &result.Version); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, err
 		}
@@ -205,8 +493,47 @@ func (o *orderStorage) GetOrderById(ctx context.Context, orderId int64, userId u
 	return &result, nil
 }
 
+// NextNumber hands out the next order sequence of the day. The upsert holds
+// the day's row lock until commit, so concurrent orders never share a number.
+// Numbers of orders that fail afterwards are not reused.
+func (o *orderStorage) NextNumber(ctx context.Context, day time.Time) (int64, error) {
+	var number int64
+	key := day.Format("2006-01-02")
+
+	tx := o.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return 0, err
+	}
+
+	if err := tx.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]interface{}{"last_number": gorm.Expr("last_number + 1")}),
+	}).Create(&schema.OrderSequence{Day: key, LastNumber: 1}).Error; err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	if err := tx.WithContext(ctx).Model(&schema.OrderSequence{}).Where("day = ?", key).
+		Select("last_number").Scan(&number).Error; err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	return number, tx.Commit().Error
+}
+
+// UpdatePayment marks the pending order paid, issues its invoice and records
+// the payment event in the same transaction.
 func (o *orderStorage) UpdatePayment(ctx context.Context, data schema.Order) error {
-	var amount float64
+	var order struct {
+		Id     int64
+		Amount float64
+	}
 	data1 := fmt.Sprintf("%.2f", data.Amount)
 
 	tx := o.Gorm.Begin()
@@ -220,23 +547,325 @@ func (o *orderStorage) UpdatePayment(ctx context.Context, data schema.Order) err
 		return err
 	}
 
-	if err := tx.WithContext(ctx).Where("user_id = ? and product_id=? and status <> 'paid'", data.UserId, data.ProductId).Select("amount").
-		First(&data).Scan(&amount).Error; err != nil {
+	// with more variants of the product pending, the variant picks the order
+	pending := tx.WithContext(ctx).Where("user_id = ? and product_id=? and status = 'pending'", data.UserId, data.ProductId)
+	if data.VariantId > 0 {
+		pending = pending.Where("variant_id = ?", data.VariantId)
+	}
+
+	if err := pending.Select("id", "amount + shipping_cost AS amount").
+		First(&data).Scan(&order).Error; err != nil {
 		tx.Rollback()
 		return err
 	}
-	data2 := fmt.Sprintf("%.2f", amount)
+	data2 := fmt.Sprintf("%.2f", order.Amount)
 
 	if data1 != data2 {
 		tx.Rollback()
 		return errors.New("payment amount does not match")
 	}
 
-	if err := tx.WithContext(ctx).Model(&data).Where("user_id = ? and product_id=? and status <> 'paid'", data.UserId, data.ProductId).
-		Update("status", "paid").Error; err != nil {
+	paidAt := time.Now()
+	if err := tx.WithContext(ctx).Model(&schema.Order{}).Where("id = ?", order.Id).
+		Updates(map[string]interface{}{"status": "paid", "paid_at": paidAt, "version": gorm.Expr("version + 1")}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	invoiceNumber, err := issueInvoice(tx.WithContext(ctx), order.Id, paidAt)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Create(&schema.OrderEvent{
+		OrderId:    order.Id,
+		Type:       schema.OrderEventPaid,
+		FromStatus: "pending",
+		ToStatus:   "paid",
+		ActorId:    data.UserId,
+		ActorRole:  "user",
+		Payload: eventPayload(map[string]interface{}{
+			"amount":  data.Amount,
+			"invoice": invoiceNumber,
+		}),
+	}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// issueInvoice takes the next number of the year from invoice_sequences. The
+// upsert locks the sequence row until the payment commits, which keeps the
+// numbers gap-free under concurrent payments.
+func issueInvoice(tx *gorm.DB, orderId int64, issuedAt time.Time) (string, error) {
+	var order schema.Order
+	var user schema.User
+	var product schema.Product
+	var number int64
+
+	if err := tx.Where("id = ?", orderId).First(&order).Error; err != nil {
+		return "", err
+	}
+
+	if err := tx.Select("name", "email").Where("id = ?", order.UserId).First(&user).Error; err != nil {
+		return "", err
+	}
+
+	// the product may have been trashed since it was ordered
+	if err := tx.Unscoped().Select("name").Where("id = ?", order.ProductId).First(&product).Error; err != nil {
+		return "", err
+	}
+
+	year := issuedAt.Year()
+	if err := tx.Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]interface{}{"last_number": gorm.Expr("last_number + 1")}),
+	}).Create(&schema.InvoiceSequence{Year: year, LastNumber: 1}).Error; err != nil {
+		return "", err
+	}
+
+	if err := tx.Model(&schema.InvoiceSequence{}).Where("year = ?", year).
+		Select("last_number").Scan(&number).Error; err != nil {
+		return "", err
+	}
+
+	// Amount is after discount and includes the tax, so the list price of
+	// the goods is recovered by adding the discount back and, for exclusive
+	// tax, taking the tax out
+	subtotal := order.Amount + order.DiscountAmount
+	if !order.TaxInclusive {
+		subtotal -= order.TaxAmount
+	}
+
+	var unitPrice float64
+	if order.Quantity > 0 {
+		unitPrice = subtotal / float64(order.Quantity)
+	}
+
+	invoice := schema.Invoice{
+		Number:         fmt.Sprintf("INV/%d/%06d", year, number),
+		Year:           year,
+		Sequence:       number,
+		OrderId:        orderId,
+		OrderNumber:    order.Number,
+		UserId:         order.UserId,
+		BuyerName:      user.Name,
+		BuyerEmail:     user.Email,
+		Shipping:       order.Shipping,
+		Courier:        order.Courier,
+		Service:        order.Service,
+		Subtotal:       subtotal,
+		DiscountAmount: order.DiscountAmount,
+		NetAmount:      order.NetAmount,
+		TaxRate:        order.TaxRate,
+		TaxAmount:      order.TaxAmount,
+		TaxInclusive:   order.TaxInclusive,
+		ShippingCost:   order.ShippingCost,
+		Total:          order.Amount + order.ShippingCost,
+		IssuedAt:       issuedAt,
+	}
+
+	if err := tx.Create(&invoice).Error; err != nil {
+		return "", err
+	}
+
+	if err := tx.Create(&schema.InvoiceLine{
+		InvoiceId:   int64(invoice.Id),
+		ProductId:   order.ProductId,
+		Description: product.Name,
+		Quantity:    order.Quantity,
+		UnitPrice:   unitPrice,
+		Amount:      subtotal,
+	}).Error; err != nil {
+		return "", err
+	}
+
+	return invoice.Number, nil
+}
+
+// GetTaxReport sums the tax of orders paid in [from, to), per tax rate.
+func (o *orderStorage) GetTaxReport(ctx context.Context, from, to time.Time) ([]model.TaxReport, error) {
+	qry := `SELECT tax_rate, COUNT(*), COALESCE(SUM(net_amount),0), COALESCE(SUM(tax_amount),0),
+	COALESCE(SUM(amount),0)
+	FROM orders
+	WHERE status IN ('paid','shipped','completed') AND paid_at >= ? AND paid_at < ?
+	GROUP BY tax_rate
+	ORDER BY tax_rate
+	`
+
+	rows, err := o.Native.QueryContext(ctx, qry, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reports := []model.TaxReport{}
+	for rows.Next() {
+		var res model.TaxReport
+		if err := rows.Scan(&res.TaxRate, &res.TotalOrders, &res.NetAmount,
+			&res.TaxAmount, &res.GrossAmount); err != nil {
+			return nil, err
+		}
+		reports = append(reports, res)
+	}
+
+	return reports, nil
+}
+
+// CancelOrder cancels a pending order, returns its quantity to stock and
+// releases the coupon it redeemed. A version other than 0 must be the current
+// version of the order.
+func (o *orderStorage) CancelOrder(ctx context.Context, orderId int64, actorId int64, actorRole string, reason string, version uint) error {
+	var order schema.Order
+	var redemptions []schema.CouponRedemption
+
+	tx := o.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", orderId).First(&order).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("data not found")
+		}
+		return err
+	}
+
+	if version > 0 && order.Version != version {
+		tx.Rollback()
+		return errors.New("version mismatch")
+	}
+
+	if order.Status != "pending" {
+		tx.Rollback()
+		return errors.New("only pending orders can be cancelled")
+	}
+
+	if err := tx.WithContext(ctx).Model(&schema.Order{}).Where("id = ?", orderId).
+		Updates(map[string]interface{}{"status": "cancelled", "version": gorm.Expr("version + 1")}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := restock(tx.WithContext(ctx), order, actorId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Model(&schema.ProductVariant{}).Where("id = ?", order.VariantId).
+		Update("qty", gorm.Expr("qty + ?", order.Quantity)).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// a trashed product is restocked too, so it is restored with its stock
+	if err := tx.WithContext(ctx).Unscoped().Model(&schema.Product{}).Where("id = ?", order.ProductId).
+		Updates(map[string]interface{}{"qty": gorm.Expr("qty + ?", order.Quantity), "version": gorm.Expr("version + 1")}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Where("order_id = ?", orderId).Find(&redemptions).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for _, redemption := range redemptions {
+		if err := tx.WithContext(ctx).Model(&schema.Coupon{}).Where("id = ? AND used_count > 0", redemption.CouponId).
+			Update("used_count", gorm.Expr("used_count - 1")).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := tx.WithContext(ctx).Where("order_id = ?", orderId).Delete(&schema.CouponRedemption{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Create(&schema.OrderEvent{
+		OrderId:    orderId,
+		Type:       schema.OrderEventCancelled,
+		FromStatus: "pending",
+		ToStatus:   "cancelled",
+		ActorId:    actorId,
+		ActorRole:  actorRole,
+		Payload: eventPayload(map[string]interface{}{
+			"reason":           reason,
+			"restocked":        order.Quantity,
+			"released_coupons": len(redemptions),
+		}),
+	}).Error; err != nil {
 		tx.Rollback()
 		return err
 	}
 
 	return tx.Commit().Error
 }
+
+// restock returns the quantity of a cancelled order to the warehouse it
+// ships from. An order placed before warehouses were set up took no stock
+// from any of them, so only its variant and product are restocked.
+func restock(tx *gorm.DB, order schema.Order, actorId int64) error {
+	if order.WarehouseId == 0 {
+		return nil
+	}
+
+	if err := tx.Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]interface{}{"qty": gorm.Expr("qty + ?", order.Quantity)}),
+	}).Create(&schema.WarehouseStock{
+		WarehouseId: order.WarehouseId,
+		VariantId:   order.VariantId,
+		ProductId:   order.ProductId,
+		Qty:         order.Quantity,
+	}).Error; err != nil {
+		return err
+	}
+
+	return tx.Create(&schema.StockMovement{
+		WarehouseId: order.WarehouseId,
+		VariantId:   order.VariantId,
+		ProductId:   order.ProductId,
+		Qty:         order.Quantity,
+		Type:        schema.MovementCancel,
+		OrderId:     int64(order.Id),
+		ActorId:     actorId,
+	}).Error
+}
+
+func (o *orderStorage) GetEvents(ctx context.Context, orderId int64) ([]schema.OrderEvent, error) {
+	qry := `SELECT id, created_at, updated_at, order_id, type, COALESCE(from_status,""),
+	COALESCE(to_status,""), actor_id, actor_role, COALESCE(payload,"")
+	FROM order_events
+	WHERE order_id = ?
+	ORDER BY created_at, id
+	`
+
+	rows, err := o.Native.QueryContext(ctx, qry, orderId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []schema.OrderEvent{}
+	for rows.Next() {
+		var res schema.OrderEvent
+		if err := rows.Scan(&res.Id, &res.CreatedAt, &res.UpdatedAt, &res.OrderId, &res.Type,
+			&res.FromStatus, &res.ToStatus, &res.ActorId, &res.ActorRole, &res.Payload); err != nil {
+			return nil, err
+		}
+		events = append(events, res)
+	}
+
+	return events, nil
+}
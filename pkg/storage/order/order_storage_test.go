@@ -0,0 +1,149 @@
+package order
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"kanggo/pkg/dbtest"
+	"kanggo/pkg/entity/schema"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsertOrder(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("merge into an order placed before warehouses", func(t *testing.T) {
+		native, db, mock := dbtest.New(t)
+		s := NewOrderStorage(native, db)
+
+		mock.Expect("Begin")
+		mock.Expect("SELECT `id`,`user_id`,`variant_id`,`warehouse_id` FROM `orders`").
+			Rows([]string{"id", "user_id", "variant_id", "warehouse_id"}, []driver.Value{int64(5), int64(1), int64(3), int64(0)})
+		mock.Expect("SELECT `amount` FROM `orders`").Rows([]string{"amount"}, []driver.Value{float64(100000)})
+		mock.Expect("SELECT `amount` FROM `orders`").Rows([]string{"amount"}, []driver.Value{float64(100000)})
+		mock.Expect("UPDATE `orders` SET `amount`")
+		mock.Expect("UPDATE `orders` SET `updated_at`")
+		merge := mock.Expect("UPDATE `orders` SET .*`warehouse_id`")
+		stock := mock.Expect("UPDATE `warehouse_stocks` SET `qty`")
+		mock.Expect("INSERT INTO `stock_movements`").Result(1, 1)
+		mock.Expect("UPDATE `product_variants` SET `qty`")
+		mock.Expect("UPDATE `products` SET")
+		mock.Expect("SELECT `id`,`qty`,`reorder_level` FROM `products`").
+			Rows([]string{"id", "qty", "reorder_level"}, []driver.Value{int64(1), int64(40), int64(5)})
+		mock.Expect("SELECT \\* FROM `stock_alerts`").Rows([]string{"id"})
+		mock.Expect("INSERT INTO `order_events`").Result(1, 1)
+		mock.Expect("Commit")
+
+		err := s.InsertOrder(ctx, schema.Order{UserId: 1, ProductId: 1, VariantId: 3, WarehouseId: 2, Amount: 50000}, 5, nil)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.Done())
+		assert.Contains(t, merge.Args, driver.Value(int64(2)))
+		assert.Equal(t, []driver.Value{int64(2), int64(3), int64(5)}, stock.Args[len(stock.Args)-3:])
+	})
+
+	t.Run("new order without a number", func(t *testing.T) {
+		native, db, mock := dbtest.New(t)
+		s := NewOrderStorage(native, db)
+
+		mock.Expect("Begin")
+		mock.Expect("SELECT `id`,`user_id`,`variant_id`,`warehouse_id` FROM `orders`").
+			Rows([]string{"id", "user_id", "variant_id", "warehouse_id"})
+		mock.Expect("Rollback")
+
+		err := s.InsertOrder(ctx, schema.Order{UserId: 1, ProductId: 1, VariantId: 3, WarehouseId: 2, Amount: 50000}, 5, nil)
+
+		assert.EqualError(t, err, "order number required")
+		assert.NoError(t, mock.Done())
+	})
+}
+
+func TestUpdatePayment(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("product trashed since the order", func(t *testing.T) {
+		native, db, mock := dbtest.New(t)
+		s := NewOrderStorage(native, db)
+
+		mock.Expect("Begin")
+		mock.Expect("SELECT `id`,amount \\+ shipping_cost AS amount FROM `orders`").
+			Rows([]string{"id", "amount"}, []driver.Value{int64(5), float64(111000)})
+		mock.Expect("SELECT `id`,amount \\+ shipping_cost AS amount FROM `orders`").
+			Rows([]string{"id", "amount"}, []driver.Value{int64(5), float64(111000)})
+		mock.Expect("UPDATE `orders` SET")
+		mock.Expect("SELECT \\* FROM `orders`").
+			Rows([]string{"id", "number", "user_id", "product_id", "quantity", "amount"},
+				[]driver.Value{int64(5), "KG-000005", int64(1), int64(3), int64(1), float64(100000)})
+		mock.Expect("SELECT `name`,`email` FROM `users`").
+			Rows([]string{"name", "email"}, []driver.Value{"Bayu", "bayu@gmail.com"})
+		product := mock.Expect("SELECT `name` FROM `products`").
+			Rows([]string{"name"}, []driver.Value{"Semen 50kg"})
+		mock.Expect("INSERT INTO `invoice_sequences`").Result(1, 1)
+		mock.Expect("SELECT `last_number` FROM `invoice_sequences`").
+			Rows([]string{"last_number"}, []driver.Value{int64(1)})
+		mock.Expect("INSERT INTO `invoices`").Result(1, 1)
+		mock.Expect("INSERT INTO `invoice_lines`").Result(1, 1)
+		mock.Expect("INSERT INTO `order_events`").Result(1, 1)
+		mock.Expect("Commit")
+
+		err := s.UpdatePayment(ctx, schema.Order{UserId: 1, ProductId: 3, Amount: 111000})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.Done())
+		assert.NotContains(t, product.Query, "deleted_at")
+	})
+}
+
+func TestCancelOrder(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("product trashed since the order", func(t *testing.T) {
+		native, db, mock := dbtest.New(t)
+		s := NewOrderStorage(native, db)
+
+		mock.Expect("Begin")
+		mock.Expect("SELECT \\* FROM `orders`").
+			Rows([]string{"id", "product_id", "variant_id", "warehouse_id", "quantity", "status", "version"},
+				[]driver.Value{int64(5), int64(3), int64(7), int64(2), int64(4), "pending", int64(1)})
+		mock.Expect("UPDATE `orders` SET")
+		mock.Expect("INSERT INTO `warehouse_stocks`").Result(1, 1)
+		mock.Expect("INSERT INTO `stock_movements`").Result(1, 1)
+		mock.Expect("UPDATE `product_variants` SET `qty`")
+		product := mock.Expect("UPDATE `products` SET")
+		mock.Expect("SELECT \\* FROM `coupon_redemptions`").Rows([]string{"id"})
+		mock.Expect("DELETE FROM `coupon_redemptions`")
+		mock.Expect("INSERT INTO `order_events`").Result(1, 1)
+		mock.Expect("Commit")
+
+		err := s.CancelOrder(ctx, 5, 1, "user", "", 0)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.Done())
+		assert.NotContains(t, product.Query, "deleted_at")
+	})
+
+	t.Run("order placed before warehouses", func(t *testing.T) {
+		native, db, mock := dbtest.New(t)
+		s := NewOrderStorage(native, db)
+
+		mock.Expect("Begin")
+		mock.Expect("SELECT \\* FROM `orders`").
+			Rows([]string{"id", "product_id", "variant_id", "warehouse_id", "quantity", "status", "version"},
+				[]driver.Value{int64(5), int64(3), int64(7), int64(0), int64(4), "pending", int64(1)})
+		mock.Expect("UPDATE `orders` SET")
+		variant := mock.Expect("UPDATE `product_variants` SET `qty`")
+		mock.Expect("UPDATE `products` SET")
+		mock.Expect("SELECT \\* FROM `coupon_redemptions`").Rows([]string{"id"})
+		mock.Expect("DELETE FROM `coupon_redemptions`")
+		mock.Expect("INSERT INTO `order_events`").Result(1, 1)
+		mock.Expect("Commit")
+
+		err := s.CancelOrder(ctx, 5, 1, "user", "", 0)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.Done())
+		assert.Equal(t, driver.Value(int64(4)), variant.Args[0])
+	})
+}
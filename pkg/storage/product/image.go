@@ -0,0 +1,203 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"kanggo/pkg/entity/schema"
+	"strings"
+)
+
+// InsertImage adds the image after the other images of the product.
+func (p *productStorage) InsertImage(ctx context.Context, data schema.ProductImage) (*schema.ProductImage, error) {
+	var position int64
+
+	tx := p.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return nil, err
+	}
+
+	if err := lockProduct(tx.WithContext(ctx), data.ProductId); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.WithContext(ctx).Model(&schema.ProductImage{}).Where("product_id = ?", data.ProductId).
+		Select("COALESCE(MAX(position) + 1, 0)").Scan(&position).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	data.Position = position
+
+	if err := tx.WithContext(ctx).Create(&data).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := touchProduct(tx.WithContext(ctx), data.ProductId); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+func (p *productStorage) GetImages(ctx context.Context, productIds []int64) ([]schema.ProductImage, error) {
+	images := []schema.ProductImage{}
+	if len(productIds) == 0 {
+		return images, nil
+	}
+
+	args := make([]interface{}, len(productIds))
+	for i, id := range productIds {
+		args[i] = id
+	}
+
+	qry := `SELECT id, created_at, updated_at, product_id, object_key, thumbnail_key, content_type,
+	size, width, height, position
+	FROM product_images WHERE product_id IN (?` + strings.Repeat(",?", len(productIds)-1) + `)
+	ORDER BY product_id, position, id`
+
+	rows, err := p.Native.QueryContext(ctx, qry, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		image := schema.ProductImage{}
+		if err := rows.Scan(&image.Id, &image.CreatedAt, &image.UpdatedAt, &image.ProductId,
+			&image.ObjectKey, &image.ThumbnailKey, &image.ContentType,
+			&image.Size, &image.Width, &image.Height, &image.Position); err != nil {
+			return nil, err
+		}
+
+		images = append(images, image)
+	}
+
+	return images, rows.Err()
+}
+
+func (p *productStorage) GetImageById(ctx context.Context, id int64) (*schema.ProductImage, error) {
+	image := schema.ProductImage{}
+	qry := `SELECT id, created_at, updated_at, product_id, object_key, thumbnail_key, content_type,
+	size, width, height, position
+	FROM product_images WHERE id = ?`
+
+	res := p.Native.QueryRowContext(ctx, qry, id)
+	if err := res.Scan(&image.Id, &image.CreatedAt, &image.UpdatedAt, &image.ProductId,
+		&image.ObjectKey, &image.ThumbnailKey, &image.ContentType,
+		&image.Size, &image.Width, &image.Height, &image.Position); err != nil {
+		return nil, err
+	}
+
+	return &image, nil
+}
+
+func (p *productStorage) DeleteImage(ctx context.Context, productId, id int64) error {
+	tx := p.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	result := tx.WithContext(ctx).Where("product_id = ?", productId).Delete(&schema.ProductImage{}, id)
+	if result.Error != nil {
+		tx.Rollback()
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return errors.New("data not found")
+	}
+
+	if err := touchProduct(tx.WithContext(ctx), productId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// ReorderImages puts the images of a product in the order of ids, which must
+// list each of its images once.
+func (p *productStorage) ReorderImages(ctx context.Context, productId int64, ids []int64) error {
+	var current []int64
+
+	tx := p.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	if err := lockProduct(tx.WithContext(ctx), productId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Model(&schema.ProductImage{}).Where("product_id = ?", productId).
+		Pluck("id", &current).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if !sameIds(current, ids) {
+		tx.Rollback()
+		return errors.New("image_ids must list every image of the product once")
+	}
+
+	for position, id := range ids {
+		if err := tx.WithContext(ctx).Model(&schema.ProductImage{}).Where("id = ?", id).
+			Update("position", position).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := touchProduct(tx.WithContext(ctx), productId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func sameIds(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	seen := map[int64]bool{}
+	for _, id := range a {
+		seen[id] = true
+	}
+
+	for _, id := range b {
+		if !seen[id] {
+			return false
+		}
+		delete(seen, id)
+	}
+
+	return true
+}
@@ -0,0 +1,291 @@
+package product
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"kanggo/pkg/entity/schema"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	// ImportRow is a row of a product import file. Columns are the product
+	// columns the file has; a product that exists only gets those.
+	ImportRow struct {
+		Line    int
+		Sku     string
+		Product schema.Product
+		Columns []string
+	}
+
+	ImportResult struct {
+		Line    int
+		Created bool
+		Err     error
+	}
+)
+
+func (r ImportRow) Has(column string) bool {
+	for _, c := range r.Columns {
+		if c == column {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FindSkus returns which of the skus belong to a variant already.
+func (p *productStorage) FindSkus(ctx context.Context, skus []string) ([]string, error) {
+	found := []string{}
+	if len(skus) == 0 {
+		return found, nil
+	}
+
+	args := []interface{}{}
+	for _, sku := range skus {
+		args = append(args, sku)
+	}
+
+	qry := `SELECT sku FROM product_variants WHERE sku IN (?` + strings.Repeat(", ?", len(skus)-1) + `)`
+
+	rows, err := p.Native.QueryContext(ctx, qry, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var sku string
+		if err := rows.Scan(&sku); err != nil {
+			return nil, err
+		}
+
+		found = append(found, sku)
+	}
+
+	return found, rows.Err()
+}
+
+// Import upserts a batch of rows by sku in one transaction. A row that fails
+// is rolled back to its savepoint and reported in its result, so it does
+// not take the rest of the batch with it.
+func (p *productStorage) Import(ctx context.Context, rows []ImportRow) ([]ImportResult, error) {
+	results := []ImportResult{}
+	now := time.Now()
+
+	tx := p.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return nil, err
+	}
+
+	for i, row := range rows {
+		savepoint := fmt.Sprintf("row_%d", i)
+		if err := tx.SavePoint(savepoint).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		created, err := importRow(tx.WithContext(ctx), row, now)
+		if err != nil {
+			if err := tx.RollbackTo(savepoint).Error; err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+		}
+
+		results = append(results, ImportResult{Line: row.Line, Created: created, Err: err})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// importRow creates a product for an unknown sku, with the sku as its only
+// variant. For a known sku the product gets the product columns of the row
+// and the variant its price, stock and weight, the weight going to the
+// product too when it has no other variants.
+func importRow(tx *gorm.DB, row ImportRow, now time.Time) (bool, error) {
+	var variant schema.ProductVariant
+	var variants int64
+
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("sku = ?", row.Sku).First(&variant).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if !row.Has("name") || !row.Has("price") {
+			return false, errors.New("name and price are required for a new product")
+		}
+
+		product := row.Product
+		if err := checkBarcode(tx, product); err != nil {
+			return false, err
+		}
+
+		if err := tx.Create(&product).Error; err != nil {
+			return false, err
+		}
+
+		return true, insertVariant(tx, schema.ProductVariant{
+			ProductId: int64(product.Id),
+			Sku:       row.Sku,
+			Options:   "{}",
+			Price:     product.Price,
+			Qty:       product.Qty,
+			Weight:    product.Weight,
+		})
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if err := lockProduct(tx, variant.ProductId); err != nil {
+		return false, err
+	}
+
+	if err := tx.Model(&schema.ProductVariant{}).Where("product_id = ?", variant.ProductId).
+		Count(&variants).Error; err != nil {
+		return false, err
+	}
+
+	columns := []string{}
+	values := map[string]interface{}{}
+	for _, column := range row.Columns {
+		switch column {
+		case "sku":
+		case "price":
+			values["price"] = row.Product.Price
+		case "qty":
+			values["qty"] = row.Product.Qty
+		case "weight":
+			values["weight"] = row.Product.Weight
+			if variants == 1 {
+				columns = append(columns, column)
+			}
+		default:
+			columns = append(columns, column)
+		}
+	}
+
+	if row.Has("barcode") {
+		if err := checkBarcode(tx, schema.Product{Base: schema.Base{Id: uint(variant.ProductId)}, Barcode: row.Product.Barcode}); err != nil {
+			return false, err
+		}
+	}
+
+	if len(columns) > 0 {
+		if err := tx.Model(&schema.Product{}).Where("id = ?", variant.ProductId).
+			Select(columns).Updates(row.Product).Error; err != nil {
+			return false, err
+		}
+	}
+
+	if row.Has("price") {
+		if err := recordPrice(tx, variant, row.Product.Price, now); err != nil {
+			return false, err
+		}
+	}
+
+	if len(values) > 0 {
+		if err := tx.Model(&schema.ProductVariant{}).Where("id = ?", variant.Id).Updates(values).Error; err != nil {
+			return false, err
+		}
+	}
+
+	if row.Has("price") {
+		if _, err := applyPrice(tx, int64(variant.Id), now); err != nil {
+			return false, err
+		}
+	}
+
+	if row.Has("qty") {
+		if err := placeStock(tx, variant, row.Product.Qty); err != nil {
+			return false, err
+		}
+	}
+
+	return false, syncProduct(tx, variant.ProductId)
+}
+
+// Export calls each for every variant of the products outside the trash,
+// product by product. The variant carries its regular price, so a running
+// sale does not end up as the price when the export is imported again.
+func (p *productStorage) Export(ctx context.Context, each func(product schema.Product, variant schema.ProductVariant) error) error {
+	now := time.Now()
+	qry := `SELECT p.id, p.name, COALESCE(p.description, ""), p.length, p.width, p.height, p.tax_category,
+	p.unit, COALESCE(p.brand, ""), COALESCE(p.barcode, ""), p.min_order_qty, p.status,
+	v.id, v.sku, ` + regularPrice + `, v.qty, v.weight
+	FROM products p JOIN product_variants v ON v.product_id = p.id
+	WHERE p.deleted_at IS NULL ORDER BY p.id, v.id`
+
+	rows, err := p.Native.QueryContext(ctx, qry, now, now)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		product := schema.Product{}
+		variant := schema.ProductVariant{}
+		if err := rows.Scan(&product.Id, &product.Name, &product.Description, &product.Length, &product.Width,
+			&product.Height, &product.TaxCategory, &product.Unit, &product.Brand, &product.Barcode,
+			&product.MinOrderQty, &product.Status, &variant.Id, &variant.Sku, &variant.Price, &variant.Qty,
+			&variant.Weight); err != nil {
+			return err
+		}
+		variant.ProductId = int64(product.Id)
+
+		if err := each(product, variant); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (p *productStorage) InsertImportJob(ctx context.Context, data schema.ProductImportJob) (*schema.ProductImportJob, error) {
+	if err := p.Gorm.WithContext(ctx).Create(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+// UpdateImportJob saves the progress of a job, zero counts included.
+func (p *productStorage) UpdateImportJob(ctx context.Context, data schema.ProductImportJob) error {
+	return p.Gorm.WithContext(ctx).Model(&schema.ProductImportJob{}).Where("id = ?", data.Id).
+		Select("status", "created", "updated", "failed", "errors", "message", "finished_at").
+		Updates(data).Error
+}
+
+func (p *productStorage) GetImportJob(ctx context.Context, id int64) (*schema.ProductImportJob, error) {
+	var finishedAt sql.NullTime
+	job := schema.ProductImportJob{}
+	qry := `SELECT id, created_at, updated_at, filename, dry_run, status, total, created, updated, failed,
+	COALESCE(errors, ""), COALESCE(message, ""), created_by, finished_at
+	FROM product_import_jobs WHERE id = ?`
+
+	res := p.Native.QueryRowContext(ctx, qry, id)
+	if err := res.Scan(&job.Id, &job.CreatedAt, &job.UpdatedAt, &job.Filename, &job.DryRun, &job.Status,
+		&job.Total, &job.Created, &job.Updated, &job.Failed, &job.Errors, &job.Message, &job.CreatedBy,
+		&finishedAt); err != nil {
+		return nil, err
+	}
+	if finishedAt.Valid {
+		job.FinishedAt = &finishedAt.Time
+	}
+
+	return &job, nil
+}
@@ -0,0 +1,363 @@
+package product
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"kanggo/pkg/entity/schema"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// effectivePrice is the price of the variant v at a time given twice: the
+// sale price running then, or else the regular price in effect then. A
+// variant without a price history keeps its own price.
+const effectivePrice = `COALESCE((SELECT pp.price FROM product_prices pp
+	WHERE pp.variant_id = v.id AND pp.effective_from <= ? AND (pp.effective_to IS NULL OR pp.effective_to > ?)
+	ORDER BY pp.sale DESC, pp.effective_from DESC, pp.id DESC LIMIT 1), v.price)`
+
+// regularPrice is the regular price of the variant v at a time given twice,
+// leaving sales out.
+const regularPrice = `COALESCE((SELECT pp.price FROM product_prices pp
+	WHERE pp.variant_id = v.id AND pp.sale = FALSE AND pp.effective_from <= ? AND (pp.effective_to IS NULL OR pp.effective_to > ?)
+	ORDER BY pp.effective_from DESC, pp.id DESC LIMIT 1), v.price)`
+
+// GetPrices returns the price history of the product's variants, the
+// latest first, scheduled prices included.
+func (p *productStorage) GetPrices(ctx context.Context, productId int64) ([]schema.ProductPrice, error) {
+	qry := `SELECT id, created_at, updated_at, product_id, variant_id, price, sale, effective_from, effective_to
+	FROM product_prices WHERE product_id = ? ORDER BY effective_from DESC, id DESC`
+
+	rows, err := p.Native.QueryContext(ctx, qry, productId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prices := []schema.ProductPrice{}
+	for rows.Next() {
+		var to sql.NullTime
+		price := schema.ProductPrice{}
+		if err := rows.Scan(&price.Id, &price.CreatedAt, &price.UpdatedAt, &price.ProductId, &price.VariantId,
+			&price.Price, &price.Sale, &price.EffectiveFrom, &to); err != nil {
+			return nil, err
+		}
+		if to.Valid {
+			price.EffectiveTo = &to.Time
+		}
+
+		prices = append(prices, price)
+	}
+
+	return prices, rows.Err()
+}
+
+// InsertPrice adds a price to the history of a variant. A price that is
+// already due is applied right away; a later one is left to ApplyPrices.
+func (p *productStorage) InsertPrice(ctx context.Context, data schema.ProductPrice) (*schema.ProductPrice, error) {
+	var variant schema.ProductVariant
+	var sales int64
+
+	tx := p.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return nil, err
+	}
+
+	if err := lockProduct(tx.WithContext(ctx), data.ProductId); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.WithContext(ctx).Where("id = ? AND product_id = ?", data.VariantId, data.ProductId).
+		First(&variant).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("variant not found")
+		}
+		return nil, err
+	}
+
+	if err := ensureBaseline(tx.WithContext(ctx), variant); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if data.Sale {
+		if err := tx.WithContext(ctx).Model(&schema.ProductPrice{}).
+			Where("variant_id = ? AND sale = ? AND effective_from < ? AND effective_to > ?",
+				data.VariantId, true, data.EffectiveTo, data.EffectiveFrom).
+			Count(&sales).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		if sales > 0 {
+			tx.Rollback()
+			return nil, errors.New("sale price overlaps another sale")
+		}
+	}
+
+	if err := insertPrice(tx.WithContext(ctx), &data); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	changed, err := applyPrice(tx.WithContext(ctx), data.VariantId, time.Now())
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if changed {
+		if err := syncProduct(tx.WithContext(ctx), data.ProductId); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+// DeletePrice cancels a price of the product that has not taken effect yet.
+// The regular price before a cancelled regular price holds on in its place.
+func (p *productStorage) DeletePrice(ctx context.Context, productId, id int64) error {
+	var price schema.ProductPrice
+
+	tx := p.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	if err := lockProduct(tx.WithContext(ctx), productId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Where("id = ? AND product_id = ?", id, productId).First(&price).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("data not found")
+		}
+		return err
+	}
+
+	if !price.EffectiveFrom.After(time.Now()) {
+		tx.Rollback()
+		return errors.New("only scheduled prices can be cancelled")
+	}
+
+	if !price.Sale {
+		if err := tx.WithContext(ctx).Model(&schema.ProductPrice{}).
+			Where("variant_id = ? AND sale = ? AND effective_to = ?", price.VariantId, false, price.EffectiveFrom).
+			Update("effective_to", price.EffectiveTo).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := tx.WithContext(ctx).Delete(&price).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// EffectivePrice returns the price of the variant at the given time, which
+// is what an order placed then pays, even before ApplyPrices caught up.
+func (p *productStorage) EffectivePrice(ctx context.Context, variantId int64, at time.Time) (float64, error) {
+	var price float64
+
+	qry := `SELECT ` + effectivePrice + ` FROM product_variants v WHERE v.id = ?`
+	if err := p.Native.QueryRowContext(ctx, qry, at, at, variantId).Scan(&price); err != nil {
+		return 0, err
+	}
+
+	return price, nil
+}
+
+// ApplyPrices sets every variant whose price changed by the given time,
+// because a scheduled price or a sale started or a sale ended, to its
+// effective price, and returns how many variants were repriced.
+func (p *productStorage) ApplyPrices(ctx context.Context, at time.Time) (int64, error) {
+	var applied int64
+	variants := []schema.ProductVariant{}
+
+	// products in the trash are repriced once they are restored
+	qry := `SELECT v.id, v.product_id FROM product_variants v
+	JOIN products p ON p.id = v.product_id AND p.deleted_at IS NULL
+	WHERE v.price <> ` + effectivePrice
+
+	rows, err := p.Native.QueryContext(ctx, qry, at, at)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		variant := schema.ProductVariant{}
+		if err := rows.Scan(&variant.Id, &variant.ProductId); err != nil {
+			return 0, err
+		}
+
+		variants = append(variants, variant)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	// each variant is repriced under the lock of its product, so a change
+	// made in the meantime is not overwritten
+	for _, variant := range variants {
+		changed, err := p.applyVariantPrice(ctx, variant.ProductId, int64(variant.Id), at)
+		if err != nil {
+			return applied, err
+		}
+		if changed {
+			applied++
+		}
+	}
+
+	return applied, nil
+}
+
+func (p *productStorage) applyVariantPrice(ctx context.Context, productId, variantId int64, at time.Time) (bool, error) {
+	tx := p.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return false, err
+	}
+
+	if err := lockProduct(tx.WithContext(ctx), productId); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	changed, err := applyPrice(tx.WithContext(ctx), variantId, at)
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if changed {
+		if err := syncProduct(tx.WithContext(ctx), productId); err != nil {
+			tx.Rollback()
+			return false, err
+		}
+	}
+
+	return changed, tx.Commit().Error
+}
+
+// applyPrice sets the variant to its effective price at the given time and
+// reports whether that changed its price.
+func applyPrice(tx *gorm.DB, variantId int64, at time.Time) (bool, error) {
+	result := tx.Exec(`UPDATE product_variants v SET v.price = `+effectivePrice+` WHERE v.id = ?`, at, at, variantId)
+	return result.RowsAffected > 0, result.Error
+}
+
+// recordPrice adds a regular price set directly on a variant to its
+// history, unless it is the regular price already in effect. A running
+// sale keeps its price until it ends, so the caller applies the effective
+// price afterwards.
+func recordPrice(tx *gorm.DB, variant schema.ProductVariant, price float64, at time.Time) error {
+	var current schema.ProductPrice
+
+	if err := ensureBaseline(tx, variant); err != nil {
+		return err
+	}
+
+	err := tx.Where("variant_id = ? AND sale = ? AND effective_from <= ? AND (effective_to IS NULL OR effective_to > ?)",
+		variant.Id, false, at, at).Order("effective_from DESC, id DESC").First(&current).Error
+	if err == nil && current.Price == price {
+		return nil
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	return insertPrice(tx, &schema.ProductPrice{
+		ProductId:     variant.ProductId,
+		VariantId:     int64(variant.Id),
+		Price:         price,
+		EffectiveFrom: at,
+	})
+}
+
+// ensureBaseline starts the history of a variant created before prices
+// were recorded with its current price.
+func ensureBaseline(tx *gorm.DB, variant schema.ProductVariant) error {
+	var regular int64
+	if err := tx.Model(&schema.ProductPrice{}).Where("variant_id = ? AND sale = ?", variant.Id, false).
+		Count(&regular).Error; err != nil {
+		return err
+	}
+
+	if regular > 0 {
+		return nil
+	}
+
+	return tx.Create(&schema.ProductPrice{
+		ProductId:     variant.ProductId,
+		VariantId:     int64(variant.Id),
+		Price:         variant.Price,
+		EffectiveFrom: variant.CreatedAt,
+	}).Error
+}
+
+// insertPrice adds the price to the history. A regular price ends the one
+// in effect when it starts and runs until the next one, so the regular
+// prices of a variant never overlap.
+func insertPrice(tx *gorm.DB, data *schema.ProductPrice) error {
+	if !data.Sale {
+		var previous, next schema.ProductPrice
+
+		err := tx.Where("variant_id = ? AND sale = ? AND effective_from <= ?", data.VariantId, false, data.EffectiveFrom).
+			Order("effective_from DESC, id DESC").First(&previous).Error
+		switch {
+		case err == nil:
+			if previous.EffectiveFrom.Equal(data.EffectiveFrom) {
+				return errors.New("a price is already scheduled at that time")
+			}
+
+			data.EffectiveTo = previous.EffectiveTo
+			if err := tx.Model(&previous).Update("effective_to", data.EffectiveFrom).Error; err != nil {
+				return err
+			}
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			err := tx.Where("variant_id = ? AND sale = ? AND effective_from > ?", data.VariantId, false, data.EffectiveFrom).
+				Order("effective_from, id").First(&next).Error
+			if err == nil {
+				data.EffectiveTo = &next.EffectiveFrom
+			} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+		default:
+			return err
+		}
+	}
+
+	return tx.Create(data).Error
+}
@@ -4,7 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"kanggo/pkg/entity/model"
 	"kanggo/pkg/entity/schema"
+	"kanggo/pkg/inventory"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -13,12 +17,45 @@ import (
 
 type (
 	ProductStorage interface {
-		Insert(ctx context.Context, data schema.Product) error
+		Insert(ctx context.Context, data schema.Product, sku string) error
 		Update(ctx context.Context, data schema.Product) error
-		GetAll(ctx context.Context) ([]schema.Product, error)
+		Patch(ctx context.Context, id int64, version uint, values map[string]interface{}) error
+		GetAll(ctx context.Context, query model.ListQuery) ([]schema.Product, int64, error)
 		GetById(ctx context.Context, id int64) (*schema.Product, error)
-		Delete(ctx context.Context, id int64) error
-		CheckQty(ctx context.Context, productId, amount int64) (bool, error)
+		Delete(ctx context.Context, id int64, version uint) error
+		GetTrash(ctx context.Context, query model.ListQuery) ([]schema.Product, int64, error)
+		Restore(ctx context.Context, id int64) error
+		Purge(ctx context.Context, before time.Time) (int64, []schema.ProductImage, error)
+		CheckQty(ctx context.Context, variantId, amount int64) (bool, error)
+
+		GetOptions(ctx context.Context, productId int64) ([]schema.ProductOption, error)
+		SetOptions(ctx context.Context, productId int64, options []schema.ProductOption) error
+		GetVariants(ctx context.Context, productId int64) ([]schema.ProductVariant, error)
+		GetVariantById(ctx context.Context, id int64) (*schema.ProductVariant, error)
+		InsertVariant(ctx context.Context, data schema.ProductVariant) error
+		UpdateVariant(ctx context.Context, data schema.ProductVariant) error
+		DeleteVariant(ctx context.Context, productId, id int64) error
+
+		InsertImage(ctx context.Context, data schema.ProductImage) (*schema.ProductImage, error)
+		GetImages(ctx context.Context, productIds []int64) ([]schema.ProductImage, error)
+		GetImageById(ctx context.Context, id int64) (*schema.ProductImage, error)
+		DeleteImage(ctx context.Context, productId, id int64) error
+		ReorderImages(ctx context.Context, productId int64, ids []int64) error
+
+		GetPrices(ctx context.Context, productId int64) ([]schema.ProductPrice, error)
+		InsertPrice(ctx context.Context, data schema.ProductPrice) (*schema.ProductPrice, error)
+		DeletePrice(ctx context.Context, productId, id int64) error
+		EffectivePrice(ctx context.Context, variantId int64, at time.Time) (float64, error)
+		ApplyPrices(ctx context.Context, at time.Time) (int64, error)
+
+		FindSkus(ctx context.Context, skus []string) ([]string, error)
+		Import(ctx context.Context, rows []ImportRow) ([]ImportResult, error)
+		Export(ctx context.Context, each func(product schema.Product, variant schema.ProductVariant) error) error
+		InsertImportJob(ctx context.Context, data schema.ProductImportJob) (*schema.ProductImportJob, error)
+		UpdateImportJob(ctx context.Context, data schema.ProductImportJob) error
+		GetImportJob(ctx context.Context, id int64) (*schema.ProductImportJob, error)
+
+		GetStock(ctx context.Context, productIds []int64) ([]inventory.Stock, error)
 	}
 
 	productStorage struct {
@@ -34,60 +71,301 @@ func NewProductStorage(native *sql.DB, gorm *gorm.DB) ProductStorage {
 	}
 }
 
-func (p *productStorage) Insert(ctx context.Context, data schema.Product) error {
-	if err := p.Gorm.WithContext(ctx).Create(&data).Error; err != nil {
+// Insert creates the product with its default variant, which takes the
+// price, stock and weight of the product. An empty sku is generated from the
+// product id.
+func (p *productStorage) Insert(ctx context.Context, data schema.Product, sku string) error {
+	tx := p.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
 		return err
 	}
 
-	return nil
+	if err := checkBarcode(tx.WithContext(ctx), data); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Create(&data).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if sku == "" {
+		sku = defaultSku(int64(data.Id))
+	}
+
+	if err := insertVariant(tx.WithContext(ctx), schema.ProductVariant{
+		ProductId: int64(data.Id),
+		Sku:       sku,
+		Options:   "{}",
+		Price:     data.Price,
+		Qty:       data.Qty,
+		Weight:    data.Weight,
+	}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
 }
 
+// Update changes the product. A product with a single variant passes its
+// price, stock and weight on to that variant; with more variants those are
+// managed per variant and the product keeps the totals of its variants.
 func (p *productStorage) Update(ctx context.Context, data schema.Product) error {
-	result := p.Gorm.WithContext(ctx).Updates(data)
-	if result.Error != nil {
-		return result.Error
+	// stock, dimensions and the optional text fields may be cleared, so they
+	// are written even when zero
+	values := map[string]interface{}{
+		"name":          data.Name,
+		"description":   data.Description,
+		"price":         data.Price,
+		"qty":           data.Qty,
+		"weight":        data.Weight,
+		"length":        data.Length,
+		"width":         data.Width,
+		"height":        data.Height,
+		"unit":          data.Unit,
+		"brand":         data.Brand,
+		"barcode":       data.Barcode,
+		"min_order_qty": data.MinOrderQty,
+		"status":        data.Status,
+		"reorder_level": data.ReorderLevel,
+	}
+	if data.TaxCategory != "" {
+		values["tax_category"] = data.TaxCategory
 	}
 
-	if result.RowsAffected == 0 {
-		return errors.New("data not found")
+	return p.Patch(ctx, int64(data.Id), data.Version, values)
+}
+
+// Patch sets the given columns of the product, zero values included, and
+// leaves the others alone. Like Update it passes price, stock and weight on
+// to the only variant of a product, recording a new price in its history. A
+// version other than 0 must be the current version of the product.
+func (p *productStorage) Patch(ctx context.Context, id int64, version uint, values map[string]interface{}) error {
+	var variants int64
+
+	tx := p.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return err
 	}
 
-	return nil
+	if err := lockVersion(tx.WithContext(ctx), id, version); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if barcode, ok := values["barcode"].(string); ok {
+		if err := checkBarcode(tx.WithContext(ctx), schema.Product{Base: schema.Base{Id: uint(id)}, Barcode: barcode}); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	// the product is locked and known to exist, so no affected rows only
+	// means the values did not change
+	if err := tx.WithContext(ctx).Model(&schema.Product{}).Where("id = ?", id).Updates(values).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Model(&schema.ProductVariant{}).Where("product_id = ?", id).
+		Count(&variants).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	variant := map[string]interface{}{}
+	for _, column := range []string{"price", "qty", "weight"} {
+		if value, ok := values[column]; ok {
+			variant[column] = value
+		}
+	}
+
+	if variants == 1 && len(variant) > 0 {
+		var current schema.ProductVariant
+		now := time.Now()
+
+		if err := tx.WithContext(ctx).Where("product_id = ?", id).First(&current).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		price, priced := variant["price"].(float64)
+		if priced {
+			if err := recordPrice(tx.WithContext(ctx), current, price, now); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+
+		if err := tx.WithContext(ctx).Model(&schema.ProductVariant{}).Where("product_id = ?", id).
+			Updates(variant).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if priced {
+			if _, err := applyPrice(tx.WithContext(ctx), int64(current.Id), now); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+
+		if _, ok := variant["qty"]; ok {
+			var qty int64
+			if err := tx.WithContext(ctx).Model(&schema.ProductVariant{}).Where("id = ?", current.Id).
+				Select("qty").Scan(&qty).Error; err != nil {
+				tx.Rollback()
+				return err
+			}
+
+			if err := placeStock(tx.WithContext(ctx), current, qty); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+	}
+
+	if err := syncProduct(tx.WithContext(ctx), id); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
 }
 
-func (p *productStorage) GetAll(ctx context.Context) ([]schema.Product, error) {
-	qry := `SELECT * FROM products`
+// productSorts maps the sort keys of the product list to columns.
+var productSorts = map[string]string{
+	"id":         "id",
+	"name":       "name",
+	"price":      "price",
+	"qty":        "qty",
+	"created_at": "created_at",
+	"deleted_at": "deleted_at",
+}
 
-	rows, err := p.Native.QueryContext(ctx, qry)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, err
+// GetAll returns a page of products matching the query and the total number
+// of matching products. Products in the trash are left out.
+func (p *productStorage) GetAll(ctx context.Context, query model.ListQuery) ([]schema.Product, int64, error) {
+	return p.listProducts(ctx, query, false)
+}
+
+// GetTrash returns a page of the deleted products matching the query.
+func (p *productStorage) GetTrash(ctx context.Context, query model.ListQuery) ([]schema.Product, int64, error) {
+	return p.listProducts(ctx, query, true)
+}
+
+func (p *productStorage) listProducts(ctx context.Context, query model.ListQuery, trashed bool) ([]schema.Product, int64, error) {
+	var total int64
+	where := []string{"deleted_at IS NULL"}
+	if trashed {
+		where = []string{"deleted_at IS NOT NULL"}
+	}
+	args := []interface{}{}
+
+	if query.From != nil {
+		where = append(where, "created_at >= ?")
+		args = append(args, *query.From)
+	}
+	if query.To != nil {
+		where = append(where, "created_at < ?")
+		args = append(args, *query.To)
+	}
+	if query.MinPrice != nil {
+		where = append(where, "price >= ?")
+		args = append(args, *query.MinPrice)
+	}
+	if query.MaxPrice != nil {
+		where = append(where, "price <= ?")
+		args = append(args, *query.MaxPrice)
+	}
+	if query.Status != "" {
+		where = append(where, "status = ?")
+		args = append(args, query.Status)
+	}
+
+	if len(query.CategoryIds) > 0 {
+		where = append(where, "id IN (SELECT product_id FROM product_categories WHERE category_id IN (?"+
+			strings.Repeat(", ?", len(query.CategoryIds)-1)+"))")
+		for _, id := range query.CategoryIds {
+			args = append(args, id)
 		}
-		return nil, err
 	}
 
+	if err := p.Native.QueryRowContext(ctx, `SELECT COUNT(*) FROM products WHERE `+strings.Join(where, " AND "), args...).
+		Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	column, ok := productSorts[query.Sort]
+	if !ok {
+		column = "id"
+	}
+	direction := "ASC"
+	if query.Desc {
+		direction = "DESC"
+	}
+
+	if query.Cursor > 0 {
+		if query.Desc {
+			where = append(where, "id < ?")
+		} else {
+			where = append(where, "id > ?")
+		}
+		args = append(args, query.Cursor)
+	}
+
+	qry := `SELECT id, created_at, updated_at, name, COALESCE(description,""), price, qty, weight, length, width, height, tax_category,
+	unit, COALESCE(brand,""), COALESCE(barcode,""), min_order_qty, status, version, reorder_level, rating_count, rating_total, deleted_at
+	FROM products
+	WHERE ` + strings.Join(where, " AND ") + `
+	ORDER BY ` + column + ` ` + direction + `, id ` + direction + `
+	LIMIT ? OFFSET ?`
+
+	rows, err := p.Native.QueryContext(ctx, qry, append(args, query.Size, query.Offset())...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
 	products := []schema.Product{}
 	for rows.Next() {
 		var res schema.Product
-		if err := rows.Scan(&res.Id, &res.CreatedAt, &res.UpdatedAt, &res.Name, &res.Price, &res.Qty); err != nil {
-			return nil, err
+		if err := rows.Scan(&res.Id, &res.CreatedAt, &res.UpdatedAt, &res.Name, &res.Description, &res.Price, &res.Qty,
+			&res.Weight, &res.Length, &res.Width, &res.Height, &res.TaxCategory,
+			&res.Unit, &res.Brand, &res.Barcode, &res.MinOrderQty, &res.Status, &res.Version, &res.ReorderLevel, &res.RatingCount, &res.RatingTotal, &res.DeletedAt); err != nil {
+			return nil, 0, err
 		}
 		products = append(products, res)
 	}
 
-	if len(products) == 0 {
-		return nil, errors.New("data not found")
-	}
-	return products, nil
+	return products, total, nil
 }
 
 func (p *productStorage) GetById(ctx context.Context, id int64) (*schema.Product, error) {
 	product := schema.Product{}
-	qry := `SELECT * FROM products WHERE id = ?`
+	qry := `SELECT id, created_at, updated_at, name, COALESCE(description,""), price, qty, weight, length, width, height, tax_category,
+	unit, COALESCE(brand,""), COALESCE(barcode,""), min_order_qty, status, version, reorder_level, rating_count, rating_total
+	FROM products WHERE id = ? AND deleted_at IS NULL`
 
 	res := p.Native.QueryRowContext(ctx, qry, id)
 	if err := res.Scan(&product.Id, &product.CreatedAt, &product.UpdatedAt,
-		&product.Name, &product.Price, &product.Qty); err != nil {
+		&product.Name, &product.Description, &product.Price, &product.Qty,
+		&product.Weight, &product.Length, &product.Width, &product.Height, &product.TaxCategory,
+		&product.Unit, &product.Brand, &product.Barcode, &product.MinOrderQty, &product.Status, &product.Version, &product.ReorderLevel, &product.RatingCount, &product.RatingTotal); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, err
 		}
@@ -97,24 +375,40 @@ func (p *productStorage) GetById(ctx context.Context, id int64) (*schema.Product
 	return &product, nil
 }
 
-func (p *productStorage) Delete(ctx context.Context, id int64) error {
-	result := p.Gorm.WithContext(ctx).Delete(schema.Product{}, id)
-	if result.Error != nil {
-		return result.Error
+// Delete moves the product to the trash. A version other than 0 must be the
+// current version of the product.
+func (p *productStorage) Delete(ctx context.Context, id int64, version uint) error {
+	tx := p.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return err
 	}
 
-	if result.RowsAffected == 0 {
-		return errors.New("data not found")
+	if err := lockVersion(tx.WithContext(ctx), id, version); err != nil {
+		tx.Rollback()
+		return err
 	}
 
-	return nil
+	// the product goes to the trash with its variants, options and images,
+	// so it can be restored as it was
+	if err := tx.WithContext(ctx).Delete(&schema.Product{}, id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
 }
 
-func (o *productStorage) CheckQty(ctx context.Context, productId, amount int64) (bool, error) {
+func (o *productStorage) CheckQty(ctx context.Context, variantId, amount int64) (bool, error) {
 	var id int64
-	qry := `SELECT id FROM products WHERE id = ? AND qty > ?`
+	qry := `SELECT id FROM product_variants WHERE id = ? AND qty >= ?`
 
-	res := o.Native.QueryRowContext(ctx, qry, productId, amount)
+	res := o.Native.QueryRowContext(ctx, qry, variantId, amount)
 	if err := res.Scan(&id); err != nil {
 		if err == sql.ErrNoRows {
 			return false, errors.New("not enough product quantity")
@@ -124,3 +418,24 @@ func (o *productStorage) CheckQty(ctx context.Context, productId, amount int64)
 
 	return true, nil
 }
+
+// checkBarcode makes sure no other product carries the barcode of data. A
+// product without a barcode is always accepted.
+func checkBarcode(tx *gorm.DB, data schema.Product) error {
+	var products int64
+
+	if data.Barcode == "" {
+		return nil
+	}
+
+	if err := tx.Model(&schema.Product{}).Where("barcode = ? AND id <> ?", data.Barcode, data.Id).
+		Count(&products).Error; err != nil {
+		return err
+	}
+
+	if products > 0 {
+		return errors.New("barcode already exists")
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package product
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"kanggo/pkg/dbtest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckQty(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("exactly the remaining qty", func(t *testing.T) {
+		native, db, mock := dbtest.New(t)
+		s := NewProductStorage(native, db)
+
+		check := mock.Expect("SELECT id FROM product_variants WHERE id = \\? AND qty >= \\?").
+			Rows([]string{"id"}, []driver.Value{int64(3)})
+
+		ok, err := s.CheckQty(ctx, 3, 5)
+
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, []driver.Value{int64(3), int64(5)}, check.Args)
+		assert.NoError(t, mock.Done())
+	})
+
+	t.Run("more than the remaining qty", func(t *testing.T) {
+		native, db, mock := dbtest.New(t)
+		s := NewProductStorage(native, db)
+
+		mock.Expect("SELECT id FROM product_variants WHERE id = \\? AND qty >= \\?").Rows([]string{"id"})
+
+		ok, err := s.CheckQty(ctx, 3, 6)
+
+		assert.EqualError(t, err, "not enough product quantity")
+		assert.False(t, ok)
+		assert.NoError(t, mock.Done())
+	})
+}
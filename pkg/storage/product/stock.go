@@ -0,0 +1,117 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"kanggo/pkg/entity/schema"
+	"kanggo/pkg/inventory"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetStock returns the warehouse stock of the variants of the products,
+// default warehouse first.
+func (p *productStorage) GetStock(ctx context.Context, productIds []int64) ([]inventory.Stock, error) {
+	stocks := []inventory.Stock{}
+	if len(productIds) == 0 {
+		return stocks, nil
+	}
+
+	args := []interface{}{}
+	for _, id := range productIds {
+		args = append(args, id)
+	}
+
+	qry := `SELECT s.product_id, s.variant_id, w.id, w.code, w.name, w.province, w.city, w.is_default, w.active, s.qty
+	FROM warehouse_stocks s JOIN warehouses w ON w.id = s.warehouse_id
+	WHERE s.product_id IN (?` + strings.Repeat(", ?", len(productIds)-1) + `)
+	ORDER BY w.is_default DESC, w.id, s.variant_id`
+
+	rows, err := p.Native.QueryContext(ctx, qry, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var stock inventory.Stock
+		if err := rows.Scan(&stock.ProductId, &stock.VariantId, &stock.WarehouseId, &stock.Code, &stock.Name,
+			&stock.Province, &stock.City, &stock.Default, &stock.Active, &stock.Qty); err != nil {
+			return nil, err
+		}
+
+		stocks = append(stocks, stock)
+	}
+
+	return stocks, rows.Err()
+}
+
+// placeStock makes qty the stock of a variant set through the product
+// endpoints once warehouses exist, by putting the difference into the
+// default warehouse. Stock in the other warehouses stays where it is, so qty
+// cannot go below it.
+func placeStock(tx *gorm.DB, variant schema.ProductVariant, qty int64) error {
+	var warehouse schema.Warehouse
+	var stocks []schema.WarehouseStock
+	var current *schema.WarehouseStock
+	var others int64
+
+	err := tx.Where("is_default = ?", true).First(&warehouse).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("variant_id = ?", variant.Id).
+		Find(&stocks).Error; err != nil {
+		return err
+	}
+
+	for i := range stocks {
+		if stocks[i].WarehouseId == int64(warehouse.Id) {
+			current = &stocks[i]
+			continue
+		}
+		others += stocks[i].Qty
+	}
+
+	if qty < others {
+		return errors.New("qty is below the stock held in other warehouses")
+	}
+
+	change := qty - others
+	if current != nil {
+		change -= current.Qty
+	}
+	if change == 0 {
+		return nil
+	}
+
+	if current == nil {
+		err = tx.Create(&schema.WarehouseStock{
+			WarehouseId: int64(warehouse.Id),
+			VariantId:   int64(variant.Id),
+			ProductId:   variant.ProductId,
+			Qty:         change,
+		}).Error
+	} else {
+		err = tx.Model(&schema.WarehouseStock{}).Where("id = ?", current.Id).
+			Update("qty", gorm.Expr("qty + ?", change)).Error
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.Create(&schema.StockMovement{
+		WarehouseId: int64(warehouse.Id),
+		VariantId:   int64(variant.Id),
+		ProductId:   variant.ProductId,
+		Qty:         change,
+		Type:        schema.MovementAdjustment,
+		Note:        "set on the product",
+	}).Error
+}
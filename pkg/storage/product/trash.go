@@ -0,0 +1,116 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"kanggo/pkg/entity/schema"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Restore takes the product out of the trash. Its barcode may have been given
+// to another product in the meantime.
+func (p *productStorage) Restore(ctx context.Context, id int64) error {
+	var product schema.Product
+
+	tx := p.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("deleted_at IS NOT NULL").First(&product, id).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("data not found")
+		}
+		return err
+	}
+
+	if err := checkBarcode(tx.WithContext(ctx), product); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Unscoped().Model(&schema.Product{}).Where("id = ?", id).
+		Update("deleted_at", nil).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := syncProduct(tx.WithContext(ctx), id); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// Purge permanently deletes the products trashed before the given time,
+// together with their variants, options, images, categories and prices, and
+// returns how many went and their images, whose files are left to the caller.
+// A product that was ordered stays in the trash for the order history, and so
+// does one still awaited on a purchase order, which receiving would restock.
+// The stock alerts of a purged product go with it, while its stock movements
+// stay as the history of the warehouses they moved through.
+func (p *productStorage) Purge(ctx context.Context, before time.Time) (int64, []schema.ProductImage, error) {
+	var ids []int64
+	images := []schema.ProductImage{}
+
+	tx := p.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return 0, nil, err
+	}
+
+	if err := tx.WithContext(ctx).Unscoped().Model(&schema.Product{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("deleted_at < ?", before).
+		Where("NOT EXISTS (SELECT 1 FROM orders WHERE orders.product_id = products.id)").
+		Where(`NOT EXISTS (SELECT 1 FROM purchase_order_lines l JOIN purchase_orders po ON po.id = l.purchase_order_id
+			WHERE l.product_id = products.id AND po.status <> ?)`, schema.PurchaseReceived).
+		Pluck("id", &ids).Error; err != nil {
+		tx.Rollback()
+		return 0, nil, err
+	}
+
+	if len(ids) == 0 {
+		return 0, images, tx.Commit().Error
+	}
+
+	if err := tx.WithContext(ctx).Where("product_id IN ?", ids).Find(&images).Error; err != nil {
+		tx.Rollback()
+		return 0, nil, err
+	}
+
+	for _, table := range []interface{}{&schema.ProductVariant{}, &schema.ProductOption{},
+		&schema.ProductImage{}, &schema.ProductCategory{}, &schema.ProductPrice{}, &schema.WarehouseStock{}, &schema.StockAlert{}} {
+		if err := tx.WithContext(ctx).Where("product_id IN ?", ids).Delete(table).Error; err != nil {
+			tx.Rollback()
+			return 0, nil, err
+		}
+	}
+
+	if err := tx.WithContext(ctx).Unscoped().Delete(&schema.Product{}, ids).Error; err != nil {
+		tx.Rollback()
+		return 0, nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return 0, nil, err
+	}
+
+	return int64(len(ids)), images, nil
+}
@@ -0,0 +1,43 @@
+package product
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"kanggo/pkg/dbtest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPurge(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("keeps products awaited on purchase orders", func(t *testing.T) {
+		native, db, mock := dbtest.New(t)
+		s := NewProductStorage(native, db)
+
+		mock.Expect("Begin")
+		pick := mock.Expect("(?s)SELECT `id` FROM `products` .*purchase_order_lines.*po.status <> \\?.* FOR UPDATE").
+			Rows([]string{"id"}, []driver.Value{int64(4)})
+		mock.Expect("SELECT \\* FROM `product_images`").Rows([]string{"id"})
+		mock.Expect("DELETE FROM `product_variants`")
+		mock.Expect("DELETE FROM `product_options`")
+		mock.Expect("DELETE FROM `product_images`")
+		mock.Expect("DELETE FROM `product_categories`")
+		mock.Expect("DELETE FROM `product_prices`")
+		mock.Expect("DELETE FROM `warehouse_stocks`")
+		mock.Expect("DELETE FROM `stock_alerts`")
+		mock.Expect("DELETE FROM `products`")
+		mock.Expect("Commit")
+
+		before := time.Now()
+		purged, _, err := s.Purge(ctx, before)
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, 1, purged)
+		assert.NoError(t, mock.Done())
+		assert.Equal(t, []driver.Value{before, "received"}, pick.Args)
+	})
+}
@@ -0,0 +1,451 @@
+package product
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"kanggo/pkg/entity/schema"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (p *productStorage) GetOptions(ctx context.Context, productId int64) ([]schema.ProductOption, error) {
+	qry := "SELECT id, created_at, updated_at, product_id, name, position, `values` " +
+		"FROM product_options WHERE product_id = ? ORDER BY position, id"
+
+	rows, err := p.Native.QueryContext(ctx, qry, productId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	options := []schema.ProductOption{}
+	for rows.Next() {
+		option := schema.ProductOption{}
+		if err := rows.Scan(&option.Id, &option.CreatedAt, &option.UpdatedAt,
+			&option.ProductId, &option.Name, &option.Position, &option.Values); err != nil {
+			return nil, err
+		}
+
+		options = append(options, option)
+	}
+
+	return options, rows.Err()
+}
+
+// SetOptions replaces the options of a product. An option or value still
+// chosen by one of the variants cannot be removed.
+func (p *productStorage) SetOptions(ctx context.Context, productId int64, options []schema.ProductOption) error {
+	var variants []schema.ProductVariant
+
+	tx := p.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	if err := lockProduct(tx.WithContext(ctx), productId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Where("product_id = ?", productId).Delete(&schema.ProductOption{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for i := range options {
+		options[i].ProductId = productId
+		if err := tx.WithContext(ctx).Create(&options[i]).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := tx.WithContext(ctx).Where("product_id = ?", productId).Find(&variants).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for _, variant := range variants {
+		if err := checkOptions(options, variant.Options); err != nil {
+			tx.Rollback()
+			return errors.New("option is used by a variant")
+		}
+	}
+
+	if err := touchProduct(tx.WithContext(ctx), productId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func (p *productStorage) GetVariants(ctx context.Context, productId int64) ([]schema.ProductVariant, error) {
+	qry := `SELECT id, created_at, updated_at, product_id, sku, options, price, qty, weight
+	FROM product_variants WHERE product_id = ? ORDER BY id`
+
+	rows, err := p.Native.QueryContext(ctx, qry, productId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	variants := []schema.ProductVariant{}
+	for rows.Next() {
+		variant := schema.ProductVariant{}
+		if err := rows.Scan(&variant.Id, &variant.CreatedAt, &variant.UpdatedAt, &variant.ProductId,
+			&variant.Sku, &variant.Options, &variant.Price, &variant.Qty, &variant.Weight); err != nil {
+			return nil, err
+		}
+
+		variants = append(variants, variant)
+	}
+
+	return variants, rows.Err()
+}
+
+func (p *productStorage) GetVariantById(ctx context.Context, id int64) (*schema.ProductVariant, error) {
+	variant := schema.ProductVariant{}
+	qry := `SELECT id, created_at, updated_at, product_id, sku, options, price, qty, weight
+	FROM product_variants WHERE id = ?`
+
+	res := p.Native.QueryRowContext(ctx, qry, id)
+	if err := res.Scan(&variant.Id, &variant.CreatedAt, &variant.UpdatedAt, &variant.ProductId,
+		&variant.Sku, &variant.Options, &variant.Price, &variant.Qty, &variant.Weight); err != nil {
+		return nil, err
+	}
+
+	return &variant, nil
+}
+
+func (p *productStorage) InsertVariant(ctx context.Context, data schema.ProductVariant) error {
+	tx := p.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	if err := lockProduct(tx.WithContext(ctx), data.ProductId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := checkVariant(tx.WithContext(ctx), data); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := insertVariant(tx.WithContext(ctx), data); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := syncProduct(tx.WithContext(ctx), data.ProductId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// UpdateVariant changes the variant. A new price goes into its price
+// history, a new qty into the default warehouse.
+func (p *productStorage) UpdateVariant(ctx context.Context, data schema.ProductVariant) error {
+	var current schema.ProductVariant
+	now := time.Now()
+
+	tx := p.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	if err := lockProduct(tx.WithContext(ctx), data.ProductId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := checkVariant(tx.WithContext(ctx), data); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Where("id = ? AND product_id = ?", data.Id, data.ProductId).
+		First(&current).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("data not found")
+		}
+		return err
+	}
+
+	if err := recordPrice(tx.WithContext(ctx), current, data.Price, now); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Model(&schema.ProductVariant{}).
+		Where("id = ? AND product_id = ?", data.Id, data.ProductId).
+		Select("sku", "options", "price", "qty", "weight").Updates(data).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := placeStock(tx.WithContext(ctx), data, data.Qty); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// a running sale keeps its price
+	if _, err := applyPrice(tx.WithContext(ctx), int64(data.Id), now); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := syncProduct(tx.WithContext(ctx), data.ProductId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// DeleteVariant removes a variant that was never ordered. The last variant
+// of a product goes only with the product itself.
+func (p *productStorage) DeleteVariant(ctx context.Context, productId, id int64) error {
+	var variants, orders int64
+
+	tx := p.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	if err := lockProduct(tx.WithContext(ctx), productId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Model(&schema.ProductVariant{}).Where("product_id = ?", productId).
+		Count(&variants).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Model(&schema.Order{}).Where("variant_id = ?", id).
+		Count(&orders).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if orders > 0 {
+		tx.Rollback()
+		return errors.New("variant has orders")
+	}
+
+	if variants <= 1 {
+		tx.Rollback()
+		return errors.New("product must have at least one variant")
+	}
+
+	result := tx.WithContext(ctx).Where("product_id = ?", productId).Delete(&schema.ProductVariant{}, id)
+	if result.Error != nil {
+		tx.Rollback()
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return errors.New("data not found")
+	}
+
+	// the stock movements stay as history
+	for _, table := range []interface{}{&schema.ProductPrice{}, &schema.WarehouseStock{}} {
+		if err := tx.WithContext(ctx).Where("variant_id = ?", id).Delete(table).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := syncProduct(tx.WithContext(ctx), productId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func defaultSku(productId int64) string {
+	return fmt.Sprintf("SKU-%06d", productId)
+}
+
+func insertVariant(tx *gorm.DB, data schema.ProductVariant) error {
+	var skus int64
+	if err := tx.Model(&schema.ProductVariant{}).Where("sku = ?", data.Sku).Count(&skus).Error; err != nil {
+		return err
+	}
+
+	if skus > 0 {
+		return errors.New("sku already exists")
+	}
+
+	if err := tx.Create(&data).Error; err != nil {
+		return err
+	}
+
+	if err := placeStock(tx, data, data.Qty); err != nil {
+		return err
+	}
+
+	// the price history of a variant starts with its first price
+	return tx.Create(&schema.ProductPrice{
+		ProductId:     data.ProductId,
+		VariantId:     int64(data.Id),
+		Price:         data.Price,
+		EffectiveFrom: data.CreatedAt,
+	}).Error
+}
+
+// lockProduct keeps concurrent option and variant changes of a product
+// from interleaving.
+func lockProduct(tx *gorm.DB, productId int64) error {
+	var product schema.Product
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").
+		First(&product, productId).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("product not found")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// checkVariant makes sure the variant only picks values of the product's
+// options and that its SKU and option choices are not taken by another
+// variant.
+func checkVariant(tx *gorm.DB, data schema.ProductVariant) error {
+	var options []schema.ProductOption
+	var skus, combinations int64
+
+	if err := tx.Where("product_id = ?", data.ProductId).Find(&options).Error; err != nil {
+		return err
+	}
+
+	if err := checkOptions(options, data.Options); err != nil {
+		return err
+	}
+
+	if err := tx.Model(&schema.ProductVariant{}).Where("sku = ? AND id <> ?", data.Sku, data.Id).
+		Count(&skus).Error; err != nil {
+		return err
+	}
+
+	if skus > 0 {
+		return errors.New("sku already exists")
+	}
+
+	if err := tx.Model(&schema.ProductVariant{}).
+		Where("product_id = ? AND options = ? AND id <> ?", data.ProductId, data.Options, data.Id).
+		Count(&combinations).Error; err != nil {
+		return err
+	}
+
+	if combinations > 0 {
+		return errors.New("variant already exists")
+	}
+
+	return nil
+}
+
+// checkOptions reports whether every choice in the JSON object choices is
+// one of the values of the option with the same name.
+func checkOptions(options []schema.ProductOption, choices string) error {
+	picked := map[string]string{}
+	if err := json.Unmarshal([]byte(choices), &picked); err != nil {
+		return errors.New("invalid variant option")
+	}
+
+	allowed := map[string]map[string]bool{}
+	for _, option := range options {
+		values := []string{}
+		if err := json.Unmarshal([]byte(option.Values), &values); err != nil {
+			return err
+		}
+
+		allowed[option.Name] = map[string]bool{}
+		for _, value := range values {
+			allowed[option.Name][value] = true
+		}
+	}
+
+	for name, value := range picked {
+		if !allowed[name][value] {
+			return errors.New("invalid variant option")
+		}
+	}
+
+	return nil
+}
+
+// syncProduct keeps the price and qty of a product at the lowest price and
+// the total stock of its variants. Every change of a product or its variants
+// ends here, so this is where the version of the product goes up.
+func syncProduct(tx *gorm.DB, productId int64) error {
+	return tx.Exec(`UPDATE products SET
+	price = (SELECT COALESCE(MIN(price), 0) FROM product_variants WHERE product_id = ?),
+	qty = (SELECT COALESCE(SUM(qty), 0) FROM product_variants WHERE product_id = ?),
+	version = version + 1
+	WHERE id = ?`, productId, productId, productId).Error
+}
+
+// touchProduct bumps the version of a product whose options or images
+// changed, since they are part of the product a client reads.
+func touchProduct(tx *gorm.DB, productId int64) error {
+	return tx.Model(&schema.Product{}).Where("id = ?", productId).
+		Update("version", gorm.Expr("version + 1")).Error
+}
+
+// lockVersion locks the product and makes sure it is still at version, the
+// version the client last read. Version 0 skips the check.
+func lockVersion(tx *gorm.DB, productId int64, version uint) error {
+	var product schema.Product
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "version").
+		First(&product, productId).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("data not found")
+		}
+		return err
+	}
+
+	if version > 0 && product.Version != version {
+		return errors.New("version mismatch")
+	}
+
+	return nil
+}
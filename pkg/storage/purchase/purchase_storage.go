@@ -0,0 +1,543 @@
+package purchase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/entity/schema"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate mockery --name PurchaseStorage --case snake --output ../../mocks --disable-version-string
+
+type (
+	PurchaseStorage interface {
+		InsertSupplier(ctx context.Context, data schema.Supplier) error
+		UpdateSupplier(ctx context.Context, data schema.Supplier) error
+		GetSuppliers(ctx context.Context) ([]schema.Supplier, error)
+		GetSupplierById(ctx context.Context, id int64) (*schema.Supplier, error)
+		DeleteSupplier(ctx context.Context, id int64) error
+
+		Insert(ctx context.Context, data schema.PurchaseOrder, lines []schema.PurchaseOrderLine) (int64, error)
+		Update(ctx context.Context, data schema.PurchaseOrder, lines []schema.PurchaseOrderLine) error
+		GetAll(ctx context.Context, query model.ListQuery) ([]model.PurchaseOrderResponse, int64, error)
+		GetById(ctx context.Context, id int64) (*model.PurchaseOrderResponse, error)
+		GetLines(ctx context.Context, id int64) ([]model.PurchaseOrderLineResponse, error)
+		Delete(ctx context.Context, id int64) error
+		Send(ctx context.Context, id int64) error
+		Receive(ctx context.Context, id int64, lines []model.ReceiveLineRequest, actorId int64, note string) error
+	}
+
+	purchaseStorage struct {
+		Native *sql.DB
+		Gorm   *gorm.DB
+	}
+)
+
+func NewPurchaseStorage(native *sql.DB, gorm *gorm.DB) PurchaseStorage {
+	return &purchaseStorage{
+		Native: native,
+		Gorm:   gorm,
+	}
+}
+
+// Insert drafts a purchase order and numbers it after its id.
+func (p *purchaseStorage) Insert(ctx context.Context, data schema.PurchaseOrder, lines []schema.PurchaseOrderLine) (int64, error) {
+	tx := p.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return 0, err
+	}
+
+	if err := prepare(tx.WithContext(ctx), &data, lines); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	data.Status = schema.PurchaseDraft
+	if err := tx.WithContext(ctx).Create(&data).Error; err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	data.Number = fmt.Sprintf("PO-%06d", data.Id)
+	if err := tx.WithContext(ctx).Model(&schema.PurchaseOrder{}).Where("id = ?", data.Id).
+		Update("number", data.Number).Error; err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	for i := range lines {
+		lines[i].PurchaseOrderId = int64(data.Id)
+	}
+	if err := tx.WithContext(ctx).Create(&lines).Error; err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	return int64(data.Id), tx.Commit().Error
+}
+
+// Update replaces the supplier, warehouse, note and lines of a draft.
+func (p *purchaseStorage) Update(ctx context.Context, data schema.PurchaseOrder, lines []schema.PurchaseOrderLine) error {
+	var current schema.PurchaseOrder
+
+	tx := p.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	if err := lockOrder(tx.WithContext(ctx), int64(data.Id), &current); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if current.Status != schema.PurchaseDraft {
+		tx.Rollback()
+		return errors.New("only draft purchase orders can be changed")
+	}
+
+	if err := prepare(tx.WithContext(ctx), &data, lines); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Model(&schema.PurchaseOrder{}).Where("id = ?", data.Id).
+		Updates(map[string]interface{}{
+			"supplier_id":  data.SupplierId,
+			"warehouse_id": data.WarehouseId,
+			"total":        data.Total,
+			"note":         data.Note,
+			"version":      gorm.Expr("version + 1"),
+		}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Where("purchase_order_id = ?", data.Id).
+		Delete(&schema.PurchaseOrderLine{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for i := range lines {
+		lines[i].PurchaseOrderId = int64(data.Id)
+	}
+	if err := tx.WithContext(ctx).Create(&lines).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// purchaseSorts maps the sort keys of the purchase order list to columns.
+var purchaseSorts = map[string]string{
+	"id":         "po.id",
+	"created_at": "po.created_at",
+	"total":      "po.total",
+	"status":     "po.status",
+}
+
+func (p *purchaseStorage) GetAll(ctx context.Context, query model.ListQuery) ([]model.PurchaseOrderResponse, int64, error) {
+	var total int64
+	where := []string{"1 = 1"}
+	args := []interface{}{}
+
+	if query.Status != "" {
+		where = append(where, "po.status = ?")
+		args = append(args, query.Status)
+	}
+	if query.From != nil {
+		where = append(where, "po.created_at >= ?")
+		args = append(args, *query.From)
+	}
+	if query.To != nil {
+		where = append(where, "po.created_at < ?")
+		args = append(args, *query.To)
+	}
+
+	if err := p.Native.QueryRowContext(ctx, `SELECT COUNT(*) FROM purchase_orders po WHERE `+strings.Join(where, " AND "), args...).
+		Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	column, ok := purchaseSorts[query.Sort]
+	if !ok {
+		column = "po.id"
+	}
+	direction := "ASC"
+	if query.Desc {
+		direction = "DESC"
+	}
+
+	if query.Cursor > 0 {
+		if query.Desc {
+			where = append(where, "po.id < ?")
+		} else {
+			where = append(where, "po.id > ?")
+		}
+		args = append(args, query.Cursor)
+	}
+
+	qry := purchaseColumns + `
+	WHERE ` + strings.Join(where, " AND ") + `
+	ORDER BY ` + column + ` ` + direction + `, po.id ` + direction + `
+	LIMIT ? OFFSET ?`
+
+	rows, err := p.Native.QueryContext(ctx, qry, append(args, query.Size, query.Offset())...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	orders := []model.PurchaseOrderResponse{}
+	for rows.Next() {
+		res, err := scanOrder(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		orders = append(orders, *res)
+	}
+
+	return orders, total, rows.Err()
+}
+
+func (p *purchaseStorage) GetById(ctx context.Context, id int64) (*model.PurchaseOrderResponse, error) {
+	return scanOrder(p.Native.QueryRowContext(ctx, purchaseColumns+` WHERE po.id = ?`, id))
+}
+
+const purchaseColumns = `SELECT po.id, COALESCE(po.number,""), po.supplier_id, COALESCE(s.name,""), po.warehouse_id,
+	po.status, po.total, COALESCE(po.note,""), po.version, po.created_at, po.sent_at, po.received_at
+	FROM purchase_orders po LEFT JOIN suppliers s ON s.id = po.supplier_id`
+
+func scanOrder(row interface{ Scan(...interface{}) error }) (*model.PurchaseOrderResponse, error) {
+	var res model.PurchaseOrderResponse
+	var sentAt, receivedAt sql.NullTime
+	if err := row.Scan(&res.Id, &res.Number, &res.SupplierId, &res.SupplierName, &res.WarehouseId,
+		&res.Status, &res.Total, &res.Note, &res.Version, &res.CreatedAt, &sentAt, &receivedAt); err != nil {
+		return nil, err
+	}
+	if sentAt.Valid {
+		res.SentAt = &sentAt.Time
+	}
+	if receivedAt.Valid {
+		res.ReceivedAt = &receivedAt.Time
+	}
+
+	return &res, nil
+}
+
+func (p *purchaseStorage) GetLines(ctx context.Context, id int64) ([]model.PurchaseOrderLineResponse, error) {
+	qry := `SELECT l.id, l.variant_id, l.product_id, COALESCE(p.name,""), COALESCE(v.sku,""), l.qty, l.received_qty, l.unit_cost
+	FROM purchase_order_lines l
+	LEFT JOIN product_variants v ON v.id = l.variant_id
+	LEFT JOIN products p ON p.id = l.product_id
+	WHERE l.purchase_order_id = ?
+	ORDER BY l.id`
+
+	rows, err := p.Native.QueryContext(ctx, qry, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lines := []model.PurchaseOrderLineResponse{}
+	for rows.Next() {
+		var res model.PurchaseOrderLineResponse
+		if err := rows.Scan(&res.Id, &res.VariantId, &res.ProductId, &res.ProductName, &res.Sku, &res.Qty,
+			&res.ReceivedQty, &res.UnitCost); err != nil {
+			return nil, err
+		}
+		lines = append(lines, res)
+	}
+
+	return lines, rows.Err()
+}
+
+func (p *purchaseStorage) Delete(ctx context.Context, id int64) error {
+	var current schema.PurchaseOrder
+
+	tx := p.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	if err := lockOrder(tx.WithContext(ctx), id, &current); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if current.Status != schema.PurchaseDraft {
+		tx.Rollback()
+		return errors.New("only draft purchase orders can be deleted")
+	}
+
+	if err := tx.WithContext(ctx).Where("purchase_order_id = ?", id).Delete(&schema.PurchaseOrderLine{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Delete(&schema.PurchaseOrder{}, id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// Send marks a draft as sent to the supplier, after which its lines are
+// fixed and goods can be received against it.
+func (p *purchaseStorage) Send(ctx context.Context, id int64) error {
+	var current schema.PurchaseOrder
+
+	tx := p.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	if err := lockOrder(tx.WithContext(ctx), id, &current); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if current.Status != schema.PurchaseDraft {
+		tx.Rollback()
+		return errors.New("only draft purchase orders can be sent")
+	}
+
+	if err := tx.WithContext(ctx).Model(&schema.PurchaseOrder{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":  schema.PurchaseSent,
+			"sent_at": time.Now(),
+			"version": gorm.Expr("version + 1"),
+		}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// Receive adds the delivered goods to the stock of the warehouse of the
+// purchase order. Every line received is a receipt movement carrying the
+// order number, so the stock can be traced back to the supplier. The order
+// is received once every line is, and partially received until then.
+func (p *purchaseStorage) Receive(ctx context.Context, id int64, lines []model.ReceiveLineRequest, actorId int64, note string) error {
+	var current schema.PurchaseOrder
+	var ordered []schema.PurchaseOrderLine
+	var pending int64
+
+	tx := p.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	if err := lockOrder(tx.WithContext(ctx), id, &current); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if current.Status != schema.PurchaseSent && current.Status != schema.PurchasePartiallyReceived {
+		tx.Rollback()
+		return errors.New("purchase order is not awaiting goods")
+	}
+
+	if err := tx.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("purchase_order_id = ?", id).Find(&ordered).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	byId := map[int64]schema.PurchaseOrderLine{}
+	for _, line := range ordered {
+		byId[int64(line.Id)] = line
+	}
+
+	for _, received := range lines {
+		line, ok := byId[received.LineId]
+		if !ok {
+			tx.Rollback()
+			return errors.New("line not found")
+		}
+
+		if line.ReceivedQty+received.Qty > line.Qty {
+			tx.Rollback()
+			return errors.New("received qty exceeds the ordered qty")
+		}
+
+		if err := tx.WithContext(ctx).Model(&schema.PurchaseOrderLine{}).Where("id = ?", line.Id).
+			Update("received_qty", gorm.Expr("received_qty + ?", received.Qty)).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if err := receiveStock(tx.WithContext(ctx), current, line, received.Qty, actorId, note); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := tx.WithContext(ctx).Model(&schema.PurchaseOrderLine{}).
+		Where("purchase_order_id = ? AND received_qty < qty", id).Count(&pending).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	values := map[string]interface{}{
+		"status":  schema.PurchasePartiallyReceived,
+		"version": gorm.Expr("version + 1"),
+	}
+	if pending == 0 {
+		values["status"] = schema.PurchaseReceived
+		values["received_at"] = time.Now()
+	}
+
+	if err := tx.WithContext(ctx).Model(&schema.PurchaseOrder{}).Where("id = ?", id).
+		Updates(values).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// receiveStock adds the goods to the warehouse stock, records the receipt
+// and brings the qty of the variant and the product up to their new totals.
+func receiveStock(tx *gorm.DB, order schema.PurchaseOrder, line schema.PurchaseOrderLine, qty, actorId int64, note string) error {
+	if err := tx.Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]interface{}{"qty": gorm.Expr("qty + ?", qty)}),
+	}).Create(&schema.WarehouseStock{
+		WarehouseId: order.WarehouseId,
+		VariantId:   line.VariantId,
+		ProductId:   line.ProductId,
+		Qty:         qty,
+	}).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Create(&schema.StockMovement{
+		WarehouseId: order.WarehouseId,
+		VariantId:   line.VariantId,
+		ProductId:   line.ProductId,
+		Qty:         qty,
+		Type:        schema.MovementReceipt,
+		Reference:   order.Number,
+		ActorId:     actorId,
+		Note:        note,
+	}).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Exec(`UPDATE product_variants SET
+	qty = (SELECT COALESCE(SUM(qty), 0) FROM warehouse_stocks WHERE variant_id = ?)
+	WHERE id = ?`, line.VariantId, line.VariantId).Error; err != nil {
+		return err
+	}
+
+	return tx.Exec(`UPDATE products SET
+	qty = (SELECT COALESCE(SUM(qty), 0) FROM product_variants WHERE product_id = ?),
+	version = version + 1
+	WHERE id = ?`, line.ProductId, line.ProductId).Error
+}
+
+// prepare checks the supplier and the warehouse of the order, fills in the
+// product of each line and totals the lines. Goods go to the default
+// warehouse unless the order names one.
+func prepare(tx *gorm.DB, data *schema.PurchaseOrder, lines []schema.PurchaseOrderLine) error {
+	var supplier schema.Supplier
+	var warehouse schema.Warehouse
+
+	if err := tx.Select("id", "active").First(&supplier, data.SupplierId).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("supplier not found")
+		}
+		return err
+	}
+
+	if !supplier.Active {
+		return errors.New("supplier is inactive")
+	}
+
+	if data.WarehouseId > 0 {
+		if err := tx.Select("id", "active").First(&warehouse, data.WarehouseId).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("warehouse not found")
+			}
+			return err
+		}
+		if !warehouse.Active {
+			return errors.New("warehouse is inactive")
+		}
+	} else {
+		if err := tx.Select("id").Where("is_default = ?", true).First(&warehouse).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("no warehouse to receive the goods into")
+			}
+			return err
+		}
+		data.WarehouseId = int64(warehouse.Id)
+	}
+
+	data.Total = 0
+	for i := range lines {
+		var variant schema.ProductVariant
+		if err := tx.Select("id", "product_id").First(&variant, lines[i].VariantId).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("variant not found")
+			}
+			return err
+		}
+
+		lines[i].ProductId = variant.ProductId
+		data.Total += float64(lines[i].Qty) * lines[i].UnitCost
+	}
+
+	return nil
+}
+
+func lockOrder(tx *gorm.DB, id int64, order *schema.PurchaseOrder) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(order, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("data not found")
+		}
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package purchase
+
+import (
+	"context"
+	"errors"
+	"kanggo/pkg/entity/schema"
+
+	"gorm.io/gorm"
+)
+
+func (p *purchaseStorage) InsertSupplier(ctx context.Context, data schema.Supplier) error {
+	return p.Gorm.WithContext(ctx).Create(&data).Error
+}
+
+func (p *purchaseStorage) UpdateSupplier(ctx context.Context, data schema.Supplier) error {
+	var current schema.Supplier
+	if err := p.Gorm.WithContext(ctx).Select("id").First(&current, data.Id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("data not found")
+		}
+		return err
+	}
+
+	return p.Gorm.WithContext(ctx).Model(&schema.Supplier{}).Where("id = ?", data.Id).
+		Updates(map[string]interface{}{
+			"name":    data.Name,
+			"email":   data.Email,
+			"phone":   data.Phone,
+			"address": data.Address,
+			"active":  data.Active,
+			"version": gorm.Expr("version + 1"),
+		}).Error
+}
+
+func (p *purchaseStorage) GetSuppliers(ctx context.Context) ([]schema.Supplier, error) {
+	qry := `SELECT id, created_at, updated_at, name, COALESCE(email,""), COALESCE(phone,""), COALESCE(address,""), active
+	FROM suppliers ORDER BY name, id`
+
+	rows, err := p.Native.QueryContext(ctx, qry)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suppliers := []schema.Supplier{}
+	for rows.Next() {
+		var res schema.Supplier
+		if err := rows.Scan(&res.Id, &res.CreatedAt, &res.UpdatedAt, &res.Name, &res.Email, &res.Phone,
+			&res.Address, &res.Active); err != nil {
+			return nil, err
+		}
+		suppliers = append(suppliers, res)
+	}
+
+	return suppliers, rows.Err()
+}
+
+func (p *purchaseStorage) GetSupplierById(ctx context.Context, id int64) (*schema.Supplier, error) {
+	var res schema.Supplier
+	qry := `SELECT id, created_at, updated_at, name, COALESCE(email,""), COALESCE(phone,""), COALESCE(address,""), active
+	FROM suppliers WHERE id = ?`
+
+	if err := p.Native.QueryRowContext(ctx, qry, id).Scan(&res.Id, &res.CreatedAt, &res.UpdatedAt, &res.Name,
+		&res.Email, &res.Phone, &res.Address, &res.Active); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// DeleteSupplier removes a supplier nothing was ordered from. A supplier
+// with purchase orders is made inactive instead.
+func (p *purchaseStorage) DeleteSupplier(ctx context.Context, id int64) error {
+	var orders int64
+
+	tx := p.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Model(&schema.PurchaseOrder{}).Where("supplier_id = ?", id).
+		Count(&orders).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if orders > 0 {
+		tx.Rollback()
+		return errors.New("supplier has purchase orders")
+	}
+
+	result := tx.WithContext(ctx).Delete(&schema.Supplier{}, id)
+	if result.Error != nil {
+		tx.Rollback()
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return errors.New("data not found")
+	}
+
+	return tx.Commit().Error
+}
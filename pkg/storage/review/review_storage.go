@@ -0,0 +1,270 @@
+package review
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/entity/schema"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate mockery --name ReviewStorage --case snake --output ../../mocks --disable-version-string
+
+type (
+	ReviewStorage interface {
+		Insert(ctx context.Context, data schema.Review) (int64, error)
+		GetAll(ctx context.Context, productId int64, query model.ListQuery) ([]model.ReviewResponse, int64, error)
+		GetById(ctx context.Context, id int64) (*model.ReviewResponse, error)
+		Moderate(ctx context.Context, id int64, status string, actorId int64) error
+	}
+
+	reviewStorage struct {
+		Native *sql.DB
+		Gorm   *gorm.DB
+	}
+)
+
+func NewReviewStorage(native *sql.DB, gorm *gorm.DB) ReviewStorage {
+	return &reviewStorage{
+		Native: native,
+		Gorm:   gorm,
+	}
+}
+
+// verifiedStatuses are the statuses of an order whose goods have been paid
+// for, which entitle the buyer to review them.
+var verifiedStatuses = []string{"paid", "shipped", "completed"}
+
+// Insert posts the review of a user who bought the product, pending
+// moderation.
+func (r *reviewStorage) Insert(ctx context.Context, data schema.Review) (int64, error) {
+	var count int64
+
+	tx := r.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return 0, err
+	}
+
+	if err := tx.WithContext(ctx).Model(&schema.Product{}).Where("id = ?", data.ProductId).
+		Count(&count).Error; err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	if count == 0 {
+		tx.Rollback()
+		return 0, errors.New("product not found")
+	}
+
+	var order schema.Order
+	if err := tx.WithContext(ctx).Select("id").
+		Where("user_id = ? AND product_id = ? AND status IN ?", data.UserId, data.ProductId, verifiedStatuses).
+		Order("id").First(&order).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, errors.New("only buyers of the product can review it")
+		}
+		return 0, err
+	}
+
+	if err := tx.WithContext(ctx).Model(&schema.Review{}).Where("product_id = ? AND user_id = ?", data.ProductId, data.UserId).
+		Count(&count).Error; err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	if count > 0 {
+		tx.Rollback()
+		return 0, errors.New("product already reviewed")
+	}
+
+	data.OrderId = int64(order.Id)
+	data.Status = schema.ReviewPending
+	// the count above does not hold off a concurrent review, which the
+	// unique index turns away instead
+	if err := tx.WithContext(ctx).Create(&data).Error; err != nil {
+		tx.Rollback()
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+			return 0, errors.New("product already reviewed")
+		}
+		return 0, err
+	}
+
+	return int64(data.Id), tx.Commit().Error
+}
+
+// reviewSorts maps the sort keys of the review list to columns.
+var reviewSorts = map[string]string{
+	"id":     "r.id",
+	"rating": "r.rating",
+}
+
+// GetAll lists the reviews of a product, or of every product when productId
+// is 0, narrowed to a status by query.Status.
+func (r *reviewStorage) GetAll(ctx context.Context, productId int64, query model.ListQuery) ([]model.ReviewResponse, int64, error) {
+	var total int64
+	where := []string{"1 = 1"}
+	args := []interface{}{}
+
+	if productId > 0 {
+		where = append(where, "r.product_id = ?")
+		args = append(args, productId)
+	}
+	if query.Status != "" {
+		where = append(where, "r.status = ?")
+		args = append(args, query.Status)
+	}
+	if query.From != nil {
+		where = append(where, "r.created_at >= ?")
+		args = append(args, *query.From)
+	}
+	if query.To != nil {
+		where = append(where, "r.created_at < ?")
+		args = append(args, *query.To)
+	}
+
+	if err := r.Native.QueryRowContext(ctx, `SELECT COUNT(*) FROM reviews r WHERE `+strings.Join(where, " AND "), args...).
+		Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	column, ok := reviewSorts[query.Sort]
+	if !ok {
+		column = "r.id"
+	}
+	direction := "ASC"
+	if query.Desc {
+		direction = "DESC"
+	}
+
+	if query.Cursor > 0 {
+		if query.Desc {
+			where = append(where, "r.id < ?")
+		} else {
+			where = append(where, "r.id > ?")
+		}
+		args = append(args, query.Cursor)
+	}
+
+	qry := reviewColumns + `
+	WHERE ` + strings.Join(where, " AND ") + `
+	ORDER BY ` + column + ` ` + direction + `, r.id ` + direction + `
+	LIMIT ? OFFSET ?`
+
+	rows, err := r.Native.QueryContext(ctx, qry, append(args, query.Size, query.Offset())...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	reviews := []model.ReviewResponse{}
+	for rows.Next() {
+		res, err := scanReview(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		reviews = append(reviews, *res)
+	}
+
+	return reviews, total, rows.Err()
+}
+
+func (r *reviewStorage) GetById(ctx context.Context, id int64) (*model.ReviewResponse, error) {
+	return scanReview(r.Native.QueryRowContext(ctx, reviewColumns+` WHERE r.id = ?`, id))
+}
+
+const reviewColumns = `SELECT r.id, r.product_id, r.user_id, COALESCE(u.name,""), r.rating, COALESCE(r.text,""),
+	r.status, r.created_at, r.moderated_at
+	FROM reviews r LEFT JOIN users u ON u.id = r.user_id`
+
+func scanReview(row interface{ Scan(...interface{}) error }) (*model.ReviewResponse, error) {
+	var res model.ReviewResponse
+	var moderatedAt sql.NullTime
+	if err := row.Scan(&res.Id, &res.ProductId, &res.UserId, &res.UserName, &res.Rating, &res.Text,
+		&res.Status, &res.CreatedAt, &moderatedAt); err != nil {
+		return nil, err
+	}
+	if moderatedAt.Valid {
+		res.ModeratedAt = &moderatedAt.Time
+	}
+
+	return &res, nil
+}
+
+// Moderate approves or hides a review. The rating of the product is moved
+// by the review alone, adding it when approved and taking it back when an
+// approved review is hidden, and the product version with it.
+func (r *reviewStorage) Moderate(ctx context.Context, id int64, status string, actorId int64) error {
+	var current schema.Review
+
+	tx := r.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, id).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("data not found")
+		}
+		return err
+	}
+
+	if current.Status == status {
+		tx.Rollback()
+		return nil
+	}
+
+	if err := tx.WithContext(ctx).Model(&schema.Review{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":       status,
+			"moderated_by": actorId,
+			"moderated_at": time.Now(),
+			"version":      gorm.Expr("version + 1"),
+		}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var count, rating int64
+	switch {
+	case status == schema.ReviewApproved:
+		count, rating = 1, current.Rating
+	case current.Status == schema.ReviewApproved:
+		count, rating = -1, -current.Rating
+	}
+
+	if count != 0 {
+		// a product in the trash keeps its rating for when it is restored
+		if err := tx.WithContext(ctx).Unscoped().Model(&schema.Product{}).Where("id = ?", current.ProductId).
+			UpdateColumns(map[string]interface{}{
+				"rating_count": gorm.Expr("rating_count + ?", count),
+				"rating_total": gorm.Expr("rating_total + ?", rating),
+				"version":      gorm.Expr("version + 1"),
+			}).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
@@ -0,0 +1,58 @@
+package review
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"kanggo/pkg/dbtest"
+	"kanggo/pkg/entity/schema"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsert(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reviewed concurrently", func(t *testing.T) {
+		native, db, mock := dbtest.New(t)
+		s := NewReviewStorage(native, db)
+
+		mock.Expect("Begin")
+		mock.Expect("SELECT count\\(\\*\\) FROM `products`").Rows([]string{"count"}, []driver.Value{int64(1)})
+		mock.Expect("SELECT `id` FROM `orders`").Rows([]string{"id"}, []driver.Value{int64(7)})
+		mock.Expect("SELECT count\\(\\*\\) FROM `reviews`").Rows([]string{"count"}, []driver.Value{int64(0)})
+		mock.Expect("INSERT INTO `reviews`").
+			Error(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry '1-2' for key 'idx_review_product_user'"})
+		mock.Expect("Rollback")
+
+		_, err := s.Insert(ctx, schema.Review{ProductId: 1, UserId: 2, Rating: 5})
+
+		assert.EqualError(t, err, "product already reviewed")
+		assert.NoError(t, mock.Done())
+	})
+}
+
+func TestModerate(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("hide an approved review", func(t *testing.T) {
+		native, db, mock := dbtest.New(t)
+		s := NewReviewStorage(native, db)
+
+		mock.Expect("Begin")
+		mock.Expect("SELECT \\* FROM `reviews` .* FOR UPDATE").
+			Rows([]string{"id", "product_id", "user_id", "rating", "status"},
+				[]driver.Value{int64(9), int64(1), int64(2), int64(4), schema.ReviewApproved})
+		mock.Expect("UPDATE `reviews` SET")
+		product := mock.Expect("UPDATE `products` SET `rating_count`=rating_count \\+ \\?,`rating_total`=rating_total \\+ \\?,`version`=version \\+ 1")
+		mock.Expect("Commit")
+
+		err := s.Moderate(ctx, 9, schema.ReviewHidden, 1)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.Done())
+		assert.Equal(t, []driver.Value{int64(-1), int64(-4), int64(1)}, product.Args)
+	})
+}
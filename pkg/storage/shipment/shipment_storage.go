@@ -0,0 +1,251 @@
+package shipment
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"kanggo/pkg/entity/schema"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate mockery --name ShipmentStorage --case snake --output ../../mocks --disable-version-string
+
+type (
+	ShipmentStorage interface {
+		Insert(ctx context.Context, data schema.Shipment) error
+		InsertEvent(ctx context.Context, data schema.ShipmentEvent, actorId int64) error
+		GetById(ctx context.Context, id int64) (*schema.Shipment, error)
+		GetByOrderId(ctx context.Context, orderId int64) (*schema.Shipment, error)
+		GetEvents(ctx context.Context, shipmentId int64) ([]schema.ShipmentEvent, error)
+	}
+
+	shipmentStorage struct {
+		Native *sql.DB
+		Gorm   *gorm.DB
+	}
+)
+
+func NewShipmentStorage(native *sql.DB, gorm *gorm.DB) ShipmentStorage {
+	return &shipmentStorage{
+		Native: native,
+		Gorm:   gorm,
+	}
+}
+
+func (s *shipmentStorage) Insert(ctx context.Context, data schema.Shipment) error {
+	var order schema.Order
+	var count int64
+
+	tx := s.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", data.OrderId).Select("id", "status").First(&order).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("data not found")
+		}
+		return err
+	}
+
+	if order.Status != "paid" {
+		tx.Rollback()
+		return errors.New("order is not paid")
+	}
+
+	if err := tx.WithContext(ctx).Model(&schema.Shipment{}).Where("order_id = ?", data.OrderId).
+		Count(&count).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if count > 0 {
+		tx.Rollback()
+		return errors.New("shipment already exists")
+	}
+
+	if err := tx.WithContext(ctx).Create(&data).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// InsertEvent appends a tracking event and moves the shipment and its order
+// along: the first event marks the order shipped, a delivered event marks it
+// completed. Each order status change is recorded as an order event of the
+// admin posting the tracking event.
+func (s *shipmentStorage) InsertEvent(ctx context.Context, data schema.ShipmentEvent, actorId int64) error {
+	var shipment schema.Shipment
+	var order schema.Order
+
+	tx := s.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", data.ShipmentId).First(&shipment).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("data not found")
+		}
+		return err
+	}
+
+	if shipment.DeliveredAt != nil {
+		tx.Rollback()
+		return errors.New("shipment already delivered")
+	}
+
+	if err := tx.WithContext(ctx).Create(&data).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if shipment.ShippedAt == nil {
+		if err := tx.WithContext(ctx).Model(&schema.Shipment{}).Where("id = ?", shipment.Id).
+			Update("shipped_at", data.OccurredAt).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		res := tx.WithContext(ctx).Model(&schema.Order{}).Where("id = ? AND status = 'paid'", shipment.OrderId).
+			Updates(map[string]interface{}{"status": "shipped", "version": gorm.Expr("version + 1")})
+		if res.Error != nil {
+			tx.Rollback()
+			return res.Error
+		}
+
+		if res.RowsAffected > 0 {
+			if err := recordStatusChange(tx.WithContext(ctx), shipment.OrderId, "paid", "shipped", actorId, data); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+	}
+
+	if data.Status == "delivered" {
+		if err := tx.WithContext(ctx).Model(&schema.Shipment{}).Where("id = ?", shipment.Id).
+			Update("delivered_at", data.OccurredAt).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if err := tx.WithContext(ctx).Where("id = ?", shipment.OrderId).Select("id", "status").
+			First(&order).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if order.Status == "paid" || order.Status == "shipped" {
+			if err := tx.WithContext(ctx).Model(&schema.Order{}).Where("id = ?", shipment.OrderId).
+				Updates(map[string]interface{}{"status": "completed", "version": gorm.Expr("version + 1")}).Error; err != nil {
+				tx.Rollback()
+				return err
+			}
+
+			if err := recordStatusChange(tx.WithContext(ctx), shipment.OrderId, order.Status, "completed", actorId, data); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+func recordStatusChange(tx *gorm.DB, orderId int64, from, to string, actorId int64, event schema.ShipmentEvent) error {
+	payload, _ := json.Marshal(map[string]interface{}{
+		"shipment_id":     event.ShipmentId,
+		"tracking_status": event.Status,
+		"location":        event.Location,
+	})
+
+	return tx.Create(&schema.OrderEvent{
+		OrderId:    orderId,
+		Type:       schema.OrderEventStatusChanged,
+		FromStatus: from,
+		ToStatus:   to,
+		ActorId:    actorId,
+		ActorRole:  "admin",
+		Payload:    string(payload),
+	}).Error
+}
+
+func (s *shipmentStorage) GetById(ctx context.Context, id int64) (*schema.Shipment, error) {
+	shipment := schema.Shipment{}
+	qry := `SELECT id, created_at, updated_at, order_id, courier, COALESCE(service,""), tracking_number,
+	shipped_at, delivered_at
+	FROM shipments WHERE id = ?`
+
+	res := s.Native.QueryRowContext(ctx, qry, id)
+	if err := res.Scan(&shipment.Id, &shipment.CreatedAt, &shipment.UpdatedAt, &shipment.OrderId,
+		&shipment.Courier, &shipment.Service, &shipment.TrackingNumber,
+		&shipment.ShippedAt, &shipment.DeliveredAt); err != nil {
+		return nil, err
+	}
+
+	return &shipment, nil
+}
+
+func (s *shipmentStorage) GetByOrderId(ctx context.Context, orderId int64) (*schema.Shipment, error) {
+	shipment := schema.Shipment{}
+	qry := `SELECT id, created_at, updated_at, order_id, courier, COALESCE(service,""), tracking_number,
+	shipped_at, delivered_at
+	FROM shipments WHERE order_id = ?`
+
+	res := s.Native.QueryRowContext(ctx, qry, orderId)
+	if err := res.Scan(&shipment.Id, &shipment.CreatedAt, &shipment.UpdatedAt, &shipment.OrderId,
+		&shipment.Courier, &shipment.Service, &shipment.TrackingNumber,
+		&shipment.ShippedAt, &shipment.DeliveredAt); err != nil {
+		return nil, err
+	}
+
+	return &shipment, nil
+}
+
+func (s *shipmentStorage) GetEvents(ctx context.Context, shipmentId int64) ([]schema.ShipmentEvent, error) {
+	qry := `SELECT id, created_at, updated_at, shipment_id, status, COALESCE(location,""),
+	COALESCE(description,""), occurred_at
+	FROM shipment_events
+	WHERE shipment_id = ?
+	ORDER BY occurred_at, id
+	`
+
+	rows, err := s.Native.QueryContext(ctx, qry, shipmentId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []schema.ShipmentEvent{}
+	for rows.Next() {
+		var res schema.ShipmentEvent
+		if err := rows.Scan(&res.Id, &res.CreatedAt, &res.UpdatedAt, &res.ShipmentId, &res.Status,
+			&res.Location, &res.Description, &res.OccurredAt); err != nil {
+			return nil, err
+		}
+		events = append(events, res)
+	}
+
+	return events, nil
+}
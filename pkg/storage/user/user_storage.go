@@ -2,10 +2,16 @@ package user
 
 import (
 	"context"
+	"crypto/subtle"
 	"database/sql"
+	"errors"
+	"kanggo/pkg/entity/model"
 	"kanggo/pkg/entity/schema"
+	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 //go:generate mockery --name UserStorage --case snake --output ../../mocks --disable-version-string
@@ -14,6 +20,17 @@ type (
 	UserStorage interface {
 		Insert(ctx context.Context, data schema.User) error
 		GetByEmail(ctx context.Context, email string) (*schema.User, error)
+		GetById(ctx context.Context, id uint64) (*schema.User, error)
+		Update(ctx context.Context, data schema.User) error
+		UpdatePassword(ctx context.Context, id uint64, password string) error
+		Delete(ctx context.Context, id uint64) error
+		GetAll(ctx context.Context, search string, limit, offset int) ([]schema.User, int64, error)
+		GetOrderSummary(ctx context.Context, id uint64) (*model.OrderSummary, error)
+		UpdateStatus(ctx context.Context, id uint64, status string) error
+		UpdateRole(ctx context.Context, id uint64, role string) error
+		SetPasswordResetRequired(ctx context.Context, id uint64) error
+		SetPendingEmail(ctx context.Context, id uint64, email, tokenHash string, expiresAt time.Time) error
+		ConfirmEmail(ctx context.Context, id uint64, tokenHash string) error
 	}
 
 	userStorage struct {
@@ -40,9 +57,10 @@ func (m *userStorage) Insert(ctx context.Context, data schema.User) error {
 func (m *userStorage) GetByEmail(ctx context.Context, email string) (*schema.User, error) {
 
 	user := schema.User{}
-	qry := `SELECT id, name, email, password FROM users WHERE email = ? `
+	qry := `SELECT id, name, email, password, role, status, password_reset_required FROM users WHERE email = ? `
 	res := m.Native.QueryRowContext(ctx, qry, email)
-	if err := res.Scan(&user.Base.Id, &user.Name, &user.Email, &user.Password); err != nil {
+	if err := res.Scan(&user.Base.Id, &user.Name, &user.Email, &user.Password,
+		&user.Role, &user.Status, &user.PasswordResetRequired); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, err
 		}
@@ -51,3 +69,233 @@ func (m *userStorage) GetByEmail(ctx context.Context, email string) (*schema.Use
 
 	return &user, nil
 }
+
+func (m *userStorage) GetById(ctx context.Context, id uint64) (*schema.User, error) {
+	user := schema.User{}
+	qry := `SELECT id, created_at, COALESCE(name,""), email, password, role, status, password_reset_required,
+	COALESCE(pending_email,"")
+	FROM users WHERE id = ? `
+	res := m.Native.QueryRowContext(ctx, qry, id)
+	if err := res.Scan(&user.Base.Id, &user.CreatedAt, &user.Name, &user.Email, &user.Password,
+		&user.Role, &user.Status, &user.PasswordResetRequired, &user.PendingEmail); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (m *userStorage) Update(ctx context.Context, data schema.User) error {
+	result := m.Gorm.WithContext(ctx).Updates(data)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("data not found")
+	}
+
+	return nil
+}
+
+func (m *userStorage) UpdatePassword(ctx context.Context, id uint64, password string) error {
+	result := m.Gorm.WithContext(ctx).Model(&schema.User{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"password": password, "password_reset_required": false})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("data not found")
+	}
+
+	return nil
+}
+
+func (m *userStorage) Delete(ctx context.Context, id uint64) error {
+	result := m.Gorm.WithContext(ctx).Delete(schema.User{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("data not found")
+	}
+
+	return nil
+}
+
+func (m *userStorage) GetAll(ctx context.Context, search string, limit, offset int) ([]schema.User, int64, error) {
+	var total int64
+	pattern := "%" + search + "%"
+
+	qry := `SELECT COUNT(*) FROM users WHERE name LIKE ? OR email LIKE ?`
+	if err := m.Native.QueryRowContext(ctx, qry, pattern, pattern).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	qry = `SELECT id, created_at, COALESCE(name,""), email, role, status, password_reset_required
+	FROM users
+	WHERE name LIKE ? OR email LIKE ?
+	ORDER BY id
+	LIMIT ? OFFSET ?
+	`
+
+	rows, err := m.Native.QueryContext(ctx, qry, pattern, pattern, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	users := []schema.User{}
+	for rows.Next() {
+		var res schema.User
+		if err := rows.Scan(&res.Id, &res.CreatedAt, &res.Name, &res.Email,
+			&res.Role, &res.Status, &res.PasswordResetRequired); err != nil {
+			return nil, 0, err
+		}
+		users = append(users, res)
+	}
+
+	return users, total, nil
+}
+
+func (m *userStorage) GetOrderSummary(ctx context.Context, id uint64) (*model.OrderSummary, error) {
+	var summary model.OrderSummary
+
+	qry := `SELECT COUNT(*),
+	COALESCE(SUM(CASE WHEN status IN ('paid','shipped','completed') THEN 1 ELSE 0 END),0),
+	COALESCE(SUM(CASE WHEN status = 'pending' THEN 1 ELSE 0 END),0),
+	COALESCE(SUM(amount),0),
+	COALESCE(SUM(CASE WHEN status IN ('paid','shipped','completed') THEN amount ELSE 0 END),0)
+	FROM orders WHERE user_id = ?
+	`
+
+	res := m.Native.QueryRowContext(ctx, qry, id)
+	if err := res.Scan(&summary.TotalOrders, &summary.PaidOrders, &summary.PendingOrders,
+		&summary.TotalAmount, &summary.PaidAmount); err != nil {
+		return nil, err
+	}
+
+	return &summary, nil
+}
+
+func (m *userStorage) UpdateStatus(ctx context.Context, id uint64, status string) error {
+	result := m.Gorm.WithContext(ctx).Model(&schema.User{}).Where("id = ?", id).Update("status", status)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("data not found")
+	}
+
+	return nil
+}
+
+func (m *userStorage) UpdateRole(ctx context.Context, id uint64, role string) error {
+	result := m.Gorm.WithContext(ctx).Model(&schema.User{}).Where("id = ?", id).Update("role", role)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("data not found")
+	}
+
+	return nil
+}
+
+func (m *userStorage) SetPasswordResetRequired(ctx context.Context, id uint64) error {
+	result := m.Gorm.WithContext(ctx).Model(&schema.User{}).Where("id = ?", id).Update("password_reset_required", true)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("data not found")
+	}
+
+	return nil
+}
+
+// SetPendingEmail keeps the address the user is changing to until the token
+// mailed to it is confirmed. A newer change replaces an unconfirmed one.
+func (m *userStorage) SetPendingEmail(ctx context.Context, id uint64, email, tokenHash string, expiresAt time.Time) error {
+	result := m.Gorm.WithContext(ctx).Model(&schema.User{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"pending_email":          email,
+			"email_token_hash":       tokenHash,
+			"email_token_expires_at": expiresAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("data not found")
+	}
+
+	return nil
+}
+
+// ConfirmEmail makes the pending address the email of the user when the
+// token matches and has not expired. The address may have been registered
+// by someone else in the meantime.
+func (m *userStorage) ConfirmEmail(ctx context.Context, id uint64, tokenHash string) error {
+	var user schema.User
+	var count int64
+
+	tx := m.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, id).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("data not found")
+		}
+		return err
+	}
+
+	if user.PendingEmail == "" || user.EmailTokenExpiresAt == nil || time.Now().After(*user.EmailTokenExpiresAt) ||
+		subtle.ConstantTimeCompare([]byte(user.EmailTokenHash), []byte(tokenHash)) != 1 {
+		tx.Rollback()
+		return errors.New("invalid or expired token")
+	}
+
+	if err := tx.WithContext(ctx).Model(&schema.User{}).Where("email = ? AND id <> ?", user.PendingEmail, id).
+		Count(&count).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if count > 0 {
+		tx.Rollback()
+		return errors.New("email already registered")
+	}
+
+	if err := tx.WithContext(ctx).Model(&schema.User{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"email":                  user.PendingEmail,
+			"pending_email":          "",
+			"email_token_hash":       "",
+			"email_token_expires_at": nil,
+			"version":                gorm.Expr("version + 1"),
+		}).Error; err != nil {
+		tx.Rollback()
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+			return errors.New("email already registered")
+		}
+		return err
+	}
+
+	return tx.Commit().Error
+}
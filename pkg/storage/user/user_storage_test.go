@@ -0,0 +1,65 @@
+package user
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"kanggo/pkg/dbtest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfirmEmail(t *testing.T) {
+	ctx := context.Background()
+	columns := []string{"id", "email", "pending_email", "email_token_hash", "email_token_expires_at"}
+
+	t.Run("success", func(t *testing.T) {
+		native, db, mock := dbtest.New(t)
+		s := NewUserStorage(native, db)
+
+		mock.Expect("Begin")
+		mock.Expect("SELECT \\* FROM `users` .* FOR UPDATE").
+			Rows(columns, []driver.Value{int64(1), "agung@gmail.com", "bayu@gmail.com", "hash", time.Now().Add(time.Hour)})
+		mock.Expect("SELECT count\\(\\*\\) FROM `users`").Rows([]string{"count"}, []driver.Value{int64(0)})
+		swap := mock.Expect("UPDATE `users` SET `email`=\\?,`email_token_expires_at`=\\?,`email_token_hash`=\\?,`pending_email`=\\?")
+		mock.Expect("Commit")
+
+		err := s.ConfirmEmail(ctx, 1, "hash")
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.Done())
+		assert.Equal(t, []driver.Value{"bayu@gmail.com", nil, "", ""}, swap.Args[:4])
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		native, db, mock := dbtest.New(t)
+		s := NewUserStorage(native, db)
+
+		mock.Expect("Begin")
+		mock.Expect("SELECT \\* FROM `users` .* FOR UPDATE").
+			Rows(columns, []driver.Value{int64(1), "agung@gmail.com", "bayu@gmail.com", "hash", time.Now().Add(-time.Minute)})
+		mock.Expect("Rollback")
+
+		err := s.ConfirmEmail(ctx, 1, "hash")
+
+		assert.EqualError(t, err, "invalid or expired token")
+		assert.NoError(t, mock.Done())
+	})
+
+	t.Run("wrong token", func(t *testing.T) {
+		native, db, mock := dbtest.New(t)
+		s := NewUserStorage(native, db)
+
+		mock.Expect("Begin")
+		mock.Expect("SELECT \\* FROM `users` .* FOR UPDATE").
+			Rows(columns, []driver.Value{int64(1), "agung@gmail.com", "bayu@gmail.com", "hash", time.Now().Add(time.Hour)})
+		mock.Expect("Rollback")
+
+		err := s.ConfirmEmail(ctx, 1, "other")
+
+		assert.EqualError(t, err, "invalid or expired token")
+		assert.NoError(t, mock.Done())
+	})
+}
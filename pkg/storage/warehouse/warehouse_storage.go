@@ -0,0 +1,600 @@
+package warehouse
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/entity/schema"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate mockery --name WarehouseStorage --case snake --output ../../mocks --disable-version-string
+
+type (
+	WarehouseStorage interface {
+		Insert(ctx context.Context, data schema.Warehouse) error
+		Update(ctx context.Context, data schema.Warehouse) error
+		GetAll(ctx context.Context) ([]schema.Warehouse, error)
+		GetById(ctx context.Context, id int64) (*schema.Warehouse, error)
+		Delete(ctx context.Context, id int64) error
+
+		GetStock(ctx context.Context, warehouseId int64) ([]model.StockLevelResponse, error)
+		SetStock(ctx context.Context, data schema.WarehouseStock, actorId int64, note string) error
+		Transfer(ctx context.Context, data model.StockTransferRequest, actorId int64) (string, error)
+		GetMovements(ctx context.Context, warehouseId, variantId int64, query model.ListQuery) ([]schema.StockMovement, int64, error)
+	}
+
+	warehouseStorage struct {
+		Native *sql.DB
+		Gorm   *gorm.DB
+	}
+)
+
+func NewWarehouseStorage(native *sql.DB, gorm *gorm.DB) WarehouseStorage {
+	return &warehouseStorage{
+		Native: native,
+		Gorm:   gorm,
+	}
+}
+
+// Insert adds a warehouse. The first warehouse becomes the default one and
+// takes over the stock of every variant, so that from then on the stock of
+// a variant is the sum of its warehouse stock.
+func (w *warehouseStorage) Insert(ctx context.Context, data schema.Warehouse) error {
+	var count int64
+
+	tx := w.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	if err := checkCode(tx.WithContext(ctx), data); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Model(&schema.Warehouse{}).Count(&count).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if count == 0 {
+		data.IsDefault = true
+	}
+
+	if data.IsDefault {
+		if !data.Active {
+			tx.Rollback()
+			return errors.New("the default warehouse must be active")
+		}
+
+		if err := tx.WithContext(ctx).Model(&schema.Warehouse{}).Where("is_default = ?", true).
+			Update("is_default", false).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := tx.WithContext(ctx).Create(&data).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if count == 0 {
+		if err := tx.WithContext(ctx).Exec(`INSERT INTO warehouse_stocks (warehouse_id, variant_id, product_id, qty)
+		SELECT ?, id, product_id, qty FROM product_variants WHERE qty > 0`, data.Id).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if err := tx.WithContext(ctx).Exec(`INSERT INTO stock_movements (warehouse_id, variant_id, product_id, qty, type, note)
+		SELECT ?, id, product_id, qty, ?, 'opening stock' FROM product_variants WHERE qty > 0`,
+			data.Id, schema.MovementAdjustment).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+// Update changes the warehouse. Another warehouse takes over as the default
+// one by being made the default, not by the default one giving it up.
+func (w *warehouseStorage) Update(ctx context.Context, data schema.Warehouse) error {
+	var current schema.Warehouse
+
+	tx := w.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	if err := lockWarehouse(tx.WithContext(ctx), int64(data.Id), &current); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := checkCode(tx.WithContext(ctx), data); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if current.IsDefault && !data.IsDefault {
+		tx.Rollback()
+		return errors.New("make another warehouse the default instead")
+	}
+
+	if data.IsDefault && !data.Active {
+		tx.Rollback()
+		return errors.New("the default warehouse must be active")
+	}
+
+	if data.IsDefault && !current.IsDefault {
+		if err := tx.WithContext(ctx).Model(&schema.Warehouse{}).Where("is_default = ?", true).
+			Update("is_default", false).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := tx.WithContext(ctx).Model(&schema.Warehouse{}).Where("id = ?", data.Id).
+		Updates(map[string]interface{}{
+			"code":        data.Code,
+			"name":        data.Name,
+			"street":      data.Street,
+			"province":    data.Province,
+			"city":        data.City,
+			"postal_code": data.PostalCode,
+			"is_default":  data.IsDefault,
+			"active":      data.Active,
+			"version":     gorm.Expr("version + 1"),
+		}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func (w *warehouseStorage) GetAll(ctx context.Context) ([]schema.Warehouse, error) {
+	qry := `SELECT id, created_at, updated_at, code, name, COALESCE(street,""), province, city,
+	COALESCE(postal_code,""), is_default, active, version
+	FROM warehouses
+	ORDER BY is_default DESC, code
+	`
+
+	rows, err := w.Native.QueryContext(ctx, qry)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	warehouses := []schema.Warehouse{}
+	for rows.Next() {
+		var res schema.Warehouse
+		if err := rows.Scan(&res.Id, &res.CreatedAt, &res.UpdatedAt, &res.Code, &res.Name, &res.Street,
+			&res.Province, &res.City, &res.PostalCode, &res.IsDefault, &res.Active, &res.Version); err != nil {
+			return nil, err
+		}
+		warehouses = append(warehouses, res)
+	}
+
+	return warehouses, nil
+}
+
+func (w *warehouseStorage) GetById(ctx context.Context, id int64) (*schema.Warehouse, error) {
+	var res schema.Warehouse
+	qry := `SELECT id, created_at, updated_at, code, name, COALESCE(street,""), province, city,
+	COALESCE(postal_code,""), is_default, active, version
+	FROM warehouses
+	WHERE id = ?
+	`
+
+	if err := w.Native.QueryRowContext(ctx, qry, id).Scan(&res.Id, &res.CreatedAt, &res.UpdatedAt, &res.Code,
+		&res.Name, &res.Street, &res.Province, &res.City, &res.PostalCode, &res.IsDefault, &res.Active,
+		&res.Version); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// Delete removes an empty warehouse that no pending order ships from. Its
+// stock movements stay as history.
+func (w *warehouseStorage) Delete(ctx context.Context, id int64) error {
+	var current schema.Warehouse
+	var stock, orders int64
+
+	tx := w.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	if err := lockWarehouse(tx.WithContext(ctx), id, &current); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if current.IsDefault {
+		tx.Rollback()
+		return errors.New("the default warehouse cannot be deleted")
+	}
+
+	if err := tx.WithContext(ctx).Model(&schema.WarehouseStock{}).Where("warehouse_id = ?", id).
+		Select("COALESCE(SUM(qty), 0)").Scan(&stock).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if stock > 0 {
+		tx.Rollback()
+		return errors.New("warehouse still holds stock")
+	}
+
+	if err := tx.WithContext(ctx).Model(&schema.Order{}).Where("warehouse_id = ? AND status = 'pending'", id).
+		Count(&orders).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if orders > 0 {
+		tx.Rollback()
+		return errors.New("warehouse has pending orders")
+	}
+
+	if err := tx.WithContext(ctx).Model(&schema.PurchaseOrder{}).Where("warehouse_id = ? AND status <> ?", id, schema.PurchaseReceived).
+		Count(&orders).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if orders > 0 {
+		tx.Rollback()
+		return errors.New("warehouse has open purchase orders")
+	}
+
+	if err := tx.WithContext(ctx).Where("warehouse_id = ?", id).Delete(&schema.WarehouseStock{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Delete(&schema.Warehouse{}, id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// GetStock lists the stock the warehouse holds of the products outside the
+// trash.
+func (w *warehouseStorage) GetStock(ctx context.Context, warehouseId int64) ([]model.StockLevelResponse, error) {
+	qry := `SELECT s.variant_id, s.product_id, p.name, v.sku, s.qty
+	FROM warehouse_stocks s
+	JOIN product_variants v ON v.id = s.variant_id
+	JOIN products p ON p.id = s.product_id
+	WHERE s.warehouse_id = ? AND p.deleted_at IS NULL
+	ORDER BY p.name, v.sku
+	`
+
+	rows, err := w.Native.QueryContext(ctx, qry, warehouseId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stocks := []model.StockLevelResponse{}
+	for rows.Next() {
+		var res model.StockLevelResponse
+		if err := rows.Scan(&res.VariantId, &res.ProductId, &res.ProductName, &res.Sku, &res.Qty); err != nil {
+			return nil, err
+		}
+		stocks = append(stocks, res)
+	}
+
+	return stocks, nil
+}
+
+// SetStock sets the stock of a variant in the warehouse to data.Qty and
+// records the difference as an adjustment. The variant and its product take
+// the new total.
+func (w *warehouseStorage) SetStock(ctx context.Context, data schema.WarehouseStock, actorId int64, note string) error {
+	var warehouse schema.Warehouse
+	var variant schema.ProductVariant
+	var current schema.WarehouseStock
+
+	tx := w.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	if err := lockWarehouse(tx.WithContext(ctx), data.WarehouseId, &warehouse); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Select("id", "product_id").First(&variant, data.VariantId).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("variant not found")
+		}
+		return err
+	}
+	data.ProductId = variant.ProductId
+
+	err := tx.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("warehouse_id = ? AND variant_id = ?", data.WarehouseId, data.VariantId).First(&current).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		tx.Rollback()
+		return err
+	}
+
+	change := data.Qty - current.Qty
+	if change == 0 {
+		return tx.Commit().Error
+	}
+
+	if err := addStock(tx.WithContext(ctx), data.WarehouseId, data.VariantId, data.ProductId, change); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Create(&schema.StockMovement{
+		WarehouseId: data.WarehouseId,
+		VariantId:   data.VariantId,
+		ProductId:   data.ProductId,
+		Qty:         change,
+		Type:        schema.MovementAdjustment,
+		ActorId:     actorId,
+		Note:        note,
+	}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := syncStock(tx.WithContext(ctx), data.ProductId, data.VariantId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// Transfer moves stock of a variant from one warehouse to another. The two
+// movements share a reference, which is returned. The total stock of the
+// variant does not change.
+func (w *warehouseStorage) Transfer(ctx context.Context, data model.StockTransferRequest, actorId int64) (string, error) {
+	var from, to schema.Warehouse
+	var variant schema.ProductVariant
+
+	tx := w.Gorm.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return "", err
+	}
+
+	// the warehouses are locked in id order, so opposite transfers cannot
+	// deadlock
+	first, second := &from, &to
+	firstId, secondId := data.FromWarehouseId, data.ToWarehouseId
+	if firstId > secondId {
+		first, second = second, first
+		firstId, secondId = secondId, firstId
+	}
+
+	for _, lock := range []struct {
+		id        int64
+		warehouse *schema.Warehouse
+	}{{firstId, first}, {secondId, second}} {
+		if err := lockWarehouse(tx.WithContext(ctx), lock.id, lock.warehouse); err != nil {
+			tx.Rollback()
+			if err.Error() == "data not found" {
+				return "", errors.New("warehouse not found")
+			}
+			return "", err
+		}
+	}
+
+	if err := tx.WithContext(ctx).Select("id", "product_id").First(&variant, data.VariantId).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", errors.New("variant not found")
+		}
+		return "", err
+	}
+
+	result := tx.WithContext(ctx).Model(&schema.WarehouseStock{}).
+		Where("warehouse_id = ? AND variant_id = ? AND qty >= ?", data.FromWarehouseId, data.VariantId, data.Qty).
+		Update("qty", gorm.Expr("qty - ?", data.Qty))
+	if result.Error != nil {
+		tx.Rollback()
+		return "", result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return "", errors.New("not enough stock in the warehouse")
+	}
+
+	if err := addStock(tx.WithContext(ctx), data.ToWarehouseId, data.VariantId, variant.ProductId, data.Qty); err != nil {
+		tx.Rollback()
+		return "", err
+	}
+
+	out := schema.StockMovement{
+		WarehouseId: data.FromWarehouseId,
+		VariantId:   data.VariantId,
+		ProductId:   variant.ProductId,
+		Qty:         -data.Qty,
+		Type:        schema.MovementTransferOut,
+		ActorId:     actorId,
+		Note:        data.Note,
+	}
+	if err := tx.WithContext(ctx).Create(&out).Error; err != nil {
+		tx.Rollback()
+		return "", err
+	}
+
+	reference := fmt.Sprintf("TRF-%06d", out.Id)
+	if err := tx.WithContext(ctx).Model(&schema.StockMovement{}).Where("id = ?", out.Id).
+		Update("reference", reference).Error; err != nil {
+		tx.Rollback()
+		return "", err
+	}
+
+	if err := tx.WithContext(ctx).Create(&schema.StockMovement{
+		WarehouseId: data.ToWarehouseId,
+		VariantId:   data.VariantId,
+		ProductId:   variant.ProductId,
+		Qty:         data.Qty,
+		Type:        schema.MovementTransferIn,
+		Reference:   reference,
+		ActorId:     actorId,
+		Note:        data.Note,
+	}).Error; err != nil {
+		tx.Rollback()
+		return "", err
+	}
+
+	// the per-warehouse stock is part of the product a client reads
+	if err := tx.WithContext(ctx).Model(&schema.Product{}).Where("id = ?", variant.ProductId).
+		Update("version", gorm.Expr("version + 1")).Error; err != nil {
+		tx.Rollback()
+		return "", err
+	}
+
+	return reference, tx.Commit().Error
+}
+
+// GetMovements returns a page of the stock movements of the warehouse,
+// newest first, of one variant when variantId is set.
+func (w *warehouseStorage) GetMovements(ctx context.Context, warehouseId, variantId int64, query model.ListQuery) ([]schema.StockMovement, int64, error) {
+	var total int64
+	where := "warehouse_id = ?"
+	args := []interface{}{warehouseId}
+
+	if variantId > 0 {
+		where += " AND variant_id = ?"
+		args = append(args, variantId)
+	}
+
+	if err := w.Native.QueryRowContext(ctx, `SELECT COUNT(*) FROM stock_movements WHERE `+where, args...).
+		Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	if query.Cursor > 0 {
+		where += " AND id < ?"
+		args = append(args, query.Cursor)
+	}
+
+	qry := `SELECT id, created_at, updated_at, warehouse_id, variant_id, product_id, qty, type,
+	COALESCE(reference,""), order_id, actor_id, COALESCE(note,"")
+	FROM stock_movements
+	WHERE ` + where + `
+	ORDER BY id DESC
+	LIMIT ? OFFSET ?`
+
+	rows, err := w.Native.QueryContext(ctx, qry, append(args, query.Size, query.Offset())...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	movements := []schema.StockMovement{}
+	for rows.Next() {
+		var res schema.StockMovement
+		if err := rows.Scan(&res.Id, &res.CreatedAt, &res.UpdatedAt, &res.WarehouseId, &res.VariantId,
+			&res.ProductId, &res.Qty, &res.Type, &res.Reference, &res.OrderId, &res.ActorId, &res.Note); err != nil {
+			return nil, 0, err
+		}
+		movements = append(movements, res)
+	}
+
+	return movements, total, nil
+}
+
+func lockWarehouse(tx *gorm.DB, id int64, warehouse *schema.Warehouse) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(warehouse, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("data not found")
+		}
+		return err
+	}
+
+	return nil
+}
+
+func checkCode(tx *gorm.DB, data schema.Warehouse) error {
+	var count int64
+	if err := tx.Model(&schema.Warehouse{}).Where("code = ? AND id <> ?", data.Code, data.Id).
+		Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return errors.New("code already exists")
+	}
+
+	return nil
+}
+
+// addStock adds qty, which may be negative, to the stock of the variant in
+// the warehouse, creating the row on the first delivery.
+func addStock(tx *gorm.DB, warehouseId, variantId, productId, qty int64) error {
+	return tx.Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]interface{}{"qty": gorm.Expr("qty + ?", qty)}),
+	}).Create(&schema.WarehouseStock{
+		WarehouseId: warehouseId,
+		VariantId:   variantId,
+		ProductId:   productId,
+		Qty:         qty,
+	}).Error
+}
+
+// syncStock sets the qty of the variant to its total warehouse stock and
+// that of the product to the total of its variants.
+func syncStock(tx *gorm.DB, productId, variantId int64) error {
+	if err := tx.Exec(`UPDATE product_variants SET
+	qty = (SELECT COALESCE(SUM(qty), 0) FROM warehouse_stocks WHERE variant_id = ?)
+	WHERE id = ?`, variantId, variantId).Error; err != nil {
+		return err
+	}
+
+	return tx.Exec(`UPDATE products SET
+	qty = (SELECT COALESCE(SUM(qty), 0) FROM product_variants WHERE product_id = ?),
+	version = version + 1
+	WHERE id = ?`, productId, productId).Error
+}
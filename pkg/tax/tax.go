@@ -0,0 +1,129 @@
+package tax
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"time"
+)
+
+type (
+	// Rule is a VAT rate valid between EffectiveFrom (inclusive) and
+	// EffectiveTo (exclusive, nil means open ended). Products whose tax
+	// category is listed in ExemptCategories are not taxed.
+	Rule struct {
+		Name             string     `json:"name"`
+		Rate             float64    `json:"rate"`
+		Inclusive        bool       `json:"inclusive"`
+		ExemptCategories []string   `json:"exempt_categories"`
+		EffectiveFrom    time.Time  `json:"effective_from"`
+		EffectiveTo      *time.Time `json:"effective_to"`
+	}
+
+	// Result is the tax treatment of one order line. Gross is what the buyer
+	// pays for the line, Net is the taxable base.
+	Result struct {
+		Rule      string
+		Rate      float64
+		Inclusive bool
+		Net       float64
+		Tax       float64
+		Gross     float64
+	}
+
+	Engine struct {
+		rules []Rule
+	}
+)
+
+func NewEngine(rules []Rule) *Engine {
+	return &Engine{
+		rules: rules,
+	}
+}
+
+// LoadEngine reads the tax rules from a JSON file of the form
+// {"rules": [Rule, ...]}.
+func LoadEngine(path string) (*Engine, error) {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var config struct {
+		Rules []Rule `json:"rules"`
+	}
+	if err := json.Unmarshal(file, &config); err != nil {
+		return nil, err
+	}
+
+	return NewEngine(config.Rules), nil
+}
+
+// Rule returns the rule in effect at the given time. When rules overlap the
+// one that started last wins.
+func (e *Engine) Rule(at time.Time) *Rule {
+	var current *Rule
+	for i := range e.rules {
+		rule := &e.rules[i]
+		if at.Before(rule.EffectiveFrom) {
+			continue
+		}
+		if rule.EffectiveTo != nil && !at.Before(*rule.EffectiveTo) {
+			continue
+		}
+		if current == nil || rule.EffectiveFrom.After(current.EffectiveFrom) {
+			current = rule
+		}
+	}
+
+	return current
+}
+
+// Calculate applies the rule in effect at the given time to a line amount.
+// With inclusive pricing the amount already contains the tax, otherwise the
+// tax is added on top. Without a rule, or for an exempt category, the line is
+// untaxed.
+func (e *Engine) Calculate(at time.Time, category string, amount float64) Result {
+	rule := e.Rule(at)
+	if rule == nil || rule.exempt(category) {
+		result := Result{Net: amount, Gross: amount}
+		if rule != nil {
+			result.Rule = rule.Name
+			result.Inclusive = rule.Inclusive
+		}
+		return result
+	}
+
+	result := Result{
+		Rule:      rule.Name,
+		Rate:      rule.Rate,
+		Inclusive: rule.Inclusive,
+	}
+
+	if rule.Inclusive {
+		result.Gross = amount
+		result.Tax = round(amount * rule.Rate / (1 + rule.Rate))
+		result.Net = round(amount - result.Tax)
+	} else {
+		result.Net = amount
+		result.Tax = round(amount * rule.Rate)
+		result.Gross = round(amount + result.Tax)
+	}
+
+	return result
+}
+
+func (r *Rule) exempt(category string) bool {
+	for _, c := range r.ExemptCategories {
+		if c == category {
+			return true
+		}
+	}
+
+	return false
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,63 @@
+package tax
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculate(t *testing.T) {
+	switchover := time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC)
+	engine := NewEngine([]Rule{
+		{
+			Name:             "PPN 10%",
+			Rate:             0.10,
+			ExemptCategories: []string{"basic_goods"},
+			EffectiveFrom:    time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC),
+			EffectiveTo:      &switchover,
+		},
+		{
+			Name:             "PPN 11%",
+			Rate:             0.11,
+			Inclusive:        true,
+			ExemptCategories: []string{"basic_goods"},
+			EffectiveFrom:    switchover,
+		},
+	})
+
+	t.Run("exclusive before switchover", func(t *testing.T) {
+		res := engine.Calculate(switchover.Add(-time.Second), "standard", 100000)
+
+		assert.Equal(t, "PPN 10%", res.Rule)
+		assert.Equal(t, float64(100000), res.Net)
+		assert.Equal(t, float64(10000), res.Tax)
+		assert.Equal(t, float64(110000), res.Gross)
+	})
+
+	t.Run("inclusive from switchover", func(t *testing.T) {
+		res := engine.Calculate(switchover, "standard", 111000)
+
+		assert.Equal(t, "PPN 11%", res.Rule)
+		assert.True(t, res.Inclusive)
+		assert.Equal(t, float64(100000), res.Net)
+		assert.Equal(t, float64(11000), res.Tax)
+		assert.Equal(t, float64(111000), res.Gross)
+	})
+
+	t.Run("exempt category", func(t *testing.T) {
+		res := engine.Calculate(switchover, "basic_goods", 50000)
+
+		assert.Equal(t, float64(0), res.Rate)
+		assert.Equal(t, float64(0), res.Tax)
+		assert.Equal(t, float64(50000), res.Gross)
+	})
+
+	t.Run("no rule in effect", func(t *testing.T) {
+		res := engine.Calculate(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), "standard", 50000)
+
+		assert.Equal(t, "", res.Rule)
+		assert.Equal(t, float64(50000), res.Net)
+		assert.Equal(t, float64(50000), res.Gross)
+	})
+}
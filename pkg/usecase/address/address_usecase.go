@@ -0,0 +1,137 @@
+package address
+
+import (
+	"context"
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/entity/schema"
+	storage "kanggo/pkg/storage/address"
+)
+
+//go:generate mockery --name AddressUsecase --case snake --output ../../mocks --disable-version-string
+
+type (
+	AddressUsecase interface {
+		Insert(ctx context.Context, userId uint64, data model.AddressRequest) error
+		Update(ctx context.Context, id int64, userId uint64, data model.AddressRequest) error
+		GetAll(ctx context.Context, userId uint64) ([]model.AddressResponse, error)
+		GetById(ctx context.Context, id int64, userId uint64) (*model.AddressResponse, error)
+		Delete(ctx context.Context, id int64, userId uint64) error
+		SetDefault(ctx context.Context, id int64, userId uint64) error
+	}
+
+	addressUsecase struct {
+		addressStorage storage.AddressStorage
+	}
+)
+
+func NewAddressUsecase(addressStorage storage.AddressStorage) AddressUsecase {
+	return &addressUsecase{
+		addressStorage: addressStorage,
+	}
+}
+
+func (a *addressUsecase) Insert(ctx context.Context, userId uint64, data model.AddressRequest) error {
+	request := schema.Address{
+		UserId:     int64(userId),
+		Recipient:  data.Recipient,
+		Phone:      data.Phone,
+		Street:     data.Street,
+		Province:   data.Province,
+		City:       data.City,
+		District:   data.District,
+		PostalCode: data.PostalCode,
+		IsDefault:  data.IsDefault,
+	}
+
+	if err := a.addressStorage.Insert(ctx, request); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (a *addressUsecase) Update(ctx context.Context, id int64, userId uint64, data model.AddressRequest) error {
+	res, err := a.addressStorage.GetById(ctx, id, userId)
+	if err != nil {
+		return err
+	}
+
+	request := schema.Address{
+		Base:       schema.Base{Id: res.Id},
+		UserId:     int64(userId),
+		Recipient:  data.Recipient,
+		Phone:      data.Phone,
+		Street:     data.Street,
+		Province:   data.Province,
+		City:       data.City,
+		District:   data.District,
+		PostalCode: data.PostalCode,
+	}
+
+	if err := a.addressStorage.Update(ctx, request); err != nil {
+		return err
+	}
+
+	if data.IsDefault && !res.IsDefault {
+		if err := a.addressStorage.SetDefault(ctx, id, userId); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *addressUsecase) GetAll(ctx context.Context, userId uint64) ([]model.AddressResponse, error) {
+	res, err := a.addressStorage.GetAll(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []model.AddressResponse{}
+	for i := range res {
+		results = append(results, toAddressResponse(res[i]))
+	}
+
+	return results, nil
+}
+
+func (a *addressUsecase) GetById(ctx context.Context, id int64, userId uint64) (*model.AddressResponse, error) {
+	res, err := a.addressStorage.GetById(ctx, id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	address := toAddressResponse(*res)
+
+	return &address, nil
+}
+
+func (a *addressUsecase) Delete(ctx context.Context, id int64, userId uint64) error {
+	if err := a.addressStorage.Delete(ctx, id, userId); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (a *addressUsecase) SetDefault(ctx context.Context, id int64, userId uint64) error {
+	if err := a.addressStorage.SetDefault(ctx, id, userId); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func toAddressResponse(res schema.Address) model.AddressResponse {
+	return model.AddressResponse{
+		Id:         int(res.Id),
+		Recipient:  res.Recipient,
+		Phone:      res.Phone,
+		Street:     res.Street,
+		Province:   res.Province,
+		City:       res.City,
+		District:   res.District,
+		PostalCode: res.PostalCode,
+		IsDefault:  res.IsDefault,
+	}
+}
@@ -0,0 +1,104 @@
+package address
+
+import (
+	"context"
+	"testing"
+
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/entity/schema"
+	"kanggo/pkg/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestInsert(t *testing.T) {
+	mockAddressStorage := new(mocks.AddressStorage)
+	a := NewAddressUsecase(mockAddressStorage)
+	ctx := context.Background()
+	var userId uint64 = 1
+
+	model := model.AddressRequest{
+		Recipient:  "Agung",
+		Phone:      "081234567890",
+		Street:     "Jl. Sudirman No. 1",
+		Province:   "DKI Jakarta",
+		City:       "Jakarta Selatan",
+		District:   "Setiabudi",
+		PostalCode: "12910",
+	}
+	schema := schema.Address{
+		UserId:     1,
+		Recipient:  "Agung",
+		Phone:      "081234567890",
+		Street:     "Jl. Sudirman No. 1",
+		Province:   "DKI Jakarta",
+		City:       "Jakarta Selatan",
+		District:   "Setiabudi",
+		PostalCode: "12910",
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockAddressStorage.On("Insert", mock.Anything, schema).Return(nil)
+
+		err := a.Insert(ctx, userId, model)
+
+		assert.Nil(t, err)
+		assert.NoError(t, err)
+		mockAddressStorage.AssertExpectations(t)
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	mockAddressStorage := new(mocks.AddressStorage)
+	a := NewAddressUsecase(mockAddressStorage)
+	ctx := context.Background()
+	var id int64 = 2
+	var userId uint64 = 1
+
+	request := model.AddressRequest{
+		Recipient:  "Agung",
+		Phone:      "081234567890",
+		Street:     "Jl. Thamrin No. 2",
+		Province:   "DKI Jakarta",
+		City:       "Jakarta Pusat",
+		District:   "Menteng",
+		PostalCode: "10310",
+		IsDefault:  true,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockAddressStorage.On("GetById", mock.Anything, id, userId).Return(&schema.Address{Base: schema.Base{Id: 2}, UserId: 1}, nil)
+		mockAddressStorage.On("Update", mock.Anything, mock.AnythingOfType("schema.Address")).Return(nil)
+		mockAddressStorage.On("SetDefault", mock.Anything, id, userId).Return(nil)
+
+		err := a.Update(ctx, id, userId, request)
+
+		assert.Nil(t, err)
+		assert.NoError(t, err)
+		mockAddressStorage.AssertExpectations(t)
+	})
+}
+
+func TestGetAll(t *testing.T) {
+	mockAddressStorage := new(mocks.AddressStorage)
+	a := NewAddressUsecase(mockAddressStorage)
+	ctx := context.Background()
+	var userId uint64 = 1
+
+	mockAddressList := []schema.Address{
+		{Base: schema.Base{Id: 1}, UserId: 1, Recipient: "Agung", City: "Jakarta Selatan", IsDefault: true},
+		{Base: schema.Base{Id: 2}, UserId: 1, Recipient: "Agung", City: "Bandung"},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockAddressStorage.On("GetAll", mock.Anything, userId).Return(mockAddressList, nil)
+
+		list, err := a.GetAll(ctx, userId)
+
+		assert.NoError(t, err)
+		assert.Len(t, list, len(mockAddressList))
+		assert.True(t, list[0].IsDefault)
+		mockAddressStorage.AssertExpectations(t)
+	})
+}
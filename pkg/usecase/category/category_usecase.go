@@ -0,0 +1,179 @@
+package category
+
+import (
+	"context"
+	"errors"
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/entity/schema"
+	storage "kanggo/pkg/storage/category"
+	"regexp"
+	"strings"
+)
+
+//go:generate mockery --name CategoryUsecase --case snake --output ../../mocks --disable-version-string
+
+type (
+	CategoryUsecase interface {
+		Insert(ctx context.Context, data model.CategoryRequest) error
+		Update(ctx context.Context, id int64, data model.CategoryRequest) error
+		GetTree(ctx context.Context) ([]model.CategoryResponse, error)
+		GetBySlug(ctx context.Context, slug string) (*model.CategoryResponse, error)
+		Delete(ctx context.Context, id int64) error
+		SetProductCategories(ctx context.Context, productId int64, data model.ProductCategoryRequest) error
+		GetProductCategories(ctx context.Context, productId int64) ([]model.CategoryResponse, error)
+	}
+
+	categoryUsecase struct {
+		categoryStorage storage.CategoryStorage
+	}
+)
+
+var (
+	slugPattern   = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	slugSeparator = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+func NewCategoryUsecase(categoryStorage storage.CategoryStorage) CategoryUsecase {
+	return &categoryUsecase{
+		categoryStorage: categoryStorage,
+	}
+}
+
+func (c *categoryUsecase) Insert(ctx context.Context, data model.CategoryRequest) error {
+	request, err := toCategory(data)
+	if err != nil {
+		return err
+	}
+
+	if err := c.categoryStorage.Insert(ctx, request); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *categoryUsecase) Update(ctx context.Context, id int64, data model.CategoryRequest) error {
+	request, err := toCategory(data)
+	if err != nil {
+		return err
+	}
+	request.Id = uint(id)
+
+	if err := c.categoryStorage.Update(ctx, request); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetTree returns the root categories with their subcategories nested, each
+// level ordered by sort order and name.
+func (c *categoryUsecase) GetTree(ctx context.Context) ([]model.CategoryResponse, error) {
+	res, err := c.categoryStorage.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	children := map[int64][]schema.Category{}
+	roots := []schema.Category{}
+	for i := range res {
+		if res[i].ParentId == nil {
+			roots = append(roots, res[i])
+			continue
+		}
+		children[*res[i].ParentId] = append(children[*res[i].ParentId], res[i])
+	}
+
+	var build func(categories []schema.Category) []model.CategoryResponse
+	build = func(categories []schema.Category) []model.CategoryResponse {
+		results := []model.CategoryResponse{}
+		for _, category := range categories {
+			rest := toCategoryResponse(category)
+			if sub := children[int64(category.Id)]; len(sub) > 0 {
+				rest.Children = build(sub)
+			}
+			results = append(results, rest)
+		}
+		return results
+	}
+
+	return build(roots), nil
+}
+
+func (c *categoryUsecase) GetBySlug(ctx context.Context, slug string) (*model.CategoryResponse, error) {
+	res, err := c.categoryStorage.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	category := toCategoryResponse(*res)
+
+	return &category, nil
+}
+
+func (c *categoryUsecase) Delete(ctx context.Context, id int64) error {
+	if err := c.categoryStorage.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *categoryUsecase) SetProductCategories(ctx context.Context, productId int64, data model.ProductCategoryRequest) error {
+	seen := map[int64]bool{}
+	categoryIds := []int64{}
+	for _, id := range data.CategoryIds {
+		if !seen[id] {
+			seen[id] = true
+			categoryIds = append(categoryIds, id)
+		}
+	}
+
+	if err := c.categoryStorage.SetProductCategories(ctx, productId, categoryIds); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *categoryUsecase) GetProductCategories(ctx context.Context, productId int64) ([]model.CategoryResponse, error) {
+	res, err := c.categoryStorage.GetProductCategories(ctx, productId)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []model.CategoryResponse{}
+	for i := range res {
+		results = append(results, toCategoryResponse(res[i]))
+	}
+
+	return results, nil
+}
+
+func toCategory(data model.CategoryRequest) (schema.Category, error) {
+	slug := strings.ToLower(strings.TrimSpace(data.Slug))
+	if slug == "" {
+		slug = strings.Trim(slugSeparator.ReplaceAllString(strings.ToLower(data.Name), "-"), "-")
+	}
+
+	if !slugPattern.MatchString(slug) {
+		return schema.Category{}, errors.New("invalid slug")
+	}
+
+	return schema.Category{
+		ParentId:  data.ParentId,
+		Name:      strings.TrimSpace(data.Name),
+		Slug:      slug,
+		SortOrder: data.SortOrder,
+	}, nil
+}
+
+func toCategoryResponse(res schema.Category) model.CategoryResponse {
+	return model.CategoryResponse{
+		Id:        int(res.Id),
+		ParentId:  res.ParentId,
+		Name:      res.Name,
+		Slug:      res.Slug,
+		SortOrder: res.SortOrder,
+	}
+}
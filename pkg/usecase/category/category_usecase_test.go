@@ -0,0 +1,97 @@
+package category
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/entity/schema"
+	"kanggo/pkg/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestInsert(t *testing.T) {
+	mockCategoryStorage := new(mocks.CategoryStorage)
+	u := NewCategoryUsecase(mockCategoryStorage)
+	ctx := context.Background()
+	parentId := int64(1)
+
+	t.Run("slug from name", func(t *testing.T) {
+		category := schema.Category{ParentId: &parentId, Name: "Semen & Mortar", Slug: "semen-mortar", SortOrder: 2}
+		mockCategoryStorage.On("Insert", mock.Anything, category).Return(nil).Once()
+
+		err := u.Insert(ctx, model.CategoryRequest{ParentId: &parentId, Name: " Semen & Mortar ", SortOrder: 2})
+
+		assert.NoError(t, err)
+		mockCategoryStorage.AssertExpectations(t)
+	})
+
+	t.Run("invalid slug", func(t *testing.T) {
+		err := u.Insert(ctx, model.CategoryRequest{Name: "Semen", Slug: "semen mortar"})
+
+		assert.EqualError(t, err, "invalid slug")
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	mockCategoryStorage := new(mocks.CategoryStorage)
+	u := NewCategoryUsecase(mockCategoryStorage)
+	ctx := context.Background()
+
+	t.Run("cycle", func(t *testing.T) {
+		parentId := int64(3)
+		category := schema.Category{Base: schema.Base{Id: 1}, ParentId: &parentId, Name: "Semen", Slug: "semen"}
+		mockCategoryStorage.On("Update", mock.Anything, category).Return(errors.New("category cannot be moved under itself")).Once()
+
+		err := u.Update(ctx, 1, model.CategoryRequest{ParentId: &parentId, Name: "Semen"})
+
+		assert.EqualError(t, err, "category cannot be moved under itself")
+		mockCategoryStorage.AssertExpectations(t)
+	})
+}
+
+func TestGetTree(t *testing.T) {
+	mockCategoryStorage := new(mocks.CategoryStorage)
+	u := NewCategoryUsecase(mockCategoryStorage)
+	ctx := context.Background()
+	root, semen := int64(1), int64(2)
+
+	mockCategories := []schema.Category{
+		{Base: schema.Base{Id: 1}, Name: "Bahan Bangunan", Slug: "bahan-bangunan"},
+		{Base: schema.Base{Id: 2}, ParentId: &root, Name: "Semen", Slug: "semen"},
+		{Base: schema.Base{Id: 3}, ParentId: &semen, Name: "Semen Putih", Slug: "semen-putih"},
+		{Base: schema.Base{Id: 4}, Name: "Cat", Slug: "cat"},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockCategoryStorage.On("GetAll", mock.Anything).Return(mockCategories, nil)
+
+		tree, err := u.GetTree(ctx)
+
+		assert.NoError(t, err)
+		assert.Len(t, tree, 2)
+		assert.Equal(t, "bahan-bangunan", tree[0].Slug)
+		assert.Equal(t, "semen", tree[0].Children[0].Slug)
+		assert.Equal(t, "semen-putih", tree[0].Children[0].Children[0].Slug)
+		assert.Empty(t, tree[1].Children)
+		mockCategoryStorage.AssertExpectations(t)
+	})
+}
+
+func TestSetProductCategories(t *testing.T) {
+	mockCategoryStorage := new(mocks.CategoryStorage)
+	u := NewCategoryUsecase(mockCategoryStorage)
+	ctx := context.Background()
+
+	t.Run("duplicates removed", func(t *testing.T) {
+		mockCategoryStorage.On("SetProductCategories", mock.Anything, int64(5), []int64{2, 3}).Return(nil)
+
+		err := u.SetProductCategories(ctx, 5, model.ProductCategoryRequest{CategoryIds: []int64{2, 3, 2}})
+
+		assert.NoError(t, err)
+		mockCategoryStorage.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,219 @@
+package coupon
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/entity/schema"
+	"kanggo/pkg/promotion"
+	categoryStorage "kanggo/pkg/storage/category"
+	storage "kanggo/pkg/storage/coupon"
+	"strings"
+	"time"
+)
+
+//go:generate mockery --name CouponUsecase --case snake --output ../../mocks --disable-version-string
+
+type (
+	CouponUsecase interface {
+		Insert(ctx context.Context, data model.CouponRequest) error
+		Update(ctx context.Context, id int64, data model.CouponRequest) error
+		GetAll(ctx context.Context) ([]model.CouponResponse, error)
+		GetById(ctx context.Context, id int64) (*model.CouponResponse, error)
+		Delete(ctx context.Context, id int64) error
+		Apply(ctx context.Context, userId uint64, data model.ApplyCouponRequest) (*model.ApplyCouponResponse, error)
+	}
+
+	couponUsecase struct {
+		couponStorage   storage.CouponStorage
+		categoryStorage categoryStorage.CategoryStorage
+	}
+)
+
+func NewCouponUsecase(couponStorage storage.CouponStorage, categoryStorage categoryStorage.CategoryStorage) CouponUsecase {
+	return &couponUsecase{
+		couponStorage:   couponStorage,
+		categoryStorage: categoryStorage,
+	}
+}
+
+func (c *couponUsecase) Insert(ctx context.Context, data model.CouponRequest) error {
+	request, err := toCoupon(data)
+	if err != nil {
+		return err
+	}
+
+	if err := c.couponStorage.Insert(ctx, request, toScope(data)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *couponUsecase) Update(ctx context.Context, id int64, data model.CouponRequest) error {
+	if _, err := c.couponStorage.GetById(ctx, id); err != nil {
+		return err
+	}
+
+	request, err := toCoupon(data)
+	if err != nil {
+		return err
+	}
+	request.Id = uint(id)
+
+	if err := c.couponStorage.Update(ctx, request, toScope(data)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *couponUsecase) GetAll(ctx context.Context) ([]model.CouponResponse, error) {
+	res, err := c.couponStorage.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []model.CouponResponse{}
+	for i := range res {
+		scope, err := c.couponStorage.GetScope(ctx, int64(res[i].Id))
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, toCouponResponse(res[i], scope))
+	}
+
+	return results, nil
+}
+
+func (c *couponUsecase) GetById(ctx context.Context, id int64) (*model.CouponResponse, error) {
+	res, err := c.couponStorage.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	scope, err := c.couponStorage.GetScope(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	coupon := toCouponResponse(*res, scope)
+
+	return &coupon, nil
+}
+
+func (c *couponUsecase) Delete(ctx context.Context, id int64) error {
+	if err := c.couponStorage.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Apply previews the discount of a coupon at checkout. Nothing is redeemed
+// until the order is placed with the same code.
+func (c *couponUsecase) Apply(ctx context.Context, userId uint64, data model.ApplyCouponRequest) (*model.ApplyCouponResponse, error) {
+	coupon, err := c.couponStorage.GetByCode(ctx, strings.ToUpper(data.Code))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("coupon not found")
+		}
+		return nil, err
+	}
+
+	scope, err := c.couponStorage.GetScope(ctx, int64(coupon.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	// the categories of the product only matter when the coupon targets some
+	var categoryIds []int64
+	if len(scope.CategoryIds) > 0 {
+		categoryIds, err = c.categoryStorage.GetProductCategoryIds(ctx, data.ProductId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	used, err := c.couponStorage.CountUserRedemptions(ctx, int64(coupon.Id), userId)
+	if err != nil {
+		return nil, err
+	}
+
+	discount, err := promotion.Discount(*coupon, scope, used, data.ProductId, categoryIds, data.Amount, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.ApplyCouponResponse{
+		Code:     coupon.Code,
+		Discount: discount,
+		Amount:   data.Amount - discount,
+	}, nil
+}
+
+func toCoupon(data model.CouponRequest) (schema.Coupon, error) {
+	if data.DiscountType == "percentage" && data.Value > 100 {
+		return schema.Coupon{}, errors.New("percentage discount cannot exceed 100")
+	}
+
+	if data.EndsAt != nil && !data.EndsAt.After(data.StartsAt) {
+		return schema.Coupon{}, errors.New("ends_at must be after starts_at")
+	}
+
+	return schema.Coupon{
+		Code:         strings.ToUpper(data.Code),
+		Description:  data.Description,
+		DiscountType: data.DiscountType,
+		Value:        data.Value,
+		MaxDiscount:  data.MaxDiscount,
+		MinSpend:     data.MinSpend,
+		UsageLimit:   data.UsageLimit,
+		PerUserLimit: data.PerUserLimit,
+		StartsAt:     data.StartsAt,
+		EndsAt:       data.EndsAt,
+		Active:       data.Active,
+	}, nil
+}
+
+func toScope(data model.CouponRequest) promotion.Scope {
+	return promotion.Scope{
+		ProductIds:  uniqueIds(data.ProductIds),
+		CategoryIds: uniqueIds(data.CategoryIds),
+	}
+}
+
+func toCouponResponse(res schema.Coupon, scope promotion.Scope) model.CouponResponse {
+	return model.CouponResponse{
+		Id:           int(res.Id),
+		Code:         res.Code,
+		Description:  res.Description,
+		DiscountType: res.DiscountType,
+		Value:        res.Value,
+		MaxDiscount:  res.MaxDiscount,
+		MinSpend:     res.MinSpend,
+		UsageLimit:   res.UsageLimit,
+		PerUserLimit: res.PerUserLimit,
+		UsedCount:    res.UsedCount,
+		StartsAt:     res.StartsAt,
+		EndsAt:       res.EndsAt,
+		Active:       res.Active,
+		ProductIds:   scope.ProductIds,
+		CategoryIds:  scope.CategoryIds,
+	}
+}
+
+func uniqueIds(ids []int64) []int64 {
+	seen := map[int64]bool{}
+	result := []int64{}
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+
+	return result
+}
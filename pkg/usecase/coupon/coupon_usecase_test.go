@@ -0,0 +1,162 @@
+package coupon
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/entity/schema"
+	"kanggo/pkg/mocks"
+	"kanggo/pkg/promotion"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var startsAt = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestInsert(t *testing.T) {
+	mockCouponStorage := new(mocks.CouponStorage)
+	u := NewCouponUsecase(mockCouponStorage, new(mocks.CategoryStorage))
+	ctx := context.Background()
+
+	request := model.CouponRequest{
+		Code:         "hemat10",
+		DiscountType: "percentage",
+		Value:        10,
+		StartsAt:     startsAt,
+		Active:       true,
+		ProductIds:   []int64{1, 2, 1},
+	}
+	coupon := schema.Coupon{
+		Code:         "HEMAT10",
+		DiscountType: "percentage",
+		Value:        10,
+		StartsAt:     startsAt,
+		Active:       true,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockCouponStorage.On("Insert", mock.Anything, coupon, promotion.Scope{ProductIds: []int64{1, 2}, CategoryIds: []int64{}}).Return(nil)
+
+		err := u.Insert(ctx, request)
+
+		assert.NoError(t, err)
+		mockCouponStorage.AssertExpectations(t)
+	})
+
+	t.Run("percentage above 100", func(t *testing.T) {
+		invalid := request
+		invalid.Value = 150
+
+		err := u.Insert(ctx, invalid)
+
+		assert.EqualError(t, err, "percentage discount cannot exceed 100")
+	})
+
+	t.Run("ends before start", func(t *testing.T) {
+		invalid := request
+		endsAt := startsAt.Add(-time.Hour)
+		invalid.EndsAt = &endsAt
+
+		err := u.Insert(ctx, invalid)
+
+		assert.EqualError(t, err, "ends_at must be after starts_at")
+	})
+}
+
+func TestGetById(t *testing.T) {
+	mockCouponStorage := new(mocks.CouponStorage)
+	u := NewCouponUsecase(mockCouponStorage, new(mocks.CategoryStorage))
+	ctx := context.Background()
+
+	coupon := schema.Coupon{
+		Base:         schema.Base{Id: 1},
+		Code:         "HEMAT10",
+		DiscountType: "percentage",
+		Value:        10,
+		StartsAt:     startsAt,
+		Active:       true,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockCouponStorage.On("GetById", mock.Anything, int64(1)).Return(&coupon, nil)
+		mockCouponStorage.On("GetScope", mock.Anything, int64(1)).Return(promotion.Scope{ProductIds: []int64{4}, CategoryIds: []int64{}}, nil)
+
+		res, err := u.GetById(ctx, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "HEMAT10", res.Code)
+		assert.Equal(t, []int64{4}, res.ProductIds)
+		mockCouponStorage.AssertExpectations(t)
+	})
+}
+
+func TestApply(t *testing.T) {
+	mockCouponStorage := new(mocks.CouponStorage)
+	u := NewCouponUsecase(mockCouponStorage, new(mocks.CategoryStorage))
+	ctx := context.Background()
+
+	coupon := schema.Coupon{
+		Base:         schema.Base{Id: 1},
+		Code:         "HEMAT10",
+		DiscountType: "percentage",
+		Value:        10,
+		PerUserLimit: 1,
+		StartsAt:     startsAt,
+		Active:       true,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockCouponStorage.On("GetByCode", mock.Anything, "HEMAT10").Return(&coupon, nil).Once()
+		mockCouponStorage.On("GetScope", mock.Anything, int64(1)).Return(promotion.Scope{}, nil).Once()
+		mockCouponStorage.On("CountUserRedemptions", mock.Anything, int64(1), uint64(1)).Return(int64(0), nil).Once()
+
+		res, err := u.Apply(ctx, 1, model.ApplyCouponRequest{Code: "hemat10", ProductId: 1, Amount: 100000})
+
+		assert.NoError(t, err)
+		assert.Equal(t, float64(10000), res.Discount)
+		assert.Equal(t, float64(90000), res.Amount)
+		mockCouponStorage.AssertExpectations(t)
+	})
+
+	t.Run("already used", func(t *testing.T) {
+		mockCouponStorage.On("GetByCode", mock.Anything, "HEMAT10").Return(&coupon, nil).Once()
+		mockCouponStorage.On("GetScope", mock.Anything, int64(1)).Return(promotion.Scope{}, nil).Once()
+		mockCouponStorage.On("CountUserRedemptions", mock.Anything, int64(1), uint64(1)).Return(int64(1), nil).Once()
+
+		_, err := u.Apply(ctx, 1, model.ApplyCouponRequest{Code: "HEMAT10", ProductId: 1, Amount: 100000})
+
+		assert.Equal(t, promotion.ErrUsageLimit, err)
+	})
+
+	t.Run("category coupon", func(t *testing.T) {
+		mockCategoryStorage := new(mocks.CategoryStorage)
+		u := NewCouponUsecase(mockCouponStorage, mockCategoryStorage)
+
+		mockCouponStorage.On("GetByCode", mock.Anything, "HEMAT10").Return(&coupon, nil).Twice()
+		mockCouponStorage.On("GetScope", mock.Anything, int64(1)).Return(promotion.Scope{CategoryIds: []int64{2}}, nil).Twice()
+		mockCouponStorage.On("CountUserRedemptions", mock.Anything, int64(1), uint64(1)).Return(int64(0), nil).Twice()
+		// product 1 is in category 7 under category 2, product 3 only in 8
+		mockCategoryStorage.On("GetProductCategoryIds", mock.Anything, int64(1)).Return([]int64{7, 2}, nil).Once()
+		mockCategoryStorage.On("GetProductCategoryIds", mock.Anything, int64(3)).Return([]int64{8}, nil).Once()
+
+		res, err := u.Apply(ctx, 1, model.ApplyCouponRequest{Code: "HEMAT10", ProductId: 1, Amount: 100000})
+		assert.NoError(t, err)
+		assert.Equal(t, float64(10000), res.Discount)
+
+		_, err = u.Apply(ctx, 1, model.ApplyCouponRequest{Code: "HEMAT10", ProductId: 3, Amount: 100000})
+		assert.Equal(t, promotion.ErrNotApplicable, err)
+		mockCategoryStorage.AssertExpectations(t)
+	})
+
+	t.Run("unknown code", func(t *testing.T) {
+		mockCouponStorage.On("GetByCode", mock.Anything, "NOPE").Return(nil, sql.ErrNoRows).Once()
+
+		_, err := u.Apply(ctx, 1, model.ApplyCouponRequest{Code: "nope", ProductId: 1, Amount: 100000})
+
+		assert.EqualError(t, err, "coupon not found")
+	})
+}
@@ -0,0 +1,63 @@
+package inventory
+
+import (
+	"context"
+	"kanggo/pkg/alert"
+	"kanggo/pkg/entity/model"
+	storage "kanggo/pkg/storage/inventory"
+)
+
+//go:generate mockery --name InventoryUsecase --case snake --output ../../mocks --disable-version-string
+
+// alertBatch is how many alerts are delivered per run of the notifier.
+const alertBatch = 100
+
+type (
+	InventoryUsecase interface {
+		GetLowStock(ctx context.Context, query model.ListQuery) ([]model.LowStockResponse, int64, error)
+		NotifyLowStock(ctx context.Context) (int, error)
+	}
+
+	inventoryUsecase struct {
+		inventoryStorage storage.InventoryStorage
+		notifier         alert.Notifier
+	}
+)
+
+func NewInventoryUsecase(inventoryStorage storage.InventoryStorage, notifier alert.Notifier) InventoryUsecase {
+	return &inventoryUsecase{
+		inventoryStorage: inventoryStorage,
+		notifier:         notifier,
+	}
+}
+
+func (i *inventoryUsecase) GetLowStock(ctx context.Context, query model.ListQuery) ([]model.LowStockResponse, int64, error) {
+	return i.inventoryStorage.GetLowStock(ctx, query)
+}
+
+// NotifyLowStock delivers the alerts raised by orders since the last run and
+// returns how many were delivered. An alert that fails to be delivered is
+// tried again on the next run, so a notifier that did get it may get it
+// twice.
+func (i *inventoryUsecase) NotifyLowStock(ctx context.Context) (int, error) {
+	alerts, err := i.inventoryStorage.GetPendingAlerts(ctx, alertBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	var failed error
+	delivered := []int64{}
+	for _, a := range alerts {
+		if err := i.notifier.Notify(ctx, a); err != nil {
+			failed = err
+			continue
+		}
+		delivered = append(delivered, a.Id)
+	}
+
+	if err := i.inventoryStorage.MarkNotified(ctx, delivered); err != nil {
+		return 0, err
+	}
+
+	return len(delivered), failed
+}
@@ -0,0 +1,78 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"kanggo/pkg/alert"
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetLowStock(t *testing.T) {
+	mockInventoryStorage := new(mocks.InventoryStorage)
+	u := NewInventoryUsecase(mockInventoryStorage, new(mocks.Notifier))
+	ctx := context.Background()
+	query := model.ListQuery{Page: 1, Size: 20, Sort: "qty"}
+	alertedAt := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	mockInventoryStorage.On("GetLowStock", mock.Anything, query).Return([]model.LowStockResponse{
+		{ProductId: 12, Name: "Semen Putih 40kg", Status: "active", Qty: 0, ReorderLevel: 10, AlertedAt: &alertedAt},
+		{ProductId: 4, Name: "Pasir 1m³", Status: "active", Qty: 3, ReorderLevel: 5},
+	}, int64(2), nil)
+
+	res, total, err := u.GetLowStock(ctx, query)
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, total)
+	assert.Equal(t, &alertedAt, res[0].AlertedAt)
+	assert.Nil(t, res[1].AlertedAt)
+}
+
+func TestNotifyLowStock(t *testing.T) {
+	ctx := context.Background()
+	pending := []alert.Alert{
+		{Id: 3, ProductId: 12, ProductName: "Semen Putih 40kg", Qty: 0, ReorderLevel: 10},
+		{Id: 4, ProductId: 4, ProductName: "Pasir 1m³", Qty: 3, ReorderLevel: 5},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockInventoryStorage := new(mocks.InventoryStorage)
+		mockNotifier := new(mocks.Notifier)
+		u := NewInventoryUsecase(mockInventoryStorage, mockNotifier)
+
+		mockInventoryStorage.On("GetPendingAlerts", mock.Anything, alertBatch).Return(pending, nil)
+		mockNotifier.On("Notify", mock.Anything, mock.AnythingOfType("alert.Alert")).Return(nil)
+		mockInventoryStorage.On("MarkNotified", mock.Anything, []int64{3, 4}).Return(nil)
+
+		delivered, err := u.NotifyLowStock(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, delivered)
+		mockNotifier.AssertNumberOfCalls(t, "Notify", 2)
+		mockInventoryStorage.AssertExpectations(t)
+	})
+
+	t.Run("failed delivery is retried", func(t *testing.T) {
+		mockInventoryStorage := new(mocks.InventoryStorage)
+		mockNotifier := new(mocks.Notifier)
+		u := NewInventoryUsecase(mockInventoryStorage, mockNotifier)
+
+		mockInventoryStorage.On("GetPendingAlerts", mock.Anything, alertBatch).Return(pending, nil)
+		mockNotifier.On("Notify", mock.Anything, pending[0]).Return(errors.New("webhook returned status 502"))
+		mockNotifier.On("Notify", mock.Anything, pending[1]).Return(nil)
+		// only the delivered alert is marked, the other one stays pending
+		mockInventoryStorage.On("MarkNotified", mock.Anything, []int64{4}).Return(nil)
+
+		delivered, err := u.NotifyLowStock(ctx)
+
+		assert.EqualError(t, err, "webhook returned status 502")
+		assert.Equal(t, 1, delivered)
+		mockInventoryStorage.AssertExpectations(t)
+	})
+}
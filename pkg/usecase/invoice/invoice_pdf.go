@@ -0,0 +1,153 @@
+package invoice
+
+import (
+	"fmt"
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/pdf"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	marginLeft  = 50.0
+	marginRight = pdf.PageWidth - 50
+	marginTop   = 60.0
+	lineHeight  = 16.0
+	pageBottom  = pdf.PageHeight - 60
+)
+
+// renderPdf lays out an A4 invoice: issuer and invoice details, the buyer,
+// the lines and the totals. Lines that do not fit continue on a new page.
+func renderPdf(issuer model.InvoiceIssuer, invoice model.InvoiceResponse) []byte {
+	doc := pdf.New()
+	y := marginTop
+
+	doc.Text(marginLeft, y, pdf.HelveticaBold, 20, "INVOICE")
+	doc.TextRight(marginRight, y, pdf.HelveticaBold, 12, invoice.Number)
+	y += lineHeight * 1.5
+
+	doc.Text(marginLeft, y, pdf.HelveticaBold, 10, issuer.Name)
+	doc.TextRight(marginRight, y, pdf.Helvetica, 10, "Date: "+invoice.IssuedAt.Format("02 January 2006"))
+	y += lineHeight
+	if issuer.Address != "" {
+		doc.Text(marginLeft, y, pdf.Helvetica, 10, issuer.Address)
+	}
+	order := invoice.OrderNumber
+	if order == "" {
+		order = fmt.Sprintf("#%d", invoice.OrderId)
+	}
+	doc.TextRight(marginRight, y, pdf.Helvetica, 10, "Order: "+order)
+	y += lineHeight
+	if issuer.TaxId != "" {
+		doc.Text(marginLeft, y, pdf.Helvetica, 10, "NPWP: "+issuer.TaxId)
+		y += lineHeight
+	}
+	y += lineHeight
+
+	doc.Text(marginLeft, y, pdf.HelveticaBold, 10, "Bill to")
+	doc.Text(300, y, pdf.HelveticaBold, 10, "Ship to")
+	y += lineHeight
+	shipTo := []string{
+		invoice.Shipping.Recipient,
+		invoice.Shipping.Phone,
+		invoice.Shipping.Street,
+		strings.Join([]string{invoice.Shipping.District, invoice.Shipping.City}, ", "),
+		strings.Join([]string{invoice.Shipping.Province, invoice.Shipping.PostalCode}, " "),
+	}
+	billTo := []string{invoice.BuyerName, invoice.BuyerEmail}
+	for i, text := range shipTo {
+		if i < len(billTo) {
+			doc.Text(marginLeft, y, pdf.Helvetica, 10, billTo[i])
+		}
+		doc.Text(300, y, pdf.Helvetica, 10, text)
+		y += lineHeight
+	}
+	y += lineHeight
+
+	header := func() {
+		doc.Text(marginLeft, y, pdf.HelveticaBold, 10, "Description")
+		doc.TextRight(340, y, pdf.HelveticaBold, 10, "Qty")
+		doc.TextRight(440, y, pdf.HelveticaBold, 10, "Unit price")
+		doc.TextRight(marginRight, y, pdf.HelveticaBold, 10, "Amount")
+		y += 6
+		doc.Line(marginLeft, y, marginRight, y)
+		y += lineHeight
+	}
+	header()
+
+	for _, line := range invoice.Lines {
+		if y > pageBottom {
+			doc.AddPage()
+			y = marginTop
+			header()
+		}
+		doc.Text(marginLeft, y, pdf.Helvetica, 10, line.Description)
+		doc.TextRight(340, y, pdf.Helvetica, 10, strconv.FormatInt(line.Quantity, 10))
+		doc.TextRight(440, y, pdf.Helvetica, 10, formatRupiah(line.UnitPrice))
+		doc.TextRight(marginRight, y, pdf.Helvetica, 10, formatRupiah(line.Amount))
+		y += lineHeight
+	}
+
+	doc.Line(marginLeft, y-10, marginRight, y-10)
+	y += 4
+
+	taxLabel := fmt.Sprintf("VAT %s%%", strconv.FormatFloat(invoice.TaxRate*100, 'f', -1, 64))
+	if invoice.TaxInclusive {
+		taxLabel += " (included)"
+	}
+
+	totals := []struct {
+		label  string
+		amount float64
+	}{
+		{"Subtotal", invoice.Subtotal},
+		{"Discount", -invoice.DiscountAmount},
+		{"Taxable amount", invoice.NetAmount},
+		{taxLabel, invoice.TaxAmount},
+		{fmt.Sprintf("Shipping (%s %s)", strings.ToUpper(invoice.Courier), invoice.Service), invoice.ShippingCost},
+	}
+
+	if y+lineHeight*float64(len(totals)+2) > pageBottom {
+		doc.AddPage()
+		y = marginTop
+	}
+
+	for _, total := range totals {
+		if total.label == "Discount" && total.amount == 0 {
+			continue
+		}
+		doc.Text(300, y, pdf.Helvetica, 10, total.label)
+		doc.TextRight(marginRight, y, pdf.Helvetica, 10, formatRupiah(total.amount))
+		y += lineHeight
+	}
+
+	doc.Line(300, y-10, marginRight, y-10)
+	y += 4
+	doc.Text(300, y, pdf.HelveticaBold, 11, "Total")
+	doc.TextRight(marginRight, y, pdf.HelveticaBold, 11, formatRupiah(invoice.Total))
+
+	return doc.Bytes()
+}
+
+// formatRupiah formats an amount the Indonesian way, e.g. Rp 1.234.567,50.
+func formatRupiah(amount float64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	cents := int64(math.Round(amount * 100))
+	whole := strconv.FormatInt(cents/100, 10)
+
+	var b strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(digit)
+	}
+
+	return fmt.Sprintf("%sRp %s,%02d", sign, b.String(), cents%100)
+}
@@ -0,0 +1,99 @@
+package invoice
+
+import (
+	"context"
+	"database/sql"
+	"kanggo/pkg/entity/model"
+	storage "kanggo/pkg/storage/invoice"
+)
+
+//go:generate mockery --name InvoiceUsecase --case snake --output ../../mocks --disable-version-string
+
+type (
+	InvoiceUsecase interface {
+		GetByOrderId(ctx context.Context, orderId int64, userId uint64, admin bool) (*model.InvoiceResponse, error)
+		GetPdf(ctx context.Context, orderId int64, userId uint64, admin bool) (*model.InvoiceResponse, []byte, error)
+	}
+
+	invoiceUsecase struct {
+		invoiceStorage storage.InvoiceStorage
+		issuer         model.InvoiceIssuer
+	}
+)
+
+func NewInvoiceUsecase(invoiceStorage storage.InvoiceStorage, issuer model.InvoiceIssuer) InvoiceUsecase {
+	return &invoiceUsecase{
+		invoiceStorage: invoiceStorage,
+		issuer:         issuer,
+	}
+}
+
+// GetByOrderId returns the invoice of an order to its owner or to an admin.
+// Other users get sql.ErrNoRows, so they cannot probe which orders exist.
+func (i *invoiceUsecase) GetByOrderId(ctx context.Context, orderId int64, userId uint64, admin bool) (*model.InvoiceResponse, error) {
+	invoice, err := i.invoiceStorage.GetByOrderId(ctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !admin && invoice.UserId != int64(userId) {
+		return nil, sql.ErrNoRows
+	}
+
+	lines, err := i.invoiceStorage.GetLines(ctx, int64(invoice.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	res := model.InvoiceResponse{
+		Id:          int64(invoice.Id),
+		Number:      invoice.Number,
+		OrderId:     invoice.OrderId,
+		OrderNumber: invoice.OrderNumber,
+		UserId:      invoice.UserId,
+		BuyerName:   invoice.BuyerName,
+		BuyerEmail:  invoice.BuyerEmail,
+		Shipping: model.ShippingAddress{
+			Recipient:  invoice.Shipping.Recipient,
+			Phone:      invoice.Shipping.Phone,
+			Street:     invoice.Shipping.Street,
+			Province:   invoice.Shipping.Province,
+			City:       invoice.Shipping.City,
+			District:   invoice.Shipping.District,
+			PostalCode: invoice.Shipping.PostalCode,
+		},
+		Courier:        invoice.Courier,
+		Service:        invoice.Service,
+		Lines:          []model.InvoiceLine{},
+		Subtotal:       invoice.Subtotal,
+		DiscountAmount: invoice.DiscountAmount,
+		NetAmount:      invoice.NetAmount,
+		TaxRate:        invoice.TaxRate,
+		TaxAmount:      invoice.TaxAmount,
+		TaxInclusive:   invoice.TaxInclusive,
+		ShippingCost:   invoice.ShippingCost,
+		Total:          invoice.Total,
+		IssuedAt:       invoice.IssuedAt,
+	}
+
+	for _, line := range lines {
+		res.Lines = append(res.Lines, model.InvoiceLine{
+			ProductId:   line.ProductId,
+			Description: line.Description,
+			Quantity:    line.Quantity,
+			UnitPrice:   line.UnitPrice,
+			Amount:      line.Amount,
+		})
+	}
+
+	return &res, nil
+}
+
+func (i *invoiceUsecase) GetPdf(ctx context.Context, orderId int64, userId uint64, admin bool) (*model.InvoiceResponse, []byte, error) {
+	invoice, err := i.GetByOrderId(ctx, orderId, userId, admin)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return invoice, renderPdf(i.issuer, *invoice), nil
+}
@@ -0,0 +1,129 @@
+package invoice
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/entity/schema"
+	"kanggo/pkg/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var issuer = model.InvoiceIssuer{Name: "PT Kanggo Indonesia", Address: "Jakarta Utara", TaxId: "01.234.567.8-901.000"}
+
+func mockInvoice() schema.Invoice {
+	return schema.Invoice{
+		Base:       schema.Base{Id: 7},
+		Number:     "INV/2022/000007",
+		Year:       2022,
+		Sequence:   7,
+		OrderId:    3,
+		UserId:     1,
+		BuyerName:  "Agung",
+		BuyerEmail: "agung@mail.com",
+		Shipping: schema.ShippingAddress{
+			Recipient:  "Agung",
+			Phone:      "081234567890",
+			Street:     "Jl. Sudirman No. 1",
+			Province:   "DKI Jakarta",
+			City:       "Jakarta Selatan",
+			District:   "Setiabudi",
+			PostalCode: "12910",
+		},
+		Courier:        "jne",
+		Service:        "REG",
+		Subtotal:       100000,
+		DiscountAmount: 10000,
+		NetAmount:      90000,
+		TaxRate:        0.11,
+		TaxAmount:      9900,
+		ShippingCost:   900000,
+		Total:          999900,
+		IssuedAt:       time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestGetByOrderId(t *testing.T) {
+	ctx := context.Background()
+	invoice := mockInvoice()
+	lines := []schema.InvoiceLine{
+		{InvoiceId: 7, ProductId: 1, Description: "Semen 50kg", Quantity: 2, UnitPrice: 50000, Amount: 100000},
+	}
+
+	t.Run("owner", func(t *testing.T) {
+		mockInvoiceStorage := new(mocks.InvoiceStorage)
+		u := NewInvoiceUsecase(mockInvoiceStorage, issuer)
+
+		mockInvoiceStorage.On("GetByOrderId", mock.Anything, int64(3)).Return(&invoice, nil)
+		mockInvoiceStorage.On("GetLines", mock.Anything, int64(7)).Return(lines, nil)
+
+		res, err := u.GetByOrderId(ctx, 3, 1, false)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "INV/2022/000007", res.Number)
+		assert.Len(t, res.Lines, 1)
+		assert.Equal(t, "Semen 50kg", res.Lines[0].Description)
+		mockInvoiceStorage.AssertExpectations(t)
+	})
+
+	t.Run("admin", func(t *testing.T) {
+		mockInvoiceStorage := new(mocks.InvoiceStorage)
+		u := NewInvoiceUsecase(mockInvoiceStorage, issuer)
+
+		mockInvoiceStorage.On("GetByOrderId", mock.Anything, int64(3)).Return(&invoice, nil)
+		mockInvoiceStorage.On("GetLines", mock.Anything, int64(7)).Return(lines, nil)
+
+		_, err := u.GetByOrderId(ctx, 3, 99, true)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("other user", func(t *testing.T) {
+		mockInvoiceStorage := new(mocks.InvoiceStorage)
+		u := NewInvoiceUsecase(mockInvoiceStorage, issuer)
+
+		mockInvoiceStorage.On("GetByOrderId", mock.Anything, int64(3)).Return(&invoice, nil)
+
+		_, err := u.GetByOrderId(ctx, 3, 2, false)
+
+		assert.Equal(t, sql.ErrNoRows, err)
+		mockInvoiceStorage.AssertNotCalled(t, "GetLines", mock.Anything, mock.Anything)
+	})
+}
+
+func TestGetPdf(t *testing.T) {
+	mockInvoiceStorage := new(mocks.InvoiceStorage)
+	u := NewInvoiceUsecase(mockInvoiceStorage, issuer)
+	ctx := context.Background()
+	invoice := mockInvoice()
+
+	lines := []schema.InvoiceLine{}
+	for i := 0; i < 60; i++ {
+		lines = append(lines, schema.InvoiceLine{InvoiceId: 7, ProductId: 1, Description: "Semen 50kg", Quantity: 1, UnitPrice: 50000, Amount: 50000})
+	}
+
+	mockInvoiceStorage.On("GetByOrderId", mock.Anything, int64(3)).Return(&invoice, nil)
+	mockInvoiceStorage.On("GetLines", mock.Anything, int64(7)).Return(lines, nil)
+
+	res, pdf, err := u.GetPdf(ctx, 3, 1, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "INV/2022/000007", res.Number)
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-")))
+	assert.Contains(t, string(pdf), "(INV/2022/000007) Tj")
+	assert.Contains(t, string(pdf), "(Rp 999.900,00) Tj")
+	assert.Contains(t, string(pdf), "/Count 2")
+}
+
+func TestFormatRupiah(t *testing.T) {
+	assert.Equal(t, "Rp 0,00", formatRupiah(0))
+	assert.Equal(t, "Rp 999,50", formatRupiah(999.5))
+	assert.Equal(t, "Rp 1.234.567,89", formatRupiah(1234567.89))
+	assert.Equal(t, "-Rp 10.000,00", formatRupiah(-10000))
+}
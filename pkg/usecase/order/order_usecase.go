@@ -2,10 +2,24 @@ package order
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"kanggo/pkg/entity/model"
 	"kanggo/pkg/entity/schema"
+	"kanggo/pkg/inventory"
+	"kanggo/pkg/ordernumber"
+	"kanggo/pkg/promotion"
+	"kanggo/pkg/shipping"
+	addressStorage "kanggo/pkg/storage/address"
+	categoryStorage "kanggo/pkg/storage/category"
+	couponStorage "kanggo/pkg/storage/coupon"
 	storage "kanggo/pkg/storage/order"
 	productStorage "kanggo/pkg/storage/product"
+	"kanggo/pkg/tax"
+	"strings"
+	"time"
 )
 
 //go:generate mockery --name OrderUsecase --case snake --output ../../mocks --disable-version-string
@@ -13,39 +27,188 @@ import (
 type (
 	OrderUsecase interface {
 		InsertOrder(ctx context.Context, data model.OrderRequest) error
-		GetAllOrder(ctx context.Context) ([]model.OrderResponse, error)
-		GetAllOrderPerUser(ctx context.Context, userId uint64) ([]model.OrderResponse, error)
+		GetAllOrder(ctx context.Context, query model.ListQuery) ([]model.OrderResponse, int64, error)
+		GetAllOrderPerUser(ctx context.Context, userId uint64, query model.ListQuery) ([]model.OrderResponse, int64, error)
 		GetOrderById(ctx context.Context, orderId int64, userId uint64) (*model.OrderResponse, error)
+		GetOrderIdByNumber(ctx context.Context, number string) (int64, error)
+		CancelOrder(ctx context.Context, orderId int64, userId uint64, admin bool, data model.CancelOrderRequest) error
+		GetEvents(ctx context.Context, orderId int64, userId uint64, admin bool) ([]model.OrderEventResponse, error)
 		UpdatePayment(ctx context.Context, data model.PaymentRequest) error
+		GetTaxReport(ctx context.Context, from, to time.Time) ([]model.TaxReport, error)
 	}
 
 	orderUsecase struct {
-		orderStorage   storage.OrderStorage
-		productStorage productStorage.ProductStorage
+		orderStorage    storage.OrderStorage
+		productStorage  productStorage.ProductStorage
+		addressStorage  addressStorage.AddressStorage
+		rateProvider    shipping.ShippingRateProvider
+		origin          shipping.Region
+		taxEngine       *tax.Engine
+		couponStorage   couponStorage.CouponStorage
+		categoryStorage categoryStorage.CategoryStorage
+		numberFormat    ordernumber.Format
 	}
 )
 
-func NewOrderUsecase(orderStorage storage.OrderStorage, productStorage productStorage.ProductStorage) OrderUsecase {
+func NewOrderUsecase(orderStorage storage.OrderStorage, productStorage productStorage.ProductStorage,
+	addressStorage addressStorage.AddressStorage, rateProvider shipping.ShippingRateProvider,
+	origin shipping.Region, taxEngine *tax.Engine, couponStorage couponStorage.CouponStorage,
+	categoryStorage categoryStorage.CategoryStorage, numberFormat ordernumber.Format) OrderUsecase {
 	return &orderUsecase{
-		orderStorage:   orderStorage,
-		productStorage: productStorage,
+		orderStorage:    orderStorage,
+		productStorage:  productStorage,
+		addressStorage:  addressStorage,
+		rateProvider:    rateProvider,
+		origin:          origin,
+		taxEngine:       taxEngine,
+		couponStorage:   couponStorage,
+		categoryStorage: categoryStorage,
+		numberFormat:    numberFormat,
 	}
 }
 
 func (o *orderUsecase) InsertOrder(ctx context.Context, data model.OrderRequest) error {
+	address, err := o.addressStorage.GetById(ctx, data.AddressId, uint64(data.UserId))
+	if err != nil {
+		return err
+	}
+
+	product, err := o.productStorage.GetById(ctx, data.ProductId)
+	if err != nil {
+		return err
+	}
+
+	if product.Status != schema.ProductActive {
+		return errors.New("product is not available")
+	}
+
+	if data.Quantity < product.MinOrderQty {
+		return errors.New("quantity is below the minimum order quantity")
+	}
+
+	variant, err := o.variant(ctx, data)
+	if err != nil {
+		return err
+	}
+
+	// the order pays the price in effect now, a running sale included, even
+	// if the scheduler has not applied it to the variant yet
+	now := time.Now()
+	unitPrice, err := o.productStorage.EffectivePrice(ctx, int64(variant.Id), now)
+	if err != nil {
+		return err
+	}
+
+	amount := unitPrice * float64(data.Quantity)
+	if data.Amount != 0 && fmt.Sprintf("%.2f", data.Amount) != fmt.Sprintf("%.2f", amount) {
+		return errors.New("amount does not match the current price")
+	}
+	data.Amount = amount
+
+	stocks, err := o.productStorage.GetStock(ctx, []int64{data.ProductId})
+	if err != nil {
+		return err
+	}
+
+	// the order ships from the warehouse chosen for it, if warehouses are
+	// set up, and is charged shipping from there
+	origin := o.origin
+	warehouse, err := inventory.Choose(stocks, int64(variant.Id), data.Quantity, address.Province, address.City)
+	if err != nil {
+		return err
+	}
+
+	var warehouseId int64
+	if warehouse != nil {
+		warehouseId = warehouse.WarehouseId
+		origin = shipping.Region{Province: warehouse.Province, City: warehouse.City}
+	}
+
+	options, err := o.rateProvider.Quote(ctx, shipping.RateRequest{
+		Origin:      origin,
+		Destination: shipping.Region{Province: address.Province, City: address.City},
+		Weight:      shipping.ChargeableWeight(variant.Weight, product.Length, product.Width, product.Height, data.Quantity),
+	})
+	if err != nil {
+		return err
+	}
+
+	option, err := shipping.Find(options, data.Courier, data.Service)
+	if err != nil {
+		return err
+	}
+
+	var redemption *schema.CouponRedemption
+	if data.CouponCode != "" {
+		redemption, err = o.applyCoupon(ctx, data)
+		if err != nil {
+			return err
+		}
+	}
+
+	// the discount lowers the taxable amount
+	var discount float64
+	if redemption != nil {
+		discount = redemption.Discount
+	}
+
+	price := o.taxEngine.Calculate(now, product.TaxCategory, data.Amount-discount)
+
 	request := schema.Order{
-		UserId:    data.UserId,
-		ProductId: data.ProductId,
-		Amount:    data.Amount,
+		UserId:         data.UserId,
+		ProductId:      data.ProductId,
+		VariantId:      int64(variant.Id),
+		Sku:            variant.Sku,
+		Quantity:       data.Quantity,
+		Amount:         price.Gross,
+		NetAmount:      price.Net,
+		TaxRate:        price.Rate,
+		TaxAmount:      price.Tax,
+		TaxInclusive:   price.Inclusive,
+		DiscountAmount: discount,
+		WarehouseId:    warehouseId,
+		Courier:        option.Courier,
+		Service:        option.Service,
+		ShippingCost:   option.Cost,
+		Shipping: schema.ShippingAddress{
+			Recipient:  address.Recipient,
+			Phone:      address.Phone,
+			Street:     address.Street,
+			Province:   address.Province,
+			City:       address.City,
+			District:   address.District,
+			PostalCode: address.PostalCode,
+		},
 	}
 
-	status, err := o.productStorage.CheckQty(ctx, data.ProductId, data.Quantity)
+	status, err := o.productStorage.CheckQty(ctx, int64(variant.Id), data.Quantity)
 	if err != nil {
 		return err
 	}
 
 	if status {
-		if err = o.orderStorage.InsertOrder(ctx, request, data.Quantity); err != nil {
+		// an order merged into a pending one keeps the number it already has,
+		// so a number is only taken for a new order
+		pending, err := o.orderStorage.HasPendingOrder(ctx, data.UserId, int64(variant.Id))
+		if err != nil {
+			return err
+		}
+
+		if !pending {
+			if request.Number, err = o.number(ctx, now); err != nil {
+				return err
+			}
+		}
+
+		err = o.orderStorage.InsertOrder(ctx, request, data.Quantity, redemption)
+		if err != nil && err.Error() == "order number required" {
+			// the pending order was paid or cancelled in the meantime
+			if request.Number, err = o.number(ctx, now); err != nil {
+				return err
+			}
+			err = o.orderStorage.InsertOrder(ctx, request, data.Quantity, redemption)
+		}
+		if err != nil {
 			return err
 		}
 
@@ -55,22 +218,102 @@ func (o *orderUsecase) InsertOrder(ctx context.Context, data model.OrderRequest)
 
 }
 
-func (o *orderUsecase) GetAllOrder(ctx context.Context) ([]model.OrderResponse, error) {
-	res, err := o.orderStorage.GetAllOrder(ctx)
+// number takes the next order number of the day.
+func (o *orderUsecase) number(ctx context.Context, now time.Time) (string, error) {
+	seq, err := o.orderStorage.NextNumber(ctx, now)
 	if err != nil {
+		return "", err
+	}
+
+	return o.numberFormat.Number(now, seq), nil
+}
+
+// variant resolves the variant being ordered. A product sold in a single
+// variant can be ordered without naming it.
+func (o *orderUsecase) variant(ctx context.Context, data model.OrderRequest) (*schema.ProductVariant, error) {
+	if data.VariantId == 0 {
+		variants, err := o.productStorage.GetVariants(ctx, data.ProductId)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(variants) != 1 {
+			return nil, errors.New("variant_id is required")
+		}
+
+		return &variants[0], nil
+	}
+
+	variant, err := o.productStorage.GetVariantById(ctx, data.VariantId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("variant not found")
+		}
 		return nil, err
 	}
 
-	return res, nil
+	if variant.ProductId != data.ProductId {
+		return nil, errors.New("variant not found")
+	}
+
+	return variant, nil
 }
 
-func (o *orderUsecase) GetAllOrderPerUser(ctx context.Context, userId uint64) ([]model.OrderResponse, error) {
-	res, err := o.orderStorage.GetAllOrderPerUser(ctx, userId)
+func (o *orderUsecase) applyCoupon(ctx context.Context, data model.OrderRequest) (*schema.CouponRedemption, error) {
+	coupon, err := o.couponStorage.GetByCode(ctx, strings.ToUpper(data.CouponCode))
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("coupon not found")
+		}
 		return nil, err
 	}
 
-	return res, nil
+	scope, err := o.couponStorage.GetScope(ctx, int64(coupon.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	var categoryIds []int64
+	if len(scope.CategoryIds) > 0 {
+		categoryIds, err = o.categoryStorage.GetProductCategoryIds(ctx, data.ProductId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	used, err := o.couponStorage.CountUserRedemptions(ctx, int64(coupon.Id), uint64(data.UserId))
+	if err != nil {
+		return nil, err
+	}
+
+	discount, err := promotion.Discount(*coupon, scope, used, data.ProductId, categoryIds, data.Amount, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return &schema.CouponRedemption{
+		CouponId: int64(coupon.Id),
+		UserId:   data.UserId,
+		Discount: discount,
+	}, nil
+}
+
+func (o *orderUsecase) GetAllOrder(ctx context.Context, query model.ListQuery) ([]model.OrderResponse, int64, error) {
+	res, total, err := o.orderStorage.GetAllOrder(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return res, total, nil
+}
+
+func (o *orderUsecase) GetAllOrderPerUser(ctx context.Context, userId uint64, query model.ListQuery) ([]model.OrderResponse, int64, error) {
+	res, total, err := o.orderStorage.GetAllOrderPerUser(ctx, userId, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return res, total, nil
 }
 
 func (o *orderUsecase) GetOrderById(ctx context.Context, orderId int64, userId uint64) (*model.OrderResponse, error) {
@@ -83,10 +326,70 @@ func (o *orderUsecase) GetOrderById(ctx context.Context, orderId int64, userId u
 	return res, nil
 }
 
+// GetOrderIdByNumber resolves an order number to the id the other order
+// routes are looked up by.
+func (o *orderUsecase) GetOrderIdByNumber(ctx context.Context, number string) (int64, error) {
+	return o.orderStorage.GetOrderIdByNumber(ctx, number)
+}
+
+// CancelOrder cancels a pending order on behalf of its owner or an admin.
+func (o *orderUsecase) CancelOrder(ctx context.Context, orderId int64, userId uint64, admin bool, data model.CancelOrderRequest) error {
+	role := "admin"
+	if !admin {
+		role = "user"
+		if _, err := o.orderStorage.GetOrderById(ctx, orderId, userId); err != nil {
+			return err
+		}
+	}
+
+	if err := o.orderStorage.CancelOrder(ctx, orderId, int64(userId), role, data.Reason, data.Version); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetEvents returns the audit trail of an order, oldest first, to its owner
+// or an admin.
+func (o *orderUsecase) GetEvents(ctx context.Context, orderId int64, userId uint64, admin bool) ([]model.OrderEventResponse, error) {
+	if !admin {
+		if _, err := o.orderStorage.GetOrderById(ctx, orderId, userId); err != nil {
+			return nil, err
+		}
+	}
+
+	events, err := o.orderStorage.GetEvents(ctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []model.OrderEventResponse{}
+	for _, event := range events {
+		payload := json.RawMessage(event.Payload)
+		if !json.Valid(payload) {
+			payload = json.RawMessage("null")
+		}
+
+		res = append(res, model.OrderEventResponse{
+			Id:         int64(event.Id),
+			Type:       event.Type,
+			FromStatus: event.FromStatus,
+			ToStatus:   event.ToStatus,
+			ActorId:    event.ActorId,
+			ActorRole:  event.ActorRole,
+			Payload:    payload,
+			CreatedAt:  event.CreatedAt,
+		})
+	}
+
+	return res, nil
+}
+
 func (o *orderUsecase) UpdatePayment(ctx context.Context, data model.PaymentRequest) error {
 	request := schema.Order{
 		UserId:    data.UserId,
 		ProductId: data.ProductId,
+		VariantId: data.VariantId,
 		Amount:    data.Amount,
 	}
 
@@ -96,3 +399,12 @@ func (o *orderUsecase) UpdatePayment(ctx context.Context, data model.PaymentRequ
 
 	return nil
 }
+
+func (o *orderUsecase) GetTaxReport(ctx context.Context, from, to time.Time) ([]model.TaxReport, error) {
+	res, err := o.orderStorage.GetTaxReport(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -2,20 +2,44 @@ package order
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"testing"
+	"time"
 
 	"kanggo/pkg/entity/model"
 	"kanggo/pkg/entity/schema"
+	"kanggo/pkg/inventory"
 	"kanggo/pkg/mocks"
+	"kanggo/pkg/ordernumber"
+	"kanggo/pkg/promotion"
+	"kanggo/pkg/shipping"
+	"kanggo/pkg/tax"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
+var (
+	origin    = shipping.Region{Province: "DKI Jakarta", City: "Jakarta Utara"}
+	taxEngine = tax.NewEngine([]tax.Rule{
+		{
+			Name:             "PPN 11%",
+			Rate:             0.11,
+			ExemptCategories: []string{"basic_goods"},
+			EffectiveFrom:    time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC),
+		},
+	})
+	numberFormat, _ = ordernumber.Parse(ordernumber.DefaultFormat)
+)
+
 func TestInsert(t *testing.T) {
 	mockProductStorage := new(mocks.ProductStorage)
 	mockOrderStorage := new(mocks.OrderStorage)
-	o := NewOrderUsecase(mockOrderStorage, mockProductStorage)
+	mockAddressStorage := new(mocks.AddressStorage)
+	mockRateProvider := new(mocks.ShippingRateProvider)
+	mockCouponStorage := new(mocks.CouponStorage)
+	o := NewOrderUsecase(mockOrderStorage, mockProductStorage, mockAddressStorage, mockRateProvider, origin, taxEngine, mockCouponStorage, new(mocks.CategoryStorage), numberFormat)
 	ctx := context.Background()
 
 	model := model.OrderRequest{
@@ -23,16 +47,99 @@ func TestInsert(t *testing.T) {
 		ProductId: 1,
 		Amount:    100000,
 		Quantity:  2,
+		AddressId: 1,
+		Courier:   "jne",
+		Service:   "REG",
 	}
-	schema := schema.Order{
-		UserId:    1,
+	product := schema.Product{
+		Base:   schema.Base{Id: 1},
+		Name:   "Semen 50kg",
+		Price:  50000,
+		Qty:    10,
+		Weight: 50000,
+
+		TaxCategory: "standard",
+		Unit:        "sak",
+		MinOrderQty: 1,
+		Status:      schema.ProductActive,
+	}
+	variant := schema.ProductVariant{
+		Base:      schema.Base{Id: 7},
 		ProductId: 1,
-		Amount:    100000,
+		Sku:       "SEMEN-50KG",
+		Options:   "{}",
+		Price:     50000,
+		Qty:       10,
+		Weight:    50000,
+	}
+	variants := []schema.ProductVariant{variant}
+	otherVariant := schema.ProductVariant{Base: schema.Base{Id: 9}, ProductId: 2}
+	options := []shipping.RateOption{
+		{Courier: "jne", Service: "REG", Cost: 900000, Etd: "1-2 days"},
+		{Courier: "jne", Service: "CARGO", Cost: 600000, Etd: "5-8 days"},
+	}
+	address := schema.Address{
+		Base:       schema.Base{Id: 1},
+		UserId:     1,
+		Recipient:  "Agung",
+		Phone:      "081234567890",
+		Street:     "Jl. Sudirman No. 1",
+		Province:   "DKI Jakarta",
+		City:       "Jakarta Selatan",
+		District:   "Setiabudi",
+		PostalCode: "12910",
+		IsDefault:  true,
+	}
+	coupon := schema.Coupon{
+		Base:         schema.Base{Id: 3},
+		Code:         "HEMAT10",
+		DiscountType: "percentage",
+		Value:        10,
+		StartsAt:     time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		Active:       true,
+	}
+	redemption := &schema.CouponRedemption{CouponId: 3, UserId: 1, Discount: 10000}
+	var noRedemption *schema.CouponRedemption
+	schema := schema.Order{
+		Number:       numberFormat.Number(time.Now(), 123),
+		UserId:       1,
+		ProductId:    1,
+		VariantId:    7,
+		Sku:          "SEMEN-50KG",
+		Quantity:     2,
+		Amount:       111000,
+		NetAmount:    100000,
+		TaxRate:      0.11,
+		TaxAmount:    11000,
+		Courier:      "jne",
+		Service:      "REG",
+		ShippingCost: 900000,
+		Shipping: schema.ShippingAddress{
+			Recipient:  "Agung",
+			Phone:      "081234567890",
+			Street:     "Jl. Sudirman No. 1",
+			Province:   "DKI Jakarta",
+			City:       "Jakarta Selatan",
+			District:   "Setiabudi",
+			PostalCode: "12910",
+		},
 	}
 
 	t.Run("success", func(t *testing.T) {
-		mockProductStorage.On("CheckQty", ctx, model.ProductId, model.Quantity).Return(true, nil)
-		mockOrderStorage.On("InsertOrder", ctx, schema, model.Quantity).Return(nil)
+		mockAddressStorage.On("GetById", ctx, model.AddressId, uint64(1)).Return(&address, nil)
+		mockProductStorage.On("GetById", ctx, model.ProductId).Return(&product, nil)
+		mockProductStorage.On("GetVariants", ctx, model.ProductId).Return(variants, nil)
+		mockProductStorage.On("GetStock", ctx, []int64{model.ProductId}).Return([]inventory.Stock{}, nil)
+		mockRateProvider.On("Quote", ctx, shipping.RateRequest{
+			Origin:      origin,
+			Destination: shipping.Region{Province: "DKI Jakarta", City: "Jakarta Selatan"},
+			Weight:      100000,
+		}).Return(options, nil)
+		mockProductStorage.On("EffectivePrice", ctx, int64(7), mock.AnythingOfType("time.Time")).Return(float64(50000), nil)
+		mockProductStorage.On("CheckQty", ctx, int64(7), model.Quantity).Return(true, nil)
+		mockOrderStorage.On("HasPendingOrder", ctx, int64(1), mock.AnythingOfType("int64")).Return(false, nil).Once()
+		mockOrderStorage.On("NextNumber", ctx, mock.AnythingOfType("time.Time")).Return(int64(123), nil).Once()
+		mockOrderStorage.On("InsertOrder", ctx, schema, model.Quantity, noRedemption).Return(nil)
 
 		err := o.InsertOrder(ctx, model)
 
@@ -40,12 +147,196 @@ func TestInsert(t *testing.T) {
 		assert.NoError(t, err)
 		mockProductStorage.AssertExpectations(t)
 	})
+
+	t.Run("with coupon", func(t *testing.T) {
+		request := model
+		request.CouponCode = "hemat10"
+		order := schema
+		order.Amount = 99900
+		order.NetAmount = 90000
+		order.TaxAmount = 9900
+		order.DiscountAmount = 10000
+
+		mockCouponStorage.On("GetByCode", ctx, "HEMAT10").Return(&coupon, nil)
+		mockCouponStorage.On("GetScope", ctx, int64(3)).Return(promotion.Scope{}, nil)
+		mockCouponStorage.On("CountUserRedemptions", ctx, int64(3), uint64(1)).Return(int64(0), nil)
+		mockOrderStorage.On("HasPendingOrder", ctx, int64(1), mock.AnythingOfType("int64")).Return(false, nil).Once()
+		mockOrderStorage.On("NextNumber", ctx, mock.AnythingOfType("time.Time")).Return(int64(123), nil).Once()
+		mockOrderStorage.On("InsertOrder", ctx, order, model.Quantity, redemption).Return(nil)
+
+		err := o.InsertOrder(ctx, request)
+
+		assert.NoError(t, err)
+		mockCouponStorage.AssertExpectations(t)
+		mockOrderStorage.AssertExpectations(t)
+	})
+
+	t.Run("sale price", func(t *testing.T) {
+		request := model
+		request.VariantId = 8
+		request.Amount = 0
+		onSale := variant
+		onSale.Id = 8
+		onSale.Sku = "SEMEN-50KG-PROMO"
+		order := schema
+		order.VariantId = 8
+		order.Sku = "SEMEN-50KG-PROMO"
+		order.Amount = 99900
+		order.NetAmount = 90000
+		order.TaxAmount = 9900
+
+		mockProductStorage.On("GetVariantById", ctx, int64(8)).Return(&onSale, nil)
+		mockProductStorage.On("EffectivePrice", ctx, int64(8), mock.AnythingOfType("time.Time")).Return(float64(45000), nil)
+		mockProductStorage.On("CheckQty", ctx, int64(8), model.Quantity).Return(true, nil)
+		mockOrderStorage.On("HasPendingOrder", ctx, int64(1), mock.AnythingOfType("int64")).Return(false, nil).Once()
+		mockOrderStorage.On("NextNumber", ctx, mock.AnythingOfType("time.Time")).Return(int64(123), nil).Once()
+		mockOrderStorage.On("InsertOrder", ctx, order, model.Quantity, noRedemption).Return(nil)
+
+		err := o.InsertOrder(ctx, request)
+
+		assert.NoError(t, err)
+		mockOrderStorage.AssertExpectations(t)
+	})
+
+	t.Run("from the nearest warehouse", func(t *testing.T) {
+		request := model
+		request.ProductId = 4
+		stocked := product
+		stocked.Id = 4
+		order := schema
+		order.ProductId = 4
+		order.WarehouseId = 2
+		order.ShippingCost = 450000
+
+		mockProductStorage.On("GetById", ctx, int64(4)).Return(&stocked, nil)
+		mockProductStorage.On("GetVariants", ctx, int64(4)).Return(variants, nil)
+		mockProductStorage.On("GetStock", ctx, []int64{4}).Return([]inventory.Stock{
+			{ProductId: 4, VariantId: 7, WarehouseId: 1, Province: "DKI Jakarta", City: "Jakarta Utara", Default: true, Active: true, Qty: 80},
+			{ProductId: 4, VariantId: 7, WarehouseId: 2, Province: "DKI Jakarta", City: "Jakarta Selatan", Active: true, Qty: 5},
+		}, nil)
+		mockRateProvider.On("Quote", ctx, shipping.RateRequest{
+			Origin:      shipping.Region{Province: "DKI Jakarta", City: "Jakarta Selatan"},
+			Destination: shipping.Region{Province: "DKI Jakarta", City: "Jakarta Selatan"},
+			Weight:      100000,
+		}).Return([]shipping.RateOption{{Courier: "jne", Service: "REG", Cost: 450000, Etd: "1 day"}}, nil)
+		mockOrderStorage.On("HasPendingOrder", ctx, int64(1), mock.AnythingOfType("int64")).Return(false, nil).Once()
+		mockOrderStorage.On("NextNumber", ctx, mock.AnythingOfType("time.Time")).Return(int64(123), nil).Once()
+		mockOrderStorage.On("InsertOrder", ctx, order, model.Quantity, noRedemption).Return(nil)
+
+		err := o.InsertOrder(ctx, request)
+
+		assert.NoError(t, err)
+		mockOrderStorage.AssertExpectations(t)
+	})
+
+	t.Run("merged into a pending order", func(t *testing.T) {
+		order := schema
+		order.Number = ""
+
+		// no NextNumber is expected, so taking a number fails the test
+		mockOrderStorage.On("HasPendingOrder", ctx, int64(1), int64(7)).Return(true, nil).Once()
+		mockOrderStorage.On("InsertOrder", ctx, order, model.Quantity, noRedemption).Return(nil).Once()
+
+		err := o.InsertOrder(ctx, model)
+
+		assert.NoError(t, err)
+		mockOrderStorage.AssertExpectations(t)
+	})
+
+	t.Run("pending order paid meanwhile", func(t *testing.T) {
+		order := schema
+		order.Number = ""
+
+		mockOrderStorage.On("HasPendingOrder", ctx, int64(1), int64(7)).Return(true, nil).Once()
+		mockOrderStorage.On("InsertOrder", ctx, order, model.Quantity, noRedemption).Return(errors.New("order number required")).Once()
+		mockOrderStorage.On("NextNumber", ctx, mock.AnythingOfType("time.Time")).Return(int64(123), nil).Once()
+
+		err := o.InsertOrder(ctx, model)
+
+		assert.NoError(t, err)
+		mockOrderStorage.AssertExpectations(t)
+		last := mockOrderStorage.Calls[len(mockOrderStorage.Calls)-1]
+		assert.Equal(t, "InsertOrder", last.Method)
+		assert.Equal(t, schema, last.Arguments.Get(1))
+	})
+
+	t.Run("not enough in any warehouse", func(t *testing.T) {
+		request := model
+		request.ProductId = 5
+		stocked := product
+		stocked.Id = 5
+
+		mockProductStorage.On("GetById", ctx, int64(5)).Return(&stocked, nil)
+		mockProductStorage.On("GetVariants", ctx, int64(5)).Return(variants, nil)
+		mockProductStorage.On("GetStock", ctx, []int64{5}).Return([]inventory.Stock{
+			{ProductId: 5, VariantId: 7, WarehouseId: 1, Province: "DKI Jakarta", City: "Jakarta Utara", Active: true, Qty: 1},
+			{ProductId: 5, VariantId: 7, WarehouseId: 2, Province: "Jawa Barat", City: "Bekasi", Active: true, Qty: 1},
+		}, nil)
+
+		err := o.InsertOrder(ctx, request)
+
+		assert.EqualError(t, err, "not enough product quantity")
+	})
+
+	t.Run("amount of an old price", func(t *testing.T) {
+		request := model
+		request.VariantId = 8
+
+		err := o.InsertOrder(ctx, request)
+
+		assert.EqualError(t, err, "amount does not match the current price")
+	})
+
+	t.Run("variant of another product", func(t *testing.T) {
+		request := model
+		request.VariantId = 9
+
+		mockProductStorage.On("GetVariantById", ctx, int64(9)).Return(&otherVariant, nil)
+
+		err := o.InsertOrder(ctx, request)
+
+		assert.EqualError(t, err, "variant not found")
+		mockProductStorage.AssertExpectations(t)
+	})
+
+	t.Run("draft product", func(t *testing.T) {
+		request := model
+		request.ProductId = 2
+		draft := product
+		draft.Id = 2
+		draft.Status = "draft"
+
+		mockProductStorage.On("GetById", ctx, int64(2)).Return(&draft, nil)
+
+		err := o.InsertOrder(ctx, request)
+
+		assert.EqualError(t, err, "product is not available")
+		mockProductStorage.AssertExpectations(t)
+	})
+
+	t.Run("below minimum order quantity", func(t *testing.T) {
+		request := model
+		request.ProductId = 3
+		bulk := product
+		bulk.Id = 3
+		bulk.MinOrderQty = 10
+
+		mockProductStorage.On("GetById", ctx, int64(3)).Return(&bulk, nil)
+
+		err := o.InsertOrder(ctx, request)
+
+		assert.EqualError(t, err, "quantity is below the minimum order quantity")
+		mockProductStorage.AssertExpectations(t)
+	})
 }
 
 func TestGetAllOrder(t *testing.T) {
 	mockProductStorage := new(mocks.ProductStorage)
 	mockOrderStorage := new(mocks.OrderStorage)
-	o := NewOrderUsecase(mockOrderStorage, mockProductStorage)
+	mockAddressStorage := new(mocks.AddressStorage)
+	mockRateProvider := new(mocks.ShippingRateProvider)
+	mockCouponStorage := new(mocks.CouponStorage)
+	o := NewOrderUsecase(mockOrderStorage, mockProductStorage, mockAddressStorage, mockRateProvider, origin, taxEngine, mockCouponStorage, new(mocks.CategoryStorage), numberFormat)
 	ctx := context.Background()
 
 	mockOrderList := []model.OrderResponse{
@@ -70,11 +361,13 @@ func TestGetAllOrder(t *testing.T) {
 	}
 
 	t.Run("success", func(t *testing.T) {
-		mockOrderStorage.On("GetAllOrder", mock.Anything).Return(mockOrderList, nil)
+		query := model.ListQuery{Page: 1, Size: 20, Sort: "id"}
+		mockOrderStorage.On("GetAllOrder", mock.Anything, query).Return(mockOrderList, int64(2), nil)
 
-		list, err := o.GetAllOrder(ctx)
+		list, total, err := o.GetAllOrder(ctx, query)
 
 		assert.NotNil(t, list)
+		assert.Equal(t, int64(2), total)
 		assert.Nil(t, err)
 		assert.NoError(t, err)
 		assert.Equal(t, mockOrderList[0].UserName, "Agung")
@@ -86,7 +379,10 @@ func TestGetAllOrder(t *testing.T) {
 func TestGetAllOrderPerUser(t *testing.T) {
 	mockProductStorage := new(mocks.ProductStorage)
 	mockOrderStorage := new(mocks.OrderStorage)
-	o := NewOrderUsecase(mockOrderStorage, mockProductStorage)
+	mockAddressStorage := new(mocks.AddressStorage)
+	mockRateProvider := new(mocks.ShippingRateProvider)
+	mockCouponStorage := new(mocks.CouponStorage)
+	o := NewOrderUsecase(mockOrderStorage, mockProductStorage, mockAddressStorage, mockRateProvider, origin, taxEngine, mockCouponStorage, new(mocks.CategoryStorage), numberFormat)
 	ctx := context.Background()
 	var userId uint64 = 1
 
@@ -112,11 +408,13 @@ func TestGetAllOrderPerUser(t *testing.T) {
 	}
 
 	t.Run("success", func(t *testing.T) {
-		mockOrderStorage.On("GetAllOrderPerUser", mock.Anything, userId).Return(mockOrderList, nil)
+		query := model.ListQuery{Page: 1, Size: 20, Sort: "id", Status: "paid"}
+		mockOrderStorage.On("GetAllOrderPerUser", mock.Anything, userId, query).Return(mockOrderList, int64(2), nil)
 
-		list, err := o.GetAllOrderPerUser(ctx, userId)
+		list, total, err := o.GetAllOrderPerUser(ctx, userId, query)
 
 		assert.NotNil(t, list)
+		assert.Equal(t, int64(2), total)
 		assert.Nil(t, err)
 		assert.NoError(t, err)
 		assert.Equal(t, mockOrderList[0].UserName, "Agung")
@@ -128,7 +426,10 @@ func TestGetAllOrderPerUser(t *testing.T) {
 func TestGetOrderById(t *testing.T) {
 	mockProductStorage := new(mocks.ProductStorage)
 	mockOrderStorage := new(mocks.OrderStorage)
-	o := NewOrderUsecase(mockOrderStorage, mockProductStorage)
+	mockAddressStorage := new(mocks.AddressStorage)
+	mockRateProvider := new(mocks.ShippingRateProvider)
+	mockCouponStorage := new(mocks.CouponStorage)
+	o := NewOrderUsecase(mockOrderStorage, mockProductStorage, mockAddressStorage, mockRateProvider, origin, taxEngine, mockCouponStorage, new(mocks.CategoryStorage), numberFormat)
 	ctx := context.Background()
 	var userId uint64 = 1
 	var orderId int64 = 1
@@ -156,10 +457,30 @@ func TestGetOrderById(t *testing.T) {
 	})
 }
 
+func TestGetOrderIdByNumber(t *testing.T) {
+	mockOrderStorage := new(mocks.OrderStorage)
+	o := NewOrderUsecase(mockOrderStorage, new(mocks.ProductStorage), new(mocks.AddressStorage),
+		new(mocks.ShippingRateProvider), origin, taxEngine, new(mocks.CouponStorage), new(mocks.CategoryStorage), numberFormat)
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		mockOrderStorage.On("GetOrderIdByNumber", mock.Anything, "KG-20261018-000123").Return(int64(1), nil)
+
+		id, err := o.GetOrderIdByNumber(ctx, "KG-20261018-000123")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), id)
+		mockOrderStorage.AssertExpectations(t)
+	})
+}
+
 func TestUpdatePayment(t *testing.T) {
 	mockProductStorage := new(mocks.ProductStorage)
 	mockOrderStorage := new(mocks.OrderStorage)
-	o := NewOrderUsecase(mockOrderStorage, mockProductStorage)
+	mockAddressStorage := new(mocks.AddressStorage)
+	mockRateProvider := new(mocks.ShippingRateProvider)
+	mockCouponStorage := new(mocks.CouponStorage)
+	o := NewOrderUsecase(mockOrderStorage, mockProductStorage, mockAddressStorage, mockRateProvider, origin, taxEngine, mockCouponStorage, new(mocks.CategoryStorage), numberFormat)
 	ctx := context.Background()
 
 	model := model.PaymentRequest{
@@ -183,3 +504,75 @@ func TestUpdatePayment(t *testing.T) {
 		mockProductStorage.AssertExpectations(t)
 	})
 }
+
+func TestCancelOrder(t *testing.T) {
+	ctx := context.Background()
+	request := model.CancelOrderRequest{Reason: "changed my mind", Version: 3}
+
+	t.Run("owner", func(t *testing.T) {
+		mockOrderStorage := new(mocks.OrderStorage)
+		o := NewOrderUsecase(mockOrderStorage, new(mocks.ProductStorage), new(mocks.AddressStorage),
+			new(mocks.ShippingRateProvider), origin, taxEngine, new(mocks.CouponStorage), new(mocks.CategoryStorage), numberFormat)
+
+		mockOrderStorage.On("GetOrderById", mock.Anything, int64(5), uint64(1)).Return(&model.OrderResponse{OrderId: 5}, nil)
+		mockOrderStorage.On("CancelOrder", mock.Anything, int64(5), int64(1), "user", "changed my mind", uint(3)).Return(nil)
+
+		err := o.CancelOrder(ctx, 5, 1, false, request)
+
+		assert.NoError(t, err)
+		mockOrderStorage.AssertExpectations(t)
+	})
+
+	t.Run("other user", func(t *testing.T) {
+		mockOrderStorage := new(mocks.OrderStorage)
+		o := NewOrderUsecase(mockOrderStorage, new(mocks.ProductStorage), new(mocks.AddressStorage),
+			new(mocks.ShippingRateProvider), origin, taxEngine, new(mocks.CouponStorage), new(mocks.CategoryStorage), numberFormat)
+
+		mockOrderStorage.On("GetOrderById", mock.Anything, int64(5), uint64(2)).Return(nil, sql.ErrNoRows)
+
+		err := o.CancelOrder(ctx, 5, 2, false, request)
+
+		assert.Equal(t, sql.ErrNoRows, err)
+		mockOrderStorage.AssertNotCalled(t, "CancelOrder", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("admin", func(t *testing.T) {
+		mockOrderStorage := new(mocks.OrderStorage)
+		o := NewOrderUsecase(mockOrderStorage, new(mocks.ProductStorage), new(mocks.AddressStorage),
+			new(mocks.ShippingRateProvider), origin, taxEngine, new(mocks.CouponStorage), new(mocks.CategoryStorage), numberFormat)
+
+		mockOrderStorage.On("CancelOrder", mock.Anything, int64(5), int64(9), "admin", "changed my mind", uint(3)).Return(nil)
+
+		err := o.CancelOrder(ctx, 5, 9, true, request)
+
+		assert.NoError(t, err)
+		mockOrderStorage.AssertExpectations(t)
+	})
+}
+
+func TestGetEvents(t *testing.T) {
+	mockOrderStorage := new(mocks.OrderStorage)
+	o := NewOrderUsecase(mockOrderStorage, new(mocks.ProductStorage), new(mocks.AddressStorage),
+		new(mocks.ShippingRateProvider), origin, taxEngine, new(mocks.CouponStorage), new(mocks.CategoryStorage), numberFormat)
+	ctx := context.Background()
+
+	events := []schema.OrderEvent{
+		{Base: schema.Base{Id: 1}, OrderId: 5, Type: schema.OrderEventCreated, ToStatus: "pending",
+			ActorId: 1, ActorRole: "user", Payload: `{"amount":111000}`},
+		{Base: schema.Base{Id: 2}, OrderId: 5, Type: schema.OrderEventPaid, FromStatus: "pending", ToStatus: "paid",
+			ActorId: 1, ActorRole: "user", Payload: ""},
+	}
+
+	t.Run("owner", func(t *testing.T) {
+		mockOrderStorage.On("GetOrderById", mock.Anything, int64(5), uint64(1)).Return(&model.OrderResponse{OrderId: 5}, nil)
+		mockOrderStorage.On("GetEvents", mock.Anything, int64(5)).Return(events, nil)
+
+		res, err := o.GetEvents(ctx, 5, 1, false)
+
+		assert.NoError(t, err)
+		assert.Len(t, res, 2)
+		assert.Equal(t, `{"amount":111000}`, string(res[0].Payload))
+		assert.Equal(t, "null", string(res[1].Payload))
+		mockOrderStorage.AssertExpectations(t)
+	})
+}
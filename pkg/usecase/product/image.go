@@ -0,0 +1,146 @@
+package product
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/entity/schema"
+	"kanggo/pkg/imaging"
+)
+
+// UploadImage stores the image and its thumbnail and appends it to the
+// images of the product.
+func (p *productUsecase) UploadImage(ctx context.Context, productId int64, data []byte) (*model.ProductImageResponse, error) {
+	if _, err := p.productStorage.GetById(ctx, productId); err != nil {
+		return nil, err
+	}
+
+	img, err := imaging.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	thumbnail, err := imaging.EncodeJPEG(imaging.Thumbnail(img.Image, imaging.ThumbnailSize))
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := randomName()
+	if err != nil {
+		return nil, err
+	}
+
+	request := schema.ProductImage{
+		ProductId:    productId,
+		ObjectKey:    fmt.Sprintf("products/%d/%s%s", productId, name, img.Extension),
+		ThumbnailKey: fmt.Sprintf("products/%d/%s_thumb.jpg", productId, name),
+		ContentType:  img.ContentType,
+		Size:         int64(len(data)),
+		Width:        int64(img.Width),
+		Height:       int64(img.Height),
+	}
+
+	if err := p.blobStore.Put(ctx, request.ObjectKey, data, img.ContentType); err != nil {
+		return nil, err
+	}
+
+	if err := p.blobStore.Put(ctx, request.ThumbnailKey, thumbnail, "image/jpeg"); err != nil {
+		p.removeBlobs(ctx, request)
+		return nil, err
+	}
+
+	res, err := p.productStorage.InsertImage(ctx, request)
+	if err != nil {
+		p.removeBlobs(ctx, request)
+		return nil, err
+	}
+
+	image := p.toImageResponse(*res)
+
+	return &image, nil
+}
+
+func (p *productUsecase) GetImages(ctx context.Context, productId int64) ([]model.ProductImageResponse, error) {
+	if _, err := p.productStorage.GetById(ctx, productId); err != nil {
+		return nil, err
+	}
+
+	images, err := p.images(ctx, []int64{productId})
+	if err != nil {
+		return nil, err
+	}
+
+	return images[productId], nil
+}
+
+func (p *productUsecase) ReorderImages(ctx context.Context, productId int64, data model.ProductImageOrderRequest) error {
+	return p.productStorage.ReorderImages(ctx, productId, data.ImageIds)
+}
+
+func (p *productUsecase) DeleteImage(ctx context.Context, productId, id int64) error {
+	image, err := p.productStorage.GetImageById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := p.productStorage.DeleteImage(ctx, productId, id); err != nil {
+		return err
+	}
+
+	p.removeBlobs(ctx, *image)
+
+	return nil
+}
+
+// images returns the images of the products, keyed by product id.
+func (p *productUsecase) images(ctx context.Context, productIds []int64) (map[int64][]model.ProductImageResponse, error) {
+	res, err := p.productStorage.GetImages(ctx, productIds)
+	if err != nil {
+		return nil, err
+	}
+
+	images := map[int64][]model.ProductImageResponse{}
+	for _, id := range productIds {
+		images[id] = []model.ProductImageResponse{}
+	}
+
+	for i := range res {
+		images[res[i].ProductId] = append(images[res[i].ProductId], p.toImageResponse(res[i]))
+	}
+
+	return images, nil
+}
+
+// removeBlobs deletes the files of images that are gone or never made it
+// into the database. A file left behind is only wasted space, so failures
+// are not reported.
+func (p *productUsecase) removeBlobs(ctx context.Context, images ...schema.ProductImage) {
+	for _, image := range images {
+		_ = p.blobStore.Delete(ctx, image.ObjectKey)
+		_ = p.blobStore.Delete(ctx, image.ThumbnailKey)
+	}
+}
+
+func (p *productUsecase) toImageResponse(image schema.ProductImage) model.ProductImageResponse {
+	return model.ProductImageResponse{
+		Id:           int64(image.Id),
+		Url:          p.blobStore.URL(image.ObjectKey),
+		ThumbnailUrl: p.blobStore.URL(image.ThumbnailKey),
+		ContentType:  image.ContentType,
+		Size:         image.Size,
+		Width:        image.Width,
+		Height:       image.Height,
+		Position:     image.Position,
+	}
+}
+
+func randomName() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
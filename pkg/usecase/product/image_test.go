@@ -0,0 +1,102 @@
+package product
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+
+	"kanggo/pkg/entity/schema"
+	"kanggo/pkg/imaging"
+	"kanggo/pkg/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUploadImage(t *testing.T) {
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 640, 480))))
+	data := buf.Bytes()
+
+	isImage := mock.MatchedBy(func(image schema.ProductImage) bool {
+		return image.ProductId == 1 && image.ContentType == "image/png" && image.Width == 640 && image.Height == 480 &&
+			strings.HasPrefix(image.ObjectKey, "products/1/") && strings.HasSuffix(image.ObjectKey, ".png") &&
+			strings.HasSuffix(image.ThumbnailKey, "_thumb.jpg")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		mockProductStorage := new(mocks.ProductStorage)
+		mockBlobStore := new(mocks.BlobStore)
+		p := NewProductUsecase(mockProductStorage, new(mocks.Searcher), new(mocks.CategoryStorage), mockBlobStore, 0)
+
+		mockProductStorage.On("GetById", mock.Anything, int64(1)).Return(&schema.Product{Base: schema.Base{Id: 1}}, nil)
+		mockBlobStore.On("Put", mock.Anything, mock.AnythingOfType("string"), data, "image/png").Return(nil).Once()
+		mockBlobStore.On("Put", mock.Anything, mock.AnythingOfType("string"), mock.Anything, "image/jpeg").Return(nil).Once()
+		mockProductStorage.On("InsertImage", mock.Anything, isImage).Return(&schema.ProductImage{
+			Base: schema.Base{Id: 9}, ProductId: 1, ObjectKey: "products/1/a.png", ThumbnailKey: "products/1/a_thumb.jpg",
+			ContentType: "image/png", Width: 640, Height: 480, Position: 2,
+		}, nil)
+		mockBlobStore.On("URL", "products/1/a.png").Return("/uploads/products/1/a.png")
+		mockBlobStore.On("URL", "products/1/a_thumb.jpg").Return("/uploads/products/1/a_thumb.jpg")
+
+		res, err := p.UploadImage(ctx, 1, data)
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, 9, res.Id)
+		assert.EqualValues(t, 2, res.Position)
+		assert.Equal(t, "/uploads/products/1/a_thumb.jpg", res.ThumbnailUrl)
+		mockProductStorage.AssertExpectations(t)
+		mockBlobStore.AssertExpectations(t)
+	})
+
+	t.Run("not an image", func(t *testing.T) {
+		mockProductStorage := new(mocks.ProductStorage)
+		p := NewProductUsecase(mockProductStorage, new(mocks.Searcher), new(mocks.CategoryStorage), new(mocks.BlobStore), 0)
+
+		mockProductStorage.On("GetById", mock.Anything, int64(1)).Return(&schema.Product{Base: schema.Base{Id: 1}}, nil)
+
+		_, err := p.UploadImage(ctx, 1, []byte("%PDF-1.4"))
+
+		assert.Equal(t, imaging.ErrUnsupported, err)
+	})
+
+	t.Run("files removed when the insert fails", func(t *testing.T) {
+		mockProductStorage := new(mocks.ProductStorage)
+		mockBlobStore := new(mocks.BlobStore)
+		p := NewProductUsecase(mockProductStorage, new(mocks.Searcher), new(mocks.CategoryStorage), mockBlobStore, 0)
+
+		mockProductStorage.On("GetById", mock.Anything, int64(1)).Return(&schema.Product{Base: schema.Base{Id: 1}}, nil)
+		mockBlobStore.On("Put", mock.Anything, mock.AnythingOfType("string"), mock.Anything, mock.AnythingOfType("string")).Return(nil)
+		mockProductStorage.On("InsertImage", mock.Anything, isImage).Return(nil, errors.New("product not found"))
+		mockBlobStore.On("Delete", mock.Anything, mock.AnythingOfType("string")).Return(nil)
+
+		_, err := p.UploadImage(ctx, 1, data)
+
+		assert.EqualError(t, err, "product not found")
+		mockBlobStore.AssertNumberOfCalls(t, "Delete", 2)
+	})
+}
+
+func TestDeleteImage(t *testing.T) {
+	mockProductStorage := new(mocks.ProductStorage)
+	mockBlobStore := new(mocks.BlobStore)
+	p := NewProductUsecase(mockProductStorage, new(mocks.Searcher), new(mocks.CategoryStorage), mockBlobStore, 0)
+	ctx := context.Background()
+
+	t.Run("image of another product", func(t *testing.T) {
+		mockProductStorage.On("GetImageById", mock.Anything, int64(9)).
+			Return(&schema.ProductImage{Base: schema.Base{Id: 9}, ProductId: 2}, nil)
+		mockProductStorage.On("DeleteImage", mock.Anything, int64(1), int64(9)).Return(errors.New("data not found"))
+
+		err := p.DeleteImage(ctx, 1, 9)
+
+		assert.EqualError(t, err, "data not found")
+		mockBlobStore.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,519 @@
+package product
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/entity/schema"
+	"kanggo/pkg/sheet"
+	storage "kanggo/pkg/storage/product"
+	"log"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	// importBatchSize is the number of rows written per transaction.
+	importBatchSize = 100
+	// asyncImportRows is the number of rows above which an import runs in
+	// the background.
+	asyncImportRows = 500
+	// maxImportErrors caps the rejected rows kept with a job.
+	maxImportErrors = 1000
+)
+
+// ErrImportFile wraps the problems with an import file as a whole, as
+// opposed to those of single rows.
+var ErrImportFile = errors.New("invalid import file")
+
+// importColumns are the columns of the import and export files. The sku
+// identifies the variant a row is for.
+var importColumns = []string{"sku", "name", "description", "price", "qty", "weight", "length", "width",
+	"height", "tax_category", "unit", "brand", "barcode", "min_order_qty", "status"}
+
+// importFields are the fields of model.ProductRequest the columns are
+// validated as.
+var importFields = map[string]string{
+	"sku":           "Sku",
+	"name":          "Name",
+	"description":   "Description",
+	"price":         "Price",
+	"qty":           "Qty",
+	"weight":        "Weight",
+	"length":        "Length",
+	"width":         "Width",
+	"height":        "Height",
+	"tax_category":  "TaxCategory",
+	"unit":          "Unit",
+	"brand":         "Brand",
+	"barcode":       "Barcode",
+	"min_order_qty": "MinOrderQty",
+	"status":        "Status",
+}
+
+// ImportProducts checks every row of the file and upserts the valid ones by
+// sku, or with a dry run only reports what would happen. A large file, or
+// any file when asked, is imported in the background; the job returned is
+// then still pending.
+func (p *productUsecase) ImportProducts(ctx context.Context, filename string, data []byte,
+	request model.ProductImportRequest, userId int64) (*model.ProductImportJobResponse, error) {
+	format, err := sheet.FormatOf(filename)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrImportFile, err)
+	}
+
+	lines, err := sheet.Read(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrImportFile, err)
+	}
+
+	columns, err := importHeader(lines[0], request.Mapping)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrImportFile, err)
+	}
+
+	rows, rejected := parseImportRows(lines[1:], columns)
+
+	job, err := p.productStorage.InsertImportJob(ctx, schema.ProductImportJob{
+		Filename:  filename,
+		DryRun:    request.DryRun,
+		Status:    schema.ImportPending,
+		Total:     int64(len(rows) + len(rejected)),
+		CreatedBy: userId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Async || len(lines)-1 > asyncImportRows {
+		go func(job schema.ProductImportJob) {
+			if _, err := p.runImport(context.Background(), job, rows, rejected); err != nil {
+				log.Printf("import job %d: %v", job.Id, err)
+			}
+		}(*job)
+
+		return toImportJobResponse(*job)
+	}
+
+	finished, err := p.runImport(ctx, *job, rows, rejected)
+	if err != nil {
+		return nil, err
+	}
+
+	return toImportJobResponse(finished)
+}
+
+// runImport writes the rows in batches and records the outcome with the
+// job. A failing batch fails the job, leaving the batches before it in
+// place. The error returned is about saving the job itself.
+func (p *productUsecase) runImport(ctx context.Context, job schema.ProductImportJob, rows []storage.ImportRow,
+	rejected []model.ProductImportError) (schema.ProductImportJob, error) {
+	job.Status = schema.ImportRunning
+	if err := p.productStorage.UpdateImportJob(ctx, job); err != nil {
+		return job, err
+	}
+
+	rows, rejected, existing, err := p.checkNewProducts(ctx, rows, rejected)
+	if err == nil && job.DryRun {
+		for _, row := range rows {
+			if existing[row.Sku] {
+				job.Updated++
+			} else {
+				job.Created++
+			}
+		}
+	}
+
+	for start := 0; err == nil && !job.DryRun && start < len(rows); start += importBatchSize {
+		end := start + importBatchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		var results []storage.ImportResult
+		results, err = p.productStorage.Import(ctx, rows[start:end])
+		for i, result := range results {
+			switch {
+			case result.Err != nil:
+				rejected = append(rejected, model.ProductImportError{
+					Line: result.Line, Sku: rows[start+i].Sku, Errors: []string{result.Err.Error()},
+				})
+			case result.Created:
+				job.Created++
+			default:
+				job.Updated++
+			}
+		}
+	}
+
+	job.Status = schema.ImportDone
+	if err != nil {
+		job.Status = schema.ImportFailed
+		job.Message = err.Error()
+	}
+
+	sort.Slice(rejected, func(i, j int) bool { return rejected[i].Line < rejected[j].Line })
+	job.Failed = int64(len(rejected))
+	if len(rejected) > maxImportErrors {
+		rejected = rejected[:maxImportErrors]
+	}
+
+	errs, err := json.Marshal(rejected)
+	if err != nil {
+		return job, err
+	}
+	job.Errors = string(errs)
+
+	now := time.Now()
+	job.FinishedAt = &now
+
+	return job, p.productStorage.UpdateImportJob(ctx, job)
+}
+
+// checkNewProducts looks up which skus exist and rejects the rows that
+// would create a product without its name or price.
+func (p *productUsecase) checkNewProducts(ctx context.Context, rows []storage.ImportRow,
+	rejected []model.ProductImportError) ([]storage.ImportRow, []model.ProductImportError, map[string]bool, error) {
+	existing := map[string]bool{}
+	for start := 0; start < len(rows); start += 1000 {
+		end := start + 1000
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		skus := []string{}
+		for _, row := range rows[start:end] {
+			skus = append(skus, row.Sku)
+		}
+
+		found, err := p.productStorage.FindSkus(ctx, skus)
+		if err != nil {
+			return nil, rejected, nil, err
+		}
+		for _, sku := range found {
+			existing[sku] = true
+		}
+	}
+
+	valid := []storage.ImportRow{}
+	for _, row := range rows {
+		if !existing[row.Sku] && (!row.Has("name") || !row.Has("price")) {
+			rejected = append(rejected, model.ProductImportError{
+				Line: row.Line, Sku: row.Sku, Errors: []string{"name and price are required for a new product"},
+			})
+			continue
+		}
+
+		valid = append(valid, row)
+	}
+
+	return valid, rejected, existing, nil
+}
+
+func (p *productUsecase) GetImportJob(ctx context.Context, id int64) (*model.ProductImportJobResponse, error) {
+	job, err := p.productStorage.GetImportJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toImportJobResponse(*job)
+}
+
+// importHeader finds the column of the file each product column is in.
+// Headers are matched without regard to case; the sku column is required.
+func importHeader(header []string, mapping string) (map[string]int, error) {
+	names := map[string]string{}
+	if mapping != "" {
+		if err := json.Unmarshal([]byte(mapping), &names); err != nil {
+			return nil, errors.New("mapping must be a JSON object of column to header")
+		}
+	}
+
+	for column := range names {
+		if _, ok := importFields[column]; !ok {
+			return nil, fmt.Errorf("unknown column %q in mapping", column)
+		}
+	}
+
+	positions := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := positions[name]; !ok {
+			positions[name] = i
+		}
+	}
+
+	columns := map[string]int{}
+	for _, column := range importColumns {
+		name, mapped := names[column]
+		if !mapped {
+			name = column
+		}
+
+		if i, ok := positions[strings.ToLower(strings.TrimSpace(name))]; ok {
+			columns[column] = i
+		} else if mapped {
+			return nil, fmt.Errorf("column %q not found", name)
+		}
+	}
+
+	if _, ok := columns["sku"]; !ok {
+		return nil, errors.New("missing column: sku")
+	}
+
+	return columns, nil
+}
+
+// parseImportRows turns the lines after the header into rows, validated
+// like a product sent to the API. Blank lines are skipped.
+func parseImportRows(lines [][]string, columns map[string]int) ([]storage.ImportRow, []model.ProductImportError) {
+	validate := validator.New()
+	rows := []storage.ImportRow{}
+	rejected := []model.ProductImportError{}
+	seen := map[string]int{}
+
+	for i, cells := range lines {
+		if blankLine(cells) {
+			continue
+		}
+
+		line := i + 2
+		row, errs := parseImportRow(validate, line, cells, columns)
+
+		if first, ok := seen[row.Sku]; ok && row.Sku != "" {
+			errs = append(errs, fmt.Sprintf("sku is already on line %d", first))
+		} else {
+			seen[row.Sku] = line
+		}
+
+		if len(errs) > 0 {
+			rejected = append(rejected, model.ProductImportError{Line: line, Sku: row.Sku, Errors: errs})
+			continue
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, rejected
+}
+
+func parseImportRow(validate *validator.Validate, line int, cells []string, columns map[string]int) (storage.ImportRow, []string) {
+	row := storage.ImportRow{Line: line}
+	request := model.ProductRequest{}
+	fields := []string{}
+	errs := []string{}
+
+	for _, column := range importColumns {
+		i, ok := columns[column]
+		if !ok {
+			continue
+		}
+		row.Columns = append(row.Columns, column)
+
+		var value string
+		if i < len(cells) {
+			value = strings.TrimSpace(cells[i])
+		}
+
+		var err error
+		switch column {
+		case "sku":
+			request.Sku = value
+		case "name":
+			request.Name = value
+		case "description":
+			request.Description = value
+		case "price":
+			request.Price, err = parseDecimal(value)
+		case "qty":
+			var qty int64
+			qty, err = parseWhole(value)
+			request.Qty = int(qty)
+		case "weight":
+			request.Weight, err = parseWhole(value)
+		case "length":
+			request.Length, err = parseWhole(value)
+		case "width":
+			request.Width, err = parseWhole(value)
+		case "height":
+			request.Height, err = parseWhole(value)
+		case "tax_category":
+			request.TaxCategory = value
+		case "unit":
+			request.Unit = value
+		case "brand":
+			request.Brand = value
+		case "barcode":
+			request.Barcode = value
+		case "min_order_qty":
+			request.MinOrderQty, err = parseWhole(value)
+		case "status":
+			request.Status = strings.ToLower(value)
+		}
+		if err != nil {
+			errs = append(errs, column+" must be a number")
+			continue
+		}
+		fields = append(fields, importFields[column])
+	}
+
+	row.Sku = request.Sku
+	if row.Sku == "" {
+		errs = append(errs, "sku is required")
+	}
+
+	if err := validate.StructPartial(request, fields...); err != nil {
+		var invalid validator.ValidationErrors
+		if !errors.As(err, &invalid) {
+			return row, append(errs, err.Error())
+		}
+		for _, fe := range invalid {
+			errs = append(errs, ruleMessage(fe))
+		}
+	}
+
+	row.Product = toProduct(request)
+	if row.Product.TaxCategory == "" {
+		row.Product.TaxCategory = productDefaults["tax_category"].(string)
+	}
+
+	return row, errs
+}
+
+// ruleMessage explains a failed validation rule in terms of the column.
+func ruleMessage(fe validator.FieldError) string {
+	column := fe.Field()
+	for name, field := range importFields {
+		if field == fe.Field() {
+			column = name
+		}
+	}
+
+	unit := ""
+	if fe.Kind() == reflect.String {
+		unit = " characters"
+	}
+
+	switch fe.Tag() {
+	case "required":
+		return column + " is required"
+	case "gt":
+		return column + " must be greater than " + fe.Param()
+	case "min":
+		return column + " must be at least " + fe.Param() + unit
+	case "max":
+		return column + " must be at most " + fe.Param() + unit
+	case "numeric":
+		return column + " must contain digits only"
+	case "oneof":
+		return column + " must be one of " + fe.Param()
+	}
+
+	return column + " is invalid"
+}
+
+// parseDecimal reads a number as spreadsheet programs write it. An empty
+// cell is zero.
+func parseDecimal(value string) (float64, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	return strconv.ParseFloat(value, 64)
+}
+
+// parseWhole reads a whole number, which a spreadsheet may have written as
+// 10.0 or 1E+3.
+func parseWhole(value string) (int64, error) {
+	number, err := parseDecimal(value)
+	if err != nil {
+		return 0, err
+	}
+
+	if number != float64(int64(number)) {
+		return 0, errors.New("not a whole number")
+	}
+
+	return int64(number), nil
+}
+
+func blankLine(cells []string) bool {
+	for _, cell := range cells {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+func toImportJobResponse(job schema.ProductImportJob) (*model.ProductImportJobResponse, error) {
+	rejected := []model.ProductImportError{}
+	if job.Errors != "" {
+		if err := json.Unmarshal([]byte(job.Errors), &rejected); err != nil {
+			return nil, err
+		}
+	}
+
+	return &model.ProductImportJobResponse{
+		Id:         int64(job.Id),
+		Filename:   job.Filename,
+		DryRun:     job.DryRun,
+		Status:     job.Status,
+		Total:      job.Total,
+		Created:    job.Created,
+		Updated:    job.Updated,
+		Failed:     job.Failed,
+		Errors:     rejected,
+		Message:    job.Message,
+		CreatedAt:  job.CreatedAt,
+		FinishedAt: job.FinishedAt,
+	}, nil
+}
+
+// ExportProducts writes the catalogue in the columns of the import file, a
+// row per variant, so an edited export can be imported again.
+func (p *productUsecase) ExportProducts(ctx context.Context, w sheet.Writer) error {
+	if err := w.Write(importColumns); err != nil {
+		return err
+	}
+
+	err := p.productStorage.Export(ctx, func(product schema.Product, variant schema.ProductVariant) error {
+		values := map[string]string{
+			"sku":           variant.Sku,
+			"name":          product.Name,
+			"description":   product.Description,
+			"price":         strconv.FormatFloat(variant.Price, 'f', -1, 64),
+			"qty":           strconv.FormatInt(variant.Qty, 10),
+			"weight":        strconv.FormatInt(variant.Weight, 10),
+			"length":        strconv.FormatInt(product.Length, 10),
+			"width":         strconv.FormatInt(product.Width, 10),
+			"height":        strconv.FormatInt(product.Height, 10),
+			"tax_category":  product.TaxCategory,
+			"unit":          product.Unit,
+			"brand":         product.Brand,
+			"barcode":       product.Barcode,
+			"min_order_qty": strconv.FormatInt(product.MinOrderQty, 10),
+			"status":        product.Status,
+		}
+
+		row := []string{}
+		for _, column := range importColumns {
+			row = append(row, values[column])
+		}
+
+		return w.Write(row)
+	})
+	if err != nil {
+		return err
+	}
+
+	return w.Close()
+}
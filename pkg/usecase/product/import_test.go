@@ -0,0 +1,168 @@
+package product
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/entity/schema"
+	"kanggo/pkg/mocks"
+	"kanggo/pkg/sheet"
+	storage "kanggo/pkg/storage/product"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestImportProducts(t *testing.T) {
+	ctx := context.Background()
+
+	file := []byte("SKU,Nama,Harga,Qty\n" +
+		"SEMEN-50KG,Semen 50kg,65000,10\n" +
+		"PASIR-1M3,,abc,2\n" +
+		"\n" +
+		"BATA-MERAH,Bata Merah,900,\n" +
+		"SEMEN-50KG,Semen 50kg,66000,4\n")
+	mapping := `{"name": "Nama", "price": "Harga"}`
+
+	t.Run("dry run", func(t *testing.T) {
+		mockProductStorage := new(mocks.ProductStorage)
+		p := NewProductUsecase(mockProductStorage, new(mocks.Searcher), new(mocks.CategoryStorage), new(mocks.BlobStore), 0)
+
+		mockProductStorage.On("InsertImportJob", ctx, mock.MatchedBy(func(job schema.ProductImportJob) bool {
+			return job.Total == 4 && job.DryRun && job.Status == schema.ImportPending
+		})).Return(&schema.ProductImportJob{Base: schema.Base{Id: 3}, DryRun: true, Total: 4}, nil).Once()
+		mockProductStorage.On("UpdateImportJob", ctx, mock.Anything).Return(nil).Twice()
+		mockProductStorage.On("FindSkus", ctx, []string{"SEMEN-50KG", "BATA-MERAH"}).Return([]string{"BATA-MERAH"}, nil).Once()
+
+		res, err := p.ImportProducts(ctx, "katalog.csv", file, model.ProductImportRequest{DryRun: true, Mapping: mapping}, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, schema.ImportDone, res.Status)
+		assert.EqualValues(t, 1, res.Created)
+		assert.EqualValues(t, 1, res.Updated)
+		assert.EqualValues(t, 2, res.Failed)
+		assert.Equal(t, []model.ProductImportError{
+			{Line: 3, Sku: "PASIR-1M3", Errors: []string{"price must be a number", "name is required"}},
+			{Line: 6, Sku: "SEMEN-50KG", Errors: []string{"sku is already on line 2"}},
+		}, res.Errors)
+		mockProductStorage.AssertNotCalled(t, "Import", mock.Anything, mock.Anything)
+		mockProductStorage.AssertExpectations(t)
+	})
+
+	t.Run("import", func(t *testing.T) {
+		mockProductStorage := new(mocks.ProductStorage)
+		p := NewProductUsecase(mockProductStorage, new(mocks.Searcher), new(mocks.CategoryStorage), new(mocks.BlobStore), 0)
+
+		rows := mock.MatchedBy(func(rows []storage.ImportRow) bool {
+			return len(rows) == 2 && rows[0].Sku == "SEMEN-50KG" && rows[0].Product.Price == 65000 &&
+				rows[1].Sku == "BATA-MERAH" && rows[1].Has("qty") && rows[1].Product.Qty == 0
+		})
+
+		mockProductStorage.On("InsertImportJob", ctx, mock.Anything).Return(&schema.ProductImportJob{Base: schema.Base{Id: 4}, Total: 4}, nil).Once()
+		mockProductStorage.On("UpdateImportJob", ctx, mock.Anything).Return(nil).Twice()
+		mockProductStorage.On("FindSkus", ctx, mock.Anything).Return([]string{}, nil).Once()
+		mockProductStorage.On("Import", ctx, rows).Return([]storage.ImportResult{
+			{Line: 2, Created: true},
+			{Line: 5, Err: errors.New("barcode is already used by another product")},
+		}, nil).Once()
+
+		res, err := p.ImportProducts(ctx, "katalog.csv", file, model.ProductImportRequest{Mapping: mapping}, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, schema.ImportDone, res.Status)
+		assert.EqualValues(t, 1, res.Created)
+		assert.EqualValues(t, 0, res.Updated)
+		assert.EqualValues(t, 3, res.Failed)
+		assert.Equal(t, []int{3, 5, 6}, []int{res.Errors[0].Line, res.Errors[1].Line, res.Errors[2].Line})
+		assert.Equal(t, []string{"barcode is already used by another product"}, res.Errors[1].Errors)
+		mockProductStorage.AssertExpectations(t)
+	})
+
+	t.Run("new product without name", func(t *testing.T) {
+		mockProductStorage := new(mocks.ProductStorage)
+		p := NewProductUsecase(mockProductStorage, new(mocks.Searcher), new(mocks.CategoryStorage), new(mocks.BlobStore), 0)
+
+		mockProductStorage.On("InsertImportJob", ctx, mock.Anything).Return(&schema.ProductImportJob{Base: schema.Base{Id: 5}, Total: 2}, nil).Once()
+		mockProductStorage.On("UpdateImportJob", ctx, mock.Anything).Return(nil).Twice()
+		mockProductStorage.On("FindSkus", ctx, []string{"SEMEN-50KG", "CAT-5KG"}).Return([]string{"SEMEN-50KG"}, nil).Once()
+		mockProductStorage.On("Import", ctx, mock.MatchedBy(func(rows []storage.ImportRow) bool {
+			return len(rows) == 1 && rows[0].Sku == "SEMEN-50KG" && !rows[0].Has("name")
+		})).Return([]storage.ImportResult{{Line: 2}}, nil).Once()
+
+		res, err := p.ImportProducts(ctx, "stok.csv", []byte("sku,qty\nSEMEN-50KG,5\nCAT-5KG,7\n"), model.ProductImportRequest{}, 1)
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, 1, res.Updated)
+		assert.Equal(t, []model.ProductImportError{
+			{Line: 3, Sku: "CAT-5KG", Errors: []string{"name and price are required for a new product"}},
+		}, res.Errors)
+		mockProductStorage.AssertExpectations(t)
+	})
+
+	t.Run("failing batch", func(t *testing.T) {
+		mockProductStorage := new(mocks.ProductStorage)
+		p := NewProductUsecase(mockProductStorage, new(mocks.Searcher), new(mocks.CategoryStorage), new(mocks.BlobStore), 0)
+
+		mockProductStorage.On("InsertImportJob", ctx, mock.Anything).Return(&schema.ProductImportJob{Base: schema.Base{Id: 6}}, nil).Once()
+		mockProductStorage.On("UpdateImportJob", ctx, mock.Anything).Return(nil).Twice()
+		mockProductStorage.On("FindSkus", ctx, mock.Anything).Return([]string{"SEMEN-50KG"}, nil).Once()
+		mockProductStorage.On("Import", ctx, mock.Anything).Return(nil, errors.New("connection reset")).Once()
+
+		res, err := p.ImportProducts(ctx, "katalog.csv", []byte("sku,qty\nSEMEN-50KG,5\n"), model.ProductImportRequest{}, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, schema.ImportFailed, res.Status)
+		assert.Equal(t, "connection reset", res.Message)
+		mockProductStorage.AssertExpectations(t)
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		p := NewProductUsecase(new(mocks.ProductStorage), new(mocks.Searcher), new(mocks.CategoryStorage), new(mocks.BlobStore), 0)
+
+		tests := []struct {
+			name     string
+			filename string
+			data     string
+			mapping  string
+			err      string
+		}{
+			{"format", "katalog.xls", "sku\n", "", "invalid import file: file must be .csv or .xlsx"},
+			{"empty", "katalog.csv", "\n", "", "invalid import file: file has no rows"},
+			{"no sku", "katalog.csv", "name,price\n", "", "invalid import file: missing column: sku"},
+			{"unknown mapping", "katalog.csv", "sku\n", `{"harga": "Harga"}`, `invalid import file: unknown column "harga" in mapping`},
+			{"mapped column missing", "katalog.csv", "sku\n", `{"price": "Harga"}`, `invalid import file: column "Harga" not found`},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := p.ImportProducts(ctx, tt.filename, []byte(tt.data), model.ProductImportRequest{Mapping: tt.mapping}, 1)
+
+				assert.True(t, errors.Is(err, ErrImportFile))
+				assert.EqualError(t, err, tt.err)
+			})
+		}
+	})
+}
+
+func TestExportProducts(t *testing.T) {
+	ctx := context.Background()
+	mockProductStorage := new(mocks.ProductStorage)
+	p := NewProductUsecase(mockProductStorage, new(mocks.Searcher), new(mocks.CategoryStorage), new(mocks.BlobStore), 0)
+
+	mockProductStorage.On("Export", ctx, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		each := args.Get(1).(func(schema.Product, schema.ProductVariant) error)
+		each(schema.Product{Name: "Semen 50kg", TaxCategory: "standard", Unit: "sak", MinOrderQty: 1, Status: "active"},
+			schema.ProductVariant{Sku: "SEMEN-50KG", Price: 65000.5, Qty: 10, Weight: 50000})
+	}).Once()
+
+	var buf bytes.Buffer
+	err := p.ExportProducts(ctx, sheet.NewWriter(&buf, sheet.CSV))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "sku,name,description,price,qty,weight,length,width,height,tax_category,unit,brand,barcode,min_order_qty,status\n"+
+		"SEMEN-50KG,Semen 50kg,,65000.5,10,50000,0,0,0,standard,sak,,,1,active\n", buf.String())
+	mockProductStorage.AssertExpectations(t)
+}
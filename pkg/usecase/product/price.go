@@ -0,0 +1,103 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/entity/schema"
+	"time"
+)
+
+func (p *productUsecase) GetPrices(ctx context.Context, productId int64) ([]model.ProductPriceResponse, error) {
+	if _, err := p.productStorage.GetById(ctx, productId); err != nil {
+		return nil, err
+	}
+
+	res, err := p.productStorage.GetPrices(ctx, productId)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	results := []model.ProductPriceResponse{}
+	for _, price := range res {
+		results = append(results, toPriceResponse(price, now))
+	}
+
+	return results, nil
+}
+
+// SchedulePrice adds a regular or sale price to the history of a variant,
+// taking effect now or at a later time. The past cannot be rewritten.
+func (p *productUsecase) SchedulePrice(ctx context.Context, productId int64, data model.ProductPriceRequest) (*model.ProductPriceResponse, error) {
+	now := time.Now()
+
+	from := data.EffectiveFrom
+	if from.IsZero() {
+		from = now
+	}
+	// a minute of leeway for the clock of the client
+	if from.Before(now.Add(-time.Minute)) {
+		return nil, errors.New("effective_from must not be in the past")
+	}
+
+	switch {
+	case data.Sale && data.EffectiveTo == nil:
+		return nil, errors.New("effective_to is required for a sale price")
+	case !data.Sale && data.EffectiveTo != nil:
+		return nil, errors.New("effective_to is only allowed for a sale price")
+	case data.EffectiveTo != nil && !data.EffectiveTo.After(from):
+		return nil, errors.New("effective_to must be after effective_from")
+	}
+
+	variantId := data.VariantId
+	if variantId == 0 {
+		variants, err := p.productStorage.GetVariants(ctx, productId)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(variants) != 1 {
+			return nil, errors.New("variant_id is required")
+		}
+		variantId = int64(variants[0].Id)
+	}
+
+	res, err := p.productStorage.InsertPrice(ctx, schema.ProductPrice{
+		ProductId:     productId,
+		VariantId:     variantId,
+		Price:         data.Price,
+		Sale:          data.Sale,
+		EffectiveFrom: from,
+		EffectiveTo:   data.EffectiveTo,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := toPriceResponse(*res, now)
+	return &result, nil
+}
+
+func (p *productUsecase) CancelPrice(ctx context.Context, productId, id int64) error {
+	return p.productStorage.DeletePrice(ctx, productId, id)
+}
+
+// ApplyPrices moves the variants whose scheduled price or sale started or
+// ended onto their effective price. It is run by the price scheduler.
+func (p *productUsecase) ApplyPrices(ctx context.Context) (int64, error) {
+	return p.productStorage.ApplyPrices(ctx, time.Now())
+}
+
+func toPriceResponse(price schema.ProductPrice, now time.Time) model.ProductPriceResponse {
+	return model.ProductPriceResponse{
+		Id:            int64(price.Id),
+		VariantId:     price.VariantId,
+		Price:         price.Price,
+		Sale:          price.Sale,
+		EffectiveFrom: price.EffectiveFrom,
+		EffectiveTo:   price.EffectiveTo,
+		Scheduled:     price.EffectiveFrom.After(now),
+		CreatedAt:     price.CreatedAt,
+	}
+}
@@ -0,0 +1,107 @@
+package product
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/entity/schema"
+	"kanggo/pkg/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSchedulePrice(t *testing.T) {
+	ctx := context.Background()
+	mockProductStorage := new(mocks.ProductStorage)
+	p := NewProductUsecase(mockProductStorage, new(mocks.Searcher), new(mocks.CategoryStorage), new(mocks.BlobStore), 0)
+
+	from := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	to := from.Add(7 * 24 * time.Hour)
+
+	t.Run("sale of the only variant", func(t *testing.T) {
+		sale := schema.ProductPrice{ProductId: 1, VariantId: 7, Price: 45000, Sale: true, EffectiveFrom: from, EffectiveTo: &to}
+		inserted := sale
+		inserted.Id = 12
+
+		mockProductStorage.On("GetVariants", ctx, int64(1)).Return([]schema.ProductVariant{{Base: schema.Base{Id: 7}, ProductId: 1}}, nil).Once()
+		mockProductStorage.On("InsertPrice", ctx, sale).Return(&inserted, nil).Once()
+
+		res, err := p.SchedulePrice(ctx, 1, model.ProductPriceRequest{Price: 45000, Sale: true, EffectiveFrom: from, EffectiveTo: &to})
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, 12, res.Id)
+		assert.True(t, res.Scheduled)
+		mockProductStorage.AssertExpectations(t)
+	})
+
+	t.Run("right away", func(t *testing.T) {
+		now := mock.MatchedBy(func(price schema.ProductPrice) bool {
+			return price.VariantId == 8 && time.Since(price.EffectiveFrom) < time.Minute
+		})
+
+		mockProductStorage.On("InsertPrice", ctx, now).Return(&schema.ProductPrice{Base: schema.Base{Id: 13}, VariantId: 8}, nil).Once()
+
+		res, err := p.SchedulePrice(ctx, 1, model.ProductPriceRequest{VariantId: 8, Price: 52000})
+
+		assert.NoError(t, err)
+		assert.False(t, res.Scheduled)
+		mockProductStorage.AssertExpectations(t)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		past := time.Now().Add(-time.Hour)
+
+		tests := []struct {
+			name    string
+			request model.ProductPriceRequest
+			err     string
+		}{
+			{"in the past", model.ProductPriceRequest{VariantId: 7, Price: 1, EffectiveFrom: past}, "effective_from must not be in the past"},
+			{"open ended sale", model.ProductPriceRequest{VariantId: 7, Price: 1, Sale: true}, "effective_to is required for a sale price"},
+			{"regular with an end", model.ProductPriceRequest{VariantId: 7, Price: 1, EffectiveTo: &to}, "effective_to is only allowed for a sale price"},
+			{"ends before it starts", model.ProductPriceRequest{VariantId: 7, Price: 1, Sale: true, EffectiveFrom: to, EffectiveTo: &from}, "effective_to must be after effective_from"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := p.SchedulePrice(ctx, 1, tt.request)
+
+				assert.EqualError(t, err, tt.err)
+			})
+		}
+	})
+
+	t.Run("variant required", func(t *testing.T) {
+		mockProductStorage.On("GetVariants", ctx, int64(2)).Return([]schema.ProductVariant{{Base: schema.Base{Id: 3}}, {Base: schema.Base{Id: 4}}}, nil).Once()
+
+		_, err := p.SchedulePrice(ctx, 2, model.ProductPriceRequest{Price: 45000})
+
+		assert.EqualError(t, err, "variant_id is required")
+	})
+}
+
+func TestGetPrices(t *testing.T) {
+	ctx := context.Background()
+	mockProductStorage := new(mocks.ProductStorage)
+	p := NewProductUsecase(mockProductStorage, new(mocks.Searcher), new(mocks.CategoryStorage), new(mocks.BlobStore), 0)
+
+	from := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	mockProductStorage.On("GetById", ctx, int64(1)).Return(&schema.Product{Base: schema.Base{Id: 1}}, nil)
+	mockProductStorage.On("GetPrices", ctx, int64(1)).Return([]schema.ProductPrice{
+		{Base: schema.Base{Id: 2}, ProductId: 1, VariantId: 7, Price: 50000, EffectiveFrom: from},
+		{Base: schema.Base{Id: 1}, ProductId: 1, VariantId: 7, Price: 48000, EffectiveFrom: from.AddDate(0, -1, 0), EffectiveTo: &from},
+	}, nil)
+
+	res, err := p.GetPrices(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Len(t, res, 2)
+	assert.EqualValues(t, 48000, res[1].Price)
+	assert.Equal(t, from, *res[1].EffectiveTo)
+	assert.Nil(t, res[0].EffectiveTo)
+	mockProductStorage.AssertExpectations(t)
+}
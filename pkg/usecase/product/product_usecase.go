@@ -2,10 +2,19 @@ package product
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"kanggo/pkg/blob"
 	"kanggo/pkg/entity/model"
 	"kanggo/pkg/entity/schema"
+	"kanggo/pkg/search"
+	"kanggo/pkg/sheet"
+	categoryStorage "kanggo/pkg/storage/category"
 	storage "kanggo/pkg/storage/product"
+	"math"
+	"strings"
+	"time"
 )
 
 //go:generate mockery --name ProductUsecase --case snake --output ../../mocks --disable-version-string
@@ -14,30 +23,62 @@ type (
 	ProductUsecase interface {
 		Insert(ctx context.Context, data model.ProductRequest) error
 		Update(ctx context.Context, id uint, data model.ProductRequest) error
-		GetAll(ctx context.Context) ([]model.ProductResponse, error)
+		Patch(ctx context.Context, id uint, data model.ProductPatchRequest) error
+		GetAll(ctx context.Context, query model.ListQuery) ([]model.ProductResponse, int64, error)
 		GetById(ctx context.Context, id int64) (*model.ProductResponse, error)
-		Delete(ctx context.Context, id int64) error
+		Delete(ctx context.Context, id int64, version uint) error
+		Search(ctx context.Context, query search.Query, category string) (*model.ProductSearchResponse, int64, error)
+
+		GetOptions(ctx context.Context, productId int64) ([]model.ProductOptionResponse, error)
+		SetOptions(ctx context.Context, productId int64, data model.ProductOptionsRequest) error
+		GetVariants(ctx context.Context, productId int64) ([]model.ProductVariantResponse, error)
+		InsertVariant(ctx context.Context, productId int64, data model.ProductVariantRequest) error
+		UpdateVariant(ctx context.Context, productId, id int64, data model.ProductVariantRequest) error
+		DeleteVariant(ctx context.Context, productId, id int64) error
+
+		UploadImage(ctx context.Context, productId int64, data []byte) (*model.ProductImageResponse, error)
+		GetImages(ctx context.Context, productId int64) ([]model.ProductImageResponse, error)
+		ReorderImages(ctx context.Context, productId int64, data model.ProductImageOrderRequest) error
+		DeleteImage(ctx context.Context, productId, id int64) error
+
+		GetTrash(ctx context.Context, query model.ListQuery) ([]model.ProductResponse, int64, error)
+		Restore(ctx context.Context, id int64) error
+		Purge(ctx context.Context) (int64, error)
+
+		GetPrices(ctx context.Context, productId int64) ([]model.ProductPriceResponse, error)
+		SchedulePrice(ctx context.Context, productId int64, data model.ProductPriceRequest) (*model.ProductPriceResponse, error)
+		CancelPrice(ctx context.Context, productId, id int64) error
+		ApplyPrices(ctx context.Context) (int64, error)
+
+		ImportProducts(ctx context.Context, filename string, data []byte, request model.ProductImportRequest, userId int64) (*model.ProductImportJobResponse, error)
+		GetImportJob(ctx context.Context, id int64) (*model.ProductImportJobResponse, error)
+		ExportProducts(ctx context.Context, w sheet.Writer) error
 	}
 
 	productUsecase struct {
-		productStorage storage.ProductStorage
+		productStorage  storage.ProductStorage
+		searcher        search.Searcher
+		categoryStorage categoryStorage.CategoryStorage
+		blobStore       blob.BlobStore
+		trashRetention  time.Duration
 	}
 )
 
-func NewProductUsecase(productStorage storage.ProductStorage) ProductUsecase {
+func NewProductUsecase(productStorage storage.ProductStorage, searcher search.Searcher,
+	categoryStorage categoryStorage.CategoryStorage, blobStore blob.BlobStore, trashRetention time.Duration) ProductUsecase {
 	return &productUsecase{
-		productStorage: productStorage,
+		productStorage:  productStorage,
+		searcher:        searcher,
+		categoryStorage: categoryStorage,
+		blobStore:       blobStore,
+		trashRetention:  trashRetention,
 	}
 }
 
 func (p *productUsecase) Insert(ctx context.Context, data model.ProductRequest) error {
-	request := schema.Product{
-		Name:  data.Name,
-		Price: data.Price,
-		Qty:   int64(data.Qty),
-	}
+	request := toProduct(data)
 
-	if err := p.productStorage.Insert(ctx, request); err != nil {
+	if err := p.productStorage.Insert(ctx, request, data.Sku); err != nil {
 		return err
 	}
 
@@ -45,12 +86,9 @@ func (p *productUsecase) Insert(ctx context.Context, data model.ProductRequest)
 }
 
 func (p *productUsecase) Update(ctx context.Context, id uint, data model.ProductRequest) error {
-	request := schema.Product{
-		Base:  schema.Base{Id: id},
-		Name:  data.Name,
-		Price: data.Price,
-		Qty:   int64(data.Qty),
-	}
+	request := toProduct(data)
+	request.Id = id
+	request.Version = data.Version
 
 	if err := p.productStorage.Update(ctx, request); err != nil {
 		return err
@@ -59,8 +97,171 @@ func (p *productUsecase) Update(ctx context.Context, id uint, data model.Product
 	return nil
 }
 
-func (p *productUsecase) GetAll(ctx context.Context) ([]model.ProductResponse, error) {
-	res, err := p.productStorage.GetAll(ctx)
+// productDefaults are the values a member set to null in a patch resets.
+// Name, price and status have no default and cannot be removed.
+var productDefaults = map[string]interface{}{
+	"description":   "",
+	"qty":           0,
+	"weight":        0,
+	"length":        0,
+	"width":         0,
+	"height":        0,
+	"tax_category":  "standard",
+	"unit":          "pcs",
+	"brand":         "",
+	"barcode":       "",
+	"min_order_qty": 1,
+	"reorder_level": 0,
+}
+
+func (p *productUsecase) Patch(ctx context.Context, id uint, data model.ProductPatchRequest) error {
+	values := map[string]interface{}{}
+
+	for _, name := range data.Null {
+		switch name {
+		case "name", "price", "status":
+			return fmt.Errorf("%s cannot be null", name)
+		}
+		if value, ok := productDefaults[name]; ok {
+			values[name] = value
+		}
+	}
+
+	if data.Name != nil {
+		values["name"] = strings.TrimSpace(*data.Name)
+	}
+	if data.Description != nil {
+		values["description"] = *data.Description
+	}
+	if data.Price != nil {
+		values["price"] = *data.Price
+	}
+	if data.Qty != nil {
+		values["qty"] = *data.Qty
+	}
+	if data.Weight != nil {
+		values["weight"] = *data.Weight
+	}
+	if data.Length != nil {
+		values["length"] = *data.Length
+	}
+	if data.Width != nil {
+		values["width"] = *data.Width
+	}
+	if data.Height != nil {
+		values["height"] = *data.Height
+	}
+	if data.TaxCategory != nil {
+		values["tax_category"] = *data.TaxCategory
+	}
+	if data.Unit != nil {
+		values["unit"] = strings.TrimSpace(*data.Unit)
+	}
+	if data.Brand != nil {
+		values["brand"] = strings.TrimSpace(*data.Brand)
+	}
+	if data.Barcode != nil {
+		values["barcode"] = *data.Barcode
+	}
+	if data.MinOrderQty != nil {
+		values["min_order_qty"] = *data.MinOrderQty
+	}
+	if data.Status != nil {
+		values["status"] = *data.Status
+	}
+	if data.ReorderLevel != nil {
+		values["reorder_level"] = *data.ReorderLevel
+	}
+
+	// an empty patch changes nothing, but the product has to exist
+	if len(values) == 0 {
+		res, err := p.productStorage.GetById(ctx, int64(id))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return errors.New("data not found")
+			}
+			return err
+		}
+		if data.Version > 0 && res.Version != data.Version {
+			return errors.New("version mismatch")
+		}
+		return nil
+	}
+
+	return p.productStorage.Patch(ctx, int64(id), data.Version, values)
+}
+
+// toProduct fills in the defaults of the optional attributes: products are
+// counted in pieces, sold from a single unit and active unless told
+// otherwise.
+func toProduct(data model.ProductRequest) schema.Product {
+	request := schema.Product{
+		Name:         data.Name,
+		Description:  data.Description,
+		Price:        data.Price,
+		Qty:          int64(data.Qty),
+		Weight:       data.Weight,
+		Length:       data.Length,
+		Width:        data.Width,
+		Height:       data.Height,
+		TaxCategory:  data.TaxCategory,
+		Unit:         strings.TrimSpace(data.Unit),
+		Brand:        strings.TrimSpace(data.Brand),
+		Barcode:      data.Barcode,
+		MinOrderQty:  data.MinOrderQty,
+		ReorderLevel: data.ReorderLevel,
+		Status:       data.Status,
+	}
+
+	if request.Unit == "" {
+		request.Unit = "pcs"
+	}
+	if request.MinOrderQty == 0 {
+		request.MinOrderQty = 1
+	}
+	if request.Status == "" {
+		request.Status = schema.ProductActive
+	}
+
+	return request
+}
+
+func (p *productUsecase) GetAll(ctx context.Context, query model.ListQuery) ([]model.ProductResponse, int64, error) {
+	if query.Category != "" {
+		ids, err := p.categoryIds(ctx, query.Category)
+		if err != nil {
+			return nil, 0, err
+		}
+		query.CategoryIds = ids
+	}
+
+	res, total, err := p.productStorage.GetAll(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	results, err := p.responses(ctx, res)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return results, total, nil
+}
+
+// responses maps a page of products to responses with their images and
+// warehouse stock.
+func (p *productUsecase) responses(ctx context.Context, res []schema.Product) ([]model.ProductResponse, error) {
+	ids := []int64{}
+	for i := range res {
+		ids = append(ids, int64(res[i].Id))
+	}
+
+	images, err := p.images(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	stock, _, err := p.stock(ctx, ids)
 	if err != nil {
 		return nil, err
 	}
@@ -68,11 +269,31 @@ func (p *productUsecase) GetAll(ctx context.Context) ([]model.ProductResponse, e
 	results := []model.ProductResponse{}
 	for i := range res {
 		rest := model.ProductResponse{
-			Id:        int(res[i].Id),
-			Name:      res[i].Name,
-			Price:     float64(res[i].Qty),
-			Qty:       int(res[i].Qty),
-			CreatedAt: fmt.Sprintf("%v", res[i].CreatedAt),
+			Id:           int(res[i].Id),
+			Name:         res[i].Name,
+			Description:  res[i].Description,
+			Price:        res[i].Price,
+			Qty:          int(res[i].Qty),
+			Weight:       res[i].Weight,
+			Length:       res[i].Length,
+			Width:        res[i].Width,
+			Height:       res[i].Height,
+			TaxCategory:  res[i].TaxCategory,
+			Unit:         res[i].Unit,
+			Brand:        res[i].Brand,
+			Barcode:      res[i].Barcode,
+			MinOrderQty:  res[i].MinOrderQty,
+			ReorderLevel: res[i].ReorderLevel,
+			Rating:       rating(res[i].RatingTotal, res[i].RatingCount),
+			RatingCount:  res[i].RatingCount,
+			Status:       res[i].Status,
+			Version:      res[i].Version,
+			CreatedAt:    fmt.Sprintf("%v", res[i].CreatedAt),
+			Images:       images[int64(res[i].Id)],
+			Stock:        stock[int64(res[i].Id)],
+		}
+		if res[i].DeletedAt.Valid {
+			rest.DeletedAt = fmt.Sprintf("%v", res[i].DeletedAt.Time)
 		}
 
 		results = append(results, rest)
@@ -89,21 +310,169 @@ func (p *productUsecase) GetById(ctx context.Context, id int64) (*model.ProductR
 	}
 
 	product := model.ProductResponse{
-		Id:        int(res.Id),
-		Name:      res.Name,
-		Price:     float64(res.Qty),
-		Qty:       int(res.Qty),
-		CreatedAt: fmt.Sprintf("%v", res.CreatedAt),
+		Id:           int(res.Id),
+		Name:         res.Name,
+		Description:  res.Description,
+		Price:        res.Price,
+		Qty:          int(res.Qty),
+		Weight:       res.Weight,
+		Length:       res.Length,
+		Width:        res.Width,
+		Height:       res.Height,
+		TaxCategory:  res.TaxCategory,
+		Unit:         res.Unit,
+		Brand:        res.Brand,
+		Barcode:      res.Barcode,
+		MinOrderQty:  res.MinOrderQty,
+		ReorderLevel: res.ReorderLevel,
+		Rating:       rating(res.RatingTotal, res.RatingCount),
+		RatingCount:  res.RatingCount,
+		Status:       res.Status,
+		Version:      res.Version,
+		CreatedAt:    fmt.Sprintf("%v", res.CreatedAt),
 	}
 
+	if product.Images, err = p.GetImages(ctx, id); err != nil {
+		return nil, err
+	}
+
+	if product.Options, err = p.GetOptions(ctx, id); err != nil {
+		return nil, err
+	}
+
+	if product.Variants, err = p.variants(ctx, id); err != nil {
+		return nil, err
+	}
+
+	stock, _, err := p.stock(ctx, []int64{id})
+	if err != nil {
+		return nil, err
+	}
+	product.Stock = stock[id]
+
 	return &product, nil
 }
 
-func (p *productUsecase) Delete(ctx context.Context, id int64) error {
-	err := p.productStorage.Delete(ctx, id)
-	if err != nil {
+// Delete moves the product to the trash. Its images stay until it is purged.
+func (p *productUsecase) Delete(ctx context.Context, id int64, version uint) error {
+	if err := p.productStorage.Delete(ctx, id, version); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+func (p *productUsecase) Search(ctx context.Context, query search.Query, category string) (*model.ProductSearchResponse, int64, error) {
+	if category != "" {
+		ids, err := p.categoryIds(ctx, category)
+		if err != nil {
+			return nil, 0, err
+		}
+		query.CategoryIds = ids
+	}
+
+	res, err := p.searcher.Search(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	results := model.ProductSearchResponse{
+		Hits: []model.ProductSearchHit{},
+		Facets: model.ProductFacets{
+			Categories: []model.CategoryFacet{},
+			PriceBands: []model.PriceBandFacet{},
+			InStock:    res.Facets.InStock,
+			OutOfStock: res.Facets.OutOfStock,
+		},
+	}
+
+	ids := []int64{}
+	for _, hit := range res.Hits {
+		ids = append(ids, int64(hit.Product.Id))
+	}
+
+	images, err := p.images(ctx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	stock, _, err := p.stock(ctx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for _, hit := range res.Hits {
+		results.Hits = append(results.Hits, model.ProductSearchHit{
+			ProductResponse: model.ProductResponse{
+				Id:           int(hit.Product.Id),
+				Name:         hit.Product.Name,
+				Description:  hit.Product.Description,
+				Price:        hit.Product.Price,
+				Qty:          int(hit.Product.Qty),
+				Weight:       hit.Product.Weight,
+				Length:       hit.Product.Length,
+				Width:        hit.Product.Width,
+				Height:       hit.Product.Height,
+				TaxCategory:  hit.Product.TaxCategory,
+				Unit:         hit.Product.Unit,
+				Brand:        hit.Product.Brand,
+				Barcode:      hit.Product.Barcode,
+				MinOrderQty:  hit.Product.MinOrderQty,
+				ReorderLevel: hit.Product.ReorderLevel,
+				Rating:       rating(hit.Product.RatingTotal, hit.Product.RatingCount),
+				RatingCount:  hit.Product.RatingCount,
+				Status:       hit.Product.Status,
+				Version:      hit.Product.Version,
+				CreatedAt:    fmt.Sprintf("%v", hit.Product.CreatedAt),
+				Images:       images[int64(hit.Product.Id)],
+				Stock:        stock[int64(hit.Product.Id)],
+			},
+			Score:      hit.Score,
+			Highlights: hit.Highlights,
+		})
+	}
+
+	for _, category := range res.Facets.Categories {
+		results.Facets.Categories = append(results.Facets.Categories, model.CategoryFacet{
+			Id:    category.Id,
+			Name:  category.Name,
+			Slug:  category.Slug,
+			Count: category.Count,
+		})
+	}
+
+	for _, band := range res.Facets.PriceBands {
+		facet := model.PriceBandFacet{Min: band.Min, Count: band.Count}
+		if band.Max > 0 {
+			max := band.Max
+			facet.Max = &max
+		}
+		results.Facets.PriceBands = append(results.Facets.PriceBands, facet)
+	}
+
+	return &results, res.Total, nil
+}
+
+// categoryIds resolves a category slug to the ids of the category and its
+// subcategories.
+func (p *productUsecase) categoryIds(ctx context.Context, slug string) ([]int64, error) {
+	category, err := p.categoryStorage.GetBySlug(ctx, slug)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("category not found")
+		}
+		return nil, err
+	}
+
+	return p.categoryStorage.GetSubtreeIds(ctx, int64(category.Id))
+}
+
+// rating averages the approved reviews of a product to one decimal, 0 when
+// it has none.
+func rating(total, count int64) float64 {
+	if count == 0 {
+		return 0
+	}
+
+	return math.Round(float64(total)/float64(count)*10) / 10
+}
@@ -2,11 +2,14 @@ package product
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 
 	"kanggo/pkg/entity/model"
 	"kanggo/pkg/entity/schema"
+	"kanggo/pkg/inventory"
 	"kanggo/pkg/mocks"
+	"kanggo/pkg/search"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -14,7 +17,7 @@ import (
 
 func TestInsert(t *testing.T) {
 	mockProductStorage := new(mocks.ProductStorage)
-	p := NewProductUsecase(mockProductStorage)
+	p := NewProductUsecase(mockProductStorage, new(mocks.Searcher), new(mocks.CategoryStorage), new(mocks.BlobStore), 0)
 	ctx := context.Background()
 
 	model := model.ProductRequest{
@@ -23,13 +26,16 @@ func TestInsert(t *testing.T) {
 		Qty:   10,
 	}
 	schema := schema.Product{
-		Name:  "Produk 1",
-		Price: 10000,
-		Qty:   10,
+		Name:        "Produk 1",
+		Price:       10000,
+		Qty:         10,
+		Unit:        "pcs",
+		MinOrderQty: 1,
+		Status:      "active",
 	}
 
 	t.Run("success", func(t *testing.T) {
-		mockProductStorage.On("Insert", mock.Anything, schema).Return(nil)
+		mockProductStorage.On("Insert", mock.Anything, schema, "").Return(nil)
 
 		err := p.Insert(ctx, model)
 
@@ -37,11 +43,33 @@ func TestInsert(t *testing.T) {
 		assert.NoError(t, err)
 		mockProductStorage.AssertExpectations(t)
 	})
+
+	t.Run("with attributes", func(t *testing.T) {
+		request := model
+		request.Unit = " sak "
+		request.Brand = "Tiga Roda"
+		request.Barcode = "8991234567890"
+		request.MinOrderQty = 5
+		request.Status = "draft"
+		product := schema
+		product.Unit = "sak"
+		product.Brand = "Tiga Roda"
+		product.Barcode = "8991234567890"
+		product.MinOrderQty = 5
+		product.Status = "draft"
+
+		mockProductStorage.On("Insert", mock.Anything, product, "").Return(nil)
+
+		err := p.Insert(ctx, request)
+
+		assert.NoError(t, err)
+		mockProductStorage.AssertExpectations(t)
+	})
 }
 
 func TestUpdate(t *testing.T) {
 	mockProductStorage := new(mocks.ProductStorage)
-	p := NewProductUsecase(mockProductStorage)
+	p := NewProductUsecase(mockProductStorage, new(mocks.Searcher), new(mocks.CategoryStorage), new(mocks.BlobStore), 0)
 	ctx := context.Background()
 	var id uint = 1
 
@@ -51,10 +79,13 @@ func TestUpdate(t *testing.T) {
 		Qty:   10,
 	}
 	schema := schema.Product{
-		Base:  schema.Base{Id: 1},
-		Name:  "Produk 1",
-		Price: 10000,
-		Qty:   10,
+		Base:        schema.Base{Id: 1},
+		Name:        "Produk 1",
+		Price:       10000,
+		Qty:         10,
+		Unit:        "pcs",
+		MinOrderQty: 1,
+		Status:      "active",
 	}
 
 	t.Run("success", func(t *testing.T) {
@@ -68,6 +99,56 @@ func TestUpdate(t *testing.T) {
 	})
 }
 
+func TestPatch(t *testing.T) {
+	mockProductStorage := new(mocks.ProductStorage)
+	p := NewProductUsecase(mockProductStorage, new(mocks.Searcher), new(mocks.CategoryStorage), new(mocks.BlobStore), 0)
+	ctx := context.Background()
+
+	t.Run("zero and null", func(t *testing.T) {
+		qty := 0
+		status := "archived"
+		request := model.ProductPatchRequest{Qty: &qty, Status: &status, Null: []string{"brand", "unit"}}
+
+		mockProductStorage.On("Patch", ctx, int64(1), uint(0), map[string]interface{}{
+			"qty":    0,
+			"status": "archived",
+			"brand":  "",
+			"unit":   "pcs",
+		}).Return(nil)
+
+		err := p.Patch(ctx, 1, request)
+
+		assert.NoError(t, err)
+		mockProductStorage.AssertExpectations(t)
+	})
+
+	t.Run("reorder level", func(t *testing.T) {
+		level := int64(20)
+
+		mockProductStorage.On("Patch", ctx, int64(3), uint(0), map[string]interface{}{"reorder_level": int64(20)}).Return(nil).Once()
+
+		err := p.Patch(ctx, 3, model.ProductPatchRequest{ReorderLevel: &level})
+
+		assert.NoError(t, err)
+		mockProductStorage.AssertExpectations(t)
+	})
+
+	t.Run("required member set to null", func(t *testing.T) {
+		err := p.Patch(ctx, 1, model.ProductPatchRequest{Null: []string{"price"}})
+
+		assert.EqualError(t, err, "price cannot be null")
+	})
+
+	t.Run("empty patch of a missing product", func(t *testing.T) {
+		mockProductStorage.On("GetById", ctx, int64(2)).Return(nil, sql.ErrNoRows)
+
+		err := p.Patch(ctx, 2, model.ProductPatchRequest{})
+
+		assert.EqualError(t, err, "data not found")
+		mockProductStorage.AssertExpectations(t)
+	})
+}
+
 func TestGetAll(t *testing.T) {
 	mockProductStorage := new(mocks.ProductStorage)
 	ctx := context.Background()
@@ -88,17 +169,73 @@ func TestGetAll(t *testing.T) {
 	}
 
 	t.Run("success", func(t *testing.T) {
-		mockProductStorage.On("GetAll", mock.Anything).Return(mockProductList, nil)
+		query := model.ListQuery{Page: 1, Size: 20, Sort: "price", Desc: true}
+		mockProductStorage.On("GetAll", mock.Anything, query).Return(mockProductList, int64(2), nil)
+		mockProductStorage.On("GetImages", mock.Anything, []int64{1, 2}).Return([]schema.ProductImage{
+			{Base: schema.Base{Id: 4}, ProductId: 2, ObjectKey: "products/2/a.jpg", ThumbnailKey: "products/2/a_thumb.jpg"},
+		}, nil)
+		mockProductStorage.On("GetStock", mock.Anything, []int64{1, 2}).Return([]inventory.Stock{
+			{ProductId: 2, VariantId: 5, WarehouseId: 1, Code: "JKT", Name: "Gudang Jakarta", Qty: 2},
+			{ProductId: 2, VariantId: 6, WarehouseId: 1, Code: "JKT", Name: "Gudang Jakarta", Qty: 1},
+			{ProductId: 2, VariantId: 6, WarehouseId: 2, Code: "SBY", Name: "Gudang Surabaya", Qty: 2},
+		}, nil)
+		mockBlobStore := new(mocks.BlobStore)
+		mockBlobStore.On("URL", mock.AnythingOfType("string")).Return(func(key string) string {
+			return "/uploads/" + key
+		})
 
-		u := NewProductUsecase(mockProductStorage)
-		list, err := u.GetAll(ctx)
+		u := NewProductUsecase(mockProductStorage, new(mocks.Searcher), new(mocks.CategoryStorage), mockBlobStore, 0)
+		list, total, err := u.GetAll(ctx, query)
 
 		assert.NotNil(t, list)
+		assert.Equal(t, int64(2), total)
 		assert.Nil(t, err)
 		assert.NoError(t, err)
 		assert.Equal(t, mockProductList[0].Name, "product 1")
 		assert.Len(t, list, len(mockProductList))
+		assert.Empty(t, list[0].Images)
+		assert.Equal(t, "/uploads/products/2/a_thumb.jpg", list[1].Images[0].ThumbnailUrl)
+		assert.Empty(t, list[0].Stock)
+		assert.Equal(t, []model.WarehouseStockResponse{
+			{WarehouseId: 1, WarehouseCode: "JKT", WarehouseName: "Gudang Jakarta", Qty: 3},
+			{WarehouseId: 2, WarehouseCode: "SBY", WarehouseName: "Gudang Surabaya", Qty: 2},
+		}, list[1].Stock)
+		mockProductStorage.AssertExpectations(t)
+	})
+}
+
+func TestGetAllByCategory(t *testing.T) {
+	mockProductStorage := new(mocks.ProductStorage)
+	mockCategoryStorage := new(mocks.CategoryStorage)
+	u := NewProductUsecase(mockProductStorage, new(mocks.Searcher), mockCategoryStorage, new(mocks.BlobStore), 0)
+	ctx := context.Background()
+
+	t.Run("subcategories included", func(t *testing.T) {
+		query := model.ListQuery{Page: 1, Size: 20, Sort: "id", Category: "semen"}
+		resolved := query
+		resolved.CategoryIds = []int64{2, 5, 6}
+
+		mockCategoryStorage.On("GetBySlug", mock.Anything, "semen").Return(&schema.Category{Base: schema.Base{Id: 2}}, nil).Once()
+		mockCategoryStorage.On("GetSubtreeIds", mock.Anything, int64(2)).Return([]int64{2, 5, 6}, nil).Once()
+		mockProductStorage.On("GetAll", mock.Anything, resolved).Return([]schema.Product{}, int64(0), nil).Once()
+		mockProductStorage.On("GetImages", mock.Anything, []int64{}).Return([]schema.ProductImage{}, nil).Once()
+		mockProductStorage.On("GetStock", mock.Anything, []int64{}).Return([]inventory.Stock{}, nil).Once()
+
+		list, total, err := u.GetAll(ctx, query)
+
+		assert.NoError(t, err)
+		assert.Empty(t, list)
+		assert.EqualValues(t, 0, total)
 		mockProductStorage.AssertExpectations(t)
+		mockCategoryStorage.AssertExpectations(t)
+	})
+
+	t.Run("unknown category", func(t *testing.T) {
+		mockCategoryStorage.On("GetBySlug", mock.Anything, "kayu").Return(nil, sql.ErrNoRows).Once()
+
+		_, _, err := u.GetAll(ctx, model.ListQuery{Page: 1, Size: 20, Category: "kayu"})
+
+		assert.EqualError(t, err, "category not found")
 	})
 }
 
@@ -108,16 +245,26 @@ func TestGetById(t *testing.T) {
 	var idProduct int64 = 1
 
 	mockProduct := schema.Product{
-		Base:  schema.Base{Id: 1},
-		Name:  "product 1",
-		Price: 10000,
-		Qty:   10,
+		Base:        schema.Base{Id: 1},
+		Name:        "product 1",
+		Price:       10000,
+		Qty:         10,
+		RatingCount: 3,
+		RatingTotal: 13,
 	}
 
 	t.Run("success", func(t *testing.T) {
 		mockProductStorage.On("GetById", mock.Anything, mock.AnythingOfType("int64")).Return(&mockProduct, nil)
+		mockProductStorage.On("GetImages", mock.Anything, []int64{idProduct}).Return([]schema.ProductImage{}, nil)
+		mockProductStorage.On("GetStock", mock.Anything, []int64{idProduct}).Return([]inventory.Stock{
+			{ProductId: 1, VariantId: 3, WarehouseId: 1, Code: "JKT", Name: "Gudang Jakarta", Qty: 10},
+		}, nil)
+		mockProductStorage.On("GetOptions", mock.Anything, idProduct).Return([]schema.ProductOption{}, nil)
+		mockProductStorage.On("GetVariants", mock.Anything, idProduct).Return([]schema.ProductVariant{
+			{Base: schema.Base{Id: 3}, ProductId: 1, Sku: "SKU-000001", Options: "{}", Price: 10000, Qty: 10},
+		}, nil)
 
-		u := NewProductUsecase(mockProductStorage)
+		u := NewProductUsecase(mockProductStorage, new(mocks.Searcher), new(mocks.CategoryStorage), new(mocks.BlobStore), 0)
 
 		detail, err := u.GetById(ctx, idProduct)
 
@@ -125,6 +272,12 @@ func TestGetById(t *testing.T) {
 		assert.Nil(t, err)
 		assert.NoError(t, err)
 		assert.Equal(t, mockProduct.Name, "product 1")
+		assert.Len(t, detail.Variants, 1)
+		assert.Equal(t, "SKU-000001", detail.Variants[0].Sku)
+		assert.EqualValues(t, 10, detail.Variants[0].Stock[0].Qty)
+		assert.EqualValues(t, 10, detail.Stock[0].Qty)
+		assert.Equal(t, 4.3, detail.Rating)
+		assert.EqualValues(t, 3, detail.RatingCount)
 		mockProductStorage.AssertExpectations(t)
 	})
 }
@@ -135,14 +288,58 @@ func TestDelete(t *testing.T) {
 	var idProduct int64 = 1
 
 	t.Run("success", func(t *testing.T) {
-		mockProductStorage.On("Delete", mock.Anything, mock.AnythingOfType("int64")).Return(nil)
+		mockProductStorage.On("Delete", mock.Anything, mock.AnythingOfType("int64"), uint(4)).Return(nil)
+		mockBlobStore := new(mocks.BlobStore)
 
-		u := NewProductUsecase(mockProductStorage)
+		u := NewProductUsecase(mockProductStorage, new(mocks.Searcher), new(mocks.CategoryStorage), mockBlobStore, 0)
 
-		err := u.Delete(ctx, idProduct)
+		err := u.Delete(ctx, idProduct, 4)
 
 		assert.Nil(t, err)
 		assert.NoError(t, err)
 		mockProductStorage.AssertExpectations(t)
+		// the images stay with the product in the trash
+		mockBlobStore.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
+
+func TestSearch(t *testing.T) {
+	mockSearcher := new(mocks.Searcher)
+	mockProductStorage := new(mocks.ProductStorage)
+	ctx := context.Background()
+
+	query := search.Query{Text: "semen", Page: 1, Size: 20}
+	mockResult := search.Result{
+		Hits: []search.Hit{
+			{
+				Product:    schema.Product{Base: schema.Base{Id: 1}, Name: "Semen 50kg", Price: 65000, Qty: 10},
+				Score:      2,
+				Highlights: map[string]string{"name": "<em>Semen</em> 50kg"},
+			},
+		},
+		Total: 1,
+		Facets: search.Facets{
+			PriceBands: []search.PriceBand{{Min: 0, Max: 100000, Count: 1}, {Min: 100000, Count: 0}},
+			InStock:    1,
+		},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockSearcher.On("Search", mock.Anything, query).Return(&mockResult, nil)
+		mockProductStorage.On("GetImages", mock.Anything, []int64{1}).Return([]schema.ProductImage{}, nil)
+		mockProductStorage.On("GetStock", mock.Anything, []int64{1}).Return([]inventory.Stock{}, nil)
+
+		u := NewProductUsecase(mockProductStorage, mockSearcher, new(mocks.CategoryStorage), new(mocks.BlobStore), 0)
+
+		res, total, err := u.Search(ctx, query, "")
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, 1, total)
+		assert.Equal(t, "Semen 50kg", res.Hits[0].Name)
+		assert.Equal(t, "<em>Semen</em> 50kg", res.Hits[0].Highlights["name"])
+		assert.EqualValues(t, 100000, *res.Facets.PriceBands[0].Max)
+		assert.Nil(t, res.Facets.PriceBands[1].Max)
+		assert.EqualValues(t, 1, res.Facets.InStock)
+		mockSearcher.AssertExpectations(t)
 	})
 }
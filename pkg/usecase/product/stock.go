@@ -0,0 +1,41 @@
+package product
+
+import (
+	"context"
+	"kanggo/pkg/entity/model"
+)
+
+// stock returns the warehouse stock of the products, keyed by product id
+// with the variants added up, and keyed by variant id. Products have no
+// entry before warehouses are set up.
+func (p *productUsecase) stock(ctx context.Context, productIds []int64) (map[int64][]model.WarehouseStockResponse, map[int64][]model.WarehouseStockResponse, error) {
+	res, err := p.productStorage.GetStock(ctx, productIds)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	products := map[int64][]model.WarehouseStockResponse{}
+	variants := map[int64][]model.WarehouseStockResponse{}
+	for _, stock := range res {
+		row := model.WarehouseStockResponse{
+			WarehouseId:   stock.WarehouseId,
+			WarehouseCode: stock.Code,
+			WarehouseName: stock.Name,
+			Qty:           stock.Qty,
+		}
+		variants[stock.VariantId] = append(variants[stock.VariantId], row)
+
+		added := false
+		for i := range products[stock.ProductId] {
+			if products[stock.ProductId][i].WarehouseId == stock.WarehouseId {
+				products[stock.ProductId][i].Qty += stock.Qty
+				added = true
+			}
+		}
+		if !added {
+			products[stock.ProductId] = append(products[stock.ProductId], row)
+		}
+	}
+
+	return products, variants, nil
+}
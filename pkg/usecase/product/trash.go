@@ -0,0 +1,38 @@
+package product
+
+import (
+	"context"
+	"kanggo/pkg/entity/model"
+	"time"
+)
+
+func (p *productUsecase) GetTrash(ctx context.Context, query model.ListQuery) ([]model.ProductResponse, int64, error) {
+	res, total, err := p.productStorage.GetTrash(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	results, err := p.responses(ctx, res)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return results, total, nil
+}
+
+func (p *productUsecase) Restore(ctx context.Context, id int64) error {
+	return p.productStorage.Restore(ctx, id)
+}
+
+// Purge permanently deletes the products that have been in the trash for
+// longer than the retention period and removes their image files.
+func (p *productUsecase) Purge(ctx context.Context) (int64, error) {
+	purged, images, err := p.productStorage.Purge(ctx, time.Now().Add(-p.trashRetention))
+	if err != nil {
+		return 0, err
+	}
+
+	p.removeBlobs(ctx, images...)
+
+	return purged, nil
+}
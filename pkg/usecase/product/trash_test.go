@@ -0,0 +1,66 @@
+package product
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/entity/schema"
+	"kanggo/pkg/inventory"
+	"kanggo/pkg/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestGetTrash(t *testing.T) {
+	ctx := context.Background()
+	mockProductStorage := new(mocks.ProductStorage)
+	p := NewProductUsecase(mockProductStorage, new(mocks.Searcher), new(mocks.CategoryStorage), new(mocks.BlobStore), 0)
+
+	deletedAt := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+	query := model.ListQuery{Page: 1, Size: 20, Sort: "deleted_at", Desc: true}
+
+	mockProductStorage.On("GetTrash", ctx, query).Return([]schema.Product{
+		{Base: schema.Base{Id: 3}, Name: "Semen Putih 40kg", DeletedAt: gorm.DeletedAt{Time: deletedAt, Valid: true}},
+	}, int64(1), nil)
+	mockProductStorage.On("GetImages", ctx, []int64{3}).Return([]schema.ProductImage{}, nil)
+	mockProductStorage.On("GetStock", ctx, []int64{3}).Return([]inventory.Stock{}, nil)
+
+	res, total, err := p.GetTrash(ctx, query)
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, total)
+	assert.Equal(t, "Semen Putih 40kg", res[0].Name)
+	assert.Equal(t, "2022-03-01 10:00:00 +0000 UTC", res[0].DeletedAt)
+	assert.Empty(t, res[0].Images)
+	mockProductStorage.AssertExpectations(t)
+}
+
+func TestPurge(t *testing.T) {
+	ctx := context.Background()
+	mockProductStorage := new(mocks.ProductStorage)
+	mockBlobStore := new(mocks.BlobStore)
+	p := NewProductUsecase(mockProductStorage, new(mocks.Searcher), new(mocks.CategoryStorage), mockBlobStore, 30*24*time.Hour)
+
+	// only products trashed longer than the retention period go
+	before := mock.MatchedBy(func(before time.Time) bool {
+		cutoff := time.Now().Add(-30 * 24 * time.Hour)
+		return before.Sub(cutoff) < time.Minute && cutoff.Sub(before) < time.Minute
+	})
+
+	mockProductStorage.On("Purge", ctx, before).Return(int64(2), []schema.ProductImage{
+		{Base: schema.Base{Id: 4}, ProductId: 1, ObjectKey: "products/1/a.jpg", ThumbnailKey: "products/1/a_thumb.jpg"},
+	}, nil)
+	mockBlobStore.On("Delete", ctx, "products/1/a.jpg").Return(nil)
+	mockBlobStore.On("Delete", ctx, "products/1/a_thumb.jpg").Return(nil)
+
+	purged, err := p.Purge(ctx)
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, purged)
+	mockProductStorage.AssertExpectations(t)
+	mockBlobStore.AssertExpectations(t)
+}
@@ -0,0 +1,158 @@
+package product
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/entity/schema"
+	"strings"
+)
+
+func (p *productUsecase) GetOptions(ctx context.Context, productId int64) ([]model.ProductOptionResponse, error) {
+	res, err := p.productStorage.GetOptions(ctx, productId)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []model.ProductOptionResponse{}
+	for i := range res {
+		values := []string{}
+		if err := json.Unmarshal([]byte(res[i].Values), &values); err != nil {
+			return nil, err
+		}
+
+		results = append(results, model.ProductOptionResponse{
+			Name:   res[i].Name,
+			Values: values,
+		})
+	}
+
+	return results, nil
+}
+
+// SetOptions replaces the options of a product, keeping the order they are
+// given in.
+func (p *productUsecase) SetOptions(ctx context.Context, productId int64, data model.ProductOptionsRequest) error {
+	names := map[string]bool{}
+	options := []schema.ProductOption{}
+	for i, option := range data.Options {
+		name := strings.TrimSpace(option.Name)
+		if names[name] {
+			return errors.New("duplicate option name")
+		}
+		names[name] = true
+
+		values := []string{}
+		seen := map[string]bool{}
+		for _, value := range option.Values {
+			value = strings.TrimSpace(value)
+			if seen[value] {
+				return errors.New("duplicate option value")
+			}
+			seen[value] = true
+			values = append(values, value)
+		}
+
+		encoded, err := json.Marshal(values)
+		if err != nil {
+			return err
+		}
+
+		options = append(options, schema.ProductOption{
+			ProductId: productId,
+			Name:      name,
+			Position:  int64(i),
+			Values:    string(encoded),
+		})
+	}
+
+	return p.productStorage.SetOptions(ctx, productId, options)
+}
+
+func (p *productUsecase) GetVariants(ctx context.Context, productId int64) ([]model.ProductVariantResponse, error) {
+	if _, err := p.productStorage.GetById(ctx, productId); err != nil {
+		return nil, err
+	}
+
+	return p.variants(ctx, productId)
+}
+
+func (p *productUsecase) InsertVariant(ctx context.Context, productId int64, data model.ProductVariantRequest) error {
+	request, err := toVariant(productId, data)
+	if err != nil {
+		return err
+	}
+
+	return p.productStorage.InsertVariant(ctx, request)
+}
+
+func (p *productUsecase) UpdateVariant(ctx context.Context, productId, id int64, data model.ProductVariantRequest) error {
+	request, err := toVariant(productId, data)
+	if err != nil {
+		return err
+	}
+	request.Id = uint(id)
+
+	return p.productStorage.UpdateVariant(ctx, request)
+}
+
+func (p *productUsecase) DeleteVariant(ctx context.Context, productId, id int64) error {
+	return p.productStorage.DeleteVariant(ctx, productId, id)
+}
+
+func (p *productUsecase) variants(ctx context.Context, productId int64) ([]model.ProductVariantResponse, error) {
+	res, err := p.productStorage.GetVariants(ctx, productId)
+	if err != nil {
+		return nil, err
+	}
+
+	_, stock, err := p.stock(ctx, []int64{productId})
+	if err != nil {
+		return nil, err
+	}
+
+	results := []model.ProductVariantResponse{}
+	for i := range res {
+		options := map[string]string{}
+		if err := json.Unmarshal([]byte(res[i].Options), &options); err != nil {
+			return nil, err
+		}
+
+		results = append(results, model.ProductVariantResponse{
+			Id:        int64(res[i].Id),
+			ProductId: res[i].ProductId,
+			Sku:       res[i].Sku,
+			Options:   options,
+			Price:     res[i].Price,
+			Qty:       res[i].Qty,
+			Weight:    res[i].Weight,
+			Stock:     stock[int64(res[i].Id)],
+		})
+	}
+
+	return results, nil
+}
+
+// toVariant stores the option choices as a JSON object. encoding/json sorts
+// map keys, so the same choices always give the same string.
+func toVariant(productId int64, data model.ProductVariantRequest) (schema.ProductVariant, error) {
+	choices := map[string]string{}
+	for name, value := range data.Options {
+		choices[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+
+	options, err := json.Marshal(choices)
+	if err != nil {
+		return schema.ProductVariant{}, err
+	}
+
+	return schema.ProductVariant{
+		ProductId: productId,
+		Sku:       strings.TrimSpace(data.Sku),
+		Options:   string(options),
+		Price:     data.Price,
+		Qty:       data.Qty,
+		Weight:    data.Weight,
+	}, nil
+}
@@ -0,0 +1,100 @@
+package product
+
+import (
+	"context"
+	"testing"
+
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/entity/schema"
+	"kanggo/pkg/inventory"
+	"kanggo/pkg/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSetOptions(t *testing.T) {
+	mockProductStorage := new(mocks.ProductStorage)
+	p := NewProductUsecase(mockProductStorage, new(mocks.Searcher), new(mocks.CategoryStorage), new(mocks.BlobStore), 0)
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		options := []schema.ProductOption{
+			{ProductId: 1, Name: "Berat", Position: 0, Values: `["40kg","50kg"]`},
+			{ProductId: 1, Name: "Warna", Position: 1, Values: `["Abu"]`},
+		}
+		mockProductStorage.On("SetOptions", mock.Anything, int64(1), options).Return(nil).Once()
+
+		err := p.SetOptions(ctx, 1, model.ProductOptionsRequest{Options: []model.ProductOptionRequest{
+			{Name: "Berat", Values: []string{"40kg", " 50kg"}},
+			{Name: "Warna ", Values: []string{"Abu"}},
+		}})
+
+		assert.NoError(t, err)
+		mockProductStorage.AssertExpectations(t)
+	})
+
+	t.Run("duplicate value", func(t *testing.T) {
+		err := p.SetOptions(ctx, 1, model.ProductOptionsRequest{Options: []model.ProductOptionRequest{
+			{Name: "Berat", Values: []string{"40kg", "40kg"}},
+		}})
+
+		assert.EqualError(t, err, "duplicate option value")
+	})
+}
+
+func TestInsertVariant(t *testing.T) {
+	mockProductStorage := new(mocks.ProductStorage)
+	p := NewProductUsecase(mockProductStorage, new(mocks.Searcher), new(mocks.CategoryStorage), new(mocks.BlobStore), 0)
+	ctx := context.Background()
+
+	t.Run("options are stored with sorted keys", func(t *testing.T) {
+		variant := schema.ProductVariant{
+			ProductId: 1,
+			Sku:       "SEMEN-40KG-ABU",
+			Options:   `{"Berat":"40kg","Warna":"Abu"}`,
+			Price:     55000,
+			Qty:       20,
+			Weight:    40000,
+		}
+		mockProductStorage.On("InsertVariant", mock.Anything, variant).Return(nil).Once()
+
+		err := p.InsertVariant(ctx, 1, model.ProductVariantRequest{
+			Sku:     " SEMEN-40KG-ABU",
+			Options: map[string]string{"Warna": "Abu", "Berat": "40kg"},
+			Price:   55000,
+			Qty:     20,
+			Weight:  40000,
+		})
+
+		assert.NoError(t, err)
+		mockProductStorage.AssertExpectations(t)
+	})
+}
+
+func TestGetVariants(t *testing.T) {
+	mockProductStorage := new(mocks.ProductStorage)
+	p := NewProductUsecase(mockProductStorage, new(mocks.Searcher), new(mocks.CategoryStorage), new(mocks.BlobStore), 0)
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		mockProductStorage.On("GetById", mock.Anything, int64(1)).Return(&schema.Product{Base: schema.Base{Id: 1}}, nil)
+		mockProductStorage.On("GetVariants", mock.Anything, int64(1)).Return([]schema.ProductVariant{
+			{Base: schema.Base{Id: 3}, ProductId: 1, Sku: "SEMEN-40KG", Options: `{"Berat":"40kg"}`, Price: 55000, Qty: 20},
+			{Base: schema.Base{Id: 4}, ProductId: 1, Sku: "SEMEN-50KG", Options: `{"Berat":"50kg"}`, Price: 65000, Qty: 0},
+		}, nil)
+		mockProductStorage.On("GetStock", mock.Anything, []int64{1}).Return([]inventory.Stock{
+			{ProductId: 1, VariantId: 3, WarehouseId: 1, Code: "JKT", Name: "Gudang Jakarta", Qty: 15},
+			{ProductId: 1, VariantId: 3, WarehouseId: 2, Code: "SBY", Name: "Gudang Surabaya", Qty: 5},
+		}, nil)
+
+		res, err := p.GetVariants(ctx, 1)
+
+		assert.NoError(t, err)
+		assert.Len(t, res, 2)
+		assert.Equal(t, map[string]string{"Berat": "50kg"}, res[1].Options)
+		assert.Len(t, res[0].Stock, 2)
+		assert.Empty(t, res[1].Stock)
+		mockProductStorage.AssertExpectations(t)
+	})
+}
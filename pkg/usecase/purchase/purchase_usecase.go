@@ -0,0 +1,229 @@
+package purchase
+
+import (
+	"context"
+	"errors"
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/entity/schema"
+	storage "kanggo/pkg/storage/purchase"
+	"strings"
+)
+
+//go:generate mockery --name PurchaseUsecase --case snake --output ../../mocks --disable-version-string
+
+type (
+	PurchaseUsecase interface {
+		InsertSupplier(ctx context.Context, data model.SupplierRequest) error
+		UpdateSupplier(ctx context.Context, id int64, data model.SupplierRequest) error
+		GetSuppliers(ctx context.Context) ([]model.SupplierResponse, error)
+		GetSupplierById(ctx context.Context, id int64) (*model.SupplierResponse, error)
+		DeleteSupplier(ctx context.Context, id int64) error
+
+		Insert(ctx context.Context, data model.PurchaseOrderRequest) (*model.PurchaseOrderResponse, error)
+		Update(ctx context.Context, id int64, data model.PurchaseOrderRequest) error
+		GetAll(ctx context.Context, query model.ListQuery) ([]model.PurchaseOrderResponse, int64, error)
+		GetById(ctx context.Context, id int64) (*model.PurchaseOrderResponse, error)
+		Delete(ctx context.Context, id int64) error
+		Send(ctx context.Context, id int64) error
+		Receive(ctx context.Context, id int64, actorId uint64, data model.ReceiveRequest) (*model.PurchaseOrderResponse, error)
+	}
+
+	purchaseUsecase struct {
+		purchaseStorage storage.PurchaseStorage
+	}
+)
+
+func NewPurchaseUsecase(purchaseStorage storage.PurchaseStorage) PurchaseUsecase {
+	return &purchaseUsecase{
+		purchaseStorage: purchaseStorage,
+	}
+}
+
+func (p *purchaseUsecase) InsertSupplier(ctx context.Context, data model.SupplierRequest) error {
+	if err := p.purchaseStorage.InsertSupplier(ctx, toSupplier(data)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *purchaseUsecase) UpdateSupplier(ctx context.Context, id int64, data model.SupplierRequest) error {
+	request := toSupplier(data)
+	request.Id = uint(id)
+
+	if err := p.purchaseStorage.UpdateSupplier(ctx, request); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *purchaseUsecase) GetSuppliers(ctx context.Context) ([]model.SupplierResponse, error) {
+	res, err := p.purchaseStorage.GetSuppliers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []model.SupplierResponse{}
+	for i := range res {
+		results = append(results, toSupplierResponse(res[i]))
+	}
+
+	return results, nil
+}
+
+func (p *purchaseUsecase) GetSupplierById(ctx context.Context, id int64) (*model.SupplierResponse, error) {
+	res, err := p.purchaseStorage.GetSupplierById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	supplier := toSupplierResponse(*res)
+
+	return &supplier, nil
+}
+
+func (p *purchaseUsecase) DeleteSupplier(ctx context.Context, id int64) error {
+	if err := p.purchaseStorage.DeleteSupplier(ctx, id); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Insert drafts a purchase order and returns it with its number.
+func (p *purchaseUsecase) Insert(ctx context.Context, data model.PurchaseOrderRequest) (*model.PurchaseOrderResponse, error) {
+	lines, err := toLines(data.Lines)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := p.purchaseStorage.Insert(ctx, toPurchaseOrder(data), lines)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.GetById(ctx, id)
+}
+
+func (p *purchaseUsecase) Update(ctx context.Context, id int64, data model.PurchaseOrderRequest) error {
+	lines, err := toLines(data.Lines)
+	if err != nil {
+		return err
+	}
+
+	request := toPurchaseOrder(data)
+	request.Id = uint(id)
+
+	if err := p.purchaseStorage.Update(ctx, request, lines); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *purchaseUsecase) GetAll(ctx context.Context, query model.ListQuery) ([]model.PurchaseOrderResponse, int64, error) {
+	return p.purchaseStorage.GetAll(ctx, query)
+}
+
+func (p *purchaseUsecase) GetById(ctx context.Context, id int64) (*model.PurchaseOrderResponse, error) {
+	res, err := p.purchaseStorage.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Lines, err = p.purchaseStorage.GetLines(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (p *purchaseUsecase) Delete(ctx context.Context, id int64) error {
+	if err := p.purchaseStorage.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *purchaseUsecase) Send(ctx context.Context, id int64) error {
+	if err := p.purchaseStorage.Send(ctx, id); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Receive records a delivery against the purchase order and returns the
+// order with what has been received so far.
+func (p *purchaseUsecase) Receive(ctx context.Context, id int64, actorId uint64, data model.ReceiveRequest) (*model.PurchaseOrderResponse, error) {
+	seen := map[int64]bool{}
+	for _, line := range data.Lines {
+		if seen[line.LineId] {
+			return nil, errors.New("a line can only be received once per delivery")
+		}
+		seen[line.LineId] = true
+	}
+
+	if err := p.purchaseStorage.Receive(ctx, id, data.Lines, int64(actorId), data.Note); err != nil {
+		return nil, err
+	}
+
+	return p.GetById(ctx, id)
+}
+
+func toSupplier(data model.SupplierRequest) schema.Supplier {
+	active := true
+	if data.Active != nil {
+		active = *data.Active
+	}
+
+	return schema.Supplier{
+		Name:    strings.TrimSpace(data.Name),
+		Email:   data.Email,
+		Phone:   data.Phone,
+		Address: data.Address,
+		Active:  active,
+	}
+}
+
+func toSupplierResponse(res schema.Supplier) model.SupplierResponse {
+	return model.SupplierResponse{
+		Id:      int64(res.Id),
+		Name:    res.Name,
+		Email:   res.Email,
+		Phone:   res.Phone,
+		Address: res.Address,
+		Active:  res.Active,
+	}
+}
+
+func toPurchaseOrder(data model.PurchaseOrderRequest) schema.PurchaseOrder {
+	return schema.PurchaseOrder{
+		SupplierId:  data.SupplierId,
+		WarehouseId: data.WarehouseId,
+		Note:        data.Note,
+	}
+}
+
+// toLines refuses a variant listed twice, which would make the lines of a
+// delivery ambiguous.
+func toLines(data []model.PurchaseOrderLineRequest) ([]schema.PurchaseOrderLine, error) {
+	seen := map[int64]bool{}
+	lines := []schema.PurchaseOrderLine{}
+	for _, line := range data {
+		if seen[line.VariantId] {
+			return nil, errors.New("a variant can only be listed once")
+		}
+		seen[line.VariantId] = true
+
+		lines = append(lines, schema.PurchaseOrderLine{
+			VariantId: line.VariantId,
+			Qty:       line.Qty,
+			UnitCost:  line.UnitCost,
+		})
+	}
+
+	return lines, nil
+}
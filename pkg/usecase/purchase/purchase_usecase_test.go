@@ -0,0 +1,113 @@
+package purchase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/entity/schema"
+	"kanggo/pkg/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestInsertSupplier(t *testing.T) {
+	mockPurchaseStorage := new(mocks.PurchaseStorage)
+	u := NewPurchaseUsecase(mockPurchaseStorage)
+	ctx := context.Background()
+
+	t.Run("active by default", func(t *testing.T) {
+		mockPurchaseStorage.On("InsertSupplier", mock.Anything, schema.Supplier{
+			Name:   "PT Semen Nusantara",
+			Email:  "sales@semen.co.id",
+			Active: true,
+		}).Return(nil).Once()
+
+		err := u.InsertSupplier(ctx, model.SupplierRequest{Name: " PT Semen Nusantara ", Email: "sales@semen.co.id"})
+
+		assert.NoError(t, err)
+		mockPurchaseStorage.AssertExpectations(t)
+	})
+}
+
+func TestInsert(t *testing.T) {
+	mockPurchaseStorage := new(mocks.PurchaseStorage)
+	u := NewPurchaseUsecase(mockPurchaseStorage)
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		mockPurchaseStorage.On("Insert", mock.Anything, schema.PurchaseOrder{SupplierId: 1, WarehouseId: 2}, []schema.PurchaseOrderLine{
+			{VariantId: 3, Qty: 100, UnitCost: 52000},
+			{VariantId: 4, Qty: 20, UnitCost: 15000},
+		}).Return(int64(7), nil).Once()
+		mockPurchaseStorage.On("GetById", mock.Anything, int64(7)).Return(&model.PurchaseOrderResponse{Id: 7, Number: "PO-000007", Status: schema.PurchaseDraft}, nil).Once()
+		mockPurchaseStorage.On("GetLines", mock.Anything, int64(7)).Return([]model.PurchaseOrderLineResponse{{Id: 1, VariantId: 3}, {Id: 2, VariantId: 4}}, nil).Once()
+
+		res, err := u.Insert(ctx, model.PurchaseOrderRequest{SupplierId: 1, WarehouseId: 2, Lines: []model.PurchaseOrderLineRequest{
+			{VariantId: 3, Qty: 100, UnitCost: 52000},
+			{VariantId: 4, Qty: 20, UnitCost: 15000},
+		}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "PO-000007", res.Number)
+		assert.Len(t, res.Lines, 2)
+		mockPurchaseStorage.AssertExpectations(t)
+	})
+
+	t.Run("variant listed twice", func(t *testing.T) {
+		_, err := u.Insert(ctx, model.PurchaseOrderRequest{SupplierId: 1, Lines: []model.PurchaseOrderLineRequest{
+			{VariantId: 3, Qty: 100},
+			{VariantId: 3, Qty: 20},
+		}})
+
+		assert.EqualError(t, err, "a variant can only be listed once")
+		mockPurchaseStorage.AssertNumberOfCalls(t, "Insert", 1)
+	})
+
+	t.Run("inactive supplier", func(t *testing.T) {
+		mockPurchaseStorage.On("Insert", mock.Anything, schema.PurchaseOrder{SupplierId: 5}, mock.Anything).Return(int64(0), errors.New("supplier is inactive")).Once()
+
+		_, err := u.Insert(ctx, model.PurchaseOrderRequest{SupplierId: 5, Lines: []model.PurchaseOrderLineRequest{{VariantId: 3, Qty: 1}}})
+
+		assert.EqualError(t, err, "supplier is inactive")
+		mockPurchaseStorage.AssertNotCalled(t, "GetById", mock.Anything, int64(0))
+	})
+}
+
+func TestReceive(t *testing.T) {
+	mockPurchaseStorage := new(mocks.PurchaseStorage)
+	u := NewPurchaseUsecase(mockPurchaseStorage)
+	ctx := context.Background()
+
+	t.Run("partially received", func(t *testing.T) {
+		lines := []model.ReceiveLineRequest{{LineId: 1, Qty: 60}}
+		mockPurchaseStorage.On("Receive", mock.Anything, int64(7), lines, int64(1), "DO-88123").Return(nil).Once()
+		mockPurchaseStorage.On("GetById", mock.Anything, int64(7)).Return(&model.PurchaseOrderResponse{Id: 7, Status: schema.PurchasePartiallyReceived}, nil).Once()
+		mockPurchaseStorage.On("GetLines", mock.Anything, int64(7)).Return([]model.PurchaseOrderLineResponse{{Id: 1, Qty: 100, ReceivedQty: 60}}, nil).Once()
+
+		res, err := u.Receive(ctx, 7, 1, model.ReceiveRequest{Lines: lines, Note: "DO-88123"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, schema.PurchasePartiallyReceived, res.Status)
+		assert.EqualValues(t, 60, res.Lines[0].ReceivedQty)
+		mockPurchaseStorage.AssertExpectations(t)
+	})
+
+	t.Run("line listed twice", func(t *testing.T) {
+		_, err := u.Receive(ctx, 7, 1, model.ReceiveRequest{Lines: []model.ReceiveLineRequest{{LineId: 1, Qty: 10}, {LineId: 1, Qty: 5}}})
+
+		assert.EqualError(t, err, "a line can only be received once per delivery")
+		mockPurchaseStorage.AssertNumberOfCalls(t, "Receive", 1)
+	})
+
+	t.Run("not sent", func(t *testing.T) {
+		mockPurchaseStorage.On("Receive", mock.Anything, int64(8), mock.Anything, int64(1), "").Return(errors.New("purchase order is not awaiting goods")).Once()
+
+		_, err := u.Receive(ctx, 8, 1, model.ReceiveRequest{Lines: []model.ReceiveLineRequest{{LineId: 4, Qty: 1}}})
+
+		assert.EqualError(t, err, "purchase order is not awaiting goods")
+		mockPurchaseStorage.AssertNotCalled(t, "GetById", mock.Anything, int64(8))
+	})
+}
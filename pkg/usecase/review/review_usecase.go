@@ -0,0 +1,74 @@
+package review
+
+import (
+	"context"
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/entity/schema"
+	storage "kanggo/pkg/storage/review"
+	"strings"
+)
+
+//go:generate mockery --name ReviewUsecase --case snake --output ../../mocks --disable-version-string
+
+type (
+	ReviewUsecase interface {
+		Insert(ctx context.Context, productId int64, userId uint64, data model.ReviewRequest) (*model.ReviewResponse, error)
+		GetByProduct(ctx context.Context, productId int64, query model.ListQuery) ([]model.ReviewResponse, int64, error)
+		GetAll(ctx context.Context, query model.ListQuery) ([]model.ReviewResponse, int64, error)
+		Approve(ctx context.Context, id int64, actorId uint64) error
+		Hide(ctx context.Context, id int64, actorId uint64) error
+	}
+
+	reviewUsecase struct {
+		reviewStorage storage.ReviewStorage
+	}
+)
+
+func NewReviewUsecase(reviewStorage storage.ReviewStorage) ReviewUsecase {
+	return &reviewUsecase{
+		reviewStorage: reviewStorage,
+	}
+}
+
+// Insert posts a review, which is shown once an admin approves it.
+func (r *reviewUsecase) Insert(ctx context.Context, productId int64, userId uint64, data model.ReviewRequest) (*model.ReviewResponse, error) {
+	id, err := r.reviewStorage.Insert(ctx, schema.Review{
+		ProductId: productId,
+		UserId:    int64(userId),
+		Rating:    data.Rating,
+		Text:      strings.TrimSpace(data.Text),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.reviewStorage.GetById(ctx, id)
+}
+
+// GetByProduct lists the approved reviews of a product.
+func (r *reviewUsecase) GetByProduct(ctx context.Context, productId int64, query model.ListQuery) ([]model.ReviewResponse, int64, error) {
+	query.Status = schema.ReviewApproved
+
+	return r.reviewStorage.GetAll(ctx, productId, query)
+}
+
+// GetAll lists the reviews of every product for moderation.
+func (r *reviewUsecase) GetAll(ctx context.Context, query model.ListQuery) ([]model.ReviewResponse, int64, error) {
+	return r.reviewStorage.GetAll(ctx, 0, query)
+}
+
+func (r *reviewUsecase) Approve(ctx context.Context, id int64, actorId uint64) error {
+	if err := r.reviewStorage.Moderate(ctx, id, schema.ReviewApproved, int64(actorId)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *reviewUsecase) Hide(ctx context.Context, id int64, actorId uint64) error {
+	if err := r.reviewStorage.Moderate(ctx, id, schema.ReviewHidden, int64(actorId)); err != nil {
+		return err
+	}
+
+	return nil
+}
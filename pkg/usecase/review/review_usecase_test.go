@@ -0,0 +1,86 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/entity/schema"
+	"kanggo/pkg/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestInsert(t *testing.T) {
+	mockReviewStorage := new(mocks.ReviewStorage)
+	u := NewReviewUsecase(mockReviewStorage)
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		mockReviewStorage.On("Insert", mock.Anything, schema.Review{ProductId: 1, UserId: 2, Rating: 5, Text: "Semennya cepat kering"}).
+			Return(int64(9), nil).Once()
+		mockReviewStorage.On("GetById", mock.Anything, int64(9)).
+			Return(&model.ReviewResponse{Id: 9, ProductId: 1, UserId: 2, Rating: 5, Status: schema.ReviewPending}, nil).Once()
+
+		res, err := u.Insert(ctx, 1, 2, model.ReviewRequest{Rating: 5, Text: " Semennya cepat kering "})
+
+		assert.NoError(t, err)
+		assert.Equal(t, schema.ReviewPending, res.Status)
+		mockReviewStorage.AssertExpectations(t)
+	})
+
+	t.Run("not a buyer", func(t *testing.T) {
+		mockReviewStorage.On("Insert", mock.Anything, mock.MatchedBy(func(r schema.Review) bool {
+			return r.UserId == 3
+		})).Return(int64(0), errors.New("only buyers of the product can review it")).Once()
+
+		_, err := u.Insert(ctx, 1, 3, model.ReviewRequest{Rating: 1})
+
+		assert.EqualError(t, err, "only buyers of the product can review it")
+		mockReviewStorage.AssertNotCalled(t, "GetById", mock.Anything, int64(0))
+	})
+}
+
+func TestGetByProduct(t *testing.T) {
+	mockReviewStorage := new(mocks.ReviewStorage)
+	u := NewReviewUsecase(mockReviewStorage)
+	ctx := context.Background()
+
+	t.Run("approved only", func(t *testing.T) {
+		mockReviewStorage.On("GetAll", mock.Anything, int64(1), model.ListQuery{Page: 1, Size: 10, Status: schema.ReviewApproved}).
+			Return([]model.ReviewResponse{{Id: 9, Rating: 5, Status: schema.ReviewApproved}}, int64(1), nil).Once()
+
+		res, total, err := u.GetByProduct(ctx, 1, model.ListQuery{Page: 1, Size: 10, Status: schema.ReviewHidden})
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, 1, total)
+		assert.Len(t, res, 1)
+		mockReviewStorage.AssertExpectations(t)
+	})
+}
+
+func TestModerate(t *testing.T) {
+	mockReviewStorage := new(mocks.ReviewStorage)
+	u := NewReviewUsecase(mockReviewStorage)
+	ctx := context.Background()
+
+	t.Run("approve", func(t *testing.T) {
+		mockReviewStorage.On("Moderate", mock.Anything, int64(9), schema.ReviewApproved, int64(1)).Return(nil).Once()
+
+		err := u.Approve(ctx, 9, 1)
+
+		assert.NoError(t, err)
+		mockReviewStorage.AssertExpectations(t)
+	})
+
+	t.Run("hide unknown review", func(t *testing.T) {
+		mockReviewStorage.On("Moderate", mock.Anything, int64(99), schema.ReviewHidden, int64(1)).Return(errors.New("data not found")).Once()
+
+		err := u.Hide(ctx, 99, 1)
+
+		assert.EqualError(t, err, "data not found")
+		mockReviewStorage.AssertExpectations(t)
+	})
+}
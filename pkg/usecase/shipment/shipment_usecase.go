@@ -0,0 +1,109 @@
+package shipment
+
+import (
+	"context"
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/entity/schema"
+	orderStorage "kanggo/pkg/storage/order"
+	storage "kanggo/pkg/storage/shipment"
+	"time"
+)
+
+//go:generate mockery --name ShipmentUsecase --case snake --output ../../mocks --disable-version-string
+
+type (
+	ShipmentUsecase interface {
+		Insert(ctx context.Context, orderId int64, data model.ShipmentRequest) error
+		InsertEvent(ctx context.Context, shipmentId int64, actorId uint64, data model.ShipmentEventRequest) error
+		GetTracking(ctx context.Context, orderId int64, userId uint64) (*model.TrackingResponse, error)
+	}
+
+	shipmentUsecase struct {
+		shipmentStorage storage.ShipmentStorage
+		orderStorage    orderStorage.OrderStorage
+	}
+)
+
+func NewShipmentUsecase(shipmentStorage storage.ShipmentStorage, orderStorage orderStorage.OrderStorage) ShipmentUsecase {
+	return &shipmentUsecase{
+		shipmentStorage: shipmentStorage,
+		orderStorage:    orderStorage,
+	}
+}
+
+func (s *shipmentUsecase) Insert(ctx context.Context, orderId int64, data model.ShipmentRequest) error {
+	request := schema.Shipment{
+		OrderId:        orderId,
+		Courier:        data.Courier,
+		Service:        data.Service,
+		TrackingNumber: data.TrackingNumber,
+	}
+
+	if err := s.shipmentStorage.Insert(ctx, request); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *shipmentUsecase) InsertEvent(ctx context.Context, shipmentId int64, actorId uint64, data model.ShipmentEventRequest) error {
+	occurredAt := time.Now()
+	if data.OccurredAt != nil {
+		occurredAt = *data.OccurredAt
+	}
+
+	request := schema.ShipmentEvent{
+		ShipmentId:  shipmentId,
+		Status:      data.Status,
+		Location:    data.Location,
+		Description: data.Description,
+		OccurredAt:  occurredAt,
+	}
+
+	if err := s.shipmentStorage.InsertEvent(ctx, request, int64(actorId)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *shipmentUsecase) GetTracking(ctx context.Context, orderId int64, userId uint64) (*model.TrackingResponse, error) {
+	order, err := s.orderStorage.GetOrderById(ctx, orderId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	shipment, err := s.shipmentStorage.GetByOrderId(ctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := s.shipmentStorage.GetEvents(ctx, int64(shipment.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	tracking := model.TrackingResponse{
+		OrderId:        order.OrderId,
+		OrderNumber:    order.OrderNumber,
+		OrderStatus:    order.Status,
+		ShipmentId:     int64(shipment.Id),
+		Courier:        shipment.Courier,
+		Service:        shipment.Service,
+		TrackingNumber: shipment.TrackingNumber,
+		ShippedAt:      shipment.ShippedAt,
+		DeliveredAt:    shipment.DeliveredAt,
+		Events:         []model.TrackingEvent{},
+	}
+
+	for i := range events {
+		tracking.Events = append(tracking.Events, model.TrackingEvent{
+			Status:      events[i].Status,
+			Location:    events[i].Location,
+			Description: events[i].Description,
+			OccurredAt:  events[i].OccurredAt,
+		})
+	}
+
+	return &tracking, nil
+}
@@ -0,0 +1,99 @@
+package shipment
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/entity/schema"
+	"kanggo/pkg/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestInsert(t *testing.T) {
+	mockShipmentStorage := new(mocks.ShipmentStorage)
+	mockOrderStorage := new(mocks.OrderStorage)
+	s := NewShipmentUsecase(mockShipmentStorage, mockOrderStorage)
+	ctx := context.Background()
+
+	request := model.ShipmentRequest{
+		Courier:        "jne",
+		Service:        "REG",
+		TrackingNumber: "JNE0012345678",
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockShipmentStorage.On("Insert", mock.Anything, schema.Shipment{
+			OrderId:        1,
+			Courier:        "jne",
+			Service:        "REG",
+			TrackingNumber: "JNE0012345678",
+		}).Return(nil)
+
+		err := s.Insert(ctx, 1, request)
+
+		assert.NoError(t, err)
+		mockShipmentStorage.AssertExpectations(t)
+	})
+}
+
+func TestInsertEvent(t *testing.T) {
+	mockShipmentStorage := new(mocks.ShipmentStorage)
+	mockOrderStorage := new(mocks.OrderStorage)
+	s := NewShipmentUsecase(mockShipmentStorage, mockOrderStorage)
+	ctx := context.Background()
+	occurredAt := time.Date(2021, 11, 20, 10, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		mockShipmentStorage.On("InsertEvent", mock.Anything, schema.ShipmentEvent{
+			ShipmentId: 3,
+			Status:     "delivered",
+			Location:   "Bandung",
+			OccurredAt: occurredAt,
+		}, int64(1)).Return(nil)
+
+		err := s.InsertEvent(ctx, 3, 1, model.ShipmentEventRequest{
+			Status:     "delivered",
+			Location:   "Bandung",
+			OccurredAt: &occurredAt,
+		})
+
+		assert.NoError(t, err)
+		mockShipmentStorage.AssertExpectations(t)
+	})
+}
+
+func TestGetTracking(t *testing.T) {
+	mockShipmentStorage := new(mocks.ShipmentStorage)
+	mockOrderStorage := new(mocks.OrderStorage)
+	s := NewShipmentUsecase(mockShipmentStorage, mockOrderStorage)
+	ctx := context.Background()
+	var orderId int64 = 1
+	var userId uint64 = 1
+	shippedAt := time.Date(2021, 11, 19, 8, 0, 0, 0, time.UTC)
+
+	mockEvents := []schema.ShipmentEvent{
+		{ShipmentId: 3, Status: "picked_up", Location: "Jakarta", OccurredAt: shippedAt},
+		{ShipmentId: 3, Status: "in_transit", Location: "Cikampek", OccurredAt: shippedAt.Add(5 * time.Hour)},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockOrderStorage.On("GetOrderById", mock.Anything, orderId, userId).
+			Return(&model.OrderResponse{OrderId: 1, UserId: 1, Status: "shipped"}, nil)
+		mockShipmentStorage.On("GetByOrderId", mock.Anything, orderId).
+			Return(&schema.Shipment{Base: schema.Base{Id: 3}, OrderId: 1, Courier: "jne", TrackingNumber: "JNE0012345678", ShippedAt: &shippedAt}, nil)
+		mockShipmentStorage.On("GetEvents", mock.Anything, int64(3)).Return(mockEvents, nil)
+
+		tracking, err := s.GetTracking(ctx, orderId, userId)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "shipped", tracking.OrderStatus)
+		assert.Equal(t, "JNE0012345678", tracking.TrackingNumber)
+		assert.Len(t, tracking.Events, 2)
+		mockOrderStorage.AssertExpectations(t)
+		mockShipmentStorage.AssertExpectations(t)
+	})
+}
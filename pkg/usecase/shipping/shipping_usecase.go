@@ -0,0 +1,155 @@
+package shipping
+
+import (
+	"context"
+	"errors"
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/entity/schema"
+	"kanggo/pkg/inventory"
+	"kanggo/pkg/shipping"
+	addressStorage "kanggo/pkg/storage/address"
+	productStorage "kanggo/pkg/storage/product"
+)
+
+//go:generate mockery --name ShippingUsecase --case snake --output ../../mocks --disable-version-string
+
+type (
+	ShippingUsecase interface {
+		Quote(ctx context.Context, userId uint64, data model.ShippingQuoteRequest) ([]model.ShippingOption, error)
+	}
+
+	shippingUsecase struct {
+		addressStorage addressStorage.AddressStorage
+		productStorage productStorage.ProductStorage
+		rateProvider   shipping.ShippingRateProvider
+		origin         shipping.Region
+	}
+)
+
+func NewShippingUsecase(addressStorage addressStorage.AddressStorage, productStorage productStorage.ProductStorage,
+	rateProvider shipping.ShippingRateProvider, origin shipping.Region) ShippingUsecase {
+	return &shippingUsecase{
+		addressStorage: addressStorage,
+		productStorage: productStorage,
+		rateProvider:   rateProvider,
+		origin:         origin,
+	}
+}
+
+// Quote prices the delivery of the items to the address. Items ship from
+// the warehouse an order of them would be fulfilled from; when they come
+// from several warehouses, each parcel is quoted on its own and only the
+// services offered for all of them are returned, at their summed cost.
+func (s *shippingUsecase) Quote(ctx context.Context, userId uint64, data model.ShippingQuoteRequest) ([]model.ShippingOption, error) {
+	address, err := s.addressStorage.GetById(ctx, data.AddressId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	weights := map[shipping.Region]int64{}
+	origins := []shipping.Region{}
+	for _, item := range data.Items {
+		product, err := s.productStorage.GetById(ctx, item.ProductId)
+		if err != nil {
+			return nil, err
+		}
+
+		// a variant may weigh differently from the product, e.g. 40kg or 50kg
+		if item.VariantId > 0 {
+			variant, err := s.productStorage.GetVariantById(ctx, item.VariantId)
+			if err != nil {
+				return nil, err
+			}
+			if variant.ProductId != item.ProductId {
+				return nil, errors.New("variant not found")
+			}
+			product.Weight = variant.Weight
+		}
+
+		origin, err := s.originOf(ctx, item, address)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := weights[origin]; !ok {
+			origins = append(origins, origin)
+		}
+		weights[origin] += shipping.ChargeableWeight(product.Weight, product.Length, product.Width, product.Height, item.Quantity)
+	}
+
+	type key struct {
+		courier string
+		service string
+	}
+
+	options := []model.ShippingOption{}
+	for i, origin := range origins {
+		res, err := s.rateProvider.Quote(ctx, shipping.RateRequest{
+			Origin:      origin,
+			Destination: shipping.Region{Province: address.Province, City: address.City},
+			Weight:      weights[origin],
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if i == 0 {
+			for j := range res {
+				options = append(options, model.ShippingOption{
+					Courier: res[j].Courier,
+					Service: res[j].Service,
+					Cost:    res[j].Cost,
+					Etd:     res[j].Etd,
+				})
+			}
+			continue
+		}
+
+		costs := map[key]float64{}
+		for j := range res {
+			costs[key{res[j].Courier, res[j].Service}] = res[j].Cost
+		}
+
+		offered := []model.ShippingOption{}
+		for _, option := range options {
+			if cost, ok := costs[key{option.Courier, option.Service}]; ok {
+				option.Cost += cost
+				offered = append(offered, option)
+			}
+		}
+		options = offered
+	}
+
+	return options, nil
+}
+
+// originOf is where the item ships from: the warehouse an order of it would
+// be fulfilled from, or the shop's origin before warehouses are set up. An
+// item without a variant is placed only if the product has a single one.
+func (s *shippingUsecase) originOf(ctx context.Context, item model.ShippingItem, address *schema.Address) (shipping.Region, error) {
+	stocks, err := s.productStorage.GetStock(ctx, []int64{item.ProductId})
+	if err != nil {
+		return shipping.Region{}, err
+	}
+
+	variantId := item.VariantId
+	if variantId == 0 {
+		for _, stock := range stocks {
+			if variantId != 0 && stock.VariantId != variantId {
+				return s.origin, nil
+			}
+			variantId = stock.VariantId
+		}
+	}
+
+	warehouse, err := inventory.Choose(stocks, variantId, item.Quantity, address.Province, address.City)
+	if err != nil {
+		return shipping.Region{}, err
+	}
+
+	if warehouse == nil {
+		return s.origin, nil
+	}
+
+	return shipping.Region{Province: warehouse.Province, City: warehouse.City}, nil
+}
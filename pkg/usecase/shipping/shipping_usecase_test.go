@@ -0,0 +1,85 @@
+package shipping
+
+import (
+	"context"
+	"testing"
+
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/entity/schema"
+	"kanggo/pkg/inventory"
+	"kanggo/pkg/mocks"
+	"kanggo/pkg/shipping"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestQuote(t *testing.T) {
+	mockAddressStorage := new(mocks.AddressStorage)
+	mockProductStorage := new(mocks.ProductStorage)
+	mockRateProvider := new(mocks.ShippingRateProvider)
+	origin := shipping.Region{Province: "DKI Jakarta", City: "Jakarta Utara"}
+	s := NewShippingUsecase(mockAddressStorage, mockProductStorage, mockRateProvider, origin)
+	ctx := context.Background()
+	var userId uint64 = 1
+
+	request := model.ShippingQuoteRequest{
+		AddressId: 1,
+		Items: []model.ShippingItem{
+			{ProductId: 1, Quantity: 2},
+			{ProductId: 2, Quantity: 1},
+		},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockAddressStorage.On("GetById", mock.Anything, int64(1), userId).
+			Return(&schema.Address{Base: schema.Base{Id: 1}, Province: "Jawa Barat", City: "Bandung"}, nil)
+		mockProductStorage.On("GetById", mock.Anything, int64(1)).
+			Return(&schema.Product{Base: schema.Base{Id: 1}, Weight: 50000}, nil)
+		mockProductStorage.On("GetById", mock.Anything, int64(2)).
+			Return(&schema.Product{Base: schema.Base{Id: 2}, Weight: 1500, Length: 400, Width: 10, Height: 6}, nil)
+		mockProductStorage.On("GetStock", mock.Anything, []int64{1}).Return([]inventory.Stock{}, nil).Once()
+		mockProductStorage.On("GetStock", mock.Anything, []int64{2}).Return([]inventory.Stock{}, nil).Once()
+		mockRateProvider.On("Quote", mock.Anything, shipping.RateRequest{
+			Origin:      origin,
+			Destination: shipping.Region{Province: "Jawa Barat", City: "Bandung"},
+			Weight:      104000,
+		}).Return([]shipping.RateOption{{Courier: "jne", Service: "REG", Cost: 1155000, Etd: "2-3 days"}}, nil)
+
+		options, err := s.Quote(ctx, userId, request)
+
+		assert.NoError(t, err)
+		assert.Len(t, options, 1)
+		assert.Equal(t, float64(1155000), options[0].Cost)
+		mockAddressStorage.AssertExpectations(t)
+		mockProductStorage.AssertExpectations(t)
+		mockRateProvider.AssertExpectations(t)
+	})
+
+	t.Run("from two warehouses", func(t *testing.T) {
+		bekasi := shipping.Region{Province: "Jawa Barat", City: "Bekasi"}
+		destination := shipping.Region{Province: "Jawa Barat", City: "Bandung"}
+
+		mockProductStorage.On("GetStock", mock.Anything, []int64{1}).Return([]inventory.Stock{
+			{ProductId: 1, VariantId: 7, WarehouseId: 1, Province: "DKI Jakarta", City: "Jakarta Utara", Active: true, Qty: 100},
+			{ProductId: 1, VariantId: 7, WarehouseId: 2, Province: "Jawa Barat", City: "Bekasi", Active: true, Qty: 10},
+		}, nil).Once()
+		mockProductStorage.On("GetStock", mock.Anything, []int64{2}).Return([]inventory.Stock{
+			{ProductId: 2, VariantId: 8, WarehouseId: 1, Province: "DKI Jakarta", City: "Jakarta Utara", Active: true, Qty: 100},
+		}, nil).Once()
+		mockRateProvider.On("Quote", mock.Anything, shipping.RateRequest{Origin: bekasi, Destination: destination, Weight: 100000}).
+			Return([]shipping.RateOption{
+				{Courier: "jne", Service: "REG", Cost: 1100000, Etd: "2-3 days"},
+				{Courier: "jne", Service: "CARGO", Cost: 600000, Etd: "5-8 days"},
+			}, nil).Once()
+		mockRateProvider.On("Quote", mock.Anything, shipping.RateRequest{Origin: origin, Destination: destination, Weight: 4000}).
+			Return([]shipping.RateOption{{Courier: "jne", Service: "REG", Cost: 44000, Etd: "2-3 days"}}, nil).Once()
+
+		options, err := s.Quote(ctx, userId, request)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []model.ShippingOption{{Courier: "jne", Service: "REG", Cost: 1144000, Etd: "2-3 days"}}, options)
+		mockProductStorage.AssertExpectations(t)
+		mockRateProvider.AssertExpectations(t)
+	})
+}
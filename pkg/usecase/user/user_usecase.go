@@ -2,10 +2,18 @@ package user
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"kanggo/pkg/entity/model"
 	"kanggo/pkg/entity/schema"
+	"kanggo/pkg/mail"
 	storage "kanggo/pkg/storage/user"
 	"kanggo/utils"
+	"time"
 
 	"github.com/jinzhu/copier"
 )
@@ -16,16 +24,32 @@ type (
 	UserUsecase interface {
 		Insert(context.Context, model.RegisterRequest) error
 		GetByEmail(context.Context, string) (*model.UserResponse, error)
+		GetById(context.Context, uint64) (*model.UserResponse, error)
+		UpdateProfile(context.Context, uint64, model.UpdateProfileRequest) error
+		ConfirmEmail(context.Context, uint64, model.VerifyEmailRequest) error
+		UpdatePassword(context.Context, uint64, model.ChangePasswordRequest) error
+		Delete(context.Context, uint64, model.DeleteAccountRequest) error
+		GetAll(ctx context.Context, search string, page, size int) (*model.UserListResponse, error)
+		GetDetail(ctx context.Context, id uint64) (*model.UserDetailResponse, error)
+		Suspend(ctx context.Context, id uint64) error
+		Reactivate(ctx context.Context, id uint64) error
+		UpdateRole(ctx context.Context, id uint64, req model.UpdateRoleRequest) error
+		ForcePasswordReset(ctx context.Context, id uint64) error
 	}
 
 	userUsecase struct {
 		userStorage storage.UserStorage
+		mailer      mail.Mailer
 	}
 )
 
-func NewUserUsecase(userStorage storage.UserStorage) UserUsecase {
+// emailTokenTTL is how long the token mailed to a new address is valid.
+const emailTokenTTL = 24 * time.Hour
+
+func NewUserUsecase(userStorage storage.UserStorage, mailer mail.Mailer) UserUsecase {
 	return &userUsecase{
 		userStorage: userStorage,
+		mailer:      mailer,
 	}
 }
 
@@ -55,7 +79,252 @@ func (u *userUsecase) GetByEmail(ctx context.Context, email string) (*model.User
 		Name:     res.Name,
 		Email:    res.Email,
 		Password: res.Password,
+		Role:     res.Role,
+		Status:   res.Status,
+
+		PasswordResetRequired: res.PasswordResetRequired,
+	}
+
+	return &user, nil
+}
+
+func (u *userUsecase) GetById(ctx context.Context, id uint64) (*model.UserResponse, error) {
+	res, err := u.userStorage.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	user := model.UserResponse{
+		Id:     int(res.Id),
+		Name:   res.Name,
+		Email:  res.Email,
+		Role:   res.Role,
+		Status: res.Status,
+
+		PasswordResetRequired: res.PasswordResetRequired,
+		PendingEmail:          res.PendingEmail,
 	}
 
 	return &user, nil
 }
+
+// UpdateProfile changes the name of the user and starts a change of email.
+// Changing the email address requires the current password so a stolen token
+// alone cannot take over the account, and the new address only replaces the
+// old one once the token mailed to it is confirmed.
+func (u *userUsecase) UpdateProfile(ctx context.Context, id uint64, req model.UpdateProfileRequest) error {
+	res, err := u.userStorage.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	data := schema.User{
+		Base: schema.Base{Id: res.Id},
+		Name: req.Name,
+	}
+
+	if req.Email != "" && req.Email != res.Email {
+		if req.Password == "" {
+			return errors.New("password is required to change email")
+		}
+
+		if !utils.CheckPasswordHash(req.Password, res.Password) {
+			return errors.New("invalid password")
+		}
+
+		_, err := u.userStorage.GetByEmail(ctx, req.Email)
+		if err == nil {
+			return errors.New("email already registered")
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		if err := u.requestEmailChange(ctx, id, req.Email); err != nil {
+			return err
+		}
+	}
+
+	if data.Name == "" {
+		return nil
+	}
+
+	if err := u.userStorage.Update(ctx, data); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// requestEmailChange mails a token to the new address, which is kept as
+// pending until the token is confirmed.
+func (u *userUsecase) requestEmailChange(ctx context.Context, id uint64, email string) error {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return err
+	}
+	token := hex.EncodeToString(raw)
+
+	if err := u.userStorage.SetPendingEmail(ctx, id, email, hashToken(token), time.Now().Add(emailTokenTTL)); err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Use this token to confirm %s as the email of your account:\r\n\r\n%s\r\n\r\n"+
+		"It expires in %d hours. If you did not ask for the change, ignore this email.", email, token, int(emailTokenTTL.Hours()))
+
+	return u.mailer.Send(ctx, email, "Confirm your new email address", body)
+}
+
+// ConfirmEmail replaces the email of the user with the pending one the token
+// was mailed to.
+func (u *userUsecase) ConfirmEmail(ctx context.Context, id uint64, req model.VerifyEmailRequest) error {
+	if err := u.userStorage.ConfirmEmail(ctx, id, hashToken(req.Token)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (u *userUsecase) UpdatePassword(ctx context.Context, id uint64, req model.ChangePasswordRequest) error {
+	res, err := u.userStorage.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !utils.CheckPasswordHash(req.CurrentPassword, res.Password) {
+		return errors.New("invalid password")
+	}
+
+	if err := u.userStorage.UpdatePassword(ctx, id, utils.HashPassword(req.NewPassword)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (u *userUsecase) Delete(ctx context.Context, id uint64, req model.DeleteAccountRequest) error {
+	res, err := u.userStorage.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !utils.CheckPasswordHash(req.Password, res.Password) {
+		return errors.New("invalid password")
+	}
+
+	if err := u.userStorage.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (u *userUsecase) GetAll(ctx context.Context, search string, page, size int) (*model.UserListResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 || size > 100 {
+		size = 20
+	}
+
+	res, total, err := u.userStorage.GetAll(ctx, search, size, (page-1)*size)
+	if err != nil {
+		return nil, err
+	}
+
+	users := []model.UserResponse{}
+	for i := range res {
+		users = append(users, model.UserResponse{
+			Id:     int(res[i].Id),
+			Name:   res[i].Name,
+			Email:  res[i].Email,
+			Role:   res[i].Role,
+			Status: res[i].Status,
+
+			PasswordResetRequired: res[i].PasswordResetRequired,
+		})
+	}
+
+	return &model.UserListResponse{
+		Users: users,
+		Page:  page,
+		Size:  size,
+		Total: total,
+	}, nil
+}
+
+func (u *userUsecase) GetDetail(ctx context.Context, id uint64) (*model.UserDetailResponse, error) {
+	user, err := u.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := u.userStorage.GetOrderSummary(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.UserDetailResponse{
+		UserResponse: *user,
+		OrderSummary: *summary,
+	}, nil
+}
+
+func (u *userUsecase) Suspend(ctx context.Context, id uint64) error {
+	res, err := u.userStorage.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if res.Status == "suspended" {
+		return errors.New("user already suspended")
+	}
+
+	return u.userStorage.UpdateStatus(ctx, id, "suspended")
+}
+
+func (u *userUsecase) Reactivate(ctx context.Context, id uint64) error {
+	res, err := u.userStorage.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if res.Status == "active" {
+		return errors.New("user already active")
+	}
+
+	return u.userStorage.UpdateStatus(ctx, id, "active")
+}
+
+func (u *userUsecase) UpdateRole(ctx context.Context, id uint64, req model.UpdateRoleRequest) error {
+	res, err := u.userStorage.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if res.Role == req.Role {
+		return nil
+	}
+
+	return u.userStorage.UpdateRole(ctx, id, req.Role)
+}
+
+// ForcePasswordReset flags the account so every request except changing the
+// password is rejected until the user picks a new one.
+func (u *userUsecase) ForcePasswordReset(ctx context.Context, id uint64) error {
+	res, err := u.userStorage.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if res.PasswordResetRequired {
+		return nil
+	}
+
+	return u.userStorage.SetPasswordResetRequired(ctx, id)
+}
@@ -2,8 +2,12 @@ package user
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"kanggo/pkg/entity/model"
 	"kanggo/pkg/entity/schema"
 	"kanggo/pkg/mocks"
+	"strings"
 	"testing"
 	"time"
 
@@ -32,7 +36,7 @@ func TestInsert(t *testing.T) {
 
 func TestGetByEmail(t *testing.T) {
 	mockUserStorage := new(mocks.UserStorage)
-	u := NewUserUsecase(mockUserStorage)
+	u := NewUserUsecase(mockUserStorage, new(mocks.Mailer))
 	ctx := context.Background()
 	var email string = "agung@gmail.com"
 
@@ -55,3 +59,177 @@ func TestGetByEmail(t *testing.T) {
 	})
 
 }
+
+func TestUpdatePassword(t *testing.T) {
+	mockUserStorage := new(mocks.UserStorage)
+	u := NewUserUsecase(mockUserStorage, new(mocks.Mailer))
+	ctx := context.Background()
+	var id uint64 = 1
+
+	mockUser := schema.User{
+		Base:     schema.Base{Id: 1},
+		Name:     "agung",
+		Email:    "agung@gmail.com",
+		Password: "$2a$14$lEQUEJ.f3N1rXzeFssrD3OrPuK2mQ4qQZpmBVySFAjM6GBw5MJzIq",
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockUserStorage.On("GetById", mock.Anything, id).Return(&mockUser, nil).Once()
+		mockUserStorage.On("UpdatePassword", mock.Anything, id, mock.AnythingOfType("string")).Return(nil).Once()
+
+		err := u.UpdatePassword(ctx, id, model.ChangePasswordRequest{
+			CurrentPassword: "agung123",
+			NewPassword:     "agung12345",
+		})
+
+		assert.Nil(t, err)
+		assert.NoError(t, err)
+		mockUserStorage.AssertExpectations(t)
+	})
+
+	t.Run("invalid password", func(t *testing.T) {
+		mockUserStorage.On("GetById", mock.Anything, id).Return(&mockUser, nil).Once()
+
+		err := u.UpdatePassword(ctx, id, model.ChangePasswordRequest{
+			CurrentPassword: "wrong1234",
+			NewPassword:     "agung12345",
+		})
+
+		assert.Error(t, err)
+		assert.Equal(t, "invalid password", err.Error())
+		mockUserStorage.AssertExpectations(t)
+	})
+}
+
+func TestUpdateProfile(t *testing.T) {
+	mockUserStorage := new(mocks.UserStorage)
+	u := NewUserUsecase(mockUserStorage, new(mocks.Mailer))
+	ctx := context.Background()
+	var id uint64 = 1
+
+	mockUser := schema.User{
+		Base:     schema.Base{Id: 1},
+		Name:     "agung",
+		Email:    "agung@gmail.com",
+		Password: "$2a$14$lEQUEJ.f3N1rXzeFssrD3OrPuK2mQ4qQZpmBVySFAjM6GBw5MJzIq",
+	}
+
+	t.Run("email change without password", func(t *testing.T) {
+		mockUserStorage.On("GetById", mock.Anything, id).Return(&mockUser, nil).Once()
+
+		err := u.UpdateProfile(ctx, id, model.UpdateProfileRequest{Email: "bayu@gmail.com"})
+
+		assert.Error(t, err)
+		assert.Equal(t, "password is required to change email", err.Error())
+		mockUserStorage.AssertExpectations(t)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		mockUserStorage.On("GetById", mock.Anything, id).Return(&mockUser, nil).Once()
+		mockUserStorage.On("Update", mock.Anything, schema.User{Base: schema.Base{Id: 1}, Name: "bayu"}).Return(nil).Once()
+
+		err := u.UpdateProfile(ctx, id, model.UpdateProfileRequest{Name: "bayu"})
+
+		assert.Nil(t, err)
+		assert.NoError(t, err)
+		mockUserStorage.AssertExpectations(t)
+	})
+
+	t.Run("email change waits for verification", func(t *testing.T) {
+		mockMailer := new(mocks.Mailer)
+		u := NewUserUsecase(mockUserStorage, mockMailer)
+
+		var tokenHash, body string
+		mockUserStorage.On("GetById", mock.Anything, id).Return(&mockUser, nil).Once()
+		mockUserStorage.On("GetByEmail", mock.Anything, "bayu@gmail.com").Return(nil, sql.ErrNoRows).Once()
+		mockUserStorage.On("SetPendingEmail", mock.Anything, id, "bayu@gmail.com", mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).
+			Run(func(args mock.Arguments) { tokenHash = args.String(3) }).Return(nil).Once()
+		mockMailer.On("Send", mock.Anything, "bayu@gmail.com", mock.AnythingOfType("string"), mock.AnythingOfType("string")).
+			Run(func(args mock.Arguments) { body = args.String(3) }).Return(nil).Once()
+
+		err := u.UpdateProfile(ctx, id, model.UpdateProfileRequest{Email: "bayu@gmail.com", Password: "agung123"})
+
+		assert.NoError(t, err)
+		assert.NotContains(t, body, tokenHash)
+		token := strings.Fields(strings.SplitN(body, ":", 2)[1])[0]
+		assert.Equal(t, tokenHash, hashToken(token))
+		mockUserStorage.AssertNumberOfCalls(t, "Update", 1)
+		mockUserStorage.AssertExpectations(t)
+		mockMailer.AssertExpectations(t)
+	})
+}
+
+func TestConfirmEmail(t *testing.T) {
+	mockUserStorage := new(mocks.UserStorage)
+	u := NewUserUsecase(mockUserStorage, new(mocks.Mailer))
+	ctx := context.Background()
+	var id uint64 = 1
+
+	t.Run("stores only the hash", func(t *testing.T) {
+		mockUserStorage.On("ConfirmEmail", mock.Anything, id, hashToken("abc123")).Return(nil).Once()
+
+		err := u.ConfirmEmail(ctx, id, model.VerifyEmailRequest{Token: "abc123"})
+
+		assert.NoError(t, err)
+		mockUserStorage.AssertExpectations(t)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		mockUserStorage.On("ConfirmEmail", mock.Anything, id, hashToken("old")).Return(errors.New("invalid or expired token")).Once()
+
+		err := u.ConfirmEmail(ctx, id, model.VerifyEmailRequest{Token: "old"})
+
+		assert.EqualError(t, err, "invalid or expired token")
+	})
+}
+
+func TestGetAll(t *testing.T) {
+	mockUserStorage := new(mocks.UserStorage)
+	u := NewUserUsecase(mockUserStorage, new(mocks.Mailer))
+	ctx := context.Background()
+
+	mockUserList := []schema.User{
+		{Base: schema.Base{Id: 1}, Name: "agung", Email: "agung@gmail.com", Role: "user", Status: "active"},
+		{Base: schema.Base{Id: 2}, Name: "bayu", Email: "bayu@gmail.com", Role: "user", Status: "suspended"},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockUserStorage.On("GetAll", mock.Anything, "gmail", 20, 0).Return(mockUserList, int64(2), nil)
+
+		list, err := u.GetAll(ctx, "gmail", 0, 0)
+
+		assert.NoError(t, err)
+		assert.Len(t, list.Users, len(mockUserList))
+		assert.Equal(t, int64(2), list.Total)
+		assert.Equal(t, 1, list.Page)
+		assert.Equal(t, "suspended", list.Users[1].Status)
+		mockUserStorage.AssertExpectations(t)
+	})
+}
+
+func TestSuspend(t *testing.T) {
+	mockUserStorage := new(mocks.UserStorage)
+	u := NewUserUsecase(mockUserStorage, new(mocks.Mailer))
+	ctx := context.Background()
+	var id uint64 = 1
+
+	t.Run("success", func(t *testing.T) {
+		mockUserStorage.On("GetById", mock.Anything, id).Return(&schema.User{Base: schema.Base{Id: 1}, Status: "active"}, nil).Once()
+		mockUserStorage.On("UpdateStatus", mock.Anything, id, "suspended").Return(nil).Once()
+
+		err := u.Suspend(ctx, id)
+
+		assert.NoError(t, err)
+		mockUserStorage.AssertExpectations(t)
+	})
+
+	t.Run("already suspended", func(t *testing.T) {
+		mockUserStorage.On("GetById", mock.Anything, id).Return(&schema.User{Base: schema.Base{Id: 1}, Status: "suspended"}, nil).Once()
+
+		err := u.Suspend(ctx, id)
+
+		assert.Error(t, err)
+		assert.Equal(t, "user already suspended", err.Error())
+		mockUserStorage.AssertExpectations(t)
+	})
+}
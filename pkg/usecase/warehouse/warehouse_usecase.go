@@ -0,0 +1,191 @@
+package warehouse
+
+import (
+	"context"
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/entity/schema"
+	storage "kanggo/pkg/storage/warehouse"
+	"strings"
+)
+
+//go:generate mockery --name WarehouseUsecase --case snake --output ../../mocks --disable-version-string
+
+type (
+	WarehouseUsecase interface {
+		Insert(ctx context.Context, data model.WarehouseRequest) error
+		Update(ctx context.Context, id int64, data model.WarehouseRequest) error
+		GetAll(ctx context.Context) ([]model.WarehouseResponse, error)
+		GetById(ctx context.Context, id int64) (*model.WarehouseResponse, error)
+		Delete(ctx context.Context, id int64) error
+
+		GetStock(ctx context.Context, id int64) ([]model.StockLevelResponse, error)
+		SetStock(ctx context.Context, id int64, actorId uint64, data model.StockRequest) error
+		Transfer(ctx context.Context, actorId uint64, data model.StockTransferRequest) (*model.StockTransferResponse, error)
+		GetMovements(ctx context.Context, id, variantId int64, query model.ListQuery) ([]model.StockMovementResponse, int64, error)
+	}
+
+	warehouseUsecase struct {
+		warehouseStorage storage.WarehouseStorage
+	}
+)
+
+func NewWarehouseUsecase(warehouseStorage storage.WarehouseStorage) WarehouseUsecase {
+	return &warehouseUsecase{
+		warehouseStorage: warehouseStorage,
+	}
+}
+
+func (w *warehouseUsecase) Insert(ctx context.Context, data model.WarehouseRequest) error {
+	if err := w.warehouseStorage.Insert(ctx, toWarehouse(data)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (w *warehouseUsecase) Update(ctx context.Context, id int64, data model.WarehouseRequest) error {
+	request := toWarehouse(data)
+	request.Id = uint(id)
+
+	if err := w.warehouseStorage.Update(ctx, request); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (w *warehouseUsecase) GetAll(ctx context.Context) ([]model.WarehouseResponse, error) {
+	res, err := w.warehouseStorage.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []model.WarehouseResponse{}
+	for i := range res {
+		results = append(results, toWarehouseResponse(res[i]))
+	}
+
+	return results, nil
+}
+
+func (w *warehouseUsecase) GetById(ctx context.Context, id int64) (*model.WarehouseResponse, error) {
+	res, err := w.warehouseStorage.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	warehouse := toWarehouseResponse(*res)
+
+	return &warehouse, nil
+}
+
+func (w *warehouseUsecase) Delete(ctx context.Context, id int64) error {
+	if err := w.warehouseStorage.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (w *warehouseUsecase) GetStock(ctx context.Context, id int64) ([]model.StockLevelResponse, error) {
+	if _, err := w.warehouseStorage.GetById(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return w.warehouseStorage.GetStock(ctx, id)
+}
+
+// SetStock sets the counted stock of a variant in the warehouse. The
+// difference is recorded as an adjustment.
+func (w *warehouseUsecase) SetStock(ctx context.Context, id int64, actorId uint64, data model.StockRequest) error {
+	stock := schema.WarehouseStock{
+		WarehouseId: id,
+		VariantId:   data.VariantId,
+		Qty:         data.Qty,
+	}
+
+	if err := w.warehouseStorage.SetStock(ctx, stock, int64(actorId), data.Note); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (w *warehouseUsecase) Transfer(ctx context.Context, actorId uint64, data model.StockTransferRequest) (*model.StockTransferResponse, error) {
+	reference, err := w.warehouseStorage.Transfer(ctx, data, int64(actorId))
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.StockTransferResponse{
+		Reference:       reference,
+		FromWarehouseId: data.FromWarehouseId,
+		ToWarehouseId:   data.ToWarehouseId,
+		VariantId:       data.VariantId,
+		Qty:             data.Qty,
+	}, nil
+}
+
+// GetMovements lists the stock movements of the warehouse, newest first,
+// optionally of a single variant.
+func (w *warehouseUsecase) GetMovements(ctx context.Context, id, variantId int64, query model.ListQuery) ([]model.StockMovementResponse, int64, error) {
+	if _, err := w.warehouseStorage.GetById(ctx, id); err != nil {
+		return nil, 0, err
+	}
+
+	res, total, err := w.warehouseStorage.GetMovements(ctx, id, variantId, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	results := []model.StockMovementResponse{}
+	for i := range res {
+		results = append(results, model.StockMovementResponse{
+			Id:          int64(res[i].Id),
+			WarehouseId: res[i].WarehouseId,
+			VariantId:   res[i].VariantId,
+			ProductId:   res[i].ProductId,
+			Qty:         res[i].Qty,
+			Type:        res[i].Type,
+			Reference:   res[i].Reference,
+			OrderId:     res[i].OrderId,
+			ActorId:     res[i].ActorId,
+			Note:        res[i].Note,
+			CreatedAt:   res[i].CreatedAt,
+		})
+	}
+
+	return results, total, nil
+}
+
+func toWarehouse(data model.WarehouseRequest) schema.Warehouse {
+	active := true
+	if data.Active != nil {
+		active = *data.Active
+	}
+
+	return schema.Warehouse{
+		Code:       strings.ToUpper(data.Code),
+		Name:       data.Name,
+		Street:     data.Street,
+		Province:   data.Province,
+		City:       data.City,
+		PostalCode: data.PostalCode,
+		IsDefault:  data.IsDefault,
+		Active:     active,
+	}
+}
+
+func toWarehouseResponse(res schema.Warehouse) model.WarehouseResponse {
+	return model.WarehouseResponse{
+		Id:         int64(res.Id),
+		Code:       res.Code,
+		Name:       res.Name,
+		Street:     res.Street,
+		Province:   res.Province,
+		City:       res.City,
+		PostalCode: res.PostalCode,
+		IsDefault:  res.IsDefault,
+		Active:     res.Active,
+	}
+}
@@ -0,0 +1,141 @@
+package warehouse
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"kanggo/pkg/entity/model"
+	"kanggo/pkg/entity/schema"
+	"kanggo/pkg/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestInsert(t *testing.T) {
+	mockWarehouseStorage := new(mocks.WarehouseStorage)
+	u := NewWarehouseUsecase(mockWarehouseStorage)
+	ctx := context.Background()
+
+	t.Run("active by default", func(t *testing.T) {
+		mockWarehouseStorage.On("Insert", mock.Anything, schema.Warehouse{
+			Code:     "JKT",
+			Name:     "Gudang Jakarta",
+			Province: "DKI Jakarta",
+			City:     "Jakarta Barat",
+			Active:   true,
+		}).Return(nil).Once()
+
+		err := u.Insert(ctx, model.WarehouseRequest{Code: "jkt", Name: "Gudang Jakarta", Province: "DKI Jakarta", City: "Jakarta Barat"})
+
+		assert.NoError(t, err)
+		mockWarehouseStorage.AssertExpectations(t)
+	})
+
+	t.Run("inactive", func(t *testing.T) {
+		active := false
+		mockWarehouseStorage.On("Insert", mock.Anything, mock.MatchedBy(func(w schema.Warehouse) bool {
+			return w.Code == "SBY" && !w.Active
+		})).Return(nil).Once()
+
+		err := u.Insert(ctx, model.WarehouseRequest{Code: "SBY", Name: "Gudang Surabaya", Province: "Jawa Timur", City: "Surabaya", Active: &active})
+
+		assert.NoError(t, err)
+		mockWarehouseStorage.AssertExpectations(t)
+	})
+}
+
+func TestGetStock(t *testing.T) {
+	mockWarehouseStorage := new(mocks.WarehouseStorage)
+	u := NewWarehouseUsecase(mockWarehouseStorage)
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		mockWarehouseStorage.On("GetById", mock.Anything, int64(1)).Return(&schema.Warehouse{Base: schema.Base{Id: 1}}, nil).Once()
+		mockWarehouseStorage.On("GetStock", mock.Anything, int64(1)).Return([]model.StockLevelResponse{
+			{VariantId: 3, ProductId: 1, ProductName: "Semen 50kg", Sku: "SEMEN-50KG", Qty: 12},
+		}, nil).Once()
+
+		res, err := u.GetStock(ctx, 1)
+
+		assert.NoError(t, err)
+		assert.Len(t, res, 1)
+		mockWarehouseStorage.AssertExpectations(t)
+	})
+
+	t.Run("unknown warehouse", func(t *testing.T) {
+		mockWarehouseStorage.On("GetById", mock.Anything, int64(9)).Return(nil, sql.ErrNoRows).Once()
+
+		_, err := u.GetStock(ctx, 9)
+
+		assert.Equal(t, sql.ErrNoRows, err)
+		mockWarehouseStorage.AssertNotCalled(t, "GetStock", mock.Anything, int64(9))
+	})
+}
+
+func TestSetStock(t *testing.T) {
+	mockWarehouseStorage := new(mocks.WarehouseStorage)
+	u := NewWarehouseUsecase(mockWarehouseStorage)
+	ctx := context.Background()
+
+	mockWarehouseStorage.On("SetStock", mock.Anything, schema.WarehouseStock{WarehouseId: 2, VariantId: 3, Qty: 40}, int64(7), "stock opname").
+		Return(nil)
+
+	err := u.SetStock(ctx, 2, 7, model.StockRequest{VariantId: 3, Qty: 40, Note: "stock opname"})
+
+	assert.NoError(t, err)
+	mockWarehouseStorage.AssertExpectations(t)
+}
+
+func TestTransfer(t *testing.T) {
+	mockWarehouseStorage := new(mocks.WarehouseStorage)
+	u := NewWarehouseUsecase(mockWarehouseStorage)
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		request := model.StockTransferRequest{FromWarehouseId: 1, ToWarehouseId: 2, VariantId: 3, Qty: 5}
+		mockWarehouseStorage.On("Transfer", mock.Anything, request, int64(7)).Return("TRF-000012", nil).Once()
+
+		res, err := u.Transfer(ctx, 7, request)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "TRF-000012", res.Reference)
+		assert.EqualValues(t, 5, res.Qty)
+	})
+
+	t.Run("not enough stock", func(t *testing.T) {
+		request := model.StockTransferRequest{FromWarehouseId: 1, ToWarehouseId: 2, VariantId: 3, Qty: 500}
+		mockWarehouseStorage.On("Transfer", mock.Anything, request, int64(7)).Return("", errors.New("not enough stock in the warehouse")).Once()
+
+		res, err := u.Transfer(ctx, 7, request)
+
+		assert.Nil(t, res)
+		assert.EqualError(t, err, "not enough stock in the warehouse")
+	})
+}
+
+func TestGetMovements(t *testing.T) {
+	mockWarehouseStorage := new(mocks.WarehouseStorage)
+	u := NewWarehouseUsecase(mockWarehouseStorage)
+	ctx := context.Background()
+	query := model.ListQuery{Page: 1, Size: 20, Sort: "id"}
+	createdAt := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	mockWarehouseStorage.On("GetById", mock.Anything, int64(1)).Return(&schema.Warehouse{Base: schema.Base{Id: 1}}, nil)
+	mockWarehouseStorage.On("GetMovements", mock.Anything, int64(1), int64(3), query).Return([]schema.StockMovement{
+		{Base: schema.Base{Id: 13, CreatedAt: createdAt}, WarehouseId: 1, VariantId: 3, ProductId: 1, Qty: -5, Type: schema.MovementTransferOut, Reference: "TRF-000012"},
+		{Base: schema.Base{Id: 9, CreatedAt: createdAt}, WarehouseId: 1, VariantId: 3, ProductId: 1, Qty: -2, Type: schema.MovementOrder, OrderId: 4},
+	}, int64(2), nil)
+
+	res, total, err := u.GetMovements(ctx, 1, 3, query)
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, total)
+	assert.EqualValues(t, 13, res[0].Id)
+	assert.Equal(t, "TRF-000012", res[0].Reference)
+	assert.EqualValues(t, 4, res[1].OrderId)
+	mockWarehouseStorage.AssertExpectations(t)
+}
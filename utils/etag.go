@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	ErrPreconditionRequired = errors.New("If-Match header is required")
+	ErrPreconditionFailed   = errors.New("resource has been modified")
+)
+
+// ETag is the strong entity tag of a row version.
+func ETag(version uint) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// IfMatch reads the version a write is conditional on from the If-Match
+// header. A * matches any version and is returned as 0. Weak tags and lists of
+// tags never match a single version, so they fail the precondition.
+func IfMatch(c *gin.Context) (uint, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		return 0, ErrPreconditionRequired
+	}
+
+	if header == "*" {
+		return 0, nil
+	}
+
+	if len(header) < 3 || header[0] != '"' || header[len(header)-1] != '"' {
+		return 0, ErrPreconditionFailed
+	}
+
+	version, err := strconv.ParseUint(header[1:len(header)-1], 10, 64)
+	if err != nil || version == 0 {
+		return 0, ErrPreconditionFailed
+	}
+
+	return uint(version), nil
+}
+
+// NotModified sets the ETag header of a read and reports whether the
+// If-None-Match header already names that tag, in which case it answers 304
+// and the handler has nothing left to do.
+func NotModified(c *gin.Context, version uint) bool {
+	etag := ETag(version)
+	c.Header("ETag", etag)
+
+	for _, tag := range strings.Split(c.GetHeader("If-None-Match"), ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == etag || tag == "*" {
+			c.Status(304)
+			return true
+		}
+	}
+
+	return false
+}
+
+// PreconditionResponse answers a write whose If-Match header is missing or
+// does not match.
+func PreconditionResponse(c *gin.Context, err error) {
+	if err == ErrPreconditionRequired {
+		Response(c, 428, err.Error(), nil)
+		return
+	}
+
+	Response(c, 412, ErrPreconditionFailed.Error(), nil)
+}
@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newConditionalContext(header, value string) (*gin.Context, *httptest.ResponseRecorder) {
+	rr := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rr)
+	c.Request = httptest.NewRequest("GET", "/product/1", nil)
+	if value != "" {
+		c.Request.Header.Set(header, value)
+	}
+	return c, rr
+}
+
+func TestIfMatch(t *testing.T) {
+	cases := []struct {
+		header  string
+		version uint
+		err     error
+	}{
+		{"", 0, ErrPreconditionRequired},
+		{"*", 0, nil},
+		{`"7"`, 7, nil},
+		{` "7" `, 7, nil},
+		{`W/"7"`, 0, ErrPreconditionFailed},
+		{`"7", "8"`, 0, ErrPreconditionFailed},
+		{`"0"`, 0, ErrPreconditionFailed},
+		{"7", 0, ErrPreconditionFailed},
+	}
+
+	for _, tc := range cases {
+		c, _ := newConditionalContext("If-Match", tc.header)
+		version, err := IfMatch(c)
+
+		assert.Equal(t, tc.err, err, tc.header)
+		assert.Equal(t, tc.version, version, tc.header)
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	t.Run("changed", func(t *testing.T) {
+		c, rr := newConditionalContext("If-None-Match", `"2"`)
+
+		assert.False(t, NotModified(c, 3))
+		assert.Equal(t, `"3"`, rr.Header().Get("ETag"))
+	})
+
+	t.Run("weak match", func(t *testing.T) {
+		c, _ := newConditionalContext("If-None-Match", `"2", W/"3"`)
+
+		assert.True(t, NotModified(c, 3))
+		assert.Equal(t, 304, c.Writer.Status())
+	})
+
+	t.Run("without header", func(t *testing.T) {
+		c, _ := newConditionalContext("If-None-Match", "")
+
+		assert.False(t, NotModified(c, 3))
+	})
+}
@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"errors"
+	"kanggo/pkg/entity/model"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// ParseListQuery reads the shared list parameters:
+//
+//	page, size           page number from 1 and page size (default 20, max 100)
+//	cursor               id of the last item seen, instead of page
+//	sort                 one of sorts, prefixed with - for descending
+//	status               exact status
+//	from, to             YYYY-MM-DD creation date range, both inclusive
+//	min_price, max_price price range
+//	category             category slug
+//
+// The first of sorts is the default sort. A cursor only works with sorting by
+// id, since ids are the only unique sort key.
+func ParseListQuery(c *gin.Context, sorts ...string) (model.ListQuery, error) {
+	query := model.ListQuery{Page: 1, Size: DefaultPageSize}
+	var err error
+
+	if v := c.Query("page"); v != "" {
+		if query.Page, err = strconv.Atoi(v); err != nil || query.Page < 1 {
+			return query, errors.New("invalid page")
+		}
+	}
+
+	if v := c.Query("size"); v != "" {
+		if query.Size, err = strconv.Atoi(v); err != nil || query.Size < 1 || query.Size > MaxPageSize {
+			return query, errors.New("invalid size")
+		}
+	}
+
+	if len(sorts) > 0 {
+		query.Sort = sorts[0]
+	}
+	if v := c.Query("sort"); v != "" {
+		query.Desc = strings.HasPrefix(v, "-")
+		query.Sort = strings.TrimPrefix(v, "-")
+		if !contains(sorts, query.Sort) {
+			return query, errors.New("invalid sort field")
+		}
+	}
+
+	if v := c.Query("cursor"); v != "" {
+		if query.Cursor, err = strconv.ParseInt(v, 10, 64); err != nil || query.Cursor < 1 {
+			return query, errors.New("invalid cursor")
+		}
+		if query.Sort != "id" {
+			return query, errors.New("cursor requires sorting by id")
+		}
+	}
+
+	query.Status = c.Query("status")
+	query.Category = c.Query("category")
+
+	if v := c.Query("from"); v != "" {
+		from, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			return query, errors.New("invalid from date, use YYYY-MM-DD")
+		}
+		query.From = &from
+	}
+
+	if v := c.Query("to"); v != "" {
+		to, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			return query, errors.New("invalid to date, use YYYY-MM-DD")
+		}
+		to = to.AddDate(0, 0, 1)
+		query.To = &to
+	}
+
+	if v := c.Query("min_price"); v != "" {
+		price, err := strconv.ParseFloat(v, 64)
+		if err != nil || price < 0 {
+			return query, errors.New("invalid min_price")
+		}
+		query.MinPrice = &price
+	}
+
+	if v := c.Query("max_price"); v != "" {
+		price, err := strconv.ParseFloat(v, 64)
+		if err != nil || price < 0 {
+			return query, errors.New("invalid max_price")
+		}
+		query.MaxPrice = &price
+	}
+
+	return query, nil
+}
+
+// NewMeta describes the returned page. lastId is the id of the last item;
+// a next cursor is only offered when sorting by id and the page is full.
+func NewMeta(query model.ListQuery, total int64, count int, lastId int64) *Meta {
+	meta := &Meta{Size: query.Size, Total: total}
+	if query.Cursor == 0 {
+		meta.Page = query.Page
+	}
+
+	if query.Sort == "id" && count == query.Size && lastId > 0 {
+		meta.NextCursor = strconv.FormatInt(lastId, 10)
+	}
+
+	return meta
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newListContext(target string) *gin.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", target, nil)
+	return c
+}
+
+func TestParseListQuery(t *testing.T) {
+	sorts := []string{"id", "name", "price"}
+
+	t.Run("defaults", func(t *testing.T) {
+		query, err := ParseListQuery(newListContext("/product"), sorts...)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, query.Page)
+		assert.Equal(t, DefaultPageSize, query.Size)
+		assert.Equal(t, "id", query.Sort)
+		assert.False(t, query.Desc)
+		assert.Equal(t, 0, query.Offset())
+	})
+
+	t.Run("page, sort and filters", func(t *testing.T) {
+		query, err := ParseListQuery(newListContext("/product?page=3&size=10&sort=-price&status=paid&from=2021-11-01&to=2021-11-30&min_price=1000&max_price=5000"), sorts...)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 20, query.Offset())
+		assert.Equal(t, "price", query.Sort)
+		assert.True(t, query.Desc)
+		assert.Equal(t, "paid", query.Status)
+		assert.Equal(t, time.Date(2021, 11, 1, 0, 0, 0, 0, time.Local), *query.From)
+		assert.Equal(t, time.Date(2021, 12, 1, 0, 0, 0, 0, time.Local), *query.To)
+		assert.Equal(t, 1000.0, *query.MinPrice)
+		assert.Equal(t, 5000.0, *query.MaxPrice)
+	})
+
+	t.Run("cursor", func(t *testing.T) {
+		query, err := ParseListQuery(newListContext("/product?cursor=42&page=5"), sorts...)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(42), query.Cursor)
+		assert.Equal(t, 0, query.Offset())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		cases := map[string]string{
+			"/product?page=0":              "invalid page",
+			"/product?size=101":            "invalid size",
+			"/product?sort=qty":            "invalid sort field",
+			"/product?cursor=abc":          "invalid cursor",
+			"/product?cursor=10&sort=name": "cursor requires sorting by id",
+			"/product?from=01-11-2021":     "invalid from date, use YYYY-MM-DD",
+			"/product?min_price=-1":        "invalid min_price",
+			"/product?max_price=seribu":    "invalid max_price",
+		}
+
+		for target, msg := range cases {
+			_, err := ParseListQuery(newListContext(target), sorts...)
+			assert.EqualError(t, err, msg, target)
+		}
+	})
+}
+
+func TestNewMeta(t *testing.T) {
+	query, _ := ParseListQuery(newListContext("/product?size=2"), "id")
+
+	meta := NewMeta(query, 5, 2, 7)
+	assert.Equal(t, 1, meta.Page)
+	assert.Equal(t, int64(5), meta.Total)
+	assert.Equal(t, "7", meta.NextCursor)
+
+	meta = NewMeta(query, 5, 1, 9)
+	assert.Empty(t, meta.NextCursor)
+}
@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OrderResolver finds the id of an order by its number.
+type OrderResolver interface {
+	GetOrderIdByNumber(ctx context.Context, number string) (int64, error)
+}
+
+// OrderId reads the :id param of an order route, which is either the order id
+// or the order number, the latter always containing a non-digit character.
+// When the order cannot be resolved the response is already written.
+func OrderId(c *gin.Context, orders OrderResolver) (int64, bool) {
+	param := c.Param("id")
+	if id, err := strconv.ParseInt(param, 10, 64); err == nil {
+		return id, true
+	}
+
+	id, err := orders.GetOrderIdByNumber(c.Request.Context(), param)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			Response(c, 404, "data not found", nil)
+			return 0, false
+		}
+		Response(c, 500, err.Error(), nil)
+		return 0, false
+	}
+
+	return id, true
+}
@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+)
+
+const MergePatchType = "application/merge-patch+json"
+
+var (
+	ErrPatchType   = errors.New("content type must be " + MergePatchType)
+	ErrPatchObject = errors.New("patch must be a JSON object")
+)
+
+// BindMergePatch decodes an RFC 7386 merge patch into obj. The fields of obj
+// should be pointers, so members left out of the patch stay nil. Since a null
+// member decodes to nil as well, the names of the members set to null are
+// returned: they ask for the field to be removed. application/json is
+// accepted too for clients that cannot set the merge patch type.
+func BindMergePatch(c *gin.Context, obj interface{}) ([]string, error) {
+	if t := c.ContentType(); t != MergePatchType && t != gin.MIMEJSON {
+		return nil, ErrPatchType
+	}
+
+	body, err := ioutil.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	members := map[string]json.RawMessage{}
+	if err := json.Unmarshal(body, &members); err != nil || members == nil {
+		return nil, ErrPatchObject
+	}
+
+	if err := json.Unmarshal(body, obj); err != nil {
+		return nil, err
+	}
+
+	null := []string{}
+	for name, value := range members {
+		if bytes.Equal(bytes.TrimSpace(value), []byte("null")) {
+			null = append(null, name)
+		}
+	}
+	sort.Strings(null)
+
+	return null, nil
+}
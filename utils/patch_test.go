@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newPatchContext(contentType, body string) *gin.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("PATCH", "/product/1", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", contentType)
+	return c
+}
+
+func TestBindMergePatch(t *testing.T) {
+	type patch struct {
+		Name  *string `json:"name"`
+		Qty   *int    `json:"qty"`
+		Brand *string `json:"brand"`
+	}
+
+	t.Run("members", func(t *testing.T) {
+		var res patch
+		null, err := BindMergePatch(newPatchContext(MergePatchType, `{"qty": 0, "brand": null}`), &res)
+
+		assert.NoError(t, err)
+		assert.Nil(t, res.Name)
+		assert.Equal(t, 0, *res.Qty)
+		assert.Nil(t, res.Brand)
+		assert.Equal(t, []string{"brand"}, null)
+	})
+
+	t.Run("plain json", func(t *testing.T) {
+		var res patch
+		null, err := BindMergePatch(newPatchContext("application/json; charset=utf-8", `{"name": "Semen"}`), &res)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Semen", *res.Name)
+		assert.Empty(t, null)
+	})
+
+	t.Run("content type", func(t *testing.T) {
+		var res patch
+		_, err := BindMergePatch(newPatchContext("text/plain", `{}`), &res)
+
+		assert.Equal(t, ErrPatchType, err)
+	})
+
+	t.Run("not an object", func(t *testing.T) {
+		for _, body := range []string{`null`, `[{"qty": 1}]`, `{"qty":`} {
+			var res patch
+			_, err := BindMergePatch(newPatchContext(MergePatchType, body), &res)
+
+			assert.Equal(t, ErrPatchObject, err, body)
+		}
+	})
+}
@@ -9,6 +9,14 @@ type (
 		Status  int         `json:"status"`
 		Message string      `json:"message"`
 		Data    interface{} `json:"data"`
+		Meta    *Meta       `json:"meta,omitempty"`
+	}
+
+	Meta struct {
+		Page       int    `json:"page,omitempty"`
+		Size       int    `json:"size"`
+		Total      int64  `json:"total"`
+		NextCursor string `json:"next_cursor,omitempty"`
 	}
 )
 
@@ -19,3 +27,13 @@ func Response(c *gin.Context, status int, msg string, data interface{}) {
 		Data:    data,
 	})
 }
+
+// ResponseList responds with a page of a list and its paging metadata.
+func ResponseList(c *gin.Context, status int, msg string, data interface{}, meta *Meta) {
+	c.JSON(status, &Respond{
+		Status:  status,
+		Message: msg,
+		Data:    data,
+		Meta:    meta,
+	})
+}